@@ -321,6 +321,7 @@ populate_health_stats(struct bio_dev_health *bdh)
 	d_tm_set_gauge(bdh->bdh_temp_warn, dev_state->temp_warn);
 
 	/** reliability */
+	dev_state->avail_spare		= page->available_spare;
 	d_tm_set_counter(bdh->bdh_avail_spare, page->available_spare);
 	d_tm_set_counter(bdh->bdh_avail_spare_thres,
 			 page->available_spare_threshold);
@@ -228,3 +228,53 @@ func (h *bdevFormatHandler) Handle(log logging.Logger, req *pbin.Request) *pbin.
 
 	return pbin.NewResponseWithPayload(fRes)
 }
+
+// bdevSelfTestHandler implements the BdevSelfTest method.
+type bdevSelfTestHandler struct {
+	bdevHandler
+}
+
+func (h *bdevSelfTestHandler) Handle(log logging.Logger, req *pbin.Request) *pbin.Response {
+	if req == nil {
+		return getNilRequestResp()
+	}
+
+	var stReq bdev.SelfTestRequest
+	if err := json.Unmarshal(req.Payload, &stReq); err != nil {
+		return pbin.NewResponseWithError(err)
+	}
+
+	h.setupProvider(log)
+
+	stRes, err := h.bdevProvider.StartSelfTest(stReq)
+	if err != nil {
+		return pbin.NewResponseWithError(err)
+	}
+
+	return pbin.NewResponseWithPayload(stRes)
+}
+
+// bdevSelfTestQueryHandler implements the BdevSelfTestQuery method.
+type bdevSelfTestQueryHandler struct {
+	bdevHandler
+}
+
+func (h *bdevSelfTestQueryHandler) Handle(log logging.Logger, req *pbin.Request) *pbin.Response {
+	if req == nil {
+		return getNilRequestResp()
+	}
+
+	var qReq bdev.SelfTestQueryRequest
+	if err := json.Unmarshal(req.Payload, &qReq); err != nil {
+		return pbin.NewResponseWithError(err)
+	}
+
+	h.setupProvider(log)
+
+	qRes, err := h.bdevProvider.QuerySelfTest(qReq)
+	if err != nil {
+		return pbin.NewResponseWithError(err)
+	}
+
+	return pbin.NewResponseWithPayload(qRes)
+}
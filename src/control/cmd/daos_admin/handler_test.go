@@ -440,7 +440,12 @@ func TestDaosAdmin_BdevPrepHandler(t *testing.T) {
 				Method:  "BdevPrepare",
 				Payload: bdevPrepareReqPayload,
 			},
-			expPayload: &bdev.PrepareResponse{},
+			// driver and hugepages default when unset in the request
+			expPayload: &bdev.PrepareResponse{
+				PCIAddrs:      []string{},
+				Driver:        "vfio-pci",
+				HugePageCount: 4096,
+			},
 		},
 		"BdevPrepare failure": {
 			req: &pbin.Request{
@@ -550,3 +555,146 @@ func TestDaosAdmin_BdevFormatHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDaosAdmin_BdevSelfTestHandler(t *testing.T) {
+	selfTestReqPayload, err := json.Marshal(bdev.SelfTestRequest{
+		ForwardableRequest: pbin.ForwardableRequest{Forwarded: true},
+		PciAddr:            "0000:80:00.0",
+		Extended:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		req        *pbin.Request
+		bmbc       *bdev.MockBackendConfig
+		expPayload *bdev.SelfTestResponse
+		expErr     *fault.Fault
+	}{
+		"nil request": {
+			expErr: pbin.PrivilegedHelperRequestFailed("nil request"),
+		},
+		"BdevSelfTest nil payload": {
+			req: &pbin.Request{
+				Method: "BdevSelfTest",
+			},
+			expErr: nilPayloadErr,
+		},
+		"BdevSelfTest success": {
+			req: &pbin.Request{
+				Method:  "BdevSelfTest",
+				Payload: selfTestReqPayload,
+			},
+			expPayload: &bdev.SelfTestResponse{
+				Code: storage.NvmeSelfTestExtended,
+			},
+		},
+		"BdevSelfTest failure": {
+			req: &pbin.Request{
+				Method:  "BdevSelfTest",
+				Payload: selfTestReqPayload,
+			},
+			bmbc: &bdev.MockBackendConfig{
+				SelfTestErr: bdev.FaultUnknown,
+			},
+			expErr: bdev.FaultUnknown,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bp := bdev.NewMockProvider(log, tc.bmbc)
+			handler := &bdevSelfTestHandler{bdevHandler: bdevHandler{bdevProvider: bp}}
+
+			resp := handler.Handle(log, tc.req)
+
+			if diff := cmp.Diff(tc.expErr, resp.Error); diff != "" {
+				t.Errorf("got wrong fault (-want, +got)\n%s\n", diff)
+			}
+			if tc.expPayload == nil {
+				tc.expPayload = &bdev.SelfTestResponse{}
+			}
+			expectPayload(t, resp, &bdev.SelfTestResponse{}, tc.expPayload)
+		})
+	}
+}
+
+func TestDaosAdmin_BdevSelfTestQueryHandler(t *testing.T) {
+	pciAddr := "0000:80:00.0"
+	queryReqPayload, err := json.Marshal(bdev.SelfTestQueryRequest{
+		ForwardableRequest: pbin.ForwardableRequest{Forwarded: true},
+		PciAddr:            pciAddr,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inProgress := &storage.NvmeSelfTestStatus{
+		PciAddr:    pciAddr,
+		Current:    storage.NvmeSelfTestShort,
+		Progress:   25,
+		LastResult: storage.NvmeSelfTestNoResult,
+	}
+
+	for name, tc := range map[string]struct {
+		req        *pbin.Request
+		bmbc       *bdev.MockBackendConfig
+		expPayload *bdev.SelfTestQueryResponse
+		expErr     *fault.Fault
+	}{
+		"nil request": {
+			expErr: pbin.PrivilegedHelperRequestFailed("nil request"),
+		},
+		"BdevSelfTestQuery nil payload": {
+			req: &pbin.Request{
+				Method: "BdevSelfTestQuery",
+			},
+			expErr: nilPayloadErr,
+		},
+		"BdevSelfTestQuery success": {
+			req: &pbin.Request{
+				Method:  "BdevSelfTestQuery",
+				Payload: queryReqPayload,
+			},
+			bmbc: &bdev.MockBackendConfig{
+				SelfTestStates: map[string][]*storage.NvmeSelfTestStatus{
+					pciAddr: {inProgress},
+				},
+			},
+			expPayload: &bdev.SelfTestQueryResponse{
+				Status: *inProgress,
+			},
+		},
+		"BdevSelfTestQuery failure": {
+			req: &pbin.Request{
+				Method:  "BdevSelfTestQuery",
+				Payload: queryReqPayload,
+			},
+			bmbc: &bdev.MockBackendConfig{
+				SelfTestErrs: map[string]error{
+					pciAddr: bdev.FaultUnknown,
+				},
+			},
+			expErr: bdev.FaultUnknown,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bp := bdev.NewMockProvider(log, tc.bmbc)
+			handler := &bdevSelfTestQueryHandler{bdevHandler: bdevHandler{bdevProvider: bp}}
+
+			resp := handler.Handle(log, tc.req)
+
+			if diff := cmp.Diff(tc.expErr, resp.Error); diff != "" {
+				t.Errorf("got wrong fault (-want, +got)\n%s\n", diff)
+			}
+			if tc.expPayload == nil {
+				tc.expPayload = &bdev.SelfTestQueryResponse{}
+			}
+			expectPayload(t, resp, &bdev.SelfTestQueryResponse{}, tc.expPayload)
+		})
+	}
+}
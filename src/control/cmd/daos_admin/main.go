@@ -40,4 +40,6 @@ func addMethodHandlers(app *pbin.App) {
 	app.AddHandler("BdevPrepare", &bdevPrepHandler{})
 	app.AddHandler("BdevScan", &bdevScanHandler{})
 	app.AddHandler("BdevFormat", &bdevFormatHandler{})
+	app.AddHandler("BdevSelfTest", &bdevSelfTestHandler{})
+	app.AddHandler("BdevSelfTestQuery", &bdevSelfTestQueryHandler{})
 }
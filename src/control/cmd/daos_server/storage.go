@@ -31,6 +31,7 @@ type storagePrepareCmd struct {
 	scs *server.StorageControlService
 	logCmd
 	commands.StoragePrepareCmd
+	DryRun bool `long:"dry-run" description:"Report the NVMe devices that would be bound and the number of hugepages that would be allocated without making any changes."`
 }
 
 func (cmd *storagePrepareCmd) Execute(args []string) error {
@@ -38,6 +39,12 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+	if cmd.DryRun {
+		if cmd.ScmOnly {
+			return errors.New("dry-run is only supported for NVMe")
+		}
+		prepScm = false
+	}
 
 	// This is a little ugly, but allows for easier unit testing.
 	// FIXME: With the benefit of hindsight, it seems apparent
@@ -67,13 +74,25 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 		}
 
 		// Prepare NVMe access through SPDK
-		if _, err := cmd.scs.NvmePrepare(bdev.PrepareRequest{
+		resp, err := cmd.scs.NvmePrepare(bdev.PrepareRequest{
 			HugePageCount: cmd.NrHugepages,
 			TargetUser:    cmd.TargetUser,
 			PCIAllowlist:  cmd.PCIAllowList,
 			ResetOnly:     cmd.Reset,
-		}); err != nil {
+			DryRun:        cmd.DryRun,
+		})
+		if err != nil {
 			scanErrors = append(scanErrors, err)
+		} else if cmd.DryRun {
+			cmd.logNvmeDryRun(resp)
+		} else if !cmd.Reset {
+			if len(resp.PCIAddrs) == 0 {
+				cmd.log.Infof("no NVMe SSDs bound to %s", resp.Driver)
+			} else {
+				cmd.log.Infof("NVMe SSDs bound to %s: %s", resp.Driver,
+					strings.Join(resp.PCIAddrs, ", "))
+			}
+			cmd.log.Infof("%d hugepages allocated", resp.HugePageCount)
 		}
 	}
 
@@ -117,6 +136,21 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 	return nil
 }
 
+// logNvmeDryRun logs the actions that the NVMe prepare would perform.
+func (cmd *storagePrepareCmd) logNvmeDryRun(resp *bdev.PrepareResponse) {
+	switch {
+	case cmd.Reset:
+		cmd.log.Info("dry-run: NVMe SSDs would be returned to the kernel driver")
+		return
+	case len(resp.PCIAddrs) == 0:
+		cmd.log.Info("dry-run: all NVMe SSDs not blocklisted would be bound")
+	default:
+		cmd.log.Infof("dry-run: NVMe SSDs that would be bound: %s",
+			strings.Join(resp.PCIAddrs, ", "))
+	}
+	cmd.log.Infof("dry-run: %d hugepages would be allocated", resp.HugePageCount)
+}
+
 type storageScanCmd struct {
 	logCmd
 }
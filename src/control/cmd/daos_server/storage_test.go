@@ -34,6 +34,7 @@ func TestDaosServer_StoragePrepare(t *testing.T) {
 		scmOnly   bool
 		reset     bool
 		force     bool
+		dryRun    bool
 		bmbc      *bdev.MockBackendConfig
 		smbc      *scm.MockBackendConfig
 		expLogMsg string
@@ -111,6 +112,32 @@ func TestDaosServer_StoragePrepare(t *testing.T) {
 			},
 			expErr: failedErr,
 		},
+		"nvme dry-run": {
+			dryRun: true,
+			bmbc: &bdev.MockBackendConfig{
+				PrepareResetErr: failedErr,
+				PrepareErr:      failedErr,
+			},
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateFreeCapacity,
+				PrepErr:       failedErr,
+			},
+			expLogMsg: "dry-run: 4096 hugepages would be allocated",
+		},
+		"nvme reset dry-run": {
+			dryRun: true,
+			reset:  true,
+			bmbc: &bdev.MockBackendConfig{
+				PrepareResetErr: failedErr,
+			},
+			expLogMsg: "dry-run: NVMe SSDs would be returned",
+		},
+		"scm-only dry-run": {
+			scmOnly: true,
+			dryRun:  true,
+			expErr:  errors.New("only supported for NVMe"),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(name)
@@ -125,6 +152,7 @@ func TestDaosServer_StoragePrepare(t *testing.T) {
 					Reset:    tc.reset,
 					Force:    tc.force,
 				},
+				DryRun: tc.dryRun,
 				logCmd: logCmd{
 					log: log,
 				},
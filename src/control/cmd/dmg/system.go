@@ -65,8 +65,9 @@ func (cmd *leaderQueryCmd) Execute(_ []string) (errOut error) {
 // rankListCmd enables rank or host list to be supplied with command to filter
 // which ranks are operated upon.
 type rankListCmd struct {
-	Ranks string `long:"ranks" short:"r" description:"Comma separated ranges or individual system ranks to operate on"`
-	Hosts string `long:"rank-hosts" description:"Hostlist representing hosts whose managed ranks are to be operated on"`
+	Ranks       string `long:"ranks" short:"r" description:"Comma separated ranges or individual system ranks to operate on"`
+	Hosts       string `long:"rank-hosts" description:"Hostlist representing hosts whose managed ranks are to be operated on"`
+	StrictRanks bool   `long:"strict-ranks" description:"Fail if any of the given ranks are not system members"`
 }
 
 // validateHostsRanks validates rank and host lists have correct format.
@@ -112,6 +113,7 @@ func (cmd *systemQueryCmd) Execute(_ []string) (errOut error) {
 	req := new(control.SystemQueryReq)
 	req.Hosts.ReplaceSet(hostSet)
 	req.Ranks.ReplaceSet(rankSet)
+	req.StrictRanks = cmd.StrictRanks
 
 	resp, err := control.SystemQuery(context.Background(), cmd.ctlInvoker, req)
 	if err != nil {
@@ -174,6 +176,7 @@ func (cmd *systemStopCmd) Execute(_ []string) (errOut error) {
 	req := &control.SystemStopReq{Prep: true, Kill: true, Force: cmd.Force}
 	req.Hosts.ReplaceSet(hostSet)
 	req.Ranks.ReplaceSet(rankSet)
+	req.StrictRanks = cmd.StrictRanks
 
 	resp, err := control.SystemStop(context.Background(), cmd.ctlInvoker, req)
 	if err != nil {
@@ -218,6 +221,7 @@ func (cmd *systemStartCmd) Execute(_ []string) (errOut error) {
 	req := new(control.SystemStartReq)
 	req.Hosts.ReplaceSet(hostSet)
 	req.Ranks.ReplaceSet(rankSet)
+	req.StrictRanks = cmd.StrictRanks
 
 	resp, err := control.SystemStart(context.Background(), cmd.ctlInvoker, req)
 	if err != nil {
@@ -13,25 +13,56 @@ import (
 	"github.com/pkg/errors"
 )
 
+// parsePCIAddrComponent parses a hexadecimal PCI address component and checks
+// that it has the expected number of digits and is within range.
+func parsePCIAddrComponent(name, str string, minDigits, maxDigits int, max uint64) (uint64, error) {
+	if len(str) < minDigits || len(str) > maxDigits {
+		return 0, errors.Errorf("invalid %s %q: expected %d-%d hex digits",
+			name, str, minDigits, maxDigits)
+	}
+
+	val, err := strconv.ParseUint(str, 16, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", name)
+	}
+	if val > max {
+		return 0, errors.Errorf("invalid %s %q: exceeds maximum 0x%x", name, str, max)
+	}
+
+	return val, nil
+}
+
 // ParsePCIAddress returns separated components of BDF format PCI address.
+//
+// The domain component may be wider than the standard 4 hex digits to
+// accommodate the addresses of devices behind a VMD which encode the VMD
+// address in the domain e.g. 5d0505:01:00.0. All components are validated and
+// zero values are returned if the address is malformed.
 func ParsePCIAddress(addr string) (dom, bus, dev, fun uint64, err error) {
+	defer func() {
+		if err != nil {
+			dom, bus, dev, fun = 0, 0, 0, 0
+			err = errors.Wrapf(err, "pci address %q", addr)
+		}
+	}()
+
 	parts := strings.Split(addr, ":")
 	devFunc := strings.Split(parts[len(parts)-1], ".")
 	if len(parts) != 3 || len(devFunc) != 2 {
-		err = errors.Errorf("unexpected pci address bdf format: %q", addr)
+		err = errors.New("unexpected bdf format")
 		return
 	}
 
-	if dom, err = strconv.ParseUint(parts[0], 16, 64); err != nil {
+	if dom, err = parsePCIAddrComponent("domain", parts[0], 1, 8, 0xffffffff); err != nil {
 		return
 	}
-	if bus, err = strconv.ParseUint(parts[1], 16, 32); err != nil {
+	if bus, err = parsePCIAddrComponent("bus", parts[1], 1, 2, 0xff); err != nil {
 		return
 	}
-	if dev, err = strconv.ParseUint(devFunc[0], 16, 32); err != nil {
+	if dev, err = parsePCIAddrComponent("device", devFunc[0], 1, 2, 0x1f); err != nil {
 		return
 	}
-	if fun, err = strconv.ParseUint(devFunc[1], 16, 32); err != nil {
+	if fun, err = parsePCIAddrComponent("function", devFunc[1], 1, 1, 0x7); err != nil {
 		return
 	}
 
@@ -40,6 +40,45 @@ func TestCommon_ParsePCIAddress(t *testing.T) {
 			expDom:  0x5d0505,
 			expBus:  0x01,
 		},
+		"full range components": {
+			addrStr: "ffffffff:ff:1f.7",
+			expDom:  0xffffffff,
+			expBus:  0xff,
+			expDev:  0x1f,
+			expFun:  0x7,
+		},
+		"missing function": {
+			addrStr: "0000:80:00",
+			expErr:  errors.New("unexpected bdf format"),
+		},
+		"too many components": {
+			addrStr: "0000:00:80:00.0",
+			expErr:  errors.New("unexpected bdf format"),
+		},
+		"empty domain": {
+			addrStr: ":80:00.0",
+			expErr:  errors.New("invalid domain"),
+		},
+		"domain too wide": {
+			addrStr: "100000000:80:00.0",
+			expErr:  errors.New("invalid domain"),
+		},
+		"bus too wide": {
+			addrStr: "0000:800:00.0",
+			expErr:  errors.New("invalid bus"),
+		},
+		"device out of range": {
+			addrStr: "0000:80:20.0",
+			expErr:  errors.New("invalid device \"20\": exceeds maximum 0x1f"),
+		},
+		"function out of range": {
+			addrStr: "0000:80:00.8",
+			expErr:  errors.New("invalid function \"8\": exceeds maximum 0x7"),
+		},
+		"error includes address": {
+			addrStr: "0000:80:00.x",
+			expErr:  errors.New("pci address \"0000:80:00.x\": invalid function"),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			dom, bus, dev, fun, err := ParsePCIAddress(tc.addrStr)
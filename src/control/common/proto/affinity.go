@@ -0,0 +1,104 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+type (
+	// NvmeSocketGroup holds the NVMe controllers attached to a NUMA node
+	// along with the number of engines configured on that node.
+	NvmeSocketGroup struct {
+		SocketID uint32
+		Ctrlrs   NvmeControllers
+		Engines  int
+	}
+
+	// NvmeNumaAffinity describes the distribution of NVMe controllers
+	// across NUMA nodes relative to the engines configured on each.
+	NvmeNumaAffinity struct {
+		Groups   []*NvmeSocketGroup // ordered by socket ID
+		Warnings []string
+	}
+)
+
+// Balanced returns true if no imbalance was found.
+func (na *NvmeNumaAffinity) Balanced() bool {
+	return len(na.Warnings) == 0
+}
+
+// NvmeNumaAffinityFromScan groups the controllers in the scan response by
+// socket ID and checks the grouping against the NUMA node of each engine, as
+// given by engineSockets (indexed by engine).
+//
+// Imbalances are reported as warnings when a socket has engines but no
+// controllers, a socket has controllers but no engines to use them, or the
+// number of controllers per engine differs between sockets.
+func NvmeNumaAffinityFromScan(resp *ctlpb.ScanNvmeResp, engineSockets []uint32) (*NvmeNumaAffinity, error) {
+	if resp == nil {
+		return nil, errors.New("nil scan response")
+	}
+
+	groups := make(map[uint32]*NvmeSocketGroup)
+	getGroup := func(id uint32) *NvmeSocketGroup {
+		if _, exists := groups[id]; !exists {
+			groups[id] = &NvmeSocketGroup{SocketID: id}
+		}
+		return groups[id]
+	}
+
+	for _, ctrlr := range resp.GetCtrlrs() {
+		grp := getGroup(uint32(ctrlr.GetSocketId()))
+		grp.Ctrlrs = append(grp.Ctrlrs, ctrlr)
+	}
+	for _, socket := range engineSockets {
+		getGroup(socket).Engines++
+	}
+
+	na := new(NvmeNumaAffinity)
+	for _, grp := range groups {
+		na.Groups = append(na.Groups, grp)
+	}
+	sort.Slice(na.Groups, func(i, j int) bool {
+		return na.Groups[i].SocketID < na.Groups[j].SocketID
+	})
+
+	var perEngine []string
+	var ratio float64
+	uneven := false
+	for _, grp := range na.Groups {
+		switch {
+		case grp.Engines > 0 && len(grp.Ctrlrs) == 0:
+			na.Warnings = append(na.Warnings, fmt.Sprintf(
+				"socket %d has %d engine(s) but no NVMe SSDs", grp.SocketID, grp.Engines))
+		case grp.Engines == 0 && len(grp.Ctrlrs) > 0:
+			na.Warnings = append(na.Warnings, fmt.Sprintf(
+				"socket %d has %d NVMe SSD(s) but no engines", grp.SocketID, len(grp.Ctrlrs)))
+		case grp.Engines > 0:
+			r := float64(len(grp.Ctrlrs)) / float64(grp.Engines)
+			if len(perEngine) > 0 && r != ratio {
+				uneven = true
+			}
+			ratio = r
+			perEngine = append(perEngine, fmt.Sprintf("socket %d: %d SSD(s) for %d engine(s)",
+				grp.SocketID, len(grp.Ctrlrs), grp.Engines))
+		}
+	}
+	if uneven {
+		na.Warnings = append(na.Warnings, "NVMe SSDs per engine unbalanced across sockets ("+
+			strings.Join(perEngine, ", ")+")")
+	}
+
+	return na, nil
+}
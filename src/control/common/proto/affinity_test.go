@@ -0,0 +1,159 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+func TestProto_NvmeNumaAffinityFromScan(t *testing.T) {
+	ctrlr := func(addr string, socket int32) *ctlpb.NvmeController {
+		return &ctlpb.NvmeController{PciAddr: addr, SocketId: socket}
+	}
+	multiSocketScan := &ctlpb.ScanNvmeResp{
+		Ctrlrs: []*ctlpb.NvmeController{
+			ctrlr("0000:80:00.0", 1),
+			ctrlr("0000:05:00.0", 0),
+			ctrlr("0000:81:00.0", 1),
+			ctrlr("0000:06:00.0", 0),
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		resp          *ctlpb.ScanNvmeResp
+		engineSockets []uint32
+		expGroups     []*NvmeSocketGroup
+		expWarnings   []string
+		expErr        error
+	}{
+		"nil response": {
+			expErr: errors.New("nil scan response"),
+		},
+		"no controllers or engines": {
+			resp: &ctlpb.ScanNvmeResp{},
+		},
+		"balanced": {
+			resp:          multiSocketScan,
+			engineSockets: []uint32{0, 1},
+			expGroups: []*NvmeSocketGroup{
+				{
+					SocketID: 0,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:05:00.0", 0),
+						ctrlr("0000:06:00.0", 0),
+					},
+					Engines: 1,
+				},
+				{
+					SocketID: 1,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:80:00.0", 1),
+						ctrlr("0000:81:00.0", 1),
+					},
+					Engines: 1,
+				},
+			},
+		},
+		"unbalanced ssds per engine": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: append(multiSocketScan.Ctrlrs, ctrlr("0000:82:00.0", 1)),
+			},
+			engineSockets: []uint32{0, 1},
+			expGroups: []*NvmeSocketGroup{
+				{
+					SocketID: 0,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:05:00.0", 0),
+						ctrlr("0000:06:00.0", 0),
+					},
+					Engines: 1,
+				},
+				{
+					SocketID: 1,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:80:00.0", 1),
+						ctrlr("0000:81:00.0", 1),
+						ctrlr("0000:82:00.0", 1),
+					},
+					Engines: 1,
+				},
+			},
+			expWarnings: []string{
+				"NVMe SSDs per engine unbalanced across sockets " +
+					"(socket 0: 2 SSD(s) for 1 engine(s), socket 1: 3 SSD(s) for 1 engine(s))",
+			},
+		},
+		"engines on one socket": {
+			resp:          multiSocketScan,
+			engineSockets: []uint32{0, 0},
+			expGroups: []*NvmeSocketGroup{
+				{
+					SocketID: 0,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:05:00.0", 0),
+						ctrlr("0000:06:00.0", 0),
+					},
+					Engines: 2,
+				},
+				{
+					SocketID: 1,
+					Ctrlrs: NvmeControllers{
+						ctrlr("0000:80:00.0", 1),
+						ctrlr("0000:81:00.0", 1),
+					},
+				},
+			},
+			expWarnings: []string{
+				"socket 1 has 2 NVMe SSD(s) but no engines",
+			},
+		},
+		"engine without local ssds": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: []*ctlpb.NvmeController{
+					ctrlr("0000:05:00.0", 0),
+				},
+			},
+			engineSockets: []uint32{0, 1},
+			expGroups: []*NvmeSocketGroup{
+				{
+					SocketID: 0,
+					Ctrlrs:   NvmeControllers{ctrlr("0000:05:00.0", 0)},
+					Engines:  1,
+				},
+				{
+					SocketID: 1,
+					Engines:  1,
+				},
+			},
+			expWarnings: []string{
+				"socket 1 has 1 engine(s) but no NVMe SSDs",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotAffinity, gotErr := NvmeNumaAffinityFromScan(tc.resp, tc.engineSockets)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expGroups, gotAffinity.Groups, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected groups (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expWarnings, gotAffinity.Warnings); diff != "" {
+				t.Fatalf("unexpected warnings (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, len(tc.expWarnings) == 0, gotAffinity.Balanced(), "balanced")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package ctl
+
+import (
+	"github.com/pkg/errors"
+)
+
+// IsSuccess returns true if the state indicates that the operation completed
+// successfully. An unset state is treated as successful.
+func (x *ResponseState) IsSuccess() bool {
+	return x.GetStatus() == ResponseStatus_CTL_SUCCESS
+}
+
+// IsError returns true if the state indicates that the operation failed.
+// Operations that are in progress or waiting have not failed.
+func (x *ResponseState) IsError() bool {
+	return x.GetStatus() < ResponseStatus_CTL_SUCCESS
+}
+
+// AsError returns an error describing the failure indicated by the state,
+// or nil if the state doesn't indicate an error.
+func (x *ResponseState) AsError() error {
+	if !x.IsError() {
+		return nil
+	}
+
+	if errMsg := x.GetError(); errMsg != "" {
+		return errors.New(errMsg)
+	}
+	if infoMsg := x.GetInfo(); infoMsg != "" {
+		return errors.Errorf("%s: %s", x.GetStatus(), infoMsg)
+	}
+	return errors.Errorf("%s: unknown error", x.GetStatus())
+}
@@ -0,0 +1,78 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package ctl
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestCtl_ResponseState(t *testing.T) {
+	for name, tc := range map[string]struct {
+		state      *ResponseState
+		expSuccess bool
+		expError   bool
+		expErr     error
+	}{
+		"unset": {
+			expSuccess: true,
+		},
+		"zero value": {
+			state:      &ResponseState{},
+			expSuccess: true,
+		},
+		"success with info": {
+			state: &ResponseState{
+				Status: ResponseStatus_CTL_SUCCESS,
+				Info:   "all good",
+			},
+			expSuccess: true,
+		},
+		"in progress": {
+			state: &ResponseState{Status: ResponseStatus_CTL_IN_PROGRESS},
+		},
+		"error with message": {
+			state: &ResponseState{
+				Status: ResponseStatus_CTL_ERR_NVME,
+				Error:  "controller failed",
+				Info:   "try again",
+			},
+			expError: true,
+			expErr:   errors.New("controller failed"),
+		},
+		"error with info only": {
+			state: &ResponseState{
+				Status: ResponseStatus_CTL_ERR_SCM,
+				Info:   "try again",
+			},
+			expError: true,
+			expErr:   errors.New("CTL_ERR_SCM: try again"),
+		},
+		"error without message": {
+			state:    &ResponseState{Status: ResponseStatus_CTL_ERR_BUSY},
+			expError: true,
+			expErr:   errors.New("CTL_ERR_BUSY: unknown error"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.expSuccess, tc.state.IsSuccess(), "IsSuccess()")
+			common.AssertEqual(t, tc.expError, tc.state.IsError(), "IsError()")
+
+			gotErr := tc.state.AsError()
+			if tc.expErr == nil {
+				if gotErr != nil {
+					t.Fatalf("unexpected error: %s", gotErr)
+				}
+				return
+			}
+			common.AssertEqual(t, tc.expErr.Error(), gotErr.Error(), "AsError()")
+		})
+	}
+}
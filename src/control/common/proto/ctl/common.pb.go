@@ -28,15 +28,17 @@ const (
 type ResponseStatus int32
 
 const (
-	ResponseStatus_CTL_SUCCESS     ResponseStatus = 0
-	ResponseStatus_CTL_IN_PROGRESS ResponseStatus = 1  // Not yet completed
-	ResponseStatus_CTL_WAITING     ResponseStatus = 2  // Blocked
-	ResponseStatus_CTL_ERR_CONF    ResponseStatus = -1 // Config file parsing error
-	ResponseStatus_CTL_ERR_NVME    ResponseStatus = -2 // NVMe subsystem error
-	ResponseStatus_CTL_ERR_SCM     ResponseStatus = -3 // SCM subsystem error
-	ResponseStatus_CTL_ERR_APP     ResponseStatus = -4 // Other application error
-	ResponseStatus_CTL_ERR_UNKNOWN ResponseStatus = -5 // Unknown error
-	ResponseStatus_CTL_NO_IMPL     ResponseStatus = -6 // No implementation
+	ResponseStatus_CTL_SUCCESS       ResponseStatus = 0
+	ResponseStatus_CTL_IN_PROGRESS   ResponseStatus = 1  // Not yet completed
+	ResponseStatus_CTL_WAITING       ResponseStatus = 2  // Blocked
+	ResponseStatus_CTL_ERR_CONF      ResponseStatus = -1 // Config file parsing error
+	ResponseStatus_CTL_ERR_NVME      ResponseStatus = -2 // NVMe subsystem error
+	ResponseStatus_CTL_ERR_SCM       ResponseStatus = -3 // SCM subsystem error
+	ResponseStatus_CTL_ERR_APP       ResponseStatus = -4 // Other application error
+	ResponseStatus_CTL_ERR_UNKNOWN   ResponseStatus = -5 // Unknown error
+	ResponseStatus_CTL_NO_IMPL       ResponseStatus = -6 // No implementation
+	ResponseStatus_CTL_ERR_BUSY      ResponseStatus = -7 // Device or resource busy
+	ResponseStatus_CTL_ERR_NOT_FOUND ResponseStatus = -8 // Device not found
 )
 
 // Enum value maps for ResponseStatus.
@@ -51,17 +53,21 @@ var (
 		-4: "CTL_ERR_APP",
 		-5: "CTL_ERR_UNKNOWN",
 		-6: "CTL_NO_IMPL",
+		-7: "CTL_ERR_BUSY",
+		-8: "CTL_ERR_NOT_FOUND",
 	}
 	ResponseStatus_value = map[string]int32{
-		"CTL_SUCCESS":     0,
-		"CTL_IN_PROGRESS": 1,
-		"CTL_WAITING":     2,
-		"CTL_ERR_CONF":    -1,
-		"CTL_ERR_NVME":    -2,
-		"CTL_ERR_SCM":     -3,
-		"CTL_ERR_APP":     -4,
-		"CTL_ERR_UNKNOWN": -5,
-		"CTL_NO_IMPL":     -6,
+		"CTL_SUCCESS":       0,
+		"CTL_IN_PROGRESS":   1,
+		"CTL_WAITING":       2,
+		"CTL_ERR_CONF":      -1,
+		"CTL_ERR_NVME":      -2,
+		"CTL_ERR_SCM":       -3,
+		"CTL_ERR_APP":       -4,
+		"CTL_ERR_UNKNOWN":   -5,
+		"CTL_NO_IMPL":       -6,
+		"CTL_ERR_BUSY":      -7,
+		"CTL_ERR_NOT_FOUND": -8,
 	}
 )
 
@@ -253,7 +259,7 @@ var file_ctl_common_proto_rawDesc = []byte{
 	0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
 	0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x2a, 0xe9, 0x01, 0x0a, 0x0e,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x2a, 0xa4, 0x02, 0x0a, 0x0e,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f,
 	0x0a, 0x0b, 0x43, 0x54, 0x4c, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12,
 	0x13, 0x0a, 0x0f, 0x43, 0x54, 0x4c, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45,
@@ -268,11 +274,15 @@ var file_ctl_common_proto_rawDesc = []byte{
 	0x1c, 0x0a, 0x0f, 0x43, 0x54, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
 	0x57, 0x4e, 0x10, 0xfb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x12, 0x18, 0x0a,
 	0x0b, 0x43, 0x54, 0x4c, 0x5f, 0x4e, 0x4f, 0x5f, 0x49, 0x4d, 0x50, 0x4c, 0x10, 0xfa, 0xff, 0xff,
-	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b,
-	0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63,
-	0x74, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x12, 0x19, 0x0a, 0x0c, 0x43, 0x54, 0x4c, 0x5f, 0x45,
+	0x52, 0x52, 0x5f, 0x42, 0x55, 0x53, 0x59, 0x10, 0xf9, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0x01, 0x12, 0x1e, 0x0a, 0x11, 0x43, 0x54, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x5f, 0x4e, 0x4f,
+	0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0xf8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0x01, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x64, 0x61, 0x6f, 0x73,
+	0x2f, 0x73, 0x72, 0x63, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x74, 0x6c, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
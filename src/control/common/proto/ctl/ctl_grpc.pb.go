@@ -42,6 +42,14 @@ type CtlSvcClient interface {
 	ResetFormatRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*RanksResp, error)
 	// Start DAOS I/O Engines on a host. (gRPC fanout)
 	StartRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*RanksResp, error)
+	// Probe readiness of DAOS I/O Engines on a host. (gRPC fanout)
+	ProbeRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*ProbeRanksResp, error)
+	// Restart DAOS I/O Engines on a host. (gRPC fanout)
+	RestartRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*RanksResp, error)
+	// Start a device self-test on an NVMe controller
+	NvmeSelfTest(ctx context.Context, in *NvmeSelfTestReq, opts ...grpc.CallOption) (*NvmeSelfTestResp, error)
+	// Query the device self-test log of an NVMe controller
+	NvmeSelfTestQuery(ctx context.Context, in *NvmeSelfTestQueryReq, opts ...grpc.CallOption) (*NvmeSelfTestQueryResp, error)
 }
 
 type ctlSvcClient struct {
@@ -160,6 +168,42 @@ func (c *ctlSvcClient) StartRanks(ctx context.Context, in *RanksReq, opts ...grp
 	return out, nil
 }
 
+func (c *ctlSvcClient) ProbeRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*ProbeRanksResp, error) {
+	out := new(ProbeRanksResp)
+	err := c.cc.Invoke(ctx, "/ctl.CtlSvc/ProbeRanks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ctlSvcClient) RestartRanks(ctx context.Context, in *RanksReq, opts ...grpc.CallOption) (*RanksResp, error) {
+	out := new(RanksResp)
+	err := c.cc.Invoke(ctx, "/ctl.CtlSvc/RestartRanks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ctlSvcClient) NvmeSelfTest(ctx context.Context, in *NvmeSelfTestReq, opts ...grpc.CallOption) (*NvmeSelfTestResp, error) {
+	out := new(NvmeSelfTestResp)
+	err := c.cc.Invoke(ctx, "/ctl.CtlSvc/NvmeSelfTest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ctlSvcClient) NvmeSelfTestQuery(ctx context.Context, in *NvmeSelfTestQueryReq, opts ...grpc.CallOption) (*NvmeSelfTestQueryResp, error) {
+	out := new(NvmeSelfTestQueryResp)
+	err := c.cc.Invoke(ctx, "/ctl.CtlSvc/NvmeSelfTestQuery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CtlSvcServer is the server API for CtlSvc service.
 // All implementations must embed UnimplementedCtlSvcServer
 // for forward compatibility
@@ -188,6 +232,14 @@ type CtlSvcServer interface {
 	ResetFormatRanks(context.Context, *RanksReq) (*RanksResp, error)
 	// Start DAOS I/O Engines on a host. (gRPC fanout)
 	StartRanks(context.Context, *RanksReq) (*RanksResp, error)
+	// Probe readiness of DAOS I/O Engines on a host. (gRPC fanout)
+	ProbeRanks(context.Context, *RanksReq) (*ProbeRanksResp, error)
+	// Restart DAOS I/O Engines on a host. (gRPC fanout)
+	RestartRanks(context.Context, *RanksReq) (*RanksResp, error)
+	// Start a device self-test on an NVMe controller
+	NvmeSelfTest(context.Context, *NvmeSelfTestReq) (*NvmeSelfTestResp, error)
+	// Query the device self-test log of an NVMe controller
+	NvmeSelfTestQuery(context.Context, *NvmeSelfTestQueryReq) (*NvmeSelfTestQueryResp, error)
 	mustEmbedUnimplementedCtlSvcServer()
 }
 
@@ -231,6 +283,18 @@ func (UnimplementedCtlSvcServer) ResetFormatRanks(context.Context, *RanksReq) (*
 func (UnimplementedCtlSvcServer) StartRanks(context.Context, *RanksReq) (*RanksResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartRanks not implemented")
 }
+func (UnimplementedCtlSvcServer) ProbeRanks(context.Context, *RanksReq) (*ProbeRanksResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProbeRanks not implemented")
+}
+func (UnimplementedCtlSvcServer) RestartRanks(context.Context, *RanksReq) (*RanksResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartRanks not implemented")
+}
+func (UnimplementedCtlSvcServer) NvmeSelfTest(context.Context, *NvmeSelfTestReq) (*NvmeSelfTestResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NvmeSelfTest not implemented")
+}
+func (UnimplementedCtlSvcServer) NvmeSelfTestQuery(context.Context, *NvmeSelfTestQueryReq) (*NvmeSelfTestQueryResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NvmeSelfTestQuery not implemented")
+}
 func (UnimplementedCtlSvcServer) mustEmbedUnimplementedCtlSvcServer() {}
 
 // UnsafeCtlSvcServer may be embedded to opt out of forward compatibility for this service.
@@ -460,6 +524,78 @@ func _CtlSvc_StartRanks_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CtlSvc_ProbeRanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RanksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CtlSvcServer).ProbeRanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.CtlSvc/ProbeRanks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CtlSvcServer).ProbeRanks(ctx, req.(*RanksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CtlSvc_RestartRanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RanksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CtlSvcServer).RestartRanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.CtlSvc/RestartRanks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CtlSvcServer).RestartRanks(ctx, req.(*RanksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CtlSvc_NvmeSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NvmeSelfTestReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CtlSvcServer).NvmeSelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.CtlSvc/NvmeSelfTest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CtlSvcServer).NvmeSelfTest(ctx, req.(*NvmeSelfTestReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CtlSvc_NvmeSelfTestQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NvmeSelfTestQueryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CtlSvcServer).NvmeSelfTestQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.CtlSvc/NvmeSelfTestQuery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CtlSvcServer).NvmeSelfTestQuery(ctx, req.(*NvmeSelfTestQueryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CtlSvc_ServiceDesc is the grpc.ServiceDesc for CtlSvc service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -515,6 +651,22 @@ var CtlSvc_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StartRanks",
 			Handler:    _CtlSvc_StartRanks_Handler,
 		},
+		{
+			MethodName: "ProbeRanks",
+			Handler:    _CtlSvc_ProbeRanks_Handler,
+		},
+		{
+			MethodName: "RestartRanks",
+			Handler:    _CtlSvc_RestartRanks_Handler,
+		},
+		{
+			MethodName: "NvmeSelfTest",
+			Handler:    _CtlSvc_NvmeSelfTest_Handler,
+		},
+		{
+			MethodName: "NvmeSelfTestQuery",
+			Handler:    _CtlSvc_NvmeSelfTestQuery_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ctl/ctl.proto",
@@ -34,8 +34,13 @@ type RanksReq struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Force bool   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"` // force operation
-	Ranks string `protobuf:"bytes,4,opt,name=ranks,proto3" json:"ranks,omitempty"`  // rankset to operate over
+	Force            bool   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`                                                 // force operation
+	Ranks            string `protobuf:"bytes,4,opt,name=ranks,proto3" json:"ranks,omitempty"`                                                  // rankset to operate over
+	Escalate         bool   `protobuf:"varint,5,opt,name=escalate,proto3" json:"escalate,omitempty"`                                           // escalate to forced operation after grace period
+	GracePeriodMs    uint32 `protobuf:"varint,6,opt,name=grace_period_ms,json=gracePeriodMs,proto3" json:"grace_period_ms,omitempty"`          // grace period before escalation or marking rank errored
+	WaitPoolServices bool   `protobuf:"varint,7,opt,name=wait_pool_services,json=waitPoolServices,proto3" json:"wait_pool_services,omitempty"` // wait for hosted pool services to start
+	RetryTransient   bool   `protobuf:"varint,8,opt,name=retry_transient,json=retryTransient,proto3" json:"retry_transient,omitempty"`         // retry dRPC calls that fail with a transient status
+	ReportSkipped    bool   `protobuf:"varint,9,opt,name=report_skipped,json=reportSkipped,proto3" json:"report_skipped,omitempty"`            // report requested ranks not hosted locally as skipped
 }
 
 func (x *RanksReq) Reset() {
@@ -84,6 +89,41 @@ func (x *RanksReq) GetRanks() string {
 	return ""
 }
 
+func (x *RanksReq) GetEscalate() bool {
+	if x != nil {
+		return x.Escalate
+	}
+	return false
+}
+
+func (x *RanksReq) GetGracePeriodMs() uint32 {
+	if x != nil {
+		return x.GracePeriodMs
+	}
+	return 0
+}
+
+func (x *RanksReq) GetWaitPoolServices() bool {
+	if x != nil {
+		return x.WaitPoolServices
+	}
+	return false
+}
+
+func (x *RanksReq) GetRetryTransient() bool {
+	if x != nil {
+		return x.RetryTransient
+	}
+	return false
+}
+
+func (x *RanksReq) GetReportSkipped() bool {
+	if x != nil {
+		return x.ReportSkipped
+	}
+	return false
+}
+
 // Generic response containing DER result from multiple ranks.
 // Used in gRPC fanout to operate on hosts with multiple ranks.
 type RanksResp struct {
@@ -91,7 +131,9 @@ type RanksResp struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Results []*shared.RankResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Results        []*shared.RankResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NoLocalRanks   bool                 `protobuf:"varint,2,opt,name=no_local_ranks,json=noLocalRanks,proto3" json:"no_local_ranks,omitempty"`    // host has none of the requested ranks
+	EscalatedRanks string               `protobuf:"bytes,3,opt,name=escalated_ranks,json=escalatedRanks,proto3" json:"escalated_ranks,omitempty"` // ranks that required forced operation
 }
 
 func (x *RanksResp) Reset() {
@@ -133,23 +175,216 @@ func (x *RanksResp) GetResults() []*shared.RankResult {
 	return nil
 }
 
+func (x *RanksResp) GetNoLocalRanks() bool {
+	if x != nil {
+		return x.NoLocalRanks
+	}
+	return false
+}
+
+func (x *RanksResp) GetEscalatedRanks() string {
+	if x != nil {
+		return x.EscalatedRanks
+	}
+	return ""
+}
+
+// Readiness of a single rank, distinct from liveness.
+type RankReadiness struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rank      uint32 `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Alive     bool   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`         // engine process is running
+	Formatted bool   `protobuf:"varint,3,opt,name=formatted,proto3" json:"formatted,omitempty"` // engine storage is formatted
+	Ready     bool   `protobuf:"varint,4,opt,name=ready,proto3" json:"ready,omitempty"`         // engine has completed startup and responds over dRPC
+	State     string `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`          // local member state
+	Msg       string `protobuf:"bytes,6,opt,name=msg,proto3" json:"msg,omitempty"`              // reason rank is not ready
+}
+
+func (x *RankReadiness) Reset() {
+	*x = RankReadiness{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_ranks_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RankReadiness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RankReadiness) ProtoMessage() {}
+
+func (x *RankReadiness) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_ranks_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RankReadiness.ProtoReflect.Descriptor instead.
+func (*RankReadiness) Descriptor() ([]byte, []int) {
+	return file_ctl_ranks_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RankReadiness) GetRank() uint32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *RankReadiness) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *RankReadiness) GetFormatted() bool {
+	if x != nil {
+		return x.Formatted
+	}
+	return false
+}
+
+func (x *RankReadiness) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *RankReadiness) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *RankReadiness) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// Response containing readiness of multiple ranks.
+// Used in gRPC fanout to probe hosts with multiple ranks.
+type ProbeRanksResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results      []*RankReadiness `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NoLocalRanks bool             `protobuf:"varint,2,opt,name=no_local_ranks,json=noLocalRanks,proto3" json:"no_local_ranks,omitempty"` // host has none of the requested ranks
+}
+
+func (x *ProbeRanksResp) Reset() {
+	*x = ProbeRanksResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_ranks_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeRanksResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeRanksResp) ProtoMessage() {}
+
+func (x *ProbeRanksResp) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_ranks_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeRanksResp.ProtoReflect.Descriptor instead.
+func (*ProbeRanksResp) Descriptor() ([]byte, []int) {
+	return file_ctl_ranks_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProbeRanksResp) GetResults() []*RankReadiness {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ProbeRanksResp) GetNoLocalRanks() bool {
+	if x != nil {
+		return x.NoLocalRanks
+	}
+	return false
+}
+
 var File_ctl_ranks_proto protoreflect.FileDescriptor
 
 var file_ctl_ranks_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x63, 0x74, 0x6c, 0x2f, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x03, 0x63, 0x74, 0x6c, 0x1a, 0x12, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x72,
-	0x61, 0x6e, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x36, 0x0a, 0x08, 0x52, 0x61,
-	0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05,
-	0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x61, 0x6e,
-	0x6b, 0x73, 0x22, 0x39, 0x0a, 0x09, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12,
-	0x2c, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x52, 0x61, 0x6e, 0x6b, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x42, 0x39, 0x5a,
-	0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6f, 0x73,
-	0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2f, 0x73, 0x72, 0x63, 0x2f,
-	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x74, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x6e, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf8, 0x01, 0x0a, 0x08, 0x52,
+	0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x61,
+	0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x65, 0x12,
+	0x26, 0x0a, 0x0f, 0x67, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f,
+	0x6d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x67, 0x72, 0x61, 0x63, 0x65, 0x50,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x4d, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x77, 0x61, 0x69, 0x74, 0x5f,
+	0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x10, 0x77, 0x61, 0x69, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x25,
+	0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x6b,
+	0x69, 0x70, 0x70, 0x65, 0x64, 0x22, 0x88, 0x01, 0x0a, 0x09, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x12, 0x2c, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x52, 0x61,
+	0x6e, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x6f, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x61,
+	0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6e, 0x6f, 0x4c, 0x6f, 0x63,
+	0x61, 0x6c, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x73, 0x63, 0x61, 0x6c,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x65, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x73,
+	0x22, 0x95, 0x01, 0x0a, 0x0d, 0x52, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x65,
+	0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65,
+	0x61, 0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x64, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2c, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x74,
+	0x6c, 0x2e, 0x52, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x6f, 0x5f, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x6e, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x42, 0x39,
+	0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6f,
+	0x73, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2f, 0x73, 0x72, 0x63,
+	0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x74, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -164,19 +399,22 @@ func file_ctl_ranks_proto_rawDescGZIP() []byte {
 	return file_ctl_ranks_proto_rawDescData
 }
 
-var file_ctl_ranks_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_ctl_ranks_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_ctl_ranks_proto_goTypes = []interface{}{
 	(*RanksReq)(nil),          // 0: ctl.RanksReq
 	(*RanksResp)(nil),         // 1: ctl.RanksResp
-	(*shared.RankResult)(nil), // 2: shared.RankResult
+	(*RankReadiness)(nil),     // 2: ctl.RankReadiness
+	(*ProbeRanksResp)(nil),    // 3: ctl.ProbeRanksResp
+	(*shared.RankResult)(nil), // 4: shared.RankResult
 }
 var file_ctl_ranks_proto_depIdxs = []int32{
-	2, // 0: ctl.RanksResp.results:type_name -> shared.RankResult
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: ctl.RanksResp.results:type_name -> shared.RankResult
+	2, // 1: ctl.ProbeRanksResp.results:type_name -> ctl.RankReadiness
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_ctl_ranks_proto_init() }
@@ -209,6 +447,30 @@ func file_ctl_ranks_proto_init() {
 				return nil
 			}
 		}
+		file_ctl_ranks_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RankReadiness); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ctl_ranks_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeRanksResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -216,7 +478,7 @@ func file_ctl_ranks_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ctl_ranks_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -263,7 +263,8 @@ type PrepareNvmeResp struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	State *ResponseState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	State  *ResponseState          `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Resets []*NvmeControllerResult `protobuf:"bytes,2,rep,name=resets,proto3" json:"resets,omitempty"`
 }
 
 func (x *PrepareNvmeResp) Reset() {
@@ -305,6 +306,13 @@ func (x *PrepareNvmeResp) GetState() *ResponseState {
 	return nil
 }
 
+func (x *PrepareNvmeResp) GetResets() []*NvmeControllerResult {
+	if x != nil {
+		return x.Resets
+	}
+	return nil
+}
+
 type ScanNvmeReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -313,6 +321,7 @@ type ScanNvmeReq struct {
 	Health bool `protobuf:"varint,1,opt,name=Health,proto3" json:"Health,omitempty"` // Retrieve NVMe device health statistics
 	Meta   bool `protobuf:"varint,2,opt,name=Meta,proto3" json:"Meta,omitempty"`     // Retrieve metadata relating to NVMe device
 	Basic  bool `protobuf:"varint,3,opt,name=Basic,proto3" json:"Basic,omitempty"`   // Strip NVMe device details to only basic
+	Force  bool `protobuf:"varint,4,opt,name=Force,proto3" json:"Force,omitempty"`   // Bypass health statistics polling throttle
 }
 
 func (x *ScanNvmeReq) Reset() {
@@ -368,6 +377,13 @@ func (x *ScanNvmeReq) GetBasic() bool {
 	return false
 }
 
+func (x *ScanNvmeReq) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
 type ScanNvmeResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -427,6 +443,9 @@ type FormatNvmeReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	PciAddrs []string `protobuf:"bytes,1,rep,name=pci_addrs,json=pciAddrs,proto3" json:"pci_addrs,omitempty"` // Format only controllers with these addresses, all if empty
+	Force    bool     `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`                      // Format controllers that are already formatted
 }
 
 func (x *FormatNvmeReq) Reset() {
@@ -461,6 +480,272 @@ func (*FormatNvmeReq) Descriptor() ([]byte, []int) {
 	return file_ctl_storage_nvme_proto_rawDescGZIP(), []int{6}
 }
 
+func (x *FormatNvmeReq) GetPciAddrs() []string {
+	if x != nil {
+		return x.PciAddrs
+	}
+	return nil
+}
+
+func (x *FormatNvmeReq) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type NvmeSelfTestReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PciAddr  string `protobuf:"bytes,1,opt,name=pci_addr,json=pciAddr,proto3" json:"pci_addr,omitempty"` // PCI address of NVMe controller
+	Extended bool   `protobuf:"varint,2,opt,name=extended,proto3" json:"extended,omitempty"`             // Run extended rather than short self-test
+}
+
+func (x *NvmeSelfTestReq) Reset() {
+	*x = NvmeSelfTestReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_storage_nvme_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NvmeSelfTestReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NvmeSelfTestReq) ProtoMessage() {}
+
+func (x *NvmeSelfTestReq) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_storage_nvme_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NvmeSelfTestReq.ProtoReflect.Descriptor instead.
+func (*NvmeSelfTestReq) Descriptor() ([]byte, []int) {
+	return file_ctl_storage_nvme_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NvmeSelfTestReq) GetPciAddr() string {
+	if x != nil {
+		return x.PciAddr
+	}
+	return ""
+}
+
+func (x *NvmeSelfTestReq) GetExtended() bool {
+	if x != nil {
+		return x.Extended
+	}
+	return false
+}
+
+type NvmeSelfTestResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // Type of self-test started
+}
+
+func (x *NvmeSelfTestResp) Reset() {
+	*x = NvmeSelfTestResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_storage_nvme_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NvmeSelfTestResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NvmeSelfTestResp) ProtoMessage() {}
+
+func (x *NvmeSelfTestResp) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_storage_nvme_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NvmeSelfTestResp.ProtoReflect.Descriptor instead.
+func (*NvmeSelfTestResp) Descriptor() ([]byte, []int) {
+	return file_ctl_storage_nvme_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NvmeSelfTestResp) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type NvmeSelfTestQueryReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PciAddr string `protobuf:"bytes,1,opt,name=pci_addr,json=pciAddr,proto3" json:"pci_addr,omitempty"` // PCI address of NVMe controller
+}
+
+func (x *NvmeSelfTestQueryReq) Reset() {
+	*x = NvmeSelfTestQueryReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_storage_nvme_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NvmeSelfTestQueryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NvmeSelfTestQueryReq) ProtoMessage() {}
+
+func (x *NvmeSelfTestQueryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_storage_nvme_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NvmeSelfTestQueryReq.ProtoReflect.Descriptor instead.
+func (*NvmeSelfTestQueryReq) Descriptor() ([]byte, []int) {
+	return file_ctl_storage_nvme_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NvmeSelfTestQueryReq) GetPciAddr() string {
+	if x != nil {
+		return x.PciAddr
+	}
+	return ""
+}
+
+type NvmeSelfTestQueryResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PciAddr    string `protobuf:"bytes,1,opt,name=pci_addr,json=pciAddr,proto3" json:"pci_addr,omitempty"`           // PCI address of NVMe controller
+	InProgress bool   `protobuf:"varint,2,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"` // Self-test is running on controller
+	Current    string `protobuf:"bytes,3,opt,name=current,proto3" json:"current,omitempty"`                          // Type of self-test in progress
+	Progress   uint32 `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`                       // Percentage complete of self-test in progress
+	HasResult  bool   `protobuf:"varint,5,opt,name=has_result,json=hasResult,proto3" json:"has_result,omitempty"`    // Controller has completed a self-test
+	Passed     bool   `protobuf:"varint,6,opt,name=passed,proto3" json:"passed,omitempty"`                           // Most recent completed self-test passed
+	LastCode   string `protobuf:"bytes,7,opt,name=last_code,json=lastCode,proto3" json:"last_code,omitempty"`        // Type of most recent completed self-test
+	LastResult string `protobuf:"bytes,8,opt,name=last_result,json=lastResult,proto3" json:"last_result,omitempty"`  // Result of most recent completed self-test
+}
+
+func (x *NvmeSelfTestQueryResp) Reset() {
+	*x = NvmeSelfTestQueryResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ctl_storage_nvme_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NvmeSelfTestQueryResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NvmeSelfTestQueryResp) ProtoMessage() {}
+
+func (x *NvmeSelfTestQueryResp) ProtoReflect() protoreflect.Message {
+	mi := &file_ctl_storage_nvme_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NvmeSelfTestQueryResp.ProtoReflect.Descriptor instead.
+func (*NvmeSelfTestQueryResp) Descriptor() ([]byte, []int) {
+	return file_ctl_storage_nvme_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NvmeSelfTestQueryResp) GetPciAddr() string {
+	if x != nil {
+		return x.PciAddr
+	}
+	return ""
+}
+
+func (x *NvmeSelfTestQueryResp) GetInProgress() bool {
+	if x != nil {
+		return x.InProgress
+	}
+	return false
+}
+
+func (x *NvmeSelfTestQueryResp) GetCurrent() string {
+	if x != nil {
+		return x.Current
+	}
+	return ""
+}
+
+func (x *NvmeSelfTestQueryResp) GetProgress() uint32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *NvmeSelfTestQueryResp) GetHasResult() bool {
+	if x != nil {
+		return x.HasResult
+	}
+	return false
+}
+
+func (x *NvmeSelfTestQueryResp) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *NvmeSelfTestQueryResp) GetLastCode() string {
+	if x != nil {
+		return x.LastCode
+	}
+	return ""
+}
+
+func (x *NvmeSelfTestQueryResp) GetLastResult() string {
+	if x != nil {
+		return x.LastResult
+	}
+	return ""
+}
+
 // Health mirrors bio_dev_state structure.
 type NvmeController_Health struct {
 	state         protoimpl.MessageState
@@ -494,7 +779,7 @@ type NvmeController_Health struct {
 func (x *NvmeController_Health) Reset() {
 	*x = NvmeController_Health{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ctl_storage_nvme_proto_msgTypes[7]
+		mi := &file_ctl_storage_nvme_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -507,7 +792,7 @@ func (x *NvmeController_Health) String() string {
 func (*NvmeController_Health) ProtoMessage() {}
 
 func (x *NvmeController_Health) ProtoReflect() protoreflect.Message {
-	mi := &file_ctl_storage_nvme_proto_msgTypes[7]
+	mi := &file_ctl_storage_nvme_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +955,7 @@ type NvmeController_Namespace struct {
 func (x *NvmeController_Namespace) Reset() {
 	*x = NvmeController_Namespace{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ctl_storage_nvme_proto_msgTypes[8]
+		mi := &file_ctl_storage_nvme_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -683,7 +968,7 @@ func (x *NvmeController_Namespace) String() string {
 func (*NvmeController_Namespace) ProtoMessage() {}
 
 func (x *NvmeController_Namespace) ProtoReflect() protoreflect.Message {
-	mi := &file_ctl_storage_nvme_proto_msgTypes[8]
+	mi := &file_ctl_storage_nvme_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,7 +1024,7 @@ type NvmeController_SmdDevice struct {
 func (x *NvmeController_SmdDevice) Reset() {
 	*x = NvmeController_SmdDevice{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ctl_storage_nvme_proto_msgTypes[9]
+		mi := &file_ctl_storage_nvme_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -752,7 +1037,7 @@ func (x *NvmeController_SmdDevice) String() string {
 func (*NvmeController_SmdDevice) ProtoMessage() {}
 
 func (x *NvmeController_SmdDevice) ProtoReflect() protoreflect.Message {
-	mi := &file_ctl_storage_nvme_proto_msgTypes[9]
+	mi := &file_ctl_storage_nvme_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -921,28 +1206,62 @@ var file_ctl_storage_nvme_proto_rawDesc = []byte{
 	0x65, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
 	0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x61, 0x72,
 	0x67, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x22, 0x3b, 0x0a,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x22, 0x6e, 0x0a,
 	0x0f, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x4e, 0x76, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
 	0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x12, 0x2e, 0x63, 0x74, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x4f, 0x0a, 0x0b, 0x53, 0x63,
-	0x61, 0x6e, 0x4e, 0x76, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x48, 0x65, 0x61,
-	0x6c, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74,
-	0x68, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x04, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x61, 0x73, 0x69, 0x63, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x42, 0x61, 0x73, 0x69, 0x63, 0x22, 0x65, 0x0a, 0x0c, 0x53,
-	0x63, 0x61, 0x6e, 0x4e, 0x76, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2b, 0x0a, 0x06, 0x63,
-	0x74, 0x72, 0x6c, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x74,
-	0x6c, 0x2e, 0x4e, 0x76, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72,
-	0x52, 0x06, 0x63, 0x74, 0x72, 0x6c, 0x72, 0x73, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x74, 0x6c, 0x2e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
-	0x74, 0x65, 0x22, 0x0f, 0x0a, 0x0d, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x4e, 0x76, 0x6d, 0x65,
-	0x52, 0x65, 0x71, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x64, 0x61, 0x6f,
-	0x73, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f, 0x63, 0x6f,
-	0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x74, 0x6c, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x74, 0x6c,
+	0x2e, 0x4e, 0x76, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x65, 0x74, 0x73, 0x22, 0x65, 0x0a,
+	0x0b, 0x53, 0x63, 0x61, 0x6e, 0x4e, 0x76, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06,
+	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x48, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x61, 0x73, 0x69,
+	0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x42, 0x61, 0x73, 0x69, 0x63, 0x12, 0x14,
+	0x0a, 0x05, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x22, 0x65, 0x0a, 0x0c, 0x53, 0x63, 0x61, 0x6e, 0x4e, 0x76, 0x6d, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x2b, 0x0a, 0x06, 0x63, 0x74, 0x72, 0x6c, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x74, 0x6c, 0x2e, 0x4e, 0x76, 0x6d, 0x65, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x06, 0x63, 0x74, 0x72, 0x6c, 0x72,
+	0x73, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x63, 0x74, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x42, 0x0a, 0x0d, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x4e, 0x76, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x63, 0x69, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x63, 0x69, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x22,
+	0x48, 0x0a, 0x0f, 0x4e, 0x76, 0x6d, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x63, 0x69, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x63, 0x69, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x22, 0x26, 0x0a, 0x10, 0x4e, 0x76, 0x6d,
+	0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x22, 0x31, 0x0a, 0x14, 0x4e, 0x76, 0x6d, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73,
+	0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x63, 0x69,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x63, 0x69,
+	0x41, 0x64, 0x64, 0x72, 0x22, 0xfe, 0x01, 0x0a, 0x15, 0x4e, 0x76, 0x6d, 0x65, 0x53, 0x65, 0x6c,
+	0x66, 0x54, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x12, 0x19,
+	0x0a, 0x08, 0x70, 0x63, 0x69, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x70, 0x63, 0x69, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x5f,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x69, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6f, 0x73, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x64,
+	0x61, 0x6f, 0x73, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x74, 0x6c,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -957,7 +1276,7 @@ func file_ctl_storage_nvme_proto_rawDescGZIP() []byte {
 	return file_ctl_storage_nvme_proto_rawDescData
 }
 
-var file_ctl_storage_nvme_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_ctl_storage_nvme_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_ctl_storage_nvme_proto_goTypes = []interface{}{
 	(*NvmeController)(nil),           // 0: ctl.NvmeController
 	(*NvmeControllerResult)(nil),     // 1: ctl.NvmeControllerResult
@@ -966,24 +1285,29 @@ var file_ctl_storage_nvme_proto_goTypes = []interface{}{
 	(*ScanNvmeReq)(nil),              // 4: ctl.ScanNvmeReq
 	(*ScanNvmeResp)(nil),             // 5: ctl.ScanNvmeResp
 	(*FormatNvmeReq)(nil),            // 6: ctl.FormatNvmeReq
-	(*NvmeController_Health)(nil),    // 7: ctl.NvmeController.Health
-	(*NvmeController_Namespace)(nil), // 8: ctl.NvmeController.Namespace
-	(*NvmeController_SmdDevice)(nil), // 9: ctl.NvmeController.SmdDevice
-	(*ResponseState)(nil),            // 10: ctl.ResponseState
+	(*NvmeSelfTestReq)(nil),          // 7: ctl.NvmeSelfTestReq
+	(*NvmeSelfTestResp)(nil),         // 8: ctl.NvmeSelfTestResp
+	(*NvmeSelfTestQueryReq)(nil),     // 9: ctl.NvmeSelfTestQueryReq
+	(*NvmeSelfTestQueryResp)(nil),    // 10: ctl.NvmeSelfTestQueryResp
+	(*NvmeController_Health)(nil),    // 11: ctl.NvmeController.Health
+	(*NvmeController_Namespace)(nil), // 12: ctl.NvmeController.Namespace
+	(*NvmeController_SmdDevice)(nil), // 13: ctl.NvmeController.SmdDevice
+	(*ResponseState)(nil),            // 14: ctl.ResponseState
 }
 var file_ctl_storage_nvme_proto_depIdxs = []int32{
-	7,  // 0: ctl.NvmeController.health_stats:type_name -> ctl.NvmeController.Health
-	8,  // 1: ctl.NvmeController.namespaces:type_name -> ctl.NvmeController.Namespace
-	9,  // 2: ctl.NvmeController.smd_devices:type_name -> ctl.NvmeController.SmdDevice
-	10, // 3: ctl.NvmeControllerResult.state:type_name -> ctl.ResponseState
-	10, // 4: ctl.PrepareNvmeResp.state:type_name -> ctl.ResponseState
-	0,  // 5: ctl.ScanNvmeResp.ctrlrs:type_name -> ctl.NvmeController
-	10, // 6: ctl.ScanNvmeResp.state:type_name -> ctl.ResponseState
-	7,  // [7:7] is the sub-list for method output_type
-	7,  // [7:7] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	11, // 0: ctl.NvmeController.health_stats:type_name -> ctl.NvmeController.Health
+	12, // 1: ctl.NvmeController.namespaces:type_name -> ctl.NvmeController.Namespace
+	13, // 2: ctl.NvmeController.smd_devices:type_name -> ctl.NvmeController.SmdDevice
+	14, // 3: ctl.NvmeControllerResult.state:type_name -> ctl.ResponseState
+	14, // 4: ctl.PrepareNvmeResp.state:type_name -> ctl.ResponseState
+	1,  // 5: ctl.PrepareNvmeResp.resets:type_name -> ctl.NvmeControllerResult
+	0,  // 6: ctl.ScanNvmeResp.ctrlrs:type_name -> ctl.NvmeController
+	14, // 7: ctl.ScanNvmeResp.state:type_name -> ctl.ResponseState
+	8,  // [8:8] is the sub-list for method output_type
+	8,  // [8:8] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_ctl_storage_nvme_proto_init() }
@@ -1078,7 +1402,7 @@ func file_ctl_storage_nvme_proto_init() {
 			}
 		}
 		file_ctl_storage_nvme_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NvmeController_Health); i {
+			switch v := v.(*NvmeSelfTestReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1090,7 +1414,7 @@ func file_ctl_storage_nvme_proto_init() {
 			}
 		}
 		file_ctl_storage_nvme_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NvmeController_Namespace); i {
+			switch v := v.(*NvmeSelfTestResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1102,6 +1426,54 @@ func file_ctl_storage_nvme_proto_init() {
 			}
 		}
 		file_ctl_storage_nvme_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NvmeSelfTestQueryReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ctl_storage_nvme_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NvmeSelfTestQueryResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ctl_storage_nvme_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NvmeController_Health); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ctl_storage_nvme_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NvmeController_Namespace); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ctl_storage_nvme_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NvmeController_SmdDevice); i {
 			case 0:
 				return &v.state
@@ -1120,7 +1492,7 @@ func file_ctl_storage_nvme_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ctl_storage_nvme_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,204 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+// ErrFirmwareRevMismatch indicates that two firmware revisions belong to
+// different product lines and cannot be compared.
+var ErrFirmwareRevMismatch = errors.New("firmware revisions are not comparable")
+
+// FirmwareRev is an NVMe firmware revision split into a product line prefix,
+// numeric version components and any trailing suffix.
+type FirmwareRev struct {
+	Prefix  string
+	Version []int
+	Suffix  string
+}
+
+// ParseFirmwareRev splits a firmware revision string into its components. The
+// prefix is any leading non-numeric text, followed by one or more numeric
+// components separated by dots, e.g. "VDV10131", "GDC5302Q" or "v1.2.3".
+func ParseFirmwareRev(rev string) (*FirmwareRev, error) {
+	rev = strings.TrimSpace(rev)
+	start := strings.IndexFunc(rev, unicode.IsDigit)
+	if start < 0 {
+		return nil, errors.Errorf("firmware revision %q has no version number", rev)
+	}
+
+	fr := &FirmwareRev{Prefix: rev[:start]}
+	rest := rev[start:]
+	for {
+		end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, errors.Errorf("firmware revision %q has an empty version component", rev)
+		}
+		v, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing firmware revision %q", rev)
+		}
+		fr.Version = append(fr.Version, v)
+
+		rest = rest[end:]
+		if !strings.HasPrefix(rest, ".") {
+			break
+		}
+		rest = rest[1:]
+	}
+	fr.Suffix = rest
+
+	return fr, nil
+}
+
+// parseProductCodeRev parses a firmware revision that starts with a three
+// character product code, which may itself contain digits (e.g. "8DV10171").
+func parseProductCodeRev(rev string) (*FirmwareRev, error) {
+	rev = strings.TrimSpace(rev)
+	if len(rev) < 4 {
+		return nil, errors.Errorf("firmware revision %q is too short", rev)
+	}
+
+	fr, err := ParseFirmwareRev(rev[3:])
+	if err != nil {
+		return nil, err
+	}
+	if fr.Prefix != "" {
+		return nil, errors.Errorf("firmware revision %q has no version number after product code", rev)
+	}
+	fr.Prefix = rev[:3]
+
+	return fr, nil
+}
+
+// Compare returns a negative value if fr is older than other, zero if they are
+// equal and a positive value if fr is newer. Version components are compared
+// numerically followed by the suffix. ErrFirmwareRevMismatch is returned if
+// the prefixes differ.
+func (fr *FirmwareRev) Compare(other *FirmwareRev) (int, error) {
+	if fr == nil || other == nil {
+		return 0, errors.New("nil firmware revision")
+	}
+	if !strings.EqualFold(fr.Prefix, other.Prefix) {
+		return 0, ErrFirmwareRevMismatch
+	}
+
+	for i := 0; i < len(fr.Version) || i < len(other.Version); i++ {
+		var a, b int
+		if i < len(fr.Version) {
+			a = fr.Version[i]
+		}
+		if i < len(other.Version) {
+			b = other.Version[i]
+		}
+		if a != b {
+			if a < b {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	return strings.Compare(fr.Suffix, other.Suffix), nil
+}
+
+// FirmwareRevCompareFn compares two firmware revision strings, returning a
+// negative value if a is older than b, zero if they are equal and a positive
+// value if a is newer.
+type FirmwareRevCompareFn func(a, b string) (int, error)
+
+func compareWith(parse func(string) (*FirmwareRev, error), ignoreSuffix bool) FirmwareRevCompareFn {
+	return func(a, b string) (int, error) {
+		fa, err := parse(a)
+		if err != nil {
+			return 0, err
+		}
+		fb, err := parse(b)
+		if err != nil {
+			return 0, err
+		}
+		if ignoreSuffix {
+			fa.Suffix, fb.Suffix = "", ""
+		}
+
+		return fa.Compare(fb)
+	}
+}
+
+var (
+	// CompareFirmwareRevs compares revisions in the generic format accepted
+	// by ParseFirmwareRev.
+	CompareFirmwareRevs = compareWith(ParseFirmwareRev, false)
+
+	// CompareIntelFirmwareRevs compares Intel revisions, which consist of
+	// a three character product code followed by a numeric version, e.g.
+	// "VDV10131".
+	CompareIntelFirmwareRevs = compareWith(parseProductCodeRev, false)
+
+	// CompareSamsungFirmwareRevs compares Samsung revisions, which consist
+	// of a three character product code, a numeric version and a market
+	// suffix that does not affect ordering, e.g. "GDC5302Q".
+	CompareSamsungFirmwareRevs = compareWith(parseProductCodeRev, true)
+)
+
+// FirmwareRevComparers maps vendor names, as they appear at the start of NVMe
+// controller model strings, to the functions used to compare their firmware
+// revisions. Controllers from vendors without an entry are compared with
+// CompareFirmwareRevs.
+var FirmwareRevComparers = map[string]FirmwareRevCompareFn{
+	"INTEL":   CompareIntelFirmwareRevs,
+	"SAMSUNG": CompareSamsungFirmwareRevs,
+}
+
+// firmwareRevComparer returns the comparison function for the vendor of the
+// given controller model.
+func firmwareRevComparer(model string) FirmwareRevCompareFn {
+	if fields := strings.Fields(model); len(fields) > 0 {
+		if fn, exists := FirmwareRevComparers[strings.ToUpper(fields[0])]; exists {
+			return fn
+		}
+	}
+
+	return CompareFirmwareRevs
+}
+
+// OutdatedNvmeControllers returns the controllers in the scan response with a
+// firmware revision older than minRev. Controllers whose firmware belongs to a
+// different product line than minRev are skipped.
+func OutdatedNvmeControllers(resp *ctlpb.ScanNvmeResp, minRev string) (NvmeControllers, error) {
+	if resp == nil {
+		return nil, errors.New("nil scan response")
+	}
+
+	var outdated NvmeControllers
+	for _, ctrlr := range resp.GetCtrlrs() {
+		cmp := firmwareRevComparer(ctrlr.GetModel())
+
+		res, err := cmp(ctrlr.GetFwRev(), minRev)
+		if err == ErrFirmwareRevMismatch {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "controller %s", ctrlr.GetPciAddr())
+		}
+		if res < 0 {
+			outdated = append(outdated, ctrlr)
+		}
+	}
+
+	return outdated, nil
+}
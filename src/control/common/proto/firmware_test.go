@@ -0,0 +1,235 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+func TestProto_ParseFirmwareRev(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rev    string
+		expRev *FirmwareRev
+		expErr error
+	}{
+		"empty": {
+			expErr: errors.New("no version number"),
+		},
+		"no digits": {
+			rev:    "ABCDEFGH",
+			expErr: errors.New("no version number"),
+		},
+		"empty component": {
+			rev:    "1..2",
+			expErr: errors.New("empty version component"),
+		},
+		"intel": {
+			rev:    "VDV10131",
+			expRev: &FirmwareRev{Prefix: "VDV", Version: []int{10131}},
+		},
+		"samsung": {
+			rev: "GDC5302Q",
+			expRev: &FirmwareRev{
+				Prefix: "GDC", Version: []int{5302}, Suffix: "Q",
+			},
+		},
+		"dotted": {
+			rev:    " 1.2.3 ",
+			expRev: &FirmwareRev{Version: []int{1, 2, 3}},
+		},
+		"dotted with prefix and suffix": {
+			rev: "v2.10-rc1",
+			expRev: &FirmwareRev{
+				Prefix: "v", Version: []int{2, 10}, Suffix: "-rc1",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotRev, gotErr := ParseFirmwareRev(tc.rev)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expRev, gotRev); diff != "" {
+				t.Fatalf("unexpected result (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestProto_CompareFirmwareRevs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cmpFn  FirmwareRevCompareFn
+		a      string
+		b      string
+		expRes int
+		expErr error
+	}{
+		"generic older": {
+			cmpFn:  CompareFirmwareRevs,
+			a:      "1.2.3",
+			b:      "1.10",
+			expRes: -1,
+		},
+		"generic newer": {
+			cmpFn:  CompareFirmwareRevs,
+			a:      "2.0",
+			b:      "1.99.99",
+			expRes: 1,
+		},
+		"generic equal with implicit zero": {
+			cmpFn: CompareFirmwareRevs,
+			a:     "1.2",
+			b:     "1.2.0",
+		},
+		"generic different prefix": {
+			cmpFn:  CompareFirmwareRevs,
+			a:      "VDV10131",
+			b:      "QDV10131",
+			expErr: ErrFirmwareRevMismatch,
+		},
+		"generic bad revision": {
+			cmpFn:  CompareFirmwareRevs,
+			a:      "unknown",
+			b:      "1.0",
+			expErr: errors.New("no version number"),
+		},
+		"intel older": {
+			cmpFn:  CompareIntelFirmwareRevs,
+			a:      "VDV10131",
+			b:      "VDV10170",
+			expRes: -1,
+		},
+		"intel numeric product code": {
+			cmpFn:  CompareIntelFirmwareRevs,
+			a:      "8DV10171",
+			b:      "8DV10151",
+			expRes: 1,
+		},
+		"intel different product code": {
+			cmpFn:  CompareIntelFirmwareRevs,
+			a:      "8DV10171",
+			b:      "VDV10170",
+			expErr: ErrFirmwareRevMismatch,
+		},
+		"intel too short": {
+			cmpFn:  CompareIntelFirmwareRevs,
+			a:      "VDV",
+			b:      "VDV10170",
+			expErr: errors.New("too short"),
+		},
+		"samsung suffix ignored": {
+			cmpFn: CompareSamsungFirmwareRevs,
+			a:     "GDC5302Q",
+			b:     "GDC5302B",
+		},
+		"samsung older": {
+			cmpFn:  CompareSamsungFirmwareRevs,
+			a:      "EDA5202Q",
+			b:      "EDA7602Q",
+			expRes: -1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotRes, gotErr := tc.cmpFn(tc.a, tc.b)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expRes, gotRes, "comparison result")
+		})
+	}
+}
+
+func TestProto_OutdatedNvmeControllers(t *testing.T) {
+	ctrlr := func(addr, model, fwRev string) *ctlpb.NvmeController {
+		return &ctlpb.NvmeController{PciAddr: addr, Model: model, FwRev: fwRev}
+	}
+
+	for name, tc := range map[string]struct {
+		resp   *ctlpb.ScanNvmeResp
+		minRev string
+		expOut NvmeControllers
+		expErr error
+	}{
+		"nil response": {
+			expErr: errors.New("nil scan response"),
+		},
+		"no controllers": {
+			resp:   &ctlpb.ScanNvmeResp{},
+			minRev: "VDV10170",
+		},
+		"mixed vendors": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: []*ctlpb.NvmeController{
+					ctrlr("0000:80:00.0", "INTEL SSDPE2KX010T8", "VDV10131"),
+					ctrlr("0000:81:00.0", "INTEL SSDPE2KX010T8", "VDV10170"),
+					ctrlr("0000:82:00.0", "INTEL SSDPE2KX010T8", "VDV10184"),
+					ctrlr("0000:83:00.0", "SAMSUNG MZQLB960HAJR", "EDA5202Q"),
+				},
+			},
+			minRev: "VDV10170",
+			expOut: NvmeControllers{
+				ctrlr("0000:80:00.0", "INTEL SSDPE2KX010T8", "VDV10131"),
+			},
+		},
+		"samsung minimum": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: []*ctlpb.NvmeController{
+					ctrlr("0000:80:00.0", "INTEL SSDPE2KX010T8", "VDV10131"),
+					ctrlr("0000:83:00.0", "SAMSUNG MZQLB960HAJR", "EDA5202Q"),
+					ctrlr("0000:84:00.0", "Samsung MZQLB960HAJR", "EDA7602B"),
+				},
+			},
+			minRev: "EDA7602Q",
+			expOut: NvmeControllers{
+				ctrlr("0000:83:00.0", "SAMSUNG MZQLB960HAJR", "EDA5202Q"),
+			},
+		},
+		"unknown vendor uses generic comparison": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: []*ctlpb.NvmeController{
+					ctrlr("0000:80:00.0", "ACME Drive", "1.2.3"),
+					ctrlr("0000:81:00.0", "ACME Drive", "1.10.0"),
+				},
+			},
+			minRev: "1.4",
+			expOut: NvmeControllers{
+				ctrlr("0000:80:00.0", "ACME Drive", "1.2.3"),
+			},
+		},
+		"unparseable revision": {
+			resp: &ctlpb.ScanNvmeResp{
+				Ctrlrs: []*ctlpb.NvmeController{
+					ctrlr("0000:80:00.0", "INTEL SSDPE2KX010T8", "VD"),
+				},
+			},
+			minRev: "VDV10170",
+			expErr: errors.New("controller 0000:80:00.0: firmware revision \"VD\" is too short"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotOut, gotErr := OutdatedNvmeControllers(tc.resp, tc.minRev)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expOut, gotOut, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected result (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
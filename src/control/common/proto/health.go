@@ -0,0 +1,218 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+// NvmeHealthSnapshot is a point-in-time capture of NVMe controller health
+// statistics, suitable for storing in an on-disk health log.
+type NvmeHealthSnapshot struct {
+	Captured time.Time           `json:"captured"`
+	Health   *storage.NvmeHealth `json:"health"`
+}
+
+// NewNvmeHealthSnapshot creates a snapshot of the given controller health
+// statistics captured at the given time.
+func NewNvmeHealthSnapshot(captured time.Time, pb *ctlpb.NvmeController_Health) (*NvmeHealthSnapshot, error) {
+	if pb == nil {
+		return nil, errors.New("nil health stats")
+	}
+
+	health, err := (*NvmeHealth)(pb).ToNative()
+	if err != nil {
+		return nil, errors.Wrap(err, "convert health stats")
+	}
+
+	return &NvmeHealthSnapshot{
+		Captured: captured,
+		Health:   health,
+	}, nil
+}
+
+// AsProto returns the health statistics held in the snapshot as a protobuf
+// message.
+func (hs *NvmeHealthSnapshot) AsProto() (*ctlpb.NvmeController_Health, error) {
+	pb := new(NvmeHealth)
+	if err := pb.FromNative(hs.Health); err != nil {
+		return nil, err
+	}
+
+	return pb.AsProto(), nil
+}
+
+// MarshalNvmeHealthSnapshot serializes a snapshot of the given controller
+// health statistics captured at the given time.
+func MarshalNvmeHealthSnapshot(captured time.Time, pb *ctlpb.NvmeController_Health) ([]byte, error) {
+	hs, err := NewNvmeHealthSnapshot(captured, pb)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(hs)
+}
+
+// UnmarshalNvmeHealthSnapshot reconstructs a snapshot serialized by
+// MarshalNvmeHealthSnapshot.
+func UnmarshalNvmeHealthSnapshot(data []byte) (*NvmeHealthSnapshot, error) {
+	hs := new(NvmeHealthSnapshot)
+	if err := json.Unmarshal(data, hs); err != nil {
+		return nil, errors.Wrap(err, "unmarshal health snapshot")
+	}
+	if hs.Health == nil {
+		return nil, errors.New("health snapshot has no health stats")
+	}
+	if hs.Captured.IsZero() {
+		return nil, errors.New("health snapshot has no capture time")
+	}
+
+	return hs, nil
+}
+
+// NvmeHealthDelta describes the change in NVMe controller health counters
+// between two snapshots. Reset is set if any counter was found to have been
+// reset between the snapshots, in which case the value accumulated since the
+// reset is used as the change in that counter.
+type NvmeHealthDelta struct {
+	Elapsed         time.Duration
+	PowerOnHours    uint64
+	PowerCycles     uint64
+	UnsafeShutdowns uint64
+	MediaErrors     uint64
+	ErrorLogEntries uint64
+	ReadErrors      uint64
+	WriteErrors     uint64
+	UnmapErrors     uint64
+	ChecksumErrors  uint64
+	Reset           bool
+}
+
+// MediaErrorRate returns the number of media errors per power-on hour over the
+// period covered by the delta.
+func (hd *NvmeHealthDelta) MediaErrorRate() float64 {
+	if hd.PowerOnHours == 0 {
+		return 0
+	}
+
+	return float64(hd.MediaErrors) / float64(hd.PowerOnHours)
+}
+
+// nvmeHealthCounterDelta computes the change in health counters between two
+// readings of the same controller. A counter lower in the later reading has
+// been reset (e.g. by a controller reset or firmware update) so the later
+// value is taken as the change and the delta is marked as reset.
+func nvmeHealthCounterDelta(a, b *storage.NvmeHealth) *NvmeHealthDelta {
+	hd := new(NvmeHealthDelta)
+	for _, c := range []struct {
+		before uint64
+		after  uint64
+		delta  *uint64
+	}{
+		{a.PowerOnHours, b.PowerOnHours, &hd.PowerOnHours},
+		{a.PowerCycles, b.PowerCycles, &hd.PowerCycles},
+		{a.UnsafeShutdowns, b.UnsafeShutdowns, &hd.UnsafeShutdowns},
+		{a.MediaErrors, b.MediaErrors, &hd.MediaErrors},
+		{a.ErrorLogEntries, b.ErrorLogEntries, &hd.ErrorLogEntries},
+		{uint64(a.ReadErrors), uint64(b.ReadErrors), &hd.ReadErrors},
+		{uint64(a.WriteErrors), uint64(b.WriteErrors), &hd.WriteErrors},
+		{uint64(a.UnmapErrors), uint64(b.UnmapErrors), &hd.UnmapErrors},
+		{uint64(a.ChecksumErrors), uint64(b.ChecksumErrors), &hd.ChecksumErrors},
+	} {
+		if c.after < c.before {
+			*c.delta = c.after
+			hd.Reset = true
+			continue
+		}
+		*c.delta = c.after - c.before
+	}
+
+	return hd
+}
+
+// NvmeHealthSnapshotDelta computes the change in health counters from the
+// earlier snapshot to the later one, see nvmeHealthCounterDelta for the
+// handling of counter resets. An error is returned if the snapshots are out of
+// order.
+func NvmeHealthSnapshotDelta(earlier, later *NvmeHealthSnapshot) (*NvmeHealthDelta, error) {
+	if earlier == nil || later == nil || earlier.Health == nil || later.Health == nil {
+		return nil, errors.New("nil health snapshot")
+	}
+	if later.Captured.Before(earlier.Captured) {
+		return nil, errors.Errorf("health snapshot captured at %s precedes %s",
+			later.Captured.Format(time.RFC3339), earlier.Captured.Format(time.RFC3339))
+	}
+
+	hd := nvmeHealthCounterDelta(earlier.Health, later.Health)
+	hd.Elapsed = later.Captured.Sub(earlier.Captured)
+
+	return hd, nil
+}
+
+// NvmeHealthRates describes the rates, in errors per hour, at which NVMe
+// controller error counters increased between two health readings. Reset is
+// set as for NvmeHealthDelta.
+type NvmeHealthRates struct {
+	Hours           float64
+	MediaErrors     float64
+	ErrorLogEntries float64
+	ReadErrors      float64
+	WriteErrors     float64
+	UnmapErrors     float64
+	ChecksumErrors  float64
+	Reset           bool
+}
+
+// NvmeHealthErrorRates computes hourly error rates from the change in health
+// counters between two readings of the same controller. The period between
+// the readings is given by elapsed if non-zero, otherwise by the increase in
+// power-on hours. An error is returned if the period is empty.
+func NvmeHealthErrorRates(earlier, later *ctlpb.NvmeController_Health, elapsed time.Duration) (*NvmeHealthRates, error) {
+	if earlier == nil || later == nil {
+		return nil, errors.New("nil health stats")
+	}
+	if elapsed < 0 {
+		return nil, errors.Errorf("invalid elapsed time %s", elapsed)
+	}
+
+	a, err := (*NvmeHealth)(earlier).ToNative()
+	if err != nil {
+		return nil, errors.Wrap(err, "convert health stats")
+	}
+	b, err := (*NvmeHealth)(later).ToNative()
+	if err != nil {
+		return nil, errors.Wrap(err, "convert health stats")
+	}
+
+	hd := nvmeHealthCounterDelta(a, b)
+	hd.Elapsed = elapsed
+
+	hours := elapsed.Hours()
+	if elapsed == 0 {
+		hours = float64(hd.PowerOnHours)
+	}
+	if hours == 0 {
+		return nil, errors.New("no time elapsed between health readings")
+	}
+
+	return &NvmeHealthRates{
+		Hours:           hours,
+		MediaErrors:     float64(hd.MediaErrors) / hours,
+		ErrorLogEntries: float64(hd.ErrorLogEntries) / hours,
+		ReadErrors:      float64(hd.ReadErrors) / hours,
+		WriteErrors:     float64(hd.WriteErrors) / hours,
+		UnmapErrors:     float64(hd.UnmapErrors) / hours,
+		ChecksumErrors:  float64(hd.ChecksumErrors) / hours,
+		Reset:           hd.Reset,
+	}, nil
+}
@@ -0,0 +1,236 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package proto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+func TestProto_NvmeHealthSnapshot_RoundTrip(t *testing.T) {
+	captured := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		health    *ctlpb.NvmeController_Health
+		data      []byte
+		expHealth *ctlpb.NvmeController_Health
+		expErr    error
+	}{
+		"nil health": {
+			expErr: errors.New("nil health stats"),
+		},
+		"success": {
+			health:    MockNvmeHealth(1),
+			expHealth: MockNvmeHealth(1),
+		},
+		"junk data": {
+			data:   []byte("{junk"),
+			expErr: errors.New("unmarshal health snapshot"),
+		},
+		"missing health": {
+			data:   []byte(`{"captured":"2021-06-01T12:00:00Z"}`),
+			expErr: errors.New("no health stats"),
+		},
+		"missing capture time": {
+			data:   []byte(`{"health":{"media_errs":1}}`),
+			expErr: errors.New("no capture time"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := tc.data
+			if data == nil {
+				var err error
+				data, err = MarshalNvmeHealthSnapshot(captured, tc.health)
+				common.CmpErr(t, tc.expErr, err)
+				if tc.expErr != nil {
+					return
+				}
+			}
+
+			hs, err := UnmarshalNvmeHealthSnapshot(data)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if !hs.Captured.Equal(captured) {
+				t.Fatalf("expected capture time %s, got %s", captured, hs.Captured)
+			}
+
+			gotHealth, err := hs.AsProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expHealth, gotHealth, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected health (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestProto_NvmeHealthSnapshotDelta(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := func(offset time.Duration, poh, mediaErrs uint64, readErrs uint32) *NvmeHealthSnapshot {
+		return &NvmeHealthSnapshot{
+			Captured: start.Add(offset),
+			Health: &storage.NvmeHealth{
+				PowerOnHours: poh,
+				PowerCycles:  3,
+				MediaErrors:  mediaErrs,
+				ReadErrors:   readErrs,
+			},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		earlier  *NvmeHealthSnapshot
+		later    *NvmeHealthSnapshot
+		expDelta *NvmeHealthDelta
+		expRate  float64
+		expErr   error
+	}{
+		"nil snapshot": {
+			earlier: snapshot(0, 100, 0, 0),
+			expErr:  errors.New("nil health snapshot"),
+		},
+		"out of order": {
+			earlier: snapshot(time.Hour, 101, 0, 0),
+			later:   snapshot(0, 100, 0, 0),
+			expErr:  errors.New("precedes"),
+		},
+		"counter reset": {
+			earlier: snapshot(0, 100, 10, 0),
+			later:   snapshot(2*time.Hour, 102, 2, 0),
+			expDelta: &NvmeHealthDelta{
+				Elapsed:      2 * time.Hour,
+				PowerOnHours: 2,
+				MediaErrors:  2,
+				Reset:        true,
+			},
+			expRate: 1,
+		},
+		"no change": {
+			earlier:  snapshot(0, 100, 10, 1),
+			later:    snapshot(0, 100, 10, 1),
+			expDelta: &NvmeHealthDelta{},
+		},
+		"success": {
+			earlier: snapshot(0, 100, 10, 1),
+			later:   snapshot(48*time.Hour, 148, 22, 4),
+			expDelta: &NvmeHealthDelta{
+				Elapsed:      48 * time.Hour,
+				PowerOnHours: 48,
+				MediaErrors:  12,
+				ReadErrors:   3,
+			},
+			expRate: 0.25,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotDelta, gotErr := NvmeHealthSnapshotDelta(tc.earlier, tc.later)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expDelta, gotDelta); diff != "" {
+				t.Fatalf("unexpected delta (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expRate, gotDelta.MediaErrorRate(), "media error rate")
+		})
+	}
+}
+
+func TestProto_NvmeHealthErrorRates(t *testing.T) {
+	health := func(poh, media, csum uint64) *ctlpb.NvmeController_Health {
+		return &ctlpb.NvmeController_Health{
+			PowerOnHours: poh,
+			MediaErrs:    media,
+			ChecksumErrs: uint32(csum),
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		earlier  *ctlpb.NvmeController_Health
+		later    *ctlpb.NvmeController_Health
+		elapsed  time.Duration
+		expRates *NvmeHealthRates
+		expErr   error
+	}{
+		"nil health": {
+			later:  health(10, 0, 0),
+			expErr: errors.New("nil health stats"),
+		},
+		"negative elapsed": {
+			earlier: health(10, 0, 0),
+			later:   health(20, 0, 0),
+			elapsed: -time.Hour,
+			expErr:  errors.New("invalid elapsed time"),
+		},
+		"no time elapsed": {
+			earlier: health(10, 1, 1),
+			later:   health(10, 5, 5),
+			expErr:  errors.New("no time elapsed"),
+		},
+		"power-on hours delta": {
+			earlier: health(100, 10, 2),
+			later:   health(104, 30, 10),
+			expRates: &NvmeHealthRates{
+				Hours:          4,
+				MediaErrors:    5,
+				ChecksumErrors: 2,
+			},
+		},
+		"wall-clock delta": {
+			earlier: health(100, 10, 2),
+			later:   health(100, 13, 2),
+			elapsed: 30 * time.Minute,
+			expRates: &NvmeHealthRates{
+				Hours:       0.5,
+				MediaErrors: 6,
+			},
+		},
+		"counter reset": {
+			earlier: health(100, 50, 8),
+			later:   health(102, 6, 12),
+			expRates: &NvmeHealthRates{
+				Hours:          2,
+				MediaErrors:    3,
+				ChecksumErrors: 2,
+				Reset:          true,
+			},
+		},
+		"power-on hours reset": {
+			earlier: health(100, 0, 0),
+			later:   health(2, 4, 0),
+			expRates: &NvmeHealthRates{
+				Hours:       2,
+				MediaErrors: 2,
+				Reset:       true,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotRates, gotErr := NvmeHealthErrorRates(tc.earlier, tc.later, tc.elapsed)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expRates, gotRates); diff != "" {
+				t.Fatalf("unexpected rates (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RankResultsSummary describes the outcome of an action across a set of
+// ranks.
+type RankResultsSummary struct {
+	StateCounts  map[string]int
+	ErroredRanks []uint32
+	FirstErrMsg  string
+}
+
+// Succeeded returns the number of ranks where the action did not fail.
+func (rrs *RankResultsSummary) Succeeded() int {
+	total := 0
+	for _, count := range rrs.StateCounts {
+		total += count
+	}
+
+	return total - len(rrs.ErroredRanks)
+}
+
+func (rrs *RankResultsSummary) String() string {
+	states := make([]string, 0, len(rrs.StateCounts))
+	for state, count := range rrs.StateCounts {
+		states = append(states, fmt.Sprintf("%s: %d", state, count))
+	}
+	sort.Strings(states)
+
+	str := fmt.Sprintf("succeeded: %d, errored: %d", rrs.Succeeded(), len(rrs.ErroredRanks))
+	if len(states) > 0 {
+		str += fmt.Sprintf(" (%s)", strings.Join(states, ", "))
+	}
+	if rrs.FirstErrMsg != "" {
+		str += fmt.Sprintf(", first error: %s", rrs.FirstErrMsg)
+	}
+
+	return str
+}
+
+// SummarizeRankResults returns a summary of the given rank results with the
+// number of results in each state and the ranks that errored, in ascending
+// order.
+func SummarizeRankResults(results []*RankResult) *RankResultsSummary {
+	summary := &RankResultsSummary{
+		StateCounts:  make(map[string]int),
+		ErroredRanks: []uint32{},
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		summary.StateCounts[result.GetState()]++
+
+		if !result.GetErrored() {
+			continue
+		}
+		summary.ErroredRanks = append(summary.ErroredRanks, result.GetRank())
+		if summary.FirstErrMsg == "" {
+			summary.FirstErrMsg = result.GetMsg()
+		}
+	}
+	sort.Slice(summary.ErroredRanks, func(i, j int) bool {
+		return summary.ErroredRanks[i] < summary.ErroredRanks[j]
+	})
+
+	return summary
+}
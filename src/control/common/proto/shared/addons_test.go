@@ -0,0 +1,63 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestShared_SummarizeRankResults(t *testing.T) {
+	for name, tc := range map[string]struct {
+		results      []*RankResult
+		expSummary   *RankResultsSummary
+		expSucceeded int
+		expString    string
+	}{
+		"no results": {
+			expSummary: &RankResultsSummary{
+				StateCounts:  map[string]int{},
+				ErroredRanks: []uint32{},
+			},
+			expString: "succeeded: 0, errored: 0",
+		},
+		"mixed results": {
+			results: []*RankResult{
+				{Rank: 3, State: "stopped"},
+				{Rank: 5, State: "errored", Errored: true, Msg: "uh oh"},
+				nil,
+				{Rank: 1, State: "stopped"},
+				{Rank: 2, State: "errored", Errored: true, Msg: "uh ohh"},
+				{Rank: 4, State: "unknown", Errored: true},
+			},
+			expSummary: &RankResultsSummary{
+				StateCounts: map[string]int{
+					"stopped": 2,
+					"errored": 2,
+					"unknown": 1,
+				},
+				ErroredRanks: []uint32{2, 4, 5},
+				FirstErrMsg:  "uh oh",
+			},
+			expSucceeded: 2,
+			expString:    "succeeded: 2, errored: 3 (errored: 2, stopped: 2, unknown: 1), first error: uh oh",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotSummary := SummarizeRankResults(tc.results)
+
+			if diff := cmp.Diff(tc.expSummary, gotSummary); diff != "" {
+				t.Fatalf("unexpected summary (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expSucceeded, gotSummary.Succeeded(), "unexpected succeeded count")
+			common.AssertEqual(t, tc.expString, gotSummary.String(), "unexpected string")
+		})
+	}
+}
@@ -114,6 +114,18 @@ func (pb *NvmeControllers) ToNative() (storage.NvmeControllers, error) {
 	return native, convert.Types(pb, &native)
 }
 
+// ValidateIdentities checks the controllers, e.g. those in a ScanNvmeResp,
+// for duplicate serial numbers or PCI addresses and returns an error listing
+// any collisions.
+func (pb *NvmeControllers) ValidateIdentities() error {
+	native, err := pb.ToNative()
+	if err != nil {
+		return err
+	}
+
+	return native.ValidateIdentities()
+}
+
 // NvmeControllerResults is an alias for protobuf NvmeControllerResult messages
 // representing operation results on a number of NVMe controllers.
 type NvmeControllerResults []*ctlpb.NvmeControllerResult
@@ -10,7 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
@@ -52,7 +52,7 @@ func TestProto_ConvertNvmeController(t *testing.T) {
 	expNative := storage.MockNvmeController()
 
 	cmpOpts := append(common.DefaultCmpOpts(),
-		cmpopts.IgnoreFields(storage.NvmeController{}, "HealthStats", "Serial"),
+		storage.NvmeControllerCmpOpt("HealthStats", "Serial"),
 	)
 	if diff := cmp.Diff(expNative, native, cmpOpts...); diff != "" {
 		t.Fatalf("unexpected result (-want, +got):\n%s\n", diff)
@@ -83,6 +83,57 @@ func TestProto_ConvertNvmeControllers(t *testing.T) {
 	}
 }
 
+func TestProto_NvmeControllers_ValidateIdentities(t *testing.T) {
+	ctrlr := func(addr, serial string) *ctlpb.NvmeController {
+		return &ctlpb.NvmeController{PciAddr: addr, Serial: serial}
+	}
+
+	for name, tc := range map[string]struct {
+		ctrlrs []*ctlpb.NvmeController
+		expErr error
+	}{
+		"no controllers": {},
+		"unique identities": {
+			ctrlrs: []*ctlpb.NvmeController{
+				ctrlr("0000:80:00.0", "serial1"),
+				ctrlr("0000:81:00.0", "serial2"),
+			},
+		},
+		"missing serials ignored": {
+			ctrlrs: []*ctlpb.NvmeController{
+				ctrlr("0000:80:00.0", ""),
+				ctrlr("0000:81:00.0", ""),
+			},
+		},
+		"duplicate serial": {
+			ctrlrs: []*ctlpb.NvmeController{
+				ctrlr("0000:80:00.0", "serial1"),
+				ctrlr("0000:81:00.0", "serial2"),
+				ctrlr("0000:82:00.0", "serial1"),
+			},
+			expErr: errors.New(`duplicate NVMe controller identities: ` +
+				`serial "serial1" shared by controllers 0000:80:00.0, 0000:82:00.0`),
+		},
+		"duplicate serial and pci address": {
+			ctrlrs: []*ctlpb.NvmeController{
+				ctrlr("0000:80:00.0", "serial1"),
+				ctrlr("0000:80:00.0", "serial2"),
+				ctrlr("0000:81:00.0", "serial2"),
+			},
+			expErr: errors.New(`duplicate NVMe controller identities: ` +
+				`pci address "0000:80:00.0" shared by 2 controllers; ` +
+				`serial "serial2" shared by controllers 0000:80:00.0, 0000:81:00.0`),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := &ctlpb.ScanNvmeResp{Ctrlrs: tc.ctrlrs}
+
+			gotErr := (*NvmeControllers)(&resp.Ctrlrs).ValidateIdentities()
+			common.CmpErr(t, tc.expErr, gotErr)
+		})
+	}
+}
+
 func TestProto_ConvertScmModule(t *testing.T) {
 	pb := MockScmModule()
 	native, err := (*ScmModule)(pb).ToNative()
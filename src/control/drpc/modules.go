@@ -127,6 +127,7 @@ func (m MgmtMethod) String() string {
 		MethodPingRank:        "Ping",
 		MethodSetRank:         "SetRank",
 		MethodSetUp:           "Setup",
+		MethodPoolSvcsUp:      "PoolSvcsUp",
 		MethodGroupUpdate:     "GroupUpdate",
 		MethodPoolCreate:      "PoolCreate",
 		MethodPoolDestroy:     "PoolDestroy",
@@ -227,6 +228,9 @@ const (
 	MethodNotifyExit MgmtMethod = C.DRPC_METHOD_MGMT_NOTIFY_EXIT
 	// MethodIdentifyStorage is a ModuleMgmt method
 	MethodIdentifyStorage MgmtMethod = C.DRPC_METHOD_MGMT_DEV_IDENTIFY
+	// MethodPoolSvcsUp defines a method for checking whether the pool
+	// services hosted by an engine have been started
+	MethodPoolSvcsUp MgmtMethod = C.DRPC_METHOD_MGMT_POOL_SVCS_UP
 )
 
 type srvMethod int32
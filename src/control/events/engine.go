@@ -87,3 +87,29 @@ func NewEngineFormatRequiredEvent(hostname string, instanceIdx uint32, formatTyp
 		},
 	})
 }
+
+// NewEngineResetFormatEvent creates an EngineResetFormat event from given
+// inputs, raised as the rank of an engine being reset progresses towards
+// awaiting format. A non-nil error indicates that the rank failed to do so.
+func NewEngineResetFormatEvent(hostname string, instanceIdx uint32, rank uint32, state string, err error) *RASEvent {
+	msg := fmt.Sprintf("DAOS engine %d (rank %d) reset format: %s", instanceIdx, rank, state)
+	sev := RASSeverityNotice
+	info := &EngineStateInfo{
+		InstanceIdx: instanceIdx,
+	}
+	if err != nil {
+		msg += fmt.Sprintf(" (%s)", err)
+		sev = RASSeverityWarning
+		info.ExitErr = common.ExitStatus(err.Error())
+	}
+
+	return New(&RASEvent{
+		Msg:          msg,
+		ID:           RASEngineResetFormat,
+		Hostname:     hostname,
+		Rank:         rank,
+		Type:         RASTypeInfoOnly,
+		Severity:     sev,
+		ExtendedInfo: info,
+	})
+}
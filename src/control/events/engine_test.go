@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
 )
@@ -79,3 +80,37 @@ func TestEvents_ConvertEngineFormatRequired(t *testing.T) {
 		t.Fatalf("unexpected event (-want, +got):\n%s\n", diff)
 	}
 }
+
+func TestEvents_ConvertEngineResetFormat(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err    error
+		expSev RASSeverityID
+	}{
+		"progress": {
+			expSev: RASSeverityNotice,
+		},
+		"failed": {
+			err:    errors.New("want AwaitFormat, got Stopped"),
+			expSev: RASSeverityWarning,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			event := NewEngineResetFormatEvent(tHost, tInstanceIdx, tRank, "Starting", tc.err)
+			common.AssertEqual(t, tc.expSev, event.Severity, "unexpected severity")
+
+			pbEvent, err := event.ToProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			returnedEvent := new(RASEvent)
+			if err := returnedEvent.FromProto(pbEvent); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(event, returnedEvent, defEvtCmpOpts...); diff != "" {
+				t.Fatalf("unexpected event (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package events
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// NewRankOperationEvent creates a RankOperation event from given inputs, raised
+// for audit purposes on completion of a state-changing operation (e.g. stop)
+// on the ranks hosted on a server. Severity is raised if any rank failed.
+func NewRankOperationEvent(hostname, operation, ranks string, succeeded, failed int, duration time.Duration) *RASEvent {
+	sev := RASSeverityNotice
+	if failed > 0 {
+		sev = RASSeverityWarning
+	}
+
+	return New(&RASEvent{
+		Msg: fmt.Sprintf("rank %s on ranks %q: %d succeeded, %d failed in %s",
+			operation, ranks, succeeded, failed, duration),
+		ID:       RASRankOperation,
+		Hostname: hostname,
+		Rank:     math.MaxUint32,
+		Type:     RASTypeInfoOnly,
+		Severity: sev,
+		ExtendedInfo: NewStrInfo(fmt.Sprintf("operation=%s ranks=%s succeeded=%d failed=%d duration_ms=%d",
+			operation, ranks, succeeded, failed, duration.Milliseconds())),
+	})
+}
@@ -0,0 +1,56 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestEvents_ConvertRankOperation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		failed  int
+		expSev  RASSeverityID
+		expInfo string
+	}{
+		"all succeeded": {
+			expSev:  RASSeverityNotice,
+			expInfo: "operation=stop ranks=1-2 succeeded=2 failed=0 duration_ms=1500",
+		},
+		"some failed": {
+			failed:  1,
+			expSev:  RASSeverityWarning,
+			expInfo: "operation=stop ranks=1-2 succeeded=2 failed=1 duration_ms=1500",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			event := NewRankOperationEvent(tHost, "stop", "1-2", 2, tc.failed,
+				1500*time.Millisecond)
+			common.AssertEqual(t, tc.expSev, event.Severity, "unexpected severity")
+			common.AssertEqual(t, tc.expInfo, string(*event.GetStrInfo()),
+				"unexpected extended info")
+
+			pbEvent, err := event.ToProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			returnedEvent := new(RASEvent)
+			if err := returnedEvent.FromProto(pbEvent); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(event, returnedEvent, defEvtCmpOpts...); diff != "" {
+				t.Fatalf("unexpected event (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -53,6 +53,9 @@ const (
 	RASSwimRankDead         RASID = C.RAS_SWIM_RANK_DEAD         // info
 	RASSystemStartFailed    RASID = C.RAS_SYSTEM_START_FAILED    // error
 	RASSystemStopFailed     RASID = C.RAS_SYSTEM_STOP_FAILED     // error
+	RASEngineResetFormat    RASID = C.RAS_ENGINE_RESET_FORMAT    // notice
+	RASRankOperation        RASID = C.RAS_RANK_OPERATION         // notice
+	RASTelemetryThreshold   RASID = C.RAS_TELEMETRY_THRESHOLD    // warning
 )
 
 func (id RASID) String() string {
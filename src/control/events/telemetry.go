@@ -0,0 +1,26 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package events
+
+import (
+	"fmt"
+)
+
+// NewTelemetryThresholdEvent creates a TelemetryThreshold event from given
+// inputs, raised when the value of the named metric crosses a threshold.
+func NewTelemetryThresholdEvent(hostname string, rank uint32, metric, desc string, value, threshold float64, sev RASSeverityID) *RASEvent {
+	return New(&RASEvent{
+		Msg:      fmt.Sprintf("%s: %s (value %g, threshold %g)", metric, desc, value, threshold),
+		ID:       RASTelemetryThreshold,
+		Hostname: hostname,
+		Rank:     rank,
+		Type:     RASTypeInfoOnly,
+		Severity: sev,
+		ExtendedInfo: NewStrInfo(fmt.Sprintf("metric=%s value=%g threshold=%g",
+			metric, value, threshold)),
+	})
+}
@@ -84,6 +84,7 @@ const (
 	BdevPCIAddressNotFound
 	BdevDuplicatesInDeviceList
 	BdevNoDevicesMatchFilter
+	BdevResetFailure
 )
 
 // DAOS system fault codes
@@ -123,6 +124,12 @@ const (
 	ServerInstancesNotStopped
 	ServerConfigInvalidNetDevClass
 	ServerVfioDisabled
+	ServerNilRequest
+	ServerNoRanksSpecified
+	ServerRankDrpcNoResult
+	ServerInsufficientHugePageMemory
+	ServerInstancesFormatted
+	ServerBdevTooSmall
 )
 
 // server config fault codes
@@ -16,7 +16,6 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/daos-stack/daos/src/control/common/proto/convert"
-	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	"github.com/daos-stack/daos/src/control/lib/hostlist"
 )
 
@@ -212,19 +211,3 @@ func convertMSResponse(ur *UnaryResponse, out interface{}) error {
 
 	return convert.Types(msResp, out)
 }
-
-// ctlStateToErr is a helper function for turning an
-// unsuccessful control response state into an error.
-func ctlStateToErr(state *ctlpb.ResponseState) error {
-	if state.GetStatus() == ctlpb.ResponseStatus_CTL_SUCCESS {
-		return nil
-	}
-
-	if errMsg := state.GetError(); errMsg != "" {
-		return errors.New(errMsg)
-	}
-	if infoMsg := state.GetInfo(); infoMsg != "" {
-		return errors.Errorf("%s: %s", state.GetStatus(), infoMsg)
-	}
-	return errors.Errorf("%s: unknown error", state.GetStatus())
-}
@@ -16,14 +16,19 @@ import (
 
 	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/lib/hostlist"
+	"github.com/daos-stack/daos/src/control/server/storage"
 )
 
 func defResCmpOpts() []cmp.Option {
 	return []cmp.Option{
 		cmp.Comparer(func(x, y *hostlist.HostSet) bool {
+			if x == nil || y == nil {
+				return x == y
+			}
 			return x.RangedString() == y.RangedString()
 		}),
 		cmpopts.IgnoreFields(HostErrorSet{}, "HostError"),
+		storage.NvmeControllerCmpOpt(),
 	}
 }
 
@@ -0,0 +1,174 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package control
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+type (
+	// NvmeSelfTestReq contains the parameters for a request to start a
+	// device self-test on an NVMe SSD.
+	NvmeSelfTestReq struct {
+		unaryRequest
+		PciAddr  string // PCI address of the SSD to test
+		Extended bool   // Run extended rather than short test
+	}
+
+	// NvmeSelfTestResp contains the per-host self-test operation started
+	// by a NvmeSelfTestReq.
+	NvmeSelfTestResp struct {
+		HostErrorsResp
+		HostCodes map[string]string
+	}
+
+	// NvmeSelfTestQueryReq contains the parameters for a request to
+	// retrieve device self-test progress and results from an NVMe SSD.
+	NvmeSelfTestQueryReq struct {
+		unaryRequest
+		PciAddr string // PCI address of the SSD to query
+	}
+
+	// NvmeSelfTestStatus represents the self-test state of a single SSD.
+	NvmeSelfTestStatus struct {
+		PciAddr    string
+		InProgress bool
+		Current    string
+		Progress   uint32
+		HasResult  bool
+		Passed     bool
+		LastCode   string
+		LastResult string
+	}
+
+	// HostSelfTestMap maps a host address to the self-test state of the
+	// queried SSD on that host.
+	HostSelfTestMap map[string]*NvmeSelfTestStatus
+
+	// NvmeSelfTestQueryResp contains the per-host results of a
+	// NvmeSelfTestQueryReq.
+	NvmeSelfTestQueryResp struct {
+		HostErrorsResp
+		HostStatus HostSelfTestMap
+	}
+)
+
+// Keys returns the sorted list of keys from the HostSelfTestMap.
+func (m HostSelfTestMap) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NvmeSelfTest concurrently starts a device self-test on the NVMe SSD with
+// the requested PCI address across all hosts supplied in the request's
+// hostlist, or all configured hosts if not explicitly specified. The test
+// runs in the background on each device, use NvmeSelfTestQuery to follow
+// its progress.
+func NvmeSelfTest(ctx context.Context, rpcClient UnaryInvoker, req *NvmeSelfTestReq) (*NvmeSelfTestResp, error) {
+	if req == nil {
+		return nil, errors.New("nil request")
+	}
+	if req.PciAddr == "" {
+		return nil, errors.New("no NVMe SSD PCI address specified")
+	}
+
+	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
+		return ctlpb.NewCtlSvcClient(conn).NvmeSelfTest(ctx, &ctlpb.NvmeSelfTestReq{
+			PciAddr:  req.PciAddr,
+			Extended: req.Extended,
+		})
+	})
+
+	ur, err := rpcClient.InvokeUnaryRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &NvmeSelfTestResp{
+		HostCodes: make(map[string]string),
+	}
+	for _, hostResp := range ur.Responses {
+		if hostResp.Error != nil {
+			if err := resp.addHostError(hostResp.Addr, hostResp.Error); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		pbResp, ok := hostResp.Message.(*ctlpb.NvmeSelfTestResp)
+		if !ok {
+			return nil, errors.Errorf("unable to unpack message: %+v", hostResp.Message)
+		}
+		resp.HostCodes[hostResp.Addr] = pbResp.GetCode()
+	}
+
+	return resp, nil
+}
+
+// NvmeSelfTestQuery concurrently retrieves the device self-test progress and
+// most recent result of the NVMe SSD with the requested PCI address across
+// all hosts supplied in the request's hostlist, or all configured hosts if
+// not explicitly specified.
+func NvmeSelfTestQuery(ctx context.Context, rpcClient UnaryInvoker, req *NvmeSelfTestQueryReq) (*NvmeSelfTestQueryResp, error) {
+	if req == nil {
+		return nil, errors.New("nil request")
+	}
+	if req.PciAddr == "" {
+		return nil, errors.New("no NVMe SSD PCI address specified")
+	}
+
+	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
+		return ctlpb.NewCtlSvcClient(conn).NvmeSelfTestQuery(ctx, &ctlpb.NvmeSelfTestQueryReq{
+			PciAddr: req.PciAddr,
+		})
+	})
+
+	ur, err := rpcClient.InvokeUnaryRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &NvmeSelfTestQueryResp{
+		HostStatus: make(HostSelfTestMap),
+	}
+	for _, hostResp := range ur.Responses {
+		if hostResp.Error != nil {
+			if err := resp.addHostError(hostResp.Addr, hostResp.Error); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		pbResp, ok := hostResp.Message.(*ctlpb.NvmeSelfTestQueryResp)
+		if !ok {
+			return nil, errors.Errorf("unable to unpack message: %+v", hostResp.Message)
+		}
+		resp.HostStatus[hostResp.Addr] = &NvmeSelfTestStatus{
+			PciAddr:    pbResp.GetPciAddr(),
+			InProgress: pbResp.GetInProgress(),
+			Current:    pbResp.GetCurrent(),
+			Progress:   pbResp.GetProgress(),
+			HasResult:  pbResp.GetHasResult(),
+			Passed:     pbResp.GetPassed(),
+			LastCode:   pbResp.GetLastCode(),
+			LastResult: pbResp.GetLastResult(),
+		}
+	}
+
+	return resp, nil
+}
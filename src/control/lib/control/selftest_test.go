@@ -0,0 +1,171 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+func TestControl_NvmeSelfTest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mic     *MockInvokerConfig
+		req     *NvmeSelfTestReq
+		expResp *NvmeSelfTestResp
+		expErr  error
+	}{
+		"no address": {
+			req:    &NvmeSelfTestReq{},
+			expErr: errors.New("no NVMe SSD PCI address"),
+		},
+		"local failure": {
+			req: &NvmeSelfTestReq{PciAddr: "0000:81:00.0"},
+			mic: &MockInvokerConfig{
+				UnaryError: errors.New("local failed"),
+			},
+			expErr: errors.New("local failed"),
+		},
+		"remote failure": {
+			req: &NvmeSelfTestReq{PciAddr: "0000:81:00.0"},
+			mic: &MockInvokerConfig{
+				UnaryResponse: MockMSResponse("host1", errors.New("remote failed"), nil),
+			},
+			expResp: &NvmeSelfTestResp{
+				HostErrorsResp: HostErrorsResp{
+					HostErrors: HostErrorsMap{
+						"remote failed": &HostErrorSet{
+							HostSet:   createTestHostSet(t, "host1"),
+							HostError: errors.New("remote failed"),
+						},
+					},
+				},
+				HostCodes: map[string]string{},
+			},
+		},
+		"success": {
+			req: &NvmeSelfTestReq{PciAddr: "0000:81:00.0", Extended: true},
+			mic: &MockInvokerConfig{
+				UnaryResponse: MockMSResponse("host1", nil, &ctlpb.NvmeSelfTestResp{
+					Code: "extended",
+				}),
+			},
+			expResp: &NvmeSelfTestResp{
+				HostCodes: map[string]string{"host1": "extended"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mic := tc.mic
+			if mic == nil {
+				mic = DefaultMockInvokerConfig()
+			}
+
+			gotResp, gotErr := NvmeSelfTest(context.TODO(), NewMockInvoker(log, mic), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp, getCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestControl_NvmeSelfTestQuery(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mic     *MockInvokerConfig
+		req     *NvmeSelfTestQueryReq
+		expResp *NvmeSelfTestQueryResp
+		expErr  error
+	}{
+		"no address": {
+			req:    &NvmeSelfTestQueryReq{},
+			expErr: errors.New("no NVMe SSD PCI address"),
+		},
+		"local failure": {
+			req: &NvmeSelfTestQueryReq{PciAddr: "0000:81:00.0"},
+			mic: &MockInvokerConfig{
+				UnaryError: errors.New("local failed"),
+			},
+			expErr: errors.New("local failed"),
+		},
+		"in progress": {
+			req: &NvmeSelfTestQueryReq{PciAddr: "0000:81:00.0"},
+			mic: &MockInvokerConfig{
+				UnaryResponse: MockMSResponse("host1", nil, &ctlpb.NvmeSelfTestQueryResp{
+					PciAddr:    "0000:81:00.0",
+					InProgress: true,
+					Current:    "extended",
+					Progress:   42,
+				}),
+			},
+			expResp: &NvmeSelfTestQueryResp{
+				HostStatus: HostSelfTestMap{
+					"host1": {
+						PciAddr:    "0000:81:00.0",
+						InProgress: true,
+						Current:    "extended",
+						Progress:   42,
+					},
+				},
+			},
+		},
+		"completed": {
+			req: &NvmeSelfTestQueryReq{PciAddr: "0000:81:00.0"},
+			mic: &MockInvokerConfig{
+				UnaryResponse: MockMSResponse("host1", nil, &ctlpb.NvmeSelfTestQueryResp{
+					PciAddr:    "0000:81:00.0",
+					HasResult:  true,
+					LastCode:   "short",
+					LastResult: "failed segments",
+				}),
+			},
+			expResp: &NvmeSelfTestQueryResp{
+				HostStatus: HostSelfTestMap{
+					"host1": {
+						PciAddr:    "0000:81:00.0",
+						HasResult:  true,
+						LastCode:   "short",
+						LastResult: "failed segments",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mic := tc.mic
+			if mic == nil {
+				mic = DefaultMockInvokerConfig()
+			}
+
+			gotResp, gotErr := NvmeSelfTestQuery(context.TODO(), NewMockInvoker(log, mic), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp, getCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -118,6 +118,136 @@ func (hsm HostStorageMap) Keys() []uint64 {
 	return keys
 }
 
+type (
+	// HostNvmeController is an NVMe controller tagged with the address of
+	// the host it was found on.
+	HostNvmeController struct {
+		Host       string                  `json:"host"`
+		Controller *storage.NvmeController `json:"controller"`
+	}
+
+	// HostNvmeScanMap provides a merged view of NVMe scan results from
+	// multiple hosts, keyed by host address.
+	HostNvmeScanMap map[string]storage.NvmeControllers
+)
+
+// MergeScanNvmeResps combines the given NVMe scan responses, keyed by the
+// address of the host that returned them, into a single HostNvmeScanMap.
+func MergeScanNvmeResps(resps map[string]*ctlpb.ScanNvmeResp) (HostNvmeScanMap, error) {
+	hnm := make(HostNvmeScanMap)
+	for hostAddr, resp := range resps {
+		if err := hnm.Add(hostAddr, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return hnm, nil
+}
+
+// Add merges the controllers in the given NVMe scan response into the
+// results for the host. Controllers already reported by the host are matched
+// by PCI address, with their namespaces and SMD devices combined and health
+// statistics filled in if previously missing, so health is never shared
+// between hosts.
+func (hnm HostNvmeScanMap) Add(hostAddr string, resp *ctlpb.ScanNvmeResp) error {
+	if hostAddr == "" {
+		return errors.New("empty host address")
+	}
+	if resp == nil {
+		return errors.Errorf("nil nvme scan response from %s", hostAddr)
+	}
+	if state := resp.GetState(); state.GetStatus() != ctlpb.ResponseStatus_CTL_SUCCESS {
+		return errors.Errorf("nvme scan failed on %s: %s", hostAddr, state.GetError())
+	}
+
+	var ctrlrs storage.NvmeControllers
+	if err := convert.Types(resp.GetCtrlrs(), &ctrlrs); err != nil {
+		return errors.Wrapf(err, "nvme scan response from %s", hostAddr)
+	}
+
+	existing := hnm[hostAddr]
+	for _, ctrlr := range ctrlrs {
+		if found := findController(existing, ctrlr.PciAddr); found != nil {
+			mergeController(found, ctrlr)
+			continue
+		}
+		existing = append(existing, ctrlr)
+	}
+	sort.Slice(existing, func(i, j int) bool {
+		return existing[i].PciAddr < existing[j].PciAddr
+	})
+	hnm[hostAddr] = existing
+
+	return nil
+}
+
+// Hosts returns the addresses of the hosts in the map in sorted order.
+func (hnm HostNvmeScanMap) Hosts() []string {
+	hosts := make([]string, 0, len(hnm))
+	for host := range hnm {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	return hosts
+}
+
+// Controllers returns the controllers from all hosts tagged with the host
+// they were found on, ordered by host and then PCI address.
+func (hnm HostNvmeScanMap) Controllers() []*HostNvmeController {
+	var hncs []*HostNvmeController
+	for _, host := range hnm.Hosts() {
+		for _, ctrlr := range hnm[host] {
+			hncs = append(hncs, &HostNvmeController{
+				Host:       host,
+				Controller: ctrlr,
+			})
+		}
+	}
+
+	return hncs
+}
+
+func findController(ctrlrs storage.NvmeControllers, pciAddr string) *storage.NvmeController {
+	for _, ctrlr := range ctrlrs {
+		if ctrlr.PciAddr == pciAddr {
+			return ctrlr
+		}
+	}
+
+	return nil
+}
+
+// mergeController adds details of the same controller from another scan
+// response to dst, existing details take precedence.
+func mergeController(dst, src *storage.NvmeController) {
+	if dst.HealthStats == nil {
+		dst.HealthStats = src.HealthStats
+	}
+
+	nsIDs := make(map[uint32]bool)
+	for _, ns := range dst.Namespaces {
+		nsIDs[ns.ID] = true
+	}
+	for _, ns := range src.Namespaces {
+		if !nsIDs[ns.ID] {
+			dst.Namespaces = append(dst.Namespaces, ns)
+			nsIDs[ns.ID] = true
+		}
+	}
+
+	devUUIDs := make(map[string]bool)
+	for _, dev := range dst.SmdDevices {
+		devUUIDs[dev.UUID] = true
+	}
+	for _, dev := range src.SmdDevices {
+		if !devUUIDs[dev.UUID] {
+			dst.SmdDevices = append(dst.SmdDevices, dev)
+			devUUIDs[dev.UUID] = true
+		}
+	}
+}
+
 type (
 	// StorageScanReq contains the parameters for a storage scan request.
 	StorageScanReq struct {
@@ -126,6 +256,7 @@ type (
 		NvmeHealth bool
 		NvmeMeta   bool
 		NvmeBasic  bool
+		NvmeForce  bool // bypass the server's health polling throttle
 	}
 
 	// StorageScanResp contains the response from a storage scan request.
@@ -220,6 +351,7 @@ func StorageScan(ctx context.Context, rpcClient UnaryInvoker, req *StorageScanRe
 				// NVMe meta option will populate usage statistics
 				Meta:  req.NvmeMeta || req.Usage,
 				Basic: req.NvmeBasic,
+				Force: req.NvmeForce,
 			},
 		})
 	})
@@ -283,14 +415,14 @@ func (spr *StoragePrepareResp) addHostResponse(hr *HostResponse) (err error) {
 	}
 
 	hs := new(HostStorage)
-	if pbResp.GetNvme().GetState().GetStatus() != ctlpb.ResponseStatus_CTL_SUCCESS {
+	if !pbResp.GetNvme().GetState().IsSuccess() {
 		pbErr := pbResp.GetNvme().GetState().GetError()
 		if err := spr.addHostError(hr.Addr, errors.New(pbErr)); err != nil {
 			return err
 		}
 	}
 
-	if pbResp.GetScm().GetState().GetStatus() != ctlpb.ResponseStatus_CTL_SUCCESS {
+	if !pbResp.GetScm().GetState().IsSuccess() {
 		pbErr := pbResp.GetScm().GetState().GetError()
 		if err := spr.addHostError(hr.Addr, errors.New(pbErr)); err != nil {
 			return err
@@ -386,7 +518,7 @@ func (sfr *StorageFormatResp) addHostResponse(hr *HostResponse) (err error) {
 				PciAddr: nr.GetPciAddr(),
 			})
 		default:
-			if err := ctlStateToErr(nr.GetState()); err != nil {
+			if err := nr.GetState().AsError(); err != nil {
 				if err := sfr.addHostError(hr.Addr, err); err != nil {
 					return err
 				}
@@ -406,7 +538,7 @@ func (sfr *StorageFormatResp) addHostResponse(hr *HostResponse) (err error) {
 				Path: sr.GetMntpoint(),
 			})
 		default:
-			if err := ctlStateToErr(sr.GetState()); err != nil {
+			if err := sr.GetState().AsError(); err != nil {
 				if err := sfr.addHostError(hr.Addr, err); err != nil {
 					return err
 				}
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/common/proto"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
 	"github.com/daos-stack/daos/src/control/logging"
@@ -138,6 +139,158 @@ func TestControl_StorageMap(t *testing.T) {
 	}
 }
 
+func TestControl_MergeScanNvmeResps(t *testing.T) {
+	pbCtrlr := func(t *testing.T, ctrlr *storage.NvmeController) *ctlpb.NvmeController {
+		t.Helper()
+		pb := new(proto.NvmeController)
+		if err := pb.FromNative(ctrlr); err != nil {
+			t.Fatal(err)
+		}
+		return pb.AsProto()
+	}
+	withHealth := func(idx int32, health *storage.NvmeHealth) *storage.NvmeController {
+		c := storage.MockNvmeController(idx)
+		c.HealthStats = health
+		return c
+	}
+	withSmd := func(idx int32, devs ...*storage.SmdDevice) *storage.NvmeController {
+		c := storage.MockNvmeController(idx)
+		c.HealthStats = nil
+		c.SmdDevices = devs
+		return c
+	}
+	host1Health := &storage.NvmeHealth{Temperature: 300, MediaErrors: 1}
+	host2Health := &storage.NvmeHealth{Temperature: 310, TempWarn: true}
+	ctrlr1 := storage.MockNvmeController(1)
+	ctrlr2 := storage.MockNvmeController(2)
+	devA := storage.MockSmdDevice(ctrlr1.PciAddr, 1)
+	devA.UUID = "dev-a"
+	devB := storage.MockSmdDevice(ctrlr1.PciAddr, 2)
+	devB.UUID = "dev-b"
+
+	type hostResp struct {
+		host   string
+		ctrlrs storage.NvmeControllers
+		state  *ctlpb.ResponseState
+	}
+
+	for name, tc := range map[string]struct {
+		resps    []hostResp
+		expMap   HostNvmeScanMap
+		expHosts []string
+		expErr   error
+	}{
+		"empty host address": {
+			resps: []hostResp{
+				{ctrlrs: storage.NvmeControllers{ctrlr1}},
+			},
+			expErr: errors.New("empty host address"),
+		},
+		"failed scan": {
+			resps: []hostResp{
+				{
+					host: "host1",
+					state: &ctlpb.ResponseState{
+						Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+						Error:  "spdk borked",
+					},
+				},
+			},
+			expErr: errors.New("nvme scan failed on host1: spdk borked"),
+		},
+		"two hosts": {
+			resps: []hostResp{
+				{
+					host:   "host1",
+					ctrlrs: storage.NvmeControllers{ctrlr2, withHealth(1, host1Health)},
+				},
+				{
+					host:   "host2",
+					ctrlrs: storage.NvmeControllers{withHealth(1, host2Health)},
+				},
+			},
+			expMap: HostNvmeScanMap{
+				"host1": {withHealth(1, host1Health), ctrlr2},
+				"host2": {withHealth(1, host2Health)},
+			},
+			expHosts: []string{"host1", "host2"},
+		},
+		"same controller reported twice by host": {
+			resps: []hostResp{
+				{
+					host:   "host1",
+					ctrlrs: storage.NvmeControllers{withSmd(1, devA)},
+				},
+				{
+					host:   "host1",
+					ctrlrs: storage.NvmeControllers{withHealth(1, host1Health), withSmd(1, devA, devB)},
+				},
+			},
+			expMap: HostNvmeScanMap{
+				"host1": {
+					func() *storage.NvmeController {
+						c := withSmd(1, devA, storage.MockSmdDevice(ctrlr1.PciAddr, 1), devB)
+						c.HealthStats = host1Health
+						return c
+					}(),
+				},
+			},
+			expHosts: []string{"host1"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotMap := make(HostNvmeScanMap)
+			var gotErr error
+			for _, hr := range tc.resps {
+				resp := &ctlpb.ScanNvmeResp{State: hr.state}
+				for _, c := range hr.ctrlrs {
+					resp.Ctrlrs = append(resp.Ctrlrs, pbCtrlr(t, c))
+				}
+				if gotErr = gotMap.Add(hr.host, resp); gotErr != nil {
+					break
+				}
+			}
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			cmpOpts := []cmp.Option{storage.NvmeControllerCmpOpt("Serial")}
+			if diff := cmp.Diff(tc.expMap, gotMap, cmpOpts...); diff != "" {
+				t.Fatalf("unexpected merged scan (-want, +got):\n%s\n", diff)
+			}
+			common.AssertStringsEqual(t, tc.expHosts, gotMap.Hosts(), "unexpected hosts")
+
+			var expTagged []*HostNvmeController
+			for _, host := range tc.expHosts {
+				for _, c := range tc.expMap[host] {
+					expTagged = append(expTagged, &HostNvmeController{Host: host, Controller: c})
+				}
+			}
+			if diff := cmp.Diff(expTagged, gotMap.Controllers(), cmpOpts...); diff != "" {
+				t.Fatalf("unexpected tagged controllers (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+
+	t.Run("merge map of responses", func(t *testing.T) {
+		gotMap, err := MergeScanNvmeResps(map[string]*ctlpb.ScanNvmeResp{
+			"host1": {Ctrlrs: []*ctlpb.NvmeController{pbCtrlr(t, withHealth(1, host1Health))}},
+			"host2": {Ctrlrs: []*ctlpb.NvmeController{pbCtrlr(t, withHealth(1, host2Health))}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		common.AssertEqual(t, host1Health.Temperature, gotMap["host1"][0].HealthStats.Temperature,
+			"host1 health")
+		common.AssertEqual(t, host2Health.Temperature, gotMap["host2"][0].HealthStats.Temperature,
+			"host2 health")
+
+		_, err = MergeScanNvmeResps(map[string]*ctlpb.ScanNvmeResp{"host1": nil})
+		common.CmpErr(t, errors.New("nil nvme scan response"), err)
+	})
+}
+
 func TestControl_StorageScan(t *testing.T) {
 	var (
 		standard       = MockServerScanResp(t, "standard")
@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/json"
 	"net"
+	"sort"
 	"strings"
 	"time"
 
@@ -44,8 +45,9 @@ var (
 )
 
 type sysRequest struct {
-	Ranks system.RankSet
-	Hosts hostlist.HostSet
+	Ranks       system.RankSet
+	Hosts       hostlist.HostSet
+	StrictRanks bool // Fail if any requested ranks are not system members.
 }
 
 type sysResponse struct {
@@ -213,6 +215,7 @@ func SystemQuery(ctx context.Context, rpcClient UnaryInvoker, req *SystemQueryRe
 	pbReq := new(mgmtpb.SystemQueryReq)
 	pbReq.Hosts = req.Hosts.String()
 	pbReq.Ranks = req.Ranks.String()
+	pbReq.StrictRanks = req.StrictRanks
 	pbReq.Sys = req.getSystem(rpcClient)
 
 	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
@@ -313,6 +316,7 @@ func SystemStart(ctx context.Context, rpcClient UnaryInvoker, req *SystemStartRe
 	pbReq := new(mgmtpb.SystemStartReq)
 	pbReq.Hosts = req.Hosts.String()
 	pbReq.Ranks = req.Ranks.String()
+	pbReq.StrictRanks = req.StrictRanks
 	pbReq.Sys = req.getSystem(rpcClient)
 
 	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
@@ -386,6 +390,7 @@ func SystemStop(ctx context.Context, rpcClient UnaryInvoker, req *SystemStopReq)
 	pbReq := new(mgmtpb.SystemStopReq)
 	pbReq.Hosts = req.Hosts.String()
 	pbReq.Ranks = req.Ranks.String()
+	pbReq.StrictRanks = req.StrictRanks
 	pbReq.Prep = req.Prep
 	pbReq.Kill = req.Kill
 	pbReq.Force = req.Force
@@ -622,6 +627,7 @@ type RanksReq struct {
 type RanksResp struct {
 	HostErrorsResp // record unresponsive hosts
 	RankResults    system.MemberResults
+	NoRanksHosts   *hostlist.HostSet // hosts with none of the requested ranks
 }
 
 // addHostResponse is responsible for validating the given HostResponse
@@ -642,6 +648,15 @@ func (srr *RanksResp) addHostResponse(hr *HostResponse) (err error) {
 
 	srr.RankResults = append(srr.RankResults, memberResults...)
 
+	nlr, ok := hr.Message.(interface{ GetNoLocalRanks() bool })
+	if !ok || !nlr.GetNoLocalRanks() {
+		return
+	}
+	if srr.NoRanksHosts == nil {
+		srr.NoRanksHosts = new(hostlist.HostSet)
+	}
+	_, err = srr.NoRanksHosts.Insert(hr.Addr)
+
 	return
 }
 
@@ -755,6 +770,27 @@ func StartRanks(ctx context.Context, rpcClient UnaryInvoker, req *RanksReq) (*Ra
 	return invokeRPCFanout(ctx, rpcClient, req)
 }
 
+// RestartRanks concurrently performs restart ranks across all hosts
+// supplied in the request's hostlist.
+//
+// Each rank is stopped, escalating to a forced stop if it fails to exit
+// gracefully, and then started again. Blocks until all results (successful or
+// otherwise) are received after invoking fan-out. Returns a single response
+// structure containing results generated with request responses from each
+// selected rank.
+func RestartRanks(ctx context.Context, rpcClient UnaryInvoker, req *RanksReq) (*RanksResp, error) {
+	pbReq := new(ctlpb.RanksReq)
+	if err := convert.Types(req, pbReq); err != nil {
+		return nil, errors.Wrapf(err, "convert request type %T->%T", req, pbReq)
+	}
+	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
+		return ctlpb.NewCtlSvcClient(conn).RestartRanks(ctx, pbReq)
+	})
+	rpcClient.Debugf("DAOS system restart-ranks request: %+v", req)
+
+	return invokeRPCFanout(ctx, rpcClient, req)
+}
+
 // PingRanks concurrently performs ping on ranks across all hosts
 // supplied in the request's hostlist.
 //
@@ -775,3 +811,87 @@ func PingRanks(ctx context.Context, rpcClient UnaryInvoker, req *RanksReq) (*Ran
 
 	return invokeRPCFanout(ctx, rpcClient, req)
 }
+
+// RankReadiness describes whether a rank has completed startup and is able to
+// service requests, as distinct from whether the rank process is alive.
+type RankReadiness struct {
+	Rank      system.Rank `json:"rank"`
+	Alive     bool        `json:"alive"`
+	Formatted bool        `json:"formatted"`
+	Ready     bool        `json:"ready"`
+	State     string      `json:"state"`
+	Msg       string      `json:"msg"`
+}
+
+// ProbeRanksResp contains the response from a system probe ranks request.
+type ProbeRanksResp struct {
+	HostErrorsResp // record unresponsive hosts
+	Results        []*RankReadiness
+	NoRanksHosts   *hostlist.HostSet // hosts with none of the requested ranks
+}
+
+// addHostResponse is responsible for validating the given HostResponse
+// and adding its results to the ProbeRanksResp.
+func (prr *ProbeRanksResp) addHostResponse(hr *HostResponse) error {
+	pbResp, ok := hr.Message.(*ctlpb.ProbeRanksResp)
+	if !ok {
+		return errors.Errorf("unable to unpack message: %+v", hr.Message)
+	}
+
+	var results []*RankReadiness
+	if err := convert.Types(pbResp.GetResults(), &results); err != nil {
+		return prr.addHostError(hr.Addr, err)
+	}
+	prr.Results = append(prr.Results, results...)
+
+	if !pbResp.GetNoLocalRanks() {
+		return nil
+	}
+	if prr.NoRanksHosts == nil {
+		prr.NoRanksHosts = new(hostlist.HostSet)
+	}
+	_, err := prr.NoRanksHosts.Insert(hr.Addr)
+
+	return err
+}
+
+// ProbeRanks concurrently probes the readiness of ranks across all hosts
+// supplied in the request's hostlist.
+//
+// Unlike PingRanks, which reports whether ranks are responsive, the results
+// indicate whether each rank has formatted storage and has completed startup
+// so that callers can wait for ranks to become ready after StartRanks.
+func ProbeRanks(ctx context.Context, rpcClient UnaryInvoker, req *RanksReq) (*ProbeRanksResp, error) {
+	pbReq := new(ctlpb.RanksReq)
+	if err := convert.Types(req, pbReq); err != nil {
+		return nil, errors.Wrapf(err, "convert request type %T->%T", req, pbReq)
+	}
+	req.setRPC(func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error) {
+		return ctlpb.NewCtlSvcClient(conn).ProbeRanks(ctx, pbReq)
+	})
+	rpcClient.Debugf("DAOS system probe-ranks request: %+v", req)
+
+	ur, err := rpcClient.InvokeUnaryRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(ProbeRanksResp)
+	for _, hostResp := range ur.Responses {
+		if hostResp.Error != nil {
+			if err := resp.addHostError(hostResp.Addr, hostResp.Error); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := resp.addHostResponse(hostResp); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(resp.Results, func(i, j int) bool {
+		return resp.Results[i].Rank < resp.Results[j].Rank
+	})
+
+	return resp, nil
+}
@@ -360,6 +360,35 @@ func TestControl_PingRanks(t *testing.T) {
 			},
 			expResp: &RanksResp{},
 		},
+		"no local ranks": {
+			uResps: []*HostResponse{
+				{
+					Addr:    "host1",
+					Message: &ctlpb.RanksResp{NoLocalRanks: true},
+				},
+				{
+					Addr: "host2",
+					Message: &ctlpb.RanksResp{
+						Results: []*sharedpb.RankResult{
+							{
+								Rank: 2, Action: "ping",
+								State: system.MemberStateReady.String(),
+							},
+						},
+					},
+				},
+				{
+					Addr:    "host3",
+					Message: &ctlpb.RanksResp{NoLocalRanks: true},
+				},
+			},
+			expResp: &RanksResp{
+				RankResults: system.MemberResults{
+					{Rank: 2, Action: "ping", State: system.MemberStateReady},
+				},
+				NoRanksHosts: hostlist.MustCreateSet("host[1,3]"),
+			},
+		},
 		"mixed results": {
 			uResps: []*HostResponse{
 				{
@@ -431,6 +460,118 @@ func TestControl_PingRanks(t *testing.T) {
 	}
 }
 
+func TestControl_ProbeRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		uErr    error
+		uResps  []*HostResponse
+		expResp *ProbeRanksResp
+		expErr  error
+	}{
+		"local failure": {
+			uErr:   errors.New("local failed"),
+			expErr: errors.New("local failed"),
+		},
+		"remote failure": {
+			uResps: []*HostResponse{
+				{
+					Addr:  "host1",
+					Error: errors.New("remote failed"),
+				},
+			},
+			expResp: &ProbeRanksResp{
+				HostErrorsResp: MockHostErrorsResp(t, &MockHostError{"host1", "remote failed"}),
+			},
+		},
+		"bad message": {
+			uResps: []*HostResponse{
+				{
+					Addr:    "host1",
+					Message: &ctlpb.RanksResp{},
+				},
+			},
+			expErr: errors.New("unable to unpack message"),
+		},
+		"no local ranks": {
+			uResps: []*HostResponse{
+				{
+					Addr:    "host1",
+					Message: &ctlpb.ProbeRanksResp{NoLocalRanks: true},
+				},
+			},
+			expResp: &ProbeRanksResp{
+				NoRanksHosts: hostlist.MustCreateSet("host1"),
+			},
+		},
+		"mixed readiness": {
+			uResps: []*HostResponse{
+				{
+					Addr: "host2",
+					Message: &ctlpb.ProbeRanksResp{
+						Results: []*ctlpb.RankReadiness{
+							{
+								Rank: 3, Alive: true, Formatted: true,
+								State: "starting", Msg: "engine not ready",
+							},
+							{
+								Rank: 2, Alive: true, Formatted: true,
+								Ready: true, State: "ready",
+							},
+						},
+					},
+				},
+				{
+					Addr: "host1",
+					Message: &ctlpb.ProbeRanksResp{
+						Results: []*ctlpb.RankReadiness{
+							{
+								Rank: 0, Alive: true, Formatted: true,
+								Ready: true, State: "ready",
+							},
+							{
+								Rank: 1, State: "awaitformat",
+								Msg: "storage not formatted",
+							},
+						},
+					},
+				},
+				{
+					Addr:  "host3",
+					Error: errors.New("connection refused"),
+				},
+			},
+			expResp: &ProbeRanksResp{
+				Results: []*RankReadiness{
+					{Rank: 0, Alive: true, Formatted: true, Ready: true, State: "ready"},
+					{Rank: 1, State: "awaitformat", Msg: "storage not formatted"},
+					{Rank: 2, Alive: true, Formatted: true, Ready: true, State: "ready"},
+					{Rank: 3, Alive: true, Formatted: true, State: "starting", Msg: "engine not ready"},
+				},
+				HostErrorsResp: MockHostErrorsResp(t, &MockHostError{"host3", "connection refused"}),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mi := NewMockInvoker(log, &MockInvokerConfig{
+				UnaryError:    tc.uErr,
+				UnaryResponse: &UnaryResponse{Responses: tc.uResps},
+			})
+
+			gotResp, gotErr := ProbeRanks(context.TODO(), mi, &RanksReq{Ranks: "0-3"})
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp, defResCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected results (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
 func TestControl_getResetRankErrors(t *testing.T) {
 	for name, tc := range map[string]struct {
 		results     system.MemberResults
@@ -21,12 +21,13 @@ nvme_discover(void);
  *
  * Removes any data container structures e.g. blobstore.
  *
- * \param ctrlr_pci_addr PCI address of NVMe controller.
+ * \param skip_blobstores Leave namespaces that already hold a blobstore
+ *			  untouched and mark their results as skipped.
  *
  * \return a pointer to a return struct (ret_t).
  */
 struct ret_t *
-nvme_wipe_namespaces(void);
+nvme_wipe_namespaces(bool skip_blobstores);
 
 /**
  * Format NVMe controller namespace.
@@ -50,4 +51,27 @@ nvme_format(char *ctrlr_pci_addr);
 struct ret_t *
 nvme_fwupdate(char *ctrlr_pci_addr, char *path, unsigned int slot);
 
+/**
+ * Start a device self-test operation on NVMe controller.
+ *
+ * \param ctrlr_pci_addr PCI address of NVMe controller.
+ * \param code Self-test code, 1 for short and 2 for extended operation.
+ *
+ * \return a pointer to a return struct (ret_t).
+ */
+struct ret_t *
+nvme_self_test(char *ctrlr_pci_addr, unsigned int code);
+
+/**
+ * Read the device self-test log page of NVMe controller.
+ *
+ * \param ctrlr_pci_addr PCI address of NVMe controller.
+ * \param buf Buffer to copy the log page into.
+ * \param len Length of buffer in bytes.
+ *
+ * \return a pointer to a return struct (ret_t).
+ */
+struct ret_t *
+nvme_self_test_log(char *ctrlr_pci_addr, void *buf, size_t len);
+
 #endif
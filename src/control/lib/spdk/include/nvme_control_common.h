@@ -61,7 +61,8 @@ struct ns_t {
 
 /**
  * \brief Result struct for namespace wipe operation containing return code,
- * namespace id, parent controller pci address, info message and link to next
+ * namespace id, parent controller pci address, info message, whether the
+ * namespace was skipped because it already holds a blobstore and link to next
  * list element.
  */
 struct wipe_res_t {
@@ -69,6 +70,7 @@ struct wipe_res_t {
 	uint32_t		 ns_id;
 	int			 rc;
 	char			 info[BUFLEN];
+	bool			 skipped;
 	struct wipe_res_t	*next;
 };
 
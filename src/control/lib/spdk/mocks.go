@@ -40,6 +40,9 @@ type MockNvmeCfg struct {
 	FormatRes      []*FormatResult
 	FormatErr      error
 	UpdateErr      error
+	SelfTestErr    error
+	SelfTestStatus *storage.NvmeSelfTestStatus
+	SelfTestLogErr error
 }
 
 // MockNvmeImpl is an implementation of the Nvme interface.
@@ -66,18 +69,23 @@ func (n *MockNvmeImpl) Discover(log logging.Logger) (storage.NvmeControllers, er
 }
 
 // Format device at given pci address, destructive operation!
-func (n *MockNvmeImpl) Format(log logging.Logger) ([]*FormatResult, error) {
-	log.Debug("mock format nvme ssds")
+//
+// Results in FormatRes are only reported as skipped if skipFormatted is set.
+func (n *MockNvmeImpl) Format(log logging.Logger, skipFormatted bool) ([]*FormatResult, error) {
+	log.Debugf("mock format nvme ssds (skip formatted: %t)", skipFormatted)
 
 	if n.Cfg.FormatErr != nil {
 		return nil, n.Cfg.FormatErr
 	}
 
-	if n.Cfg.FormatRes == nil {
-		return make([]*FormatResult, 0), nil
+	results := make([]*FormatResult, 0, len(n.Cfg.FormatRes))
+	for _, res := range n.Cfg.FormatRes {
+		r := *res
+		r.Skipped = res.Skipped && skipFormatted
+		results = append(results, &r)
 	}
 
-	return n.Cfg.FormatRes, nil
+	return results, nil
 }
 
 // Update calls C.nvme_fwupdate to update controller firmware image.
@@ -90,3 +98,30 @@ func (n *MockNvmeImpl) Update(log logging.Logger, ctrlrPciAddr string, path stri
 
 	return nil
 }
+
+// SelfTest calls C.nvme_self_test to start a device self-test.
+func (n *MockNvmeImpl) SelfTest(log logging.Logger, ctrlrPciAddr string, code storage.NvmeSelfTestCode) error {
+	if n.Cfg.SelfTestErr != nil {
+		return n.Cfg.SelfTestErr
+	}
+	log.Debugf("mock start %s self-test on nvme ssd: %q", code, ctrlrPciAddr)
+
+	return nil
+}
+
+// SelfTestLog calls C.nvme_self_test_log to read the device self-test log.
+func (n *MockNvmeImpl) SelfTestLog(log logging.Logger, ctrlrPciAddr string) (*storage.NvmeSelfTestStatus, error) {
+	if n.Cfg.SelfTestLogErr != nil {
+		return nil, n.Cfg.SelfTestLogErr
+	}
+	log.Debugf("mock read self-test log on nvme ssd: %q", ctrlrPciAddr)
+
+	if n.Cfg.SelfTestStatus == nil {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    ctrlrPciAddr,
+			LastResult: storage.NvmeSelfTestNoResult,
+		}, nil
+	}
+
+	return n.Cfg.SelfTestStatus, nil
+}
@@ -39,12 +39,17 @@ const lockfilePathPrefix = "/tmp/spdk_pci_lock_"
 type Nvme interface {
 	// Discover NVMe controllers and namespaces, and device health info
 	Discover(logging.Logger) (storage.NvmeControllers, error)
-	// Format NVMe controller namespaces
-	Format(logging.Logger) ([]*FormatResult, error)
+	// Format NVMe controller namespaces, optionally skipping namespaces
+	// that already hold a blobstore
+	Format(logging.Logger, bool) ([]*FormatResult, error)
 	// CleanLockfiles removes SPDK lockfiles for specific PCI addresses
 	CleanLockfiles(logging.Logger, ...string) error
 	// Update updates the firmware on a specific PCI address and slot
 	Update(log logging.Logger, ctrlrPciAddr string, path string, slot int32) error
+	// SelfTest starts a device self-test on a specific PCI address
+	SelfTest(log logging.Logger, ctrlrPciAddr string, code storage.NvmeSelfTestCode) error
+	// SelfTestLog reads the device self-test log of a specific PCI address
+	SelfTestLog(log logging.Logger, ctrlrPciAddr string) (*storage.NvmeSelfTestStatus, error)
 }
 
 // NvmeImpl is an implementation of the Nvme interface.
@@ -57,6 +62,7 @@ type FormatResult struct {
 	CtrlrPCIAddr string
 	NsID         uint32
 	Err          error
+	Skipped      bool // namespace already holds a blobstore
 }
 
 type remFunc func(name string) error
@@ -130,9 +136,11 @@ func (n *NvmeImpl) Discover(log logging.Logger) (storage.NvmeControllers, error)
 
 // Format devices available through SPDK, destructive operation!
 //
-// Attempt wipe of each controller namespace's LBA-0.
-func (n *NvmeImpl) Format(log logging.Logger) ([]*FormatResult, error) {
-	return collectFormatResults(C.nvme_wipe_namespaces(),
+// Attempt wipe of each controller namespace's LBA-0. If skipFormatted is set,
+// namespaces that already hold a blobstore are left untouched and their
+// results are marked as skipped.
+func (n *NvmeImpl) Format(log logging.Logger, skipFormatted bool) ([]*FormatResult, error) {
+	return collectFormatResults(C.nvme_wipe_namespaces(C.bool(skipFormatted)),
 		"NVMe Format(): C.nvme_wipe_namespaces()")
 }
 
@@ -150,6 +158,46 @@ func (n *NvmeImpl) Update(log logging.Logger, ctrlrPciAddr string, path string,
 	return wrapCleanError(err, n.CleanLockfiles(log, ctrlrPciAddr))
 }
 
+// SelfTest starts a device self-test operation of the given type via SPDK on
+// the device. The test runs in the background on the device and its progress
+// can be followed by reading the device self-test log.
+func (n *NvmeImpl) SelfTest(log logging.Logger, ctrlrPciAddr string, code storage.NvmeSelfTestCode) error {
+	csPci := C.CString(ctrlrPciAddr)
+	defer C.free(unsafe.Pointer(csPci))
+
+	_, err := collectCtrlrs(C.nvme_self_test(csPci, C.uint(code)),
+		"NVMe SelfTest(): C.nvme_self_test")
+
+	return wrapCleanError(err, n.CleanLockfiles(log, ctrlrPciAddr))
+}
+
+// SelfTestLog reads and decodes the device self-test log page via SPDK from
+// the device.
+func (n *NvmeImpl) SelfTestLog(log logging.Logger, ctrlrPciAddr string) (*storage.NvmeSelfTestStatus, error) {
+	csPci := C.CString(ctrlrPciAddr)
+	defer C.free(unsafe.Pointer(csPci))
+
+	buf := C.calloc(1, C.size_t(storage.NvmeSelfTestLogSize))
+	if buf == nil {
+		return nil, errors.New("NVMe SelfTestLog(): failed to allocate log page buffer")
+	}
+	defer C.free(buf)
+
+	_, err := collectCtrlrs(C.nvme_self_test_log(csPci, buf, C.size_t(storage.NvmeSelfTestLogSize)),
+		"NVMe SelfTestLog(): C.nvme_self_test_log")
+	if err := wrapCleanError(err, n.CleanLockfiles(log, ctrlrPciAddr)); err != nil {
+		return nil, err
+	}
+
+	status, err := storage.ParseNvmeSelfTestLog(C.GoBytes(buf, C.int(storage.NvmeSelfTestLogSize)))
+	if err != nil {
+		return nil, err
+	}
+	status.PciAddr = ctrlrPciAddr
+
+	return status, nil
+}
+
 // c2GoController is a private translation function.
 func c2GoController(ctrlr *C.struct_ctrlr_t) *storage.NvmeController {
 	return &storage.NvmeController{
@@ -173,6 +221,7 @@ func c2GoDeviceHealth(health *C.struct_nvme_stats) *storage.NvmeHealth {
 		MediaErrors:     uint64(health.media_errs),
 		ErrorLogEntries: uint64(health.err_log_entries),
 		Temperature:     uint32(health.temperature),
+		AvailSpare:      uint32(health.avail_spare),
 		TempWarn:        bool(health.temp_warn),
 		AvailSpareWarn:  bool(health.avail_spare_warn),
 		ReliabilityWarn: bool(health.dev_reliability_warn),
@@ -200,6 +249,7 @@ func c2GoFormatResult(fmtResult *C.struct_wipe_res_t) *FormatResult {
 		CtrlrPCIAddr: C.GoString(&fmtResult.ctrlr_pci_addr[0]),
 		NsID:         uint32(fmtResult.ns_id),
 		Err:          err,
+		Skipped:      bool(fmtResult.skipped),
 	}
 }
 
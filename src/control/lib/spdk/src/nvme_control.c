@@ -11,18 +11,21 @@
 #include "nvme_control.h"
 #include "nvme_control_common.h"
 
-enum lba0_write_result {
-	LBA0_WRITE_PENDING	= 0x0,
-	LBA0_WRITE_SUCCESS	= 0x1,
-	LBA0_WRITE_FAIL		= 0x2,
+enum lba0_io_result {
+	LBA0_IO_PENDING		= 0x0,
+	LBA0_IO_SUCCESS		= 0x1,
+	LBA0_IO_FAIL		= 0x2,
 };
 
 /** data structure passed to NVMe cmd completion */
 struct lba0_data {
 	struct ns_entry		*ns_entry;
-	enum lba0_write_result	 result;
+	enum lba0_io_result	 result;
 };
 
+/** signature at the start of the super block of an SPDK blobstore */
+#define BLOBSTORE_SIGNATURE	"SPDKBLOB"
+
 static void
 get_spdk_log_page_completion(void *cb_arg, const struct spdk_nvme_cpl *cpl)
 {
@@ -77,17 +80,73 @@ write_complete(void *arg, const struct spdk_nvme_cpl *completion)
 	struct lba0_data *data = arg;
 
 	if (spdk_nvme_cpl_is_success(completion)) {
-		data->result = LBA0_WRITE_SUCCESS;
+		data->result = LBA0_IO_SUCCESS;
 	} else {
 		fprintf(stderr, "I/O error status: %s\n",
 			spdk_nvme_cpl_get_status_string(&completion->status));
 		fprintf(stderr, "Write I/O failed, aborting run\n");
-		data->result = LBA0_WRITE_FAIL;
+		data->result = LBA0_IO_FAIL;
 	}
 }
 
+/** callback for read command completion when checking for a blobstore */
+static void
+read_complete(void *arg, const struct spdk_nvme_cpl *completion)
+{
+	struct lba0_data *data = arg;
+
+	if (spdk_nvme_cpl_is_success(completion)) {
+		data->result = LBA0_IO_SUCCESS;
+	} else {
+		fprintf(stderr, "I/O error status: %s\n",
+			spdk_nvme_cpl_get_status_string(&completion->status));
+		fprintf(stderr, "Read I/O failed\n");
+		data->result = LBA0_IO_FAIL;
+	}
+}
+
+/**
+ * Read the first 4K block of a ns into buf and report whether it holds the
+ * super block of an SPDK blobstore. The buffer is zeroed again afterwards so
+ * that it can be used to wipe the ns.
+ */
+static int
+has_blobstore(struct ns_entry *nentry, struct spdk_nvme_qpair *qpair,
+	      char *buf, uint32_t sector_size, bool *found)
+{
+	struct lba0_data	data;
+	int			rc;
+
+	data.result = LBA0_IO_PENDING;
+	data.ns_entry = nentry;
+
+	rc = spdk_nvme_ns_cmd_read(nentry->ns, qpair,
+				   buf, 0 /** LBA start */,
+				   4096 / sector_size /** #LBAS */,
+				   read_complete, &data, 0);
+	if (rc != 0)
+		return rc;
+
+	/** wait for command completion */
+	while (data.result == LBA0_IO_PENDING) {
+		rc = spdk_nvme_qpair_process_completions(qpair, 0);
+		if (rc < 0)
+			return rc;
+	}
+
+	if (data.result != LBA0_IO_SUCCESS)
+		return -1;
+
+	*found = (memcmp(buf, BLOBSTORE_SIGNATURE,
+			 strlen(BLOBSTORE_SIGNATURE)) == 0);
+	memset(buf, 0, 4096);
+
+	return 0;
+}
+
 static struct wipe_res_t *
-wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry)
+wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry,
+	   bool skip_blobstores)
 {
 	struct lba0_data	 data;
 	struct wipe_res_t	*res = NULL, *tmp = NULL;
@@ -142,7 +201,26 @@ wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry)
 		res->ns_id = spdk_nvme_ns_get_id(nentry->ns);
 		sector_size = spdk_nvme_ns_get_sector_size(nentry->ns);
 
-		data.result = LBA0_WRITE_PENDING;
+		/** leave namespaces already holding a blobstore untouched */
+		if (skip_blobstores) {
+			bool found = false;
+
+			rc = has_blobstore(nentry, qpair, buf, sector_size,
+					   &found);
+			if (rc != 0) {
+				snprintf(res->info, sizeof(res->info),
+					 "checking for blobstore (%d)\n", rc);
+				res->rc = -1;
+				break;
+			}
+			if (found) {
+				res->skipped = true;
+				nentry = nentry->next;
+				continue;
+			}
+		}
+
+		data.result = LBA0_IO_PENDING;
 		data.ns_entry = nentry;
 
 		/** zero out the first 4K block */
@@ -158,7 +236,7 @@ wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry)
 		}
 
 		/** wait for command completion */
-		while (data.result == LBA0_WRITE_PENDING) {
+		while (data.result == LBA0_IO_PENDING) {
 			rc = spdk_nvme_qpair_process_completions(qpair, 0);
 			if (rc < 0) {
 				fprintf(stderr,
@@ -168,7 +246,7 @@ wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry)
 		}
 
 		/** check command result */
-		if (data.result != LBA0_WRITE_SUCCESS) {
+		if (data.result != LBA0_IO_SUCCESS) {
 			snprintf(res->info, sizeof(res->info),
 				 "spdk_nvme_ns_cmd_write() failed\n");
 			res->rc = -1;
@@ -185,13 +263,14 @@ wipe_ctrlr(struct ctrlr_entry *centry, struct ns_entry *nentry)
 }
 
 static struct wipe_res_t *
-wipe_ctrlrs(void)
+wipe_ctrlrs(bool skip_blobstores)
 {
 	struct ctrlr_entry	*centry = g_controllers;
 	struct wipe_res_t	*start = NULL, *end = NULL;
 
 	while (centry != NULL) {
-		struct wipe_res_t *results = wipe_ctrlr(centry, centry->nss);
+		struct wipe_res_t *results = wipe_ctrlr(centry, centry->nss,
+							skip_blobstores);
 		struct wipe_res_t *tmp = results;
 
 		if (results == NULL) {
@@ -217,7 +296,7 @@ wipe_ctrlrs(void)
 }
 
 struct ret_t *
-nvme_wipe_namespaces(void)
+nvme_wipe_namespaces(bool skip_blobstores)
 {
 	struct ret_t	*ret = init_ret();
 	int		 rc;
@@ -246,7 +325,7 @@ nvme_wipe_namespaces(void)
 		return ret;
 	}
 
-	ret->wipe_results = wipe_ctrlrs();
+	ret->wipe_results = wipe_ctrlrs(skip_blobstores);
 	if (ret->wipe_results == NULL) {
 		snprintf(ret->info, sizeof(ret->info),
 			 "no namespaces on controller\n");
@@ -394,3 +473,114 @@ nvme_fwupdate(char *ctrlr_pci_addr, char *path, unsigned int slot)
 	ret->rc = rc;
 	return ret;
 }
+
+/** data structure passed to admin cmd completion */
+struct admin_cmd_data {
+	int	inflight;
+	int	rc;
+};
+
+static void
+admin_cmd_completion(void *cb_arg, const struct spdk_nvme_cpl *cpl)
+{
+	struct admin_cmd_data *data = cb_arg;
+
+	if (spdk_nvme_cpl_is_error(cpl)) {
+		fprintf(stderr, "Admin command error status: %s\n",
+			spdk_nvme_cpl_get_status_string(&cpl->status));
+		data->rc = -EIO;
+	}
+
+	data->inflight--;
+}
+
+struct ret_t *
+nvme_self_test(char *ctrlr_pci_addr, unsigned int code)
+{
+	const struct spdk_nvme_ctrlr_data	*cdata;
+	struct spdk_nvme_cmd			 cmd = {};
+	struct admin_cmd_data			 data = {};
+	struct ctrlr_entry			*ctrlr_entry;
+	struct ret_t				*ret;
+
+	ret = init_ret();
+
+	ret->rc = get_controller(&ctrlr_entry, ctrlr_pci_addr);
+	if (ret->rc != 0)
+		return ret;
+
+	cdata = spdk_nvme_ctrlr_get_data(ctrlr_entry->ctrlr);
+	if (!cdata->oacs.device_self_test) {
+		snprintf(ret->info, sizeof(ret->info),
+			 "controller does not support device self-test command");
+		ret->rc = -NVMEC_ERR_NOT_SUPPORTED;
+		return ret;
+	}
+
+	cmd.opc = SPDK_NVME_OPC_DEVICE_SELF_TEST;
+	cmd.nsid = SPDK_NVME_GLOBAL_NS_TAG;
+	cmd.cdw10 = code;
+
+	data.inflight++;
+	ret->rc = spdk_nvme_ctrlr_cmd_admin_raw(ctrlr_entry->ctrlr, &cmd, NULL,
+						0, admin_cmd_completion, &data);
+	if (ret->rc != 0) {
+		snprintf(ret->info, sizeof(ret->info),
+			 "failed to submit device self-test command");
+		return ret;
+	}
+
+	while (data.inflight)
+		spdk_nvme_ctrlr_process_admin_completions(ctrlr_entry->ctrlr);
+
+	ret->rc = data.rc;
+	if (ret->rc != 0)
+		snprintf(ret->info, sizeof(ret->info),
+			 "device self-test command failed");
+
+	return ret;
+}
+
+struct ret_t *
+nvme_self_test_log(char *ctrlr_pci_addr, void *buf, size_t len)
+{
+	struct admin_cmd_data	 data = {};
+	struct ctrlr_entry	*ctrlr_entry;
+	struct ret_t		*ret;
+
+	ret = init_ret();
+
+	ret->rc = get_controller(&ctrlr_entry, ctrlr_pci_addr);
+	if (ret->rc != 0)
+		return ret;
+
+	if (!spdk_nvme_ctrlr_is_log_page_supported(ctrlr_entry->ctrlr,
+					SPDK_NVME_LOG_DEVICE_SELF_TEST)) {
+		snprintf(ret->info, sizeof(ret->info),
+			 "controller does not support device self-test log");
+		ret->rc = -NVMEC_ERR_NOT_SUPPORTED;
+		return ret;
+	}
+
+	data.inflight++;
+	ret->rc = spdk_nvme_ctrlr_cmd_get_log_page(ctrlr_entry->ctrlr,
+						   SPDK_NVME_LOG_DEVICE_SELF_TEST,
+						   SPDK_NVME_GLOBAL_NS_TAG,
+						   buf, len, 0,
+						   admin_cmd_completion, &data);
+	if (ret->rc != 0) {
+		snprintf(ret->info, sizeof(ret->info),
+			 "failed to submit get log page command");
+		return ret;
+	}
+
+	while (data.inflight)
+		spdk_nvme_ctrlr_process_admin_completions(ctrlr_entry->ctrlr);
+
+	ret->rc = data.rc;
+	if (ret->rc != 0)
+		snprintf(ret->info, sizeof(ret->info),
+			 "reading device self-test log failed");
+
+	return ret;
+}
@@ -288,6 +288,7 @@ populate_dev_health(struct nvme_stats *dev_state,
 	dev_state->media_errs = page->media_errors[0];
 	dev_state->err_log_entries = page->num_error_info_log_entries[0];
 	dev_state->temperature = page->temperature;
+	dev_state->avail_spare = page->available_spare;
 	dev_state->temp_warn = cw.bits.temperature ? true : false;
 	dev_state->avail_spare_warn = cw.bits.available_spare ?
 		true : false;
@@ -0,0 +1,199 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// DefaultSysvShmPath lists the System V shared memory segments on the host,
+// which include the telemetry segments created by d_tm_init().
+const DefaultSysvShmPath = "/proc/sysvipc/shm"
+
+const (
+	// segmentKeyBase mirrors D_TM_SHARED_MEMORY_KEY, telemetry segments
+	// are created at this key plus the index passed to d_tm_init().
+	segmentKeyBase = 0x10242048
+	// maxSegmentIdx bounds the range of keys treated as telemetry
+	// segments.
+	maxSegmentIdx = 1 << 16
+	// engineProcName is the process name of the DAOS engine, whose
+	// segments are not client segments.
+	engineProcName = "daos_engine"
+)
+
+// ClientSegment identifies the telemetry segment of a running client
+// process.
+type ClientSegment struct {
+	PID int    // PID of the process that created the segment
+	Idx uint32 // segment index, as passed to Init or OpenSource
+}
+
+// pidRunning is a variable so that process liveness can be mocked in tests.
+var pidRunning = func(pid int) bool {
+	// EPERM indicates the process exists but belongs to another user.
+	err := unix.Kill(pid, 0)
+	return err == nil || err == unix.EPERM
+}
+
+// procName is a variable so that process names can be mocked in tests.
+var procName = func(pid int) (string, error) {
+	comm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(comm)), nil
+}
+
+// effectiveIDs is a variable so that the caller's identity can be mocked in
+// tests.
+var effectiveIDs = func() (uid int, gid int) {
+	return os.Geteuid(), os.Getegid()
+}
+
+// shmReadable returns true if the caller may attach to a segment with the
+// given owner and permissions for reading.
+func shmReadable(uid, gid int, mode uint64) bool {
+	euid, egid := effectiveIDs()
+	switch {
+	case euid == 0:
+		return true
+	case euid == uid:
+		return mode&0400 != 0
+	case egid == gid:
+		return mode&0040 != 0
+	default:
+		return mode&0004 != 0
+	}
+}
+
+// parseShmLine returns the key, permissions, creator PID and owner of the
+// segment described by a line of the System V shared memory listing.
+func parseShmLine(line string) (key int64, mode uint64, cpid, uid, gid int, err error) {
+	// key shmid perms size cpid lpid nattch uid gid ...
+	fields := strings.Fields(line)
+	if len(fields) < 9 {
+		err = errors.Errorf("too few fields in %q", line)
+		return
+	}
+
+	if key, err = strconv.ParseInt(fields[0], 10, 32); err != nil {
+		return
+	}
+	if mode, err = strconv.ParseUint(fields[2], 8, 32); err != nil {
+		return
+	}
+	if cpid, err = strconv.Atoi(fields[4]); err != nil {
+		return
+	}
+	if uid, err = strconv.Atoi(fields[7]); err != nil {
+		return
+	}
+	gid, err = strconv.Atoi(fields[8])
+	return
+}
+
+// FindClientSegments reads the System V shared memory listing at path, see
+// DefaultSysvShmPath, and returns the telemetry segments created by running
+// client processes, sorted by PID and index.
+//
+// Segments created by engines, left behind by processes that have exited or
+// that the caller doesn't have permission to read are skipped. A missing
+// listing indicates that no segments exist.
+func FindClientSegments(path string) ([]*ClientSegment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*ClientSegment{}, nil
+		}
+		return nil, errors.Wrap(err, "reading shared memory segments")
+	}
+	defer f.Close()
+
+	segments := []*ClientSegment{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, mode, cpid, uid, gid, err := parseShmLine(scanner.Text())
+		if err != nil {
+			continue // header or malformed line
+		}
+
+		idx := key - segmentKeyBase
+		if idx < 0 || idx >= maxSegmentIdx || cpid <= 0 {
+			continue
+		}
+		if !pidRunning(cpid) || !shmReadable(uid, gid, mode) {
+			continue
+		}
+		name, err := procName(cpid)
+		if err != nil || name == engineProcName {
+			continue
+		}
+
+		segments = append(segments, &ClientSegment{PID: cpid, Idx: uint32(idx)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading shared memory segments")
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i].PID == segments[j].PID {
+			return segments[i].Idx < segments[j].Idx
+		}
+		return segments[i].PID < segments[j].PID
+	})
+
+	return segments, nil
+}
+
+// OpenClientSource attaches to the telemetry segment of the client process
+// with the given PID, see FindClientSegments for discovering running clients.
+// If the client has created more than one segment, the lowest index is used.
+// A function that detaches from the segment is returned along with the
+// Source.
+func OpenClientSource(parent context.Context, pid int) (Source, func(), error) {
+	if !pidRunning(pid) {
+		return nil, nil, errors.Errorf("client process %d is not running", pid)
+	}
+
+	segments, err := FindClientSegments(DefaultSysvShmPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var seg *ClientSegment
+	for _, s := range segments {
+		if s.PID == pid {
+			seg = s
+			break
+		}
+	}
+	if seg == nil {
+		return nil, nil, errors.Errorf("no telemetry segment found for client process %d", pid)
+	}
+
+	src, cleanup, err := OpenSource(parent, seg.Idx)
+	if err != nil {
+		// the process may have exited since it was discovered
+		if !pidRunning(pid) {
+			return nil, nil, errors.Errorf("client process %d exited", pid)
+		}
+		return nil, nil, errors.Wrapf(err, "client process %d", pid)
+	}
+
+	return src, cleanup, nil
+}
@@ -0,0 +1,188 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+const shmHeader = "       key      shmid perms                  size  cpid  lpid nattch   uid   gid  cuid  cgid      atime      dtime      ctime                   rss                  swap"
+
+// mockShmLine returns a System V shared memory listing line for a segment.
+func mockShmLine(key int64, perms string, cpid, uid, gid int) string {
+	return fmt.Sprintf("%10d %10d %5s %21d %5d %5d %6d %5d %5d %5d %5d %10d %10d %10d %21d %21d",
+		key, 32768, perms, 1048576, cpid, cpid, 1, uid, gid, uid, gid, 0, 0, 1625000000, 4096, 0)
+}
+
+func mockClientProcs(t *testing.T, running []int, names map[int]string, euid, egid int) func() {
+	t.Helper()
+
+	origRunning, origName, origIDs := pidRunning, procName, effectiveIDs
+	pidRunning = func(pid int) bool {
+		for _, r := range running {
+			if pid == r {
+				return true
+			}
+		}
+		return false
+	}
+	procName = func(pid int) (string, error) {
+		if name, found := names[pid]; found {
+			return name, nil
+		}
+		return "", errors.Errorf("no process %d", pid)
+	}
+	effectiveIDs = func() (int, int) {
+		return euid, egid
+	}
+
+	return func() {
+		pidRunning, procName, effectiveIDs = origRunning, origName, origIDs
+	}
+}
+
+func TestTelemetry_FindClientSegments(t *testing.T) {
+	clientNames := map[int]string{99: "ior", 123: "dfuse", 456: "ior", 4321: "mdtest"}
+
+	for name, tc := range map[string]struct {
+		lines       []string
+		running     []int
+		names       map[int]string
+		euid        int
+		egid        int
+		missing     bool
+		expSegments []*ClientSegment
+	}{
+		"missing listing": {
+			missing:     true,
+			expSegments: []*ClientSegment{},
+		},
+		"no segments": {
+			expSegments: []*ClientSegment{},
+		},
+		"running clients sorted by pid and index": {
+			lines: []string{
+				mockShmLine(segmentKeyBase+4321, "660", 4321, 0, 0),
+				mockShmLine(segmentKeyBase+123, "660", 123, 0, 0),
+				mockShmLine(segmentKeyBase+7, "660", 99, 0, 0),
+				mockShmLine(segmentKeyBase+5, "660", 99, 0, 0),
+			},
+			running: []int{99, 123, 4321},
+			expSegments: []*ClientSegment{
+				{PID: 99, Idx: 5},
+				{PID: 99, Idx: 7},
+				{PID: 123, Idx: 123},
+				{PID: 4321, Idx: 4321},
+			},
+		},
+		"vanished clients skipped": {
+			lines: []string{
+				mockShmLine(segmentKeyBase+1, "660", 123, 0, 0),
+				mockShmLine(segmentKeyBase+2, "660", 456, 0, 0),
+			},
+			running:     []int{456},
+			expSegments: []*ClientSegment{{PID: 456, Idx: 2}},
+		},
+		"engine segments skipped": {
+			lines: []string{
+				mockShmLine(segmentKeyBase, "660", 1000, 0, 0),
+				mockShmLine(segmentKeyBase+1, "660", 1001, 0, 0),
+				mockShmLine(segmentKeyBase+2, "660", 123, 0, 0),
+			},
+			running: []int{123, 1000, 1001},
+			names: map[int]string{
+				1000: engineProcName, 1001: engineProcName, 123: "dfuse",
+			},
+			expSegments: []*ClientSegment{{PID: 123, Idx: 2}},
+		},
+		"non-telemetry segments skipped": {
+			lines: []string{
+				mockShmLine(0, "600", 123, 0, 0),
+				mockShmLine(segmentKeyBase-1, "660", 123, 0, 0),
+				mockShmLine(segmentKeyBase+maxSegmentIdx, "660", 123, 0, 0),
+				mockShmLine(segmentKeyBase+3, "660", 123, 0, 0),
+			},
+			running:     []int{123},
+			expSegments: []*ClientSegment{{PID: 123, Idx: 3}},
+		},
+		"unreadable segments skipped": {
+			lines: []string{
+				mockShmLine(segmentKeyBase+1, "600", 99, 1000, 1000),
+				mockShmLine(segmentKeyBase+2, "660", 123, 2000, 1000),
+				mockShmLine(segmentKeyBase+3, "660", 456, 2000, 2000),
+				mockShmLine(segmentKeyBase+4, "664", 4321, 2000, 2000),
+			},
+			running: []int{99, 123, 456, 4321},
+			euid:    1000,
+			egid:    1000,
+			expSegments: []*ClientSegment{
+				{PID: 99, Idx: 1},
+				{PID: 123, Idx: 2},
+				{PID: 4321, Idx: 4},
+			},
+		},
+		"malformed lines skipped": {
+			lines: []string{
+				"garbage",
+				strings.Replace(mockShmLine(segmentKeyBase+1, "660", 99, 0, 0), "660", "9z9", 1),
+				mockShmLine(segmentKeyBase+2, "660", 123, 0, 0),
+			},
+			running:     []int{99, 123},
+			expSegments: []*ClientSegment{{PID: 123, Idx: 2}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			testDir, cleanup := common.CreateTestDir(t)
+			defer cleanup()
+
+			names := tc.names
+			if names == nil {
+				names = clientNames
+			}
+			defer mockClientProcs(t, tc.running, names, tc.euid, tc.egid)()
+
+			path := filepath.Join(testDir, "shm")
+			if !tc.missing {
+				path = common.CreateTestFile(t, testDir,
+					strings.Join(append([]string{shmHeader}, tc.lines...), "\n")+"\n")
+			}
+
+			gotSegments, err := FindClientSegments(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expSegments, gotSegments); diff != "" {
+				t.Fatalf("unexpected segments (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_FindClientSegments_NotFile(t *testing.T) {
+	testDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	_, err := FindClientSegments(testDir)
+	common.CmpErr(t, errors.New("reading shared memory segments"), err)
+}
+
+func TestTelemetry_OpenClientSource_NotRunning(t *testing.T) {
+	defer mockClientProcs(t, nil, nil, 0, 0)()
+
+	_, _, err := OpenClientSource(context.Background(), 1234)
+	common.CmpErr(t, errors.New("client process 1234 is not running"), err)
+}
@@ -0,0 +1,152 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// CollectOptions controls how metrics found while walking the tree
+	// are delivered to the consumer.
+	CollectOptions struct {
+		// BufferSize is the number of metrics the walk may run ahead of
+		// the consumer. Zero means each metric is handed directly to
+		// the consumer before the walk continues.
+		BufferSize int
+		// StripPrefix makes the paths of the metrics found relative to
+		// the walked subtree by omitting its dirname.
+		StripPrefix bool
+		// Separator joins the components of the paths of the metrics
+		// found, e.g. "." for Graphite. When set, any dirname prefix is
+		// also converted to use it. Defaults to "/".
+		Separator string
+		// Directories sends a Directory marker on entering each
+		// directory of the walked subtree, ahead of its contents,
+		// so that consumers can rebuild the tree without parsing
+		// paths. By default only the metrics are sent.
+		Directories bool
+	}
+
+	// metricWalker walks a metric tree, passing each metric found to emit
+	// and stopping early if emit returns false.
+	metricWalker func(emit func(Metric) bool)
+)
+
+func (co *CollectOptions) validate() error {
+	if co.BufferSize < 0 {
+		return errors.Errorf("invalid collect buffer size %d", co.BufferSize)
+	}
+	return nil
+}
+
+// metricDir returns the path reported for metrics found in the directory with
+// the given path components relative to the walked subtree at dirname.
+func (co *CollectOptions) metricDir(dirname string, pathComps []string) string {
+	if co.StripPrefix || dirname == "" {
+		return strings.Join(pathComps, co.separator())
+	}
+
+	if co.Separator == "" {
+		return strings.Join(append([]string{dirname}, pathComps...), "/")
+	}
+
+	prefix := strings.FieldsFunc(dirname, func(r rune) bool { return r == '/' })
+	return strings.Join(append(prefix, pathComps...), co.Separator)
+}
+
+func (co *CollectOptions) separator() string {
+	if co.Separator == "" {
+		return "/"
+	}
+	return co.Separator
+}
+
+// sendMetrics runs the walk and delivers the metrics found to the output
+// channel, which is closed once all metrics have been delivered or the context
+// is cancelled.
+//
+// Every send selects on the context, so a consumer that stops reading can't
+// block the walk forever, as long as the context is eventually cancelled. When
+// buffered, the walk may finish and sendMetrics return before the consumer has
+// received everything, with the remainder delivered in the background. If the
+// walk is stopped by cancellation the context error is returned and metrics
+// not yet delivered are discarded.
+func sendMetrics(ctx context.Context, opts CollectOptions, walk metricWalker, out chan<- Metric) error {
+	if opts.BufferSize == 0 {
+		defer close(out)
+		return runWalk(ctx, walk, out)
+	}
+
+	// The relay holds one metric while waiting on the consumer, so the
+	// queue is one shorter than the requested buffer.
+	queue := make(chan Metric, opts.BufferSize-1)
+	go func() {
+		defer close(out)
+		for m := range queue {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- m:
+			}
+		}
+	}()
+	defer close(queue)
+
+	return runWalk(ctx, walk, queue)
+}
+
+func runWalk(ctx context.Context, walk metricWalker, out chan<- Metric) error {
+	stopped := false
+	walk(func(m Metric) bool {
+		select {
+		case <-ctx.Done():
+			stopped = true
+		case out <- m:
+		}
+		return !stopped
+	})
+
+	if stopped {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// isScalar returns true if the metric type holds a single numeric value, as
+// opposed to a point in time.
+func isScalar(mt MetricType) bool {
+	switch mt {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeDuration:
+		return true
+	default:
+		return false
+	}
+}
+
+// CollectSourceMap walks the subtree at dirname in the given source and
+// returns the value of each scalar metric found, keyed by its full path with
+// no leading separator. Timestamps and other non-scalar metrics are skipped.
+func CollectSourceMap(ctx context.Context, src Source, dirname string) (map[string]float64, error) {
+	metrics, err := collectAll(ctx, src, dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64)
+	for _, m := range metrics {
+		if !isScalar(m.Type()) {
+			continue
+		}
+		values[metricPath(m)] = m.FloatValue()
+	}
+
+	return values, nil
+}
@@ -0,0 +1,247 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// countingWalker returns a walker over a synthetic tree of the given size
+// which counts the metrics accepted by the consumer side.
+func countingWalker(size int, accepted *int32) metricWalker {
+	return func(emit func(Metric) bool) {
+		for i := 0; i < size; i++ {
+			m := NewMockMetric(fmt.Sprintf("/io/ops_%d", i), MetricTypeCounter, float64(i))
+			if !emit(m) {
+				return
+			}
+			atomic.AddInt32(accepted, 1)
+		}
+	}
+}
+
+func TestTelemetry_CollectOptions_Validate(t *testing.T) {
+	common.CmpErr(t, nil, (&CollectOptions{}).validate())
+	common.CmpErr(t, errors.New("invalid collect buffer size -1"),
+		(&CollectOptions{BufferSize: -1}).validate())
+}
+
+func TestTelemetry_CollectOptions_MetricDir(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts      CollectOptions
+		dirname   string
+		pathComps []string
+		expPath   string
+	}{
+		"no dirname": {
+			pathComps: []string{"io", "ops"},
+			expPath:   "io/ops",
+		},
+		"default": {
+			dirname:   "/io",
+			pathComps: []string{"ops", "update"},
+			expPath:   "/io/ops/update",
+		},
+		"metrics directly under dirname": {
+			dirname: "/io",
+			expPath: "/io",
+		},
+		"strip prefix": {
+			opts:      CollectOptions{StripPrefix: true},
+			dirname:   "/io",
+			pathComps: []string{"ops", "update"},
+			expPath:   "ops/update",
+		},
+		"strip prefix of metrics directly under dirname": {
+			opts:    CollectOptions{StripPrefix: true},
+			dirname: "/io",
+			expPath: "",
+		},
+		"dotted separator": {
+			opts:      CollectOptions{Separator: "."},
+			dirname:   "/io/ops",
+			pathComps: []string{"update", "latency"},
+			expPath:   "io.ops.update.latency",
+		},
+		"dotted separator with root dirname": {
+			opts:      CollectOptions{Separator: "."},
+			dirname:   "/",
+			pathComps: []string{"io", "ops"},
+			expPath:   "io.ops",
+		},
+		"dotted separator with stripped prefix": {
+			opts:      CollectOptions{StripPrefix: true, Separator: "."},
+			dirname:   "/io/ops",
+			pathComps: []string{"update", "latency"},
+			expPath:   "update.latency",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotPath := tc.opts.metricDir(tc.dirname, tc.pathComps)
+			common.AssertEqual(t, tc.expPath, gotPath, "unexpected metric path")
+		})
+	}
+}
+
+func TestTelemetry_SendMetrics(t *testing.T) {
+	for name, tc := range map[string]struct {
+		bufSize int
+	}{
+		"unbuffered": {},
+		"buffered":   {bufSize: 4},
+	} {
+		t.Run(name, func(t *testing.T) {
+			const treeSize = 100
+			var accepted int32
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			out := make(chan Metric)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- sendMetrics(ctx, CollectOptions{BufferSize: tc.bufSize},
+					countingWalker(treeSize, &accepted), out)
+			}()
+
+			// Slow consumer reads a couple of metrics then walks away.
+			for i := 0; i < 2; i++ {
+				time.Sleep(10 * time.Millisecond)
+				<-out
+			}
+			cancel()
+
+			select {
+			case err := <-errCh:
+				common.CmpErr(t, context.Canceled, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("walk not stopped by cancelled context")
+			}
+
+			// The walk can only have run as far ahead of the consumer as
+			// the buffer allows.
+			got := int(atomic.LoadInt32(&accepted))
+			if got >= treeSize || got > 2+tc.bufSize+1 {
+				t.Fatalf("walk accepted %d metrics after consumer read 2 with buffer %d",
+					got, tc.bufSize)
+			}
+
+			// The output channel is closed once the walk is abandoned.
+			timeout := time.After(5 * time.Second)
+			for {
+				select {
+				case _, more := <-out:
+					if !more {
+						return
+					}
+				case <-timeout:
+					t.Fatal("output channel not closed")
+				}
+			}
+		})
+	}
+}
+
+func TestTelemetry_SendMetrics_Complete(t *testing.T) {
+	for name, tc := range map[string]struct {
+		bufSize int
+	}{
+		"unbuffered":          {},
+		"buffered":            {bufSize: 4},
+		"buffer exceeds walk": {bufSize: 20},
+	} {
+		t.Run(name, func(t *testing.T) {
+			const treeSize = 10
+			var accepted int32
+
+			out := make(chan Metric)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- sendMetrics(context.Background(), CollectOptions{BufferSize: tc.bufSize},
+					countingWalker(treeSize, &accepted), out)
+			}()
+
+			var received int
+			for range out {
+				received++
+			}
+			common.CmpErr(t, nil, <-errCh)
+			common.AssertEqual(t, treeSize, received, "metrics received")
+		})
+	}
+}
+
+func TestTelemetry_CollectSourceMap(t *testing.T) {
+	tree := []Metric{
+		NewMockMetric("/io/ops", MetricTypeCounter, 42),
+		NewMockMetric("/io/queued", MetricTypeGauge, 3),
+		NewMockStatsMetric("/io/latency", MetricTypeGauge, 1.5),
+		NewMockMetric("/io/op_time", MetricTypeDuration, 0.25),
+		NewMockMetric("/io/last_op", MetricTypeTimestamp, 1618524000),
+		NewMockMetric("/io/snap", MetricTypeSnapshot, 1618524001),
+		NewMockMetric("/started_at", MetricTypeTimestamp, 1618523000),
+		NewMockMetric("/net/errors", MetricTypeCounter, 0),
+	}
+
+	for name, tc := range map[string]struct {
+		dirname    string
+		collectErr error
+		expValues  map[string]float64
+		expErr     error
+	}{
+		"collect fails": {
+			collectErr: errors.New("not attached"),
+			expErr:     errors.New("not attached"),
+		},
+		"whole tree": {
+			dirname: "/",
+			expValues: map[string]float64{
+				"io/ops":     42,
+				"io/queued":  3,
+				"io/latency": 1.5,
+				"io/op_time": 0.25,
+				"net/errors": 0,
+			},
+		},
+		"subtree": {
+			dirname: "/net",
+			expValues: map[string]float64{
+				"net/errors": 0,
+			},
+		},
+		"empty subtree": {
+			dirname:   "/pool",
+			expValues: map[string]float64{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := &MockSource{
+				Metrics:    tree,
+				CollectErr: tc.collectErr,
+			}
+
+			gotValues, err := CollectSourceMap(context.Background(), src, tc.dirname)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expValues, gotValues); diff != "" {
+				t.Fatalf("unexpected values (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
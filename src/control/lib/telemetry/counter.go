@@ -32,10 +32,23 @@ func (c *Counter) FloatValue() float64 {
 	return float64(c.Value())
 }
 
+func (c *Counter) Snapshot() MetricSnapshot {
+	return snapshotMetric(c)
+}
+
+// FloatValueDelta returns the increase in the counter value since the
+// previous reading. If the current value is lower than the previous value
+// then the counter has been reset (e.g. the producer restarted) and the
+// current value is returned as the delta with reset set to true.
+func (c *Counter) FloatValueDelta(previous float64) (delta float64, reset bool) {
+	return counterDelta(previous, c.FloatValue())
+}
+
 func (c *Counter) Value() uint64 {
-	if c.handle == nil || c.node == nil {
+	if c == nil || !c.rLock() {
 		return BadUintVal
 	}
+	defer c.rUnlock()
 
 	var val C.uint64_t
 
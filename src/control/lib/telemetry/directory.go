@@ -0,0 +1,54 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+// +build linux,amd64
+//
+
+package telemetry
+
+/*
+#cgo LDFLAGS: -lgurt
+
+#include "gurt/telemetry_common.h"
+*/
+import "C"
+
+// Directory marks a directory in the metric tree. It is only sent by the walk
+// when CollectOptions.Directories is set and carries no value or metadata.
+type Directory struct {
+	metricBase
+}
+
+func (d *Directory) Type() MetricType {
+	return MetricTypeDirectory
+}
+
+// FloatValue returns 0 as a directory has no value.
+func (d *Directory) FloatValue() float64 {
+	return 0
+}
+
+// String returns the name of the directory.
+func (d *Directory) String() string {
+	return d.Name()
+}
+
+func (d *Directory) Snapshot() MetricSnapshot {
+	return snapshotMetric(d)
+}
+
+func newDirectory(hdl *handle, path string, name *string, node *C.struct_d_tm_node_t) *Directory {
+	empty := ""
+	return &Directory{
+		metricBase: metricBase{
+			handle: hdl,
+			node:   node,
+			path:   path,
+			name:   name,
+			desc:   &empty,
+			units:  &empty,
+		},
+	}
+}
@@ -25,10 +25,15 @@ type Duration struct {
 	statsMetric
 }
 
+func (d *Duration) Type() MetricType {
+	return MetricTypeDuration
+}
+
 func (d *Duration) Value() time.Duration {
-	if d.handle == nil || d.node == nil {
+	if d == nil || !d.rLock() {
 		return BadDuration
 	}
+	defer d.rUnlock()
 
 	var tms C.struct_timespec
 
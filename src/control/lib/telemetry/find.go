@@ -0,0 +1,113 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// metricMatcher reports whether a metric path matches a pattern. The base
+// directory is the deepest part of the tree that can contain matches, so that
+// only that subtree needs to be walked.
+type metricMatcher struct {
+	base  string
+	match func(string) bool
+}
+
+// metricPath returns the full path of the metric, normalized to have no
+// leading separator.
+func metricPath(m Metric) string {
+	return strings.TrimPrefix(path.Join("/", m.Path(), m.Name()), "/")
+}
+
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// newMetricMatcher parses the pattern as a regular expression if it begins
+// with "^", otherwise as a glob in the syntax of path.Match, where wildcards
+// do not match across separators. Regular expressions are always anchored at
+// both ends.
+func newMetricMatcher(pattern string) (*metricMatcher, error) {
+	if pattern == "" {
+		return nil, errors.New("empty metric pattern")
+	}
+
+	if strings.HasPrefix(pattern, "^") {
+		re, err := regexp.Compile("^(?:" + strings.TrimPrefix(pattern, "^") + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid metric pattern %q", pattern)
+		}
+
+		return &metricMatcher{
+			base: "/",
+			match: func(p string) bool {
+				return re.MatchString(p) || re.MatchString("/"+p)
+			},
+		}, nil
+	}
+
+	glob := strings.TrimPrefix(pattern, "/")
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, errors.Wrapf(err, "invalid metric pattern %q", pattern)
+	}
+
+	// The directory components up to the first one containing a wildcard
+	// are literal and can be used to limit the walk. The last component is
+	// excluded as it may name a metric rather than a directory.
+	comps := strings.Split(glob, "/")
+	var literal []string
+	for _, comp := range comps[:len(comps)-1] {
+		if hasGlobMeta(comp) {
+			break
+		}
+		literal = append(literal, comp)
+	}
+
+	return &metricMatcher{
+		base: "/" + strings.Join(literal, "/"),
+		match: func(p string) bool {
+			matched, _ := path.Match(glob, p)
+			return matched
+		},
+	}, nil
+}
+
+// FindSourceMetrics returns the metrics in the given source whose paths match
+// the pattern. The pattern is a glob such as "pool/*/target/*/latency" or, if
+// it begins with "^", an anchored regular expression. For globs, only the
+// subtree under the literal leading components of the pattern is collected.
+//
+// No error is returned if nothing matches, but an error is returned if the
+// pattern is invalid or the source can't be read, which for the shared memory
+// source includes the case where the literal leading components of a glob
+// don't exist in the tree.
+func FindSourceMetrics(ctx context.Context, src Source, pattern string) ([]Metric, error) {
+	matcher, err := newMetricMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := collectAll(ctx, src, matcher.base)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding metrics matching %q", pattern)
+	}
+
+	var found []Metric
+	for _, m := range metrics {
+		if matcher.match(metricPath(m)) {
+			found = append(found, m)
+		}
+	}
+
+	return found, nil
+}
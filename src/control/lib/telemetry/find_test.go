@@ -0,0 +1,129 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// dirSource records the directory requested from the wrapped source.
+type dirSource struct {
+	*MockSource
+	dirname string
+}
+
+func (ds *dirSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	ds.dirname = dirname
+	return ds.MockSource.CollectMetrics(ctx, dirname, out)
+}
+
+func TestTelemetry_FindSourceMetrics(t *testing.T) {
+	tree := []Metric{
+		NewMockMetric("/pool/p1/target/0/latency", MetricTypeGauge, 1),
+		NewMockMetric("/pool/p1/target/0/ops", MetricTypeCounter, 2),
+		NewMockMetric("/pool/p1/target/1/latency", MetricTypeGauge, 3),
+		NewMockMetric("/pool/p2/target/0/latency", MetricTypeGauge, 4),
+		NewMockMetric("/pool/p2/ops", MetricTypeCounter, 5),
+		NewMockMetric("/io/latency", MetricTypeGauge, 6),
+	}
+
+	for name, tc := range map[string]struct {
+		pattern    string
+		collectErr error
+		expDir     string
+		expPaths   []string
+		expErr     error
+	}{
+		"empty pattern": {
+			expErr: errors.New("empty metric pattern"),
+		},
+		"bad glob": {
+			pattern: "pool/[/latency",
+			expErr:  errors.New("invalid metric pattern"),
+		},
+		"bad regexp": {
+			pattern: "^pool/(",
+			expErr:  errors.New("invalid metric pattern"),
+		},
+		"collect fails": {
+			pattern:    "pool/*/ops",
+			collectErr: errors.New("not attached"),
+			expErr:     errors.New("not attached"),
+		},
+		"exact path": {
+			pattern:  "/io/latency",
+			expDir:   "/io",
+			expPaths: []string{"io/latency"},
+		},
+		"glob": {
+			pattern: "pool/*/target/*/latency",
+			expDir:  "/pool",
+			expPaths: []string{
+				"pool/p1/target/0/latency",
+				"pool/p1/target/1/latency",
+				"pool/p2/target/0/latency",
+			},
+		},
+		"glob with literal subtree": {
+			pattern:  "pool/p1/target/?/*",
+			expDir:   "/pool/p1/target",
+			expPaths: []string{"pool/p1/target/0/latency", "pool/p1/target/0/ops", "pool/p1/target/1/latency"},
+		},
+		"glob does not cross separators": {
+			pattern:  "pool/*/ops",
+			expDir:   "/pool",
+			expPaths: []string{"pool/p2/ops"},
+		},
+		"regexp": {
+			pattern:  "^pool/p[0-9]+/.*ops",
+			expDir:   "/",
+			expPaths: []string{"pool/p1/target/0/ops", "pool/p2/ops"},
+		},
+		"regexp is anchored": {
+			pattern: "^latency",
+			expDir:  "/",
+		},
+		"no match": {
+			pattern: "pool/*/target/*/bandwidth",
+			expDir:  "/pool",
+		},
+		"no match in missing subtree": {
+			pattern: "container/*/latency",
+			expDir:  "/container",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := &dirSource{
+				MockSource: &MockSource{
+					Metrics:    tree,
+					CollectErr: tc.collectErr,
+				},
+			}
+
+			found, err := FindSourceMetrics(context.Background(), src, tc.pattern)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			var gotPaths []string
+			for _, m := range found {
+				gotPaths = append(gotPaths, metricPath(m))
+			}
+			if diff := cmp.Diff(tc.expPaths, gotPaths); diff != "" {
+				t.Fatalf("unexpected metrics (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expDir, src.dirname, "collected directory")
+		})
+	}
+}
@@ -32,10 +32,15 @@ func (g *Gauge) FloatValue() float64 {
 	return float64(g.Value())
 }
 
+func (g *Gauge) Snapshot() MetricSnapshot {
+	return snapshotMetric(g)
+}
+
 func (g *Gauge) Value() uint64 {
-	if g.handle == nil || g.node == nil {
+	if g == nil || !g.rLock() {
 		return BadUintVal
 	}
+	defer g.rUnlock()
 
 	var val C.uint64_t
 
@@ -47,6 +52,62 @@ func (g *Gauge) Value() uint64 {
 	return BadUintVal
 }
 
+// readStats refreshes the gauge statistics and returns true if the underlying
+// node has stats populated, i.e. the gauge has been set at least once.
+func (g *Gauge) readStats() bool {
+	if g.Value() == BadUintVal {
+		return false
+	}
+	return g.stats.sample_size > 0
+}
+
+// IsStats returns true if the gauge carries statistics.
+func (g *Gauge) IsStats() bool {
+	return g.readStats()
+}
+
+func (g *Gauge) FloatMin() float64 {
+	if !g.readStats() {
+		return BadFloatVal
+	}
+	return g.statsMetric.FloatMin()
+}
+
+func (g *Gauge) FloatMax() float64 {
+	if !g.readStats() {
+		return BadFloatVal
+	}
+	return g.statsMetric.FloatMax()
+}
+
+func (g *Gauge) FloatSum() float64 {
+	if !g.readStats() {
+		return BadFloatVal
+	}
+	return g.statsMetric.FloatSum()
+}
+
+func (g *Gauge) Mean() float64 {
+	if !g.readStats() {
+		return BadFloatVal
+	}
+	return g.statsMetric.Mean()
+}
+
+func (g *Gauge) StdDev() float64 {
+	if !g.readStats() {
+		return BadFloatVal
+	}
+	return g.statsMetric.StdDev()
+}
+
+func (g *Gauge) SampleSize() uint64 {
+	if !g.readStats() {
+		return 0
+	}
+	return g.statsMetric.SampleSize()
+}
+
 func newGauge(hdl *handle, path string, name *string, node *C.struct_d_tm_node_t) *Gauge {
 	return &Gauge{
 		statsMetric: statsMetric{
@@ -0,0 +1,66 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"regexp"
+	"strings"
+)
+
+type pathLabelRule struct {
+	keys  []string
+	value *regexp.Regexp
+}
+
+var (
+	idRegexp   = regexp.MustCompile(`^\d+$`)
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	// pathLabelRules maps metric path components to the label keys
+	// assigned to the values in the components that follow them e.g.
+	// pool/<uuid>/target/<id> yields pool=<uuid> and target=<id>.
+	pathLabelRules = map[string]pathLabelRule{
+		"pool":   {keys: []string{"pool"}, value: uuidRegexp},
+		"target": {keys: []string{"target"}, value: idRegexp},
+		"io":     {keys: []string{"target"}, value: idRegexp},
+		"net":    {keys: []string{"rank", "context"}, value: idRegexp},
+		"rank":   {keys: []string{"rank"}, value: idRegexp},
+	}
+)
+
+// pathLabels extracts labels from the dimensions encoded in a metric path.
+// Components that don't match a known pattern are ignored.
+func pathLabels(path string) map[string]string {
+	labels := make(map[string]string)
+
+	comps := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(comps); i++ {
+		rule, found := pathLabelRules[comps[i]]
+		if !found || i+len(rule.keys) >= len(comps) {
+			continue
+		}
+
+		values := comps[i+1 : i+1+len(rule.keys)]
+		matched := true
+		for _, val := range values {
+			if !rule.value.MatchString(val) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		for j, key := range rule.keys {
+			labels[key] = values[j]
+		}
+		i += len(rule.keys)
+	}
+
+	return labels
+}
@@ -0,0 +1,171 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// MockMetric is an in-memory Metric implementation for use in tests.
+	MockMetric struct {
+		MetricPath  string
+		MetricType  MetricType
+		Description string
+		Unit        string
+		Value       float64
+		EngineIdx   uint32
+	}
+
+	// MockStatsMetric is an in-memory StatsMetric implementation for use
+	// in tests.
+	MockStatsMetric struct {
+		MockMetric
+		Min     float64
+		Max     float64
+		Sum     float64
+		Avg     float64
+		Dev     float64
+		Samples uint64
+	}
+
+	// MockSource is an in-memory Source implementation for use in tests.
+	MockSource struct {
+		Metrics    []Metric
+		Rank       uint32
+		CollectErr error
+		RankErr    error
+	}
+)
+
+// NewMockMetric returns a MockMetric with the supplied path, type and value.
+func NewMockMetric(path string, mt MetricType, value float64) *MockMetric {
+	return &MockMetric{
+		MetricPath: path,
+		MetricType: mt,
+		Value:      value,
+	}
+}
+
+func (mm *MockMetric) Path() string {
+	return filepath.Dir(mm.MetricPath)
+}
+
+func (mm *MockMetric) Name() string {
+	return filepath.Base(mm.MetricPath)
+}
+
+func (mm *MockMetric) Type() MetricType {
+	return mm.MetricType
+}
+
+func (mm *MockMetric) Desc() string {
+	return mm.Description
+}
+
+func (mm *MockMetric) Units() string {
+	return mm.Unit
+}
+
+func (mm *MockMetric) Labels() map[string]string {
+	return pathLabels(mm.Path())
+}
+
+func (mm *MockMetric) FloatValue() float64 {
+	return mm.Value
+}
+
+func (mm *MockMetric) EngineIndex() uint32 {
+	return mm.EngineIdx
+}
+
+func (mm *MockMetric) IsStats() bool {
+	return false
+}
+
+func (mm *MockMetric) String() string {
+	return fmt.Sprintf("%g", mm.Value)
+}
+
+func (mm *MockMetric) Snapshot() MetricSnapshot {
+	return snapshotMetric(mm)
+}
+
+// NewMockStatsMetric returns a MockStatsMetric with the supplied path, type
+// and value.
+func NewMockStatsMetric(path string, mt MetricType, value float64) *MockStatsMetric {
+	return &MockStatsMetric{
+		MockMetric: *NewMockMetric(path, mt, value),
+	}
+}
+
+func (msm *MockStatsMetric) IsStats() bool {
+	return true
+}
+
+func (msm *MockStatsMetric) FloatMin() float64 {
+	return msm.Min
+}
+
+func (msm *MockStatsMetric) FloatMax() float64 {
+	return msm.Max
+}
+
+func (msm *MockStatsMetric) FloatSum() float64 {
+	return msm.Sum
+}
+
+func (msm *MockStatsMetric) Mean() float64 {
+	return msm.Avg
+}
+
+func (msm *MockStatsMetric) StdDev() float64 {
+	return msm.Dev
+}
+
+func (msm *MockStatsMetric) SampleSize() uint64 {
+	return msm.Samples
+}
+
+func (msm *MockStatsMetric) Snapshot() MetricSnapshot {
+	return snapshotMetric(msm)
+}
+
+// CollectMetrics sends the mock metrics found under dirname to the output
+// channel and closes it, mirroring the behavior of the real implementation.
+func (ms *MockSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	if ms.CollectErr != nil {
+		return ms.CollectErr
+	}
+	defer close(out)
+
+	prefix := strings.TrimSuffix(dirname, "/")
+	for _, m := range ms.Metrics {
+		path := m.Path() + "/" + m.Name()
+		if prefix != "" && path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "collecting mock metrics")
+		case out <- m:
+		}
+	}
+
+	return nil
+}
+
+// GetRank returns the configured mock rank.
+func (ms *MockSource) GetRank(_ context.Context) (uint32, error) {
+	return ms.Rank, ms.RankErr
+}
@@ -0,0 +1,107 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// sumCounters is an example consumer which relies only on the Source
+// interface, allowing it to be tested without the telemetry library.
+func sumCounters(ctx context.Context, src Source, dirname string) (float64, error) {
+	metrics := make(chan Metric)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- src.CollectMetrics(ctx, dirname, metrics)
+	}()
+
+	// The output channel is only closed on success, so watch for an
+	// error while consuming metrics.
+	var total float64
+	for {
+		select {
+		case err := <-errCh:
+			if err != nil {
+				return 0, err
+			}
+			errCh = nil
+		case m, more := <-metrics:
+			if !more {
+				return total, nil
+			}
+			if m.Type() == MetricTypeCounter {
+				total += m.FloatValue()
+			}
+		}
+	}
+}
+
+func TestTelemetry_MockSource(t *testing.T) {
+	src := &MockSource{
+		Metrics: []Metric{
+			NewMockMetric("/io/ops/read", MetricTypeCounter, 3),
+			NewMockMetric("/io/ops/write", MetricTypeCounter, 4),
+			NewMockMetric("/io/latency", MetricTypeGauge, 100),
+			NewMockMetric("/net/1/0/failed", MetricTypeCounter, 5),
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		dirname   string
+		collErr   error
+		expResult float64
+		expErr    error
+	}{
+		"all metrics": {
+			expResult: 12,
+		},
+		"subdirectory": {
+			dirname:   "/io/ops",
+			expResult: 7,
+		},
+		"unknown directory": {
+			dirname: "/io/opsx",
+		},
+		"collect fails": {
+			collErr: errors.New("whoops"),
+			expErr:  errors.New("whoops"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src.CollectErr = tc.collErr
+
+			gotResult, gotErr := sumCounters(context.Background(), src, tc.dirname)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if gotResult != tc.expResult {
+				t.Fatalf("expected %g, got %g", tc.expResult, gotResult)
+			}
+		})
+	}
+}
+
+func TestTelemetry_MockMetric(t *testing.T) {
+	m := NewMockMetric("/net/1/0/failed", MetricTypeCounter, 5)
+
+	if m.Path() != "/net/1/0" || m.Name() != "failed" {
+		t.Fatalf("unexpected path %q and name %q", m.Path(), m.Name())
+	}
+
+	expLabels := map[string]string{"rank": "1", "context": "0"}
+	if diff := cmp.Diff(expLabels, m.Labels()); diff != "" {
+		t.Fatalf("unexpected labels (-want, +got):\n%s\n", diff)
+	}
+}
@@ -0,0 +1,186 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+// +build linux,amd64
+//
+
+package telemetry
+
+/*
+#cgo LDFLAGS: -lgurt
+
+#include <stdlib.h>
+
+#include "gurt/telemetry_common.h"
+#include "gurt/telemetry_producer.h"
+
+// cgo can't call variadic functions, and the metric path is passed as an
+// argument so that it is never interpreted as a format string.
+static int
+add_producer_metric(struct d_tm_node_t **node, int metric_type, char *desc,
+		    char *units, char *path)
+{
+	return d_tm_add_metric(node, metric_type, desc, units, "%s", path);
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// The gurt producer state is global to the process, so only one telemetry
+// segment may be published at a time.
+var producer struct {
+	sync.Mutex
+	active bool
+}
+
+type (
+	// ProducerCounter is a counter metric published by this process.
+	ProducerCounter struct {
+		node *C.struct_d_tm_node_t
+	}
+
+	// ProducerGauge is a gauge metric published by this process.
+	ProducerGauge struct {
+		node *C.struct_d_tm_node_t
+	}
+)
+
+// InitProducer creates a telemetry segment with the given ID and size into
+// which metrics added by this process will be published. If retain is set, the
+// segment is left in place after FiniProducer so that the final values can
+// still be read by consumers.
+func InitProducer(id uint32, memSize uint64, retain bool) error {
+	producer.Lock()
+	defer producer.Unlock()
+
+	if producer.active {
+		return errors.New("telemetry producer already initialized")
+	}
+
+	flags := C.int(C.D_TM_SERIALIZATION)
+	if retain {
+		flags |= C.D_TM_RETAIN_SHMEM
+	}
+
+	rc := C.d_tm_init(C.int(id), C.uint64_t(memSize), flags)
+	if rc != C.DER_SUCCESS {
+		return errors.Errorf("unable to initialize telemetry producer %d.  rc = %d", id, rc)
+	}
+	producer.active = true
+
+	return nil
+}
+
+// FiniProducer releases the telemetry segment created by InitProducer. Any
+// metrics added by this process must not be used afterwards.
+func FiniProducer() {
+	producer.Lock()
+	defer producer.Unlock()
+
+	if !producer.active {
+		return
+	}
+
+	C.d_tm_fini()
+	producer.active = false
+}
+
+func addProducerMetric(metricType MetricType, path, desc, units string) (*C.struct_d_tm_node_t, error) {
+	if path == "" {
+		return nil, errors.New("empty metric path")
+	}
+
+	producer.Lock()
+	defer producer.Unlock()
+
+	if !producer.active {
+		return nil, errors.New("telemetry producer not initialized")
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	cDesc := C.CString(desc)
+	defer C.free(unsafe.Pointer(cDesc))
+	cUnits := C.CString(units)
+	defer C.free(unsafe.Pointer(cUnits))
+
+	var node *C.struct_d_tm_node_t
+	rc := C.add_producer_metric(&node, C.int(metricType), cDesc, cUnits, cPath)
+	if rc != C.DER_SUCCESS {
+		return nil, errors.Errorf("unable to add metric %s.  rc = %d", path, rc)
+	}
+
+	return node, nil
+}
+
+// AddCounter publishes a counter at the given path, which may contain "/"
+// separated directories. If a counter already exists at the path it is
+// returned.
+func AddCounter(path, desc, units string) (*ProducerCounter, error) {
+	node, err := addProducerMetric(MetricTypeCounter, path, desc, units)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProducerCounter{node: node}, nil
+}
+
+// Set sets the counter to the given value.
+func (pc *ProducerCounter) Set(val uint64) {
+	if pc == nil || pc.node == nil {
+		return
+	}
+	C.d_tm_set_counter(pc.node, C.uint64_t(val))
+}
+
+// Inc increases the counter by the given value.
+func (pc *ProducerCounter) Inc(val uint64) {
+	if pc == nil || pc.node == nil {
+		return
+	}
+	C.d_tm_inc_counter(pc.node, C.uint64_t(val))
+}
+
+// AddGauge publishes a gauge at the given path, which may contain "/"
+// separated directories. If a gauge already exists at the path it is
+// returned.
+func AddGauge(path, desc, units string) (*ProducerGauge, error) {
+	node, err := addProducerMetric(MetricTypeGauge, path, desc, units)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProducerGauge{node: node}, nil
+}
+
+// Set sets the gauge to the given value.
+func (pg *ProducerGauge) Set(val uint64) {
+	if pg == nil || pg.node == nil {
+		return
+	}
+	C.d_tm_set_gauge(pg.node, C.uint64_t(val))
+}
+
+// Inc increases the gauge by the given value.
+func (pg *ProducerGauge) Inc(val uint64) {
+	if pg == nil || pg.node == nil {
+		return
+	}
+	C.d_tm_inc_gauge(pg.node, C.uint64_t(val))
+}
+
+// Dec decreases the gauge by the given value.
+func (pg *ProducerGauge) Dec(val uint64) {
+	if pg == nil || pg.node == nil {
+		return
+	}
+	C.d_tm_dec_gauge(pg.node, C.uint64_t(val))
+}
@@ -0,0 +1,77 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+// +build linux,amd64
+//
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestTelemetry_Producer(t *testing.T) {
+	const producerID = 43
+
+	if _, err := AddCounter("uninit", "", ""); err == nil {
+		t.Fatal("expected error adding counter before init")
+	}
+
+	if err := InitProducer(producerID, 8192, false); err != nil {
+		t.Fatal(err)
+	}
+	defer FiniProducer()
+
+	common.CmpErr(t, errors.New("already initialized"), InitProducer(producerID, 8192, false))
+
+	_, err := AddCounter("", "", "")
+	common.CmpErr(t, errors.New("empty metric path"), err)
+
+	counter, err := AddCounter("ctl/rpc/count", "rpcs handled", "rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Set(40)
+	counter.Inc(2)
+
+	gauge, err := AddGauge("ctl/scan/duration", "last scan duration", "ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gauge.Set(10)
+	gauge.Inc(5)
+	gauge.Dec(3)
+
+	again, err := AddCounter("ctl/rpc/count", "rpcs handled", "rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.Inc(1)
+
+	ctx, err := Init(context.Background(), producerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Detach(ctx)
+
+	gotCounter, err := GetCounter(ctx, "ctl/rpc/count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, uint64(43), gotCounter.Value(), "unexpected counter value")
+	common.AssertEqual(t, "rpc", gotCounter.Units(), "unexpected counter units")
+
+	gotGauge, err := GetGauge(ctx, "ctl/scan/duration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, uint64(12), gotGauge.Value(), "unexpected gauge value")
+	common.AssertEqual(t, "last scan duration", gotGauge.Desc(), "unexpected gauge desc")
+}
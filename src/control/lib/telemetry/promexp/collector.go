@@ -231,7 +231,7 @@ type metricStat struct {
 }
 
 func getMetricStats(baseName, desc string, m telemetry.Metric) (stats []*metricStat) {
-	ms, ok := m.(telemetry.StatsMetric)
+	ms, ok := telemetry.AsStats(m)
 	if !ok {
 		return
 	}
@@ -308,8 +308,15 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 			}
 
 			counters.add(baseName, desc, rm.m.FloatValue(), labels)
+		case telemetry.MetricTypeTimestamp:
+			if c.isIgnored(baseName) {
+				break
+			}
+
+			// exported as seconds since the epoch
+			gauges.add(baseName, desc, rm.m.FloatValue(), labels)
 		default:
-			c.log.Errorf("metric type %d not supported", rm.m.Type())
+			c.log.Errorf("metric type %s not supported", rm.m.Type())
 		}
 	}
 
@@ -0,0 +1,91 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MetricRate is the rate of change of a counter between two reads of the
+// tree. Reset is set if the counter was found to have been reset between the
+// reads, in which case the delta is the value accumulated since the reset.
+type MetricRate struct {
+	Path    string
+	Name    string
+	Delta   float64
+	Elapsed time.Duration
+	Rate    float64 // units per second
+	Reset   bool
+}
+
+// counterRates computes the rates of the counters found in both sets of
+// metrics, in the order they appear in the later set. Other metric types and
+// counters that can't be read in either set are skipped.
+func counterRates(earlier, later []Metric, elapsed time.Duration) []MetricRate {
+	previous := make(map[string]float64)
+	for _, m := range earlier {
+		if m.Type().IsCounter() {
+			previous[m.Path()+"/"+m.Name()] = m.FloatValue()
+		}
+	}
+
+	var rates []MetricRate
+	for _, m := range later {
+		if !m.Type().IsCounter() {
+			continue
+		}
+		prev, found := previous[m.Path()+"/"+m.Name()]
+		cur := m.FloatValue()
+		if !found || prev == BadFloatVal || cur == BadFloatVal {
+			continue
+		}
+
+		rate := MetricRate{
+			Path:    m.Path(),
+			Name:    m.Name(),
+			Elapsed: elapsed,
+		}
+		rate.Delta, rate.Reset = counterDelta(prev, cur)
+		if elapsed > 0 {
+			rate.Rate = rate.Delta / elapsed.Seconds()
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates
+}
+
+// RatesSource reads the subtree at dirname in the given source twice, interval
+// apart, and returns the per-second rate of each counter over that period.
+// Counters that only appear in one of the reads are skipped.
+func RatesSource(ctx context.Context, src Source, dirname string, interval time.Duration) ([]MetricRate, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("invalid rate interval %s", interval)
+	}
+
+	earlier, err := collectAll(ctx, src, dirname)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading first snapshot")
+	}
+	start := time.Now()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	later, err := collectAll(ctx, src, dirname)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading second snapshot")
+	}
+
+	return counterRates(earlier, later, time.Since(start)), nil
+}
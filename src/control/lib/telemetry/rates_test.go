@@ -0,0 +1,154 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func rateTree(ops, bytes, queued float64) []Metric {
+	return []Metric{
+		NewMockMetric("/io/ops", MetricTypeCounter, ops),
+		NewMockMetric("/io/bytes", MetricTypeCounter, bytes),
+		NewMockMetric("/io/queued", MetricTypeGauge, queued),
+	}
+}
+
+func TestTelemetry_counterRates(t *testing.T) {
+	for name, tc := range map[string]struct {
+		earlier  []Metric
+		later    []Metric
+		elapsed  time.Duration
+		expRates []MetricRate
+	}{
+		"no metrics": {
+			elapsed: time.Second,
+		},
+		"counters advance": {
+			earlier: rateTree(10, 4096, 3),
+			later:   rateTree(30, 12288, 1),
+			elapsed: 2 * time.Second,
+			expRates: []MetricRate{
+				{Path: "/io", Name: "ops", Delta: 20, Elapsed: 2 * time.Second, Rate: 10},
+				{Path: "/io", Name: "bytes", Delta: 8192, Elapsed: 2 * time.Second, Rate: 4096},
+			},
+		},
+		"counter reset": {
+			earlier: rateTree(100, 4096, 0),
+			later:   rateTree(8, 8192, 0),
+			elapsed: 4 * time.Second,
+			expRates: []MetricRate{
+				{Path: "/io", Name: "ops", Delta: 8, Elapsed: 4 * time.Second, Rate: 2, Reset: true},
+				{Path: "/io", Name: "bytes", Delta: 4096, Elapsed: 4 * time.Second, Rate: 1024},
+			},
+		},
+		"new and unreadable counters skipped": {
+			earlier: []Metric{
+				NewMockMetric("/io/ops", MetricTypeCounter, BadFloatVal),
+			},
+			later: append(rateTree(5, 5, 5),
+				NewMockMetric("/io/errors", MetricTypeCounter, 1)),
+			elapsed: time.Second,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotRates := counterRates(tc.earlier, tc.later, tc.elapsed)
+
+			if diff := cmp.Diff(tc.expRates, gotRates); diff != "" {
+				t.Fatalf("unexpected rates (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_RatesSource(t *testing.T) {
+	const interval = 10 * time.Millisecond
+
+	for name, tc := range map[string]struct {
+		ticks    [][]Metric
+		errs     []error
+		interval time.Duration
+		expRates []MetricRate
+		expErr   error
+	}{
+		"invalid interval": {
+			expErr: errors.New("invalid rate interval"),
+		},
+		"first read fails": {
+			ticks:    [][]Metric{nil},
+			errs:     []error{errors.New("no shared memory segment")},
+			interval: interval,
+			expErr:   errors.New("first snapshot: no shared memory segment"),
+		},
+		"second read fails": {
+			ticks:    [][]Metric{rateTree(1, 1, 1), nil},
+			errs:     []error{nil, errors.New("no shared memory segment")},
+			interval: interval,
+			expErr:   errors.New("second snapshot: no shared memory segment"),
+		},
+		"counters advance": {
+			ticks:    [][]Metric{rateTree(10, 4096, 3), rateTree(30, 12288, 1)},
+			interval: interval,
+			expRates: []MetricRate{
+				{Path: "/io", Name: "ops", Delta: 20},
+				{Path: "/io", Name: "bytes", Delta: 8192},
+			},
+		},
+		"counter reset": {
+			ticks:    [][]Metric{rateTree(100, 4096, 0), rateTree(8, 4096, 0)},
+			interval: interval,
+			expRates: []MetricRate{
+				{Path: "/io", Name: "ops", Delta: 8, Reset: true},
+				{Path: "/io", Name: "bytes"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := &sequenceSource{ticks: tc.ticks, errs: tc.errs}
+
+			gotRates, gotErr := RatesSource(context.Background(), src, "/io", tc.interval)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			// Elapsed time depends on scheduling, so check the rates
+			// are consistent with it rather than exact.
+			for _, rate := range gotRates {
+				if rate.Elapsed < tc.interval {
+					t.Fatalf("%s/%s: elapsed %s shorter than interval %s",
+						rate.Path, rate.Name, rate.Elapsed, tc.interval)
+				}
+				common.AssertEqual(t, rate.Delta/rate.Elapsed.Seconds(), rate.Rate,
+					rate.Path+"/"+rate.Name+" rate")
+			}
+
+			if diff := cmp.Diff(tc.expRates, gotRates,
+				cmpopts.IgnoreFields(MetricRate{}, "Elapsed", "Rate")); diff != "" {
+				t.Fatalf("unexpected rates (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_RatesSource_Cancelled(t *testing.T) {
+	src := &sequenceSource{ticks: [][]Metric{rateTree(1, 1, 1)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := RatesSource(ctx, src, "/io", time.Hour)
+	common.CmpErr(t, context.Canceled, err)
+}
@@ -0,0 +1,155 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// segmentOpener attaches to the telemetry segment of an engine,
+	// returning a context holding the segment handle and a function
+	// to detach from it.
+	segmentOpener func(ctx context.Context, idx uint32) (context.Context, func(), error)
+
+	// rankMetric wraps a Metric from an engine segment so that its path
+	// is prefixed by the engine's rank and it reports the segment's index.
+	rankMetric struct {
+		Metric
+		idx  uint32
+		rank uint32
+	}
+
+	// rankStatsMetric preserves the StatsMetric interface of a wrapped
+	// metric.
+	rankStatsMetric struct {
+		StatsMetric
+		rm *rankMetric
+	}
+
+	// SegmentErrors maps engine indices to the errors encountered when
+	// collecting from their telemetry segments.
+	SegmentErrors map[uint32]error
+)
+
+func (rm *rankMetric) Path() string {
+	prefix := fmt.Sprintf("/rank/%d", rm.rank)
+	path := strings.Trim(rm.Metric.Path(), "/")
+	if path == "" {
+		return prefix
+	}
+	return prefix + "/" + path
+}
+
+func (rm *rankMetric) Labels() map[string]string {
+	return pathLabels(rm.Path())
+}
+
+func (rm *rankMetric) EngineIndex() uint32 {
+	return rm.idx
+}
+
+func (rm *rankMetric) Snapshot() MetricSnapshot {
+	return snapshotMetric(rm)
+}
+
+func (rsm *rankStatsMetric) Path() string {
+	return rsm.rm.Path()
+}
+
+func (rsm *rankStatsMetric) Labels() map[string]string {
+	return rsm.rm.Labels()
+}
+
+func (rsm *rankStatsMetric) EngineIndex() uint32 {
+	return rsm.rm.EngineIndex()
+}
+
+func (rsm *rankStatsMetric) Snapshot() MetricSnapshot {
+	return snapshotMetric(rsm)
+}
+
+func newRankMetric(m Metric, idx, rank uint32) Metric {
+	rm := &rankMetric{Metric: m, idx: idx, rank: rank}
+	if sm, ok := AsStats(m); ok {
+		return &rankStatsMetric{StatsMetric: sm, rm: rm}
+	}
+	return rm
+}
+
+func (se SegmentErrors) Error() string {
+	indices := make([]uint32, 0, len(se))
+	for idx := range se {
+		indices = append(indices, idx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	msgs := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		msgs = append(msgs, fmt.Sprintf("engine %d: %s", idx, se[idx]))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func collectSegment(ctx context.Context, src Source, open segmentOpener, idx uint32, out chan<- Metric) error {
+	segCtx, detach, err := open(ctx, idx)
+	if err != nil {
+		return err
+	}
+	defer detach()
+
+	rank, err := src.GetRank(segCtx)
+	if err != nil {
+		return err
+	}
+
+	metrics, err := collectAll(segCtx, src, "")
+	if err != nil {
+		return err
+	}
+
+	// metrics are frozen as the segment is detached on return, before
+	// consumers have necessarily finished reading them
+	for _, m := range metrics {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- freezeMetric(newRankMetric(m, idx, rank)):
+		}
+	}
+
+	return nil
+}
+
+// collectSegments walks the telemetry segment of each of the given engine
+// indices in turn, sending copies of their metrics to the output channel with
+// paths prefixed by the engine rank, and closes the channel when done. The
+// copies remain valid after the segments have been detached. Failure to
+// read a segment doesn't prevent collection from the others, any failures
+// are returned as SegmentErrors.
+func collectSegments(ctx context.Context, src Source, open segmentOpener, indices []uint32, out chan<- Metric) error {
+	defer close(out)
+
+	segErrs := make(SegmentErrors)
+	for _, idx := range indices {
+		if err := collectSegment(ctx, src, open, idx, out); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			segErrs[idx] = err
+		}
+	}
+
+	if len(segErrs) > 0 {
+		return segErrs
+	}
+	return nil
+}
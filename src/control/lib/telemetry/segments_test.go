@@ -0,0 +1,197 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+type testSegmentKey string
+
+const segmentIdxKey testSegmentKey = "segment"
+
+// segmentSource serves metrics from fake segments keyed by the engine index
+// set on the context by the opener.
+type segmentSource struct {
+	segments map[uint32]*MockSource
+}
+
+func (ss *segmentSource) get(ctx context.Context) *MockSource {
+	return ss.segments[ctx.Value(segmentIdxKey).(uint32)]
+}
+
+func (ss *segmentSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	return ss.get(ctx).CollectMetrics(ctx, dirname, out)
+}
+
+func (ss *segmentSource) GetRank(ctx context.Context) (uint32, error) {
+	return ss.get(ctx).GetRank(ctx)
+}
+
+func TestTelemetry_collectSegments(t *testing.T) {
+	src := &segmentSource{
+		segments: map[uint32]*MockSource{
+			0: {
+				Rank: 3,
+				Metrics: []Metric{
+					NewMockMetric("/io/ops", MetricTypeCounter, 1),
+				},
+			},
+			1: {
+				Rank: 7,
+				Metrics: []Metric{
+					NewMockMetric("/io/ops", MetricTypeCounter, 2),
+					NewMockMetric("/started_at", MetricTypeTimestamp, 3),
+				},
+			},
+			2: {
+				RankErr: errors.New("no rank"),
+			},
+			3: {
+				Rank:       4,
+				CollectErr: errors.New("bad tree"),
+			},
+		},
+	}
+
+	// Engine indices of the segments serving each rank.
+	rankIndices := map[string]uint32{"3": 0, "7": 1}
+
+	for name, tc := range map[string]struct {
+		indices    []uint32
+		expPaths   []string
+		expLabels  map[string]map[string]string
+		expErr     error
+		expDetach  int
+		expSegErrs []uint32
+	}{
+		"no segments": {},
+		"multiple segments": {
+			indices:   []uint32{0, 1},
+			expPaths:  []string{"/rank/3/io/ops", "/rank/7/io/ops", "/rank/7/started_at"},
+			expDetach: 2,
+		},
+		"failed segments don't abort collection": {
+			indices:    []uint32{2, 0, 3, 5, 1},
+			expPaths:   []string{"/rank/3/io/ops", "/rank/7/io/ops", "/rank/7/started_at"},
+			expErr:     errors.New("engine 2: no rank; engine 3: bad tree; engine 5: no segment"),
+			expDetach:  4,
+			expSegErrs: []uint32{2, 3, 5},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			detached := 0
+			open := func(ctx context.Context, idx uint32) (context.Context, func(), error) {
+				if _, found := src.segments[idx]; !found {
+					return nil, nil, errors.New("no segment")
+				}
+				return context.WithValue(ctx, segmentIdxKey, idx), func() { detached++ }, nil
+			}
+
+			out := make(chan Metric)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- collectSegments(context.Background(), src, open, tc.indices, out)
+			}()
+
+			gotPaths := []string{}
+			for m := range out {
+				gotPaths = append(gotPaths, m.Path()+"/"+m.Name())
+				rank := m.Labels()["rank"]
+				if rank == "" {
+					t.Fatalf("metric %s has no rank label", m.Path())
+				}
+				common.AssertEqual(t, rankIndices[rank], m.EngineIndex(),
+					"unexpected engine index for "+m.Path())
+			}
+			sort.Strings(gotPaths)
+			gotErr := <-errCh
+
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				segErrs, ok := gotErr.(SegmentErrors)
+				if !ok {
+					t.Fatalf("expected SegmentErrors, got %T", gotErr)
+				}
+				common.AssertEqual(t, len(tc.expSegErrs), len(segErrs), "unexpected number of segment errors")
+			}
+
+			if tc.expPaths == nil {
+				tc.expPaths = []string{}
+			}
+			if diff := cmp.Diff(tc.expPaths, gotPaths); diff != "" {
+				t.Fatalf("unexpected metrics (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expDetach, detached, "unexpected number of detached segments")
+		})
+	}
+}
+
+func TestTelemetry_collectSegments_Detached(t *testing.T) {
+	counter := NewMockMetric("/io/ops", MetricTypeCounter, 5)
+	gauge := NewMockStatsMetric("/io/latency", MetricTypeGauge, 10)
+	gauge.Min = 1
+	gauge.Max = 20
+	gauge.Samples = 4
+	src := &segmentSource{
+		segments: map[uint32]*MockSource{
+			0: {
+				Rank:    2,
+				Metrics: []Metric{counter, gauge},
+			},
+		},
+	}
+
+	// simulate the segment memory being released on detach
+	open := func(ctx context.Context, idx uint32) (context.Context, func(), error) {
+		return context.WithValue(ctx, segmentIdxKey, idx), func() {
+			counter.Value = BadFloatVal
+			gauge.Value = BadFloatVal
+			gauge.Min = BadFloatVal
+			gauge.Max = BadFloatVal
+			gauge.Samples = 0
+		}, nil
+	}
+
+	out := make(chan Metric)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- collectSegments(context.Background(), src, open, []uint32{0}, out)
+	}()
+
+	var got []Metric
+	for m := range out {
+		got = append(got, m)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	// read values only after the segment has been detached
+	common.AssertEqual(t, 2, len(got), "number of metrics collected")
+	common.AssertEqual(t, "/rank/2/io", got[0].Path(), "counter path")
+	common.AssertEqual(t, float64(5), got[0].FloatValue(), "counter value")
+	common.AssertEqual(t, "5", got[0].String(), "counter string")
+	common.AssertEqual(t, float64(10), got[1].FloatValue(), "gauge value")
+
+	sm, ok := AsStats(got[1])
+	if !ok {
+		t.Fatal("expected gauge to provide statistics")
+	}
+	common.AssertEqual(t, float64(1), sm.FloatMin(), "gauge min")
+	common.AssertEqual(t, float64(20), sm.FloatMax(), "gauge max")
+	common.AssertEqual(t, uint64(4), sm.SampleSize(), "gauge sample size")
+	_, ok = AsStats(got[0])
+	common.AssertTrue(t, !ok, "expected counter not to provide statistics")
+}
@@ -0,0 +1,169 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+type (
+	// MetricSnapshot is a copy of the state of a metric at the time it
+	// was taken. Unlike a Metric, which reads its value from the telemetry
+	// segment on access, a snapshot doesn't change and remains valid after
+	// the segment has been detached. Stats is only set for metrics that
+	// provide statistics.
+	MetricSnapshot struct {
+		Path        string
+		Name        string
+		Type        MetricType
+		Desc        string
+		Units       string
+		Labels      map[string]string
+		EngineIndex uint32
+		Value       float64
+		Stats       *SnapshotStats
+	}
+
+	// SnapshotStats holds the statistics of a metric in a MetricSnapshot.
+	SnapshotStats struct {
+		Min        float64
+		Max        float64
+		Sum        float64
+		Mean       float64
+		StdDev     float64
+		SampleSize uint64
+	}
+)
+
+// snapshotMetric copies the current state of the metric into a snapshot.
+func snapshotMetric(m Metric) MetricSnapshot {
+	snap := MetricSnapshot{
+		Path:        m.Path(),
+		Name:        m.Name(),
+		Type:        m.Type(),
+		Desc:        m.Desc(),
+		Units:       m.Units(),
+		Labels:      make(map[string]string),
+		EngineIndex: m.EngineIndex(),
+		Value:       m.FloatValue(),
+	}
+	for k, v := range m.Labels() {
+		snap.Labels[k] = v
+	}
+
+	if sm, ok := AsStats(m); ok {
+		snap.Stats = &SnapshotStats{
+			Min:        sm.FloatMin(),
+			Max:        sm.FloatMax(),
+			Sum:        sm.FloatSum(),
+			Mean:       sm.Mean(),
+			StdDev:     sm.StdDev(),
+			SampleSize: sm.SampleSize(),
+		}
+	}
+
+	return snap
+}
+
+// frozenMetric is a Metric whose state was copied from the telemetry segment
+// it was read from, so that it can be handed to consumers that may access it
+// after the segment has been detached. The statistics methods are only valid
+// if IsStats returns true.
+type frozenMetric struct {
+	snap MetricSnapshot
+	str  string
+}
+
+// freezeMetric returns a copy of the current state of the metric that remains
+// valid after the telemetry it was read from has been detached.
+func freezeMetric(m Metric) Metric {
+	return &frozenMetric{
+		snap: m.Snapshot(),
+		str:  m.String(),
+	}
+}
+
+func (fm *frozenMetric) Path() string {
+	return fm.snap.Path
+}
+
+func (fm *frozenMetric) Name() string {
+	return fm.snap.Name
+}
+
+func (fm *frozenMetric) Type() MetricType {
+	return fm.snap.Type
+}
+
+func (fm *frozenMetric) Desc() string {
+	return fm.snap.Desc
+}
+
+func (fm *frozenMetric) Units() string {
+	return fm.snap.Units
+}
+
+func (fm *frozenMetric) Labels() map[string]string {
+	labels := make(map[string]string, len(fm.snap.Labels))
+	for k, v := range fm.snap.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+func (fm *frozenMetric) FloatValue() float64 {
+	return fm.snap.Value
+}
+
+func (fm *frozenMetric) String() string {
+	return fm.str
+}
+
+func (fm *frozenMetric) EngineIndex() uint32 {
+	return fm.snap.EngineIndex
+}
+
+func (fm *frozenMetric) IsStats() bool {
+	return fm.snap.Stats != nil
+}
+
+func (fm *frozenMetric) Snapshot() MetricSnapshot {
+	snap := fm.snap
+	snap.Labels = fm.Labels()
+	if fm.snap.Stats != nil {
+		stats := *fm.snap.Stats
+		snap.Stats = &stats
+	}
+	return snap
+}
+
+func (fm *frozenMetric) stats() SnapshotStats {
+	if fm.snap.Stats == nil {
+		return SnapshotStats{}
+	}
+	return *fm.snap.Stats
+}
+
+func (fm *frozenMetric) FloatMin() float64 {
+	return fm.stats().Min
+}
+
+func (fm *frozenMetric) FloatMax() float64 {
+	return fm.stats().Max
+}
+
+func (fm *frozenMetric) FloatSum() float64 {
+	return fm.stats().Sum
+}
+
+func (fm *frozenMetric) Mean() float64 {
+	return fm.stats().Mean
+}
+
+func (fm *frozenMetric) StdDev() float64 {
+	return fm.stats().StdDev
+}
+
+func (fm *frozenMetric) SampleSize() uint64 {
+	return fm.stats().SampleSize
+}
@@ -0,0 +1,112 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTelemetry_Snapshot(t *testing.T) {
+	gauge := NewMockStatsMetric("/pool/5d62ca83-8a4e-4bd4-9cb1-3e4b0f4a7a01/ops/latency", MetricTypeGauge, 42)
+	gauge.Description = "op latency"
+	gauge.Unit = "us"
+	gauge.Min = 1
+	gauge.Max = 64
+	gauge.Sum = 107
+	gauge.Avg = 35.5
+	gauge.Dev = 31.9
+	gauge.Samples = 3
+
+	for name, tc := range map[string]struct {
+		metric  Metric
+		expSnap MetricSnapshot
+	}{
+		"counter": {
+			metric: NewMockMetric("/io/ops", MetricTypeCounter, 7),
+			expSnap: MetricSnapshot{
+				Path:   "/io",
+				Name:   "ops",
+				Type:   MetricTypeCounter,
+				Labels: map[string]string{},
+				Value:  7,
+			},
+		},
+		"gauge with stats": {
+			metric: gauge,
+			expSnap: MetricSnapshot{
+				Path:   "/pool/5d62ca83-8a4e-4bd4-9cb1-3e4b0f4a7a01/ops",
+				Name:   "latency",
+				Type:   MetricTypeGauge,
+				Desc:   "op latency",
+				Units:  "us",
+				Labels: map[string]string{"pool": "5d62ca83-8a4e-4bd4-9cb1-3e4b0f4a7a01"},
+				Value:  42,
+				Stats: &SnapshotStats{
+					Min:        1,
+					Max:        64,
+					Sum:        107,
+					Mean:       35.5,
+					StdDev:     31.9,
+					SampleSize: 3,
+				},
+			},
+		},
+		"rank metric": {
+			metric: newRankMetric(NewMockMetric("/io/ops", MetricTypeCounter, 7), 1, 3),
+			expSnap: MetricSnapshot{
+				Path:        "/rank/3/io",
+				Name:        "ops",
+				Type:        MetricTypeCounter,
+				Labels:      map[string]string{"rank": "3"},
+				EngineIndex: 1,
+				Value:       7,
+			},
+		},
+		"rank stats metric": {
+			metric: newRankMetric(gauge, 0, 2),
+			expSnap: MetricSnapshot{
+				Path:   "/rank/2/pool/5d62ca83-8a4e-4bd4-9cb1-3e4b0f4a7a01/ops",
+				Name:   "latency",
+				Type:   MetricTypeGauge,
+				Desc:   "op latency",
+				Units:  "us",
+				Labels: map[string]string{"rank": "2", "pool": "5d62ca83-8a4e-4bd4-9cb1-3e4b0f4a7a01"},
+				Value:  42,
+				Stats: &SnapshotStats{
+					Min:        1,
+					Max:        64,
+					Sum:        107,
+					Mean:       35.5,
+					StdDev:     31.9,
+					SampleSize: 3,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotSnap := tc.metric.Snapshot()
+			if diff := cmp.Diff(tc.expSnap, gotSnap); diff != "" {
+				t.Fatalf("unexpected snapshot (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_Snapshot_Stable(t *testing.T) {
+	m := NewMockStatsMetric("/io/latency", MetricTypeGauge, 42)
+	m.Samples = 1
+
+	snap := m.Snapshot()
+	m.Value = 64
+	m.Samples = 2
+
+	if snap.Value != 42 || snap.Stats.SampleSize != 1 {
+		t.Fatalf("snapshot changed with metric: %+v", snap)
+	}
+}
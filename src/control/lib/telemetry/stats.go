@@ -0,0 +1,63 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import "math"
+
+// CombinedStats holds the statistics of multiple stats metrics combined as if
+// all of their samples had been recorded by a single metric.
+type CombinedStats struct {
+	Min        float64
+	Max        float64
+	Sum        float64
+	Mean       float64
+	StdDev     float64
+	SampleSize uint64
+}
+
+// CombineStats computes the sample size weighted mean and pooled sample
+// standard deviation of the given metrics. Metrics without samples are
+// ignored.
+func CombineStats(metrics []StatsMetric) *CombinedStats {
+	cs := &CombinedStats{
+		Min: math.MaxFloat64,
+		Max: -math.MaxFloat64,
+	}
+
+	var weightedSum, sumOfSquares float64
+	for _, m := range metrics {
+		n := m.SampleSize()
+		if n == 0 {
+			continue
+		}
+		mean := m.Mean()
+		stddev := m.StdDev()
+
+		cs.SampleSize += n
+		cs.Sum += m.FloatSum()
+		cs.Min = math.Min(cs.Min, m.FloatMin())
+		cs.Max = math.Max(cs.Max, m.FloatMax())
+
+		// recover each metric's sum of squares from its sample
+		// standard deviation (as calculated by gurt) and mean
+		weightedSum += float64(n) * mean
+		sumOfSquares += float64(n-1)*stddev*stddev + float64(n)*mean*mean
+	}
+
+	if cs.SampleSize == 0 {
+		return &CombinedStats{}
+	}
+
+	total := float64(cs.SampleSize)
+	cs.Mean = weightedSum / total
+	if cs.SampleSize > 1 {
+		variance := (sumOfSquares - total*cs.Mean*cs.Mean) / (total - 1)
+		cs.StdDev = math.Sqrt(math.Max(variance, 0))
+	}
+
+	return cs
+}
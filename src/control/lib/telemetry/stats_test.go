@@ -0,0 +1,131 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type testStatsMetric struct {
+	MockMetric
+	samples []float64
+}
+
+func newTestStatsMetric(samples ...float64) *testStatsMetric {
+	return &testStatsMetric{samples: samples}
+}
+
+func (tsm *testStatsMetric) FloatMin() float64 {
+	min := math.MaxFloat64
+	for _, s := range tsm.samples {
+		min = math.Min(min, s)
+	}
+	return min
+}
+
+func (tsm *testStatsMetric) FloatMax() float64 {
+	max := -math.MaxFloat64
+	for _, s := range tsm.samples {
+		max = math.Max(max, s)
+	}
+	return max
+}
+
+func (tsm *testStatsMetric) FloatSum() float64 {
+	var sum float64
+	for _, s := range tsm.samples {
+		sum += s
+	}
+	return sum
+}
+
+func (tsm *testStatsMetric) Mean() float64 {
+	if len(tsm.samples) == 0 {
+		return 0
+	}
+	return tsm.FloatSum() / float64(len(tsm.samples))
+}
+
+// StdDev returns the sample standard deviation as calculated by gurt.
+func (tsm *testStatsMetric) StdDev() float64 {
+	if len(tsm.samples) < 2 {
+		return 0
+	}
+	mean := tsm.Mean()
+	var sumSq float64
+	for _, s := range tsm.samples {
+		sumSq += (s - mean) * (s - mean)
+	}
+	return math.Sqrt(sumSq / float64(len(tsm.samples)-1))
+}
+
+func (tsm *testStatsMetric) SampleSize() uint64 {
+	return uint64(len(tsm.samples))
+}
+
+func TestTelemetry_CombineStats(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metrics  []StatsMetric
+		expStats *CombinedStats
+	}{
+		"no metrics": {
+			expStats: &CombinedStats{},
+		},
+		"no samples": {
+			metrics:  []StatsMetric{newTestStatsMetric(), newTestStatsMetric()},
+			expStats: &CombinedStats{},
+		},
+		"single metric": {
+			metrics: []StatsMetric{newTestStatsMetric(1, 2, 3)},
+			expStats: &CombinedStats{
+				Min: 1, Max: 3, Sum: 6, Mean: 2, StdDev: 1, SampleSize: 3,
+			},
+		},
+		"single sample": {
+			metrics: []StatsMetric{newTestStatsMetric(), newTestStatsMetric(5)},
+			expStats: &CombinedStats{
+				Min: 5, Max: 5, Sum: 5, Mean: 5, SampleSize: 1,
+			},
+		},
+		"unequal sample sizes": {
+			// equivalent to a single metric with samples 1-7
+			metrics: []StatsMetric{
+				newTestStatsMetric(1, 2, 3),
+				newTestStatsMetric(4, 5, 6, 7),
+			},
+			expStats: &CombinedStats{
+				Min: 1, Max: 7, Sum: 28, Mean: 4,
+				StdDev:     math.Sqrt(28.0 / 6),
+				SampleSize: 7,
+			},
+		},
+		"overlapping samples": {
+			metrics: []StatsMetric{
+				newTestStatsMetric(10, 20),
+				newTestStatsMetric(),
+				newTestStatsMetric(15, 15, 30, 10),
+			},
+			expStats: &CombinedStats{
+				Min: 10, Max: 30, Sum: 100, Mean: 100.0 / 6,
+				StdDev:     newTestStatsMetric(10, 20, 15, 15, 30, 10).StdDev(),
+				SampleSize: 6,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotStats := CombineStats(tc.metrics)
+
+			if diff := cmp.Diff(tc.expStats, gotStats, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Fatalf("unexpected stats (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
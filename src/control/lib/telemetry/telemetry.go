@@ -29,42 +29,16 @@ import (
 	"github.com/pkg/errors"
 )
 
-type MetricType int
-
-const (
-	MetricTypeUnknown   MetricType = 0
-	MetricTypeCounter   MetricType = C.D_TM_COUNTER
-	MetricTypeDuration  MetricType = C.D_TM_DURATION
-	MetricTypeGauge     MetricType = C.D_TM_GAUGE
-	MetricTypeSnapshot  MetricType = C.D_TM_TIMER_SNAPSHOT
-	MetricTypeTimestamp MetricType = C.D_TM_TIMESTAMP
-
-	BadUintVal  = ^uint64(0)
-	BadFloatVal = float64(BadUintVal)
-	BadIntVal   = int64(BadUintVal >> 1)
-	BadDuration = time.Duration(BadIntVal)
-)
-
-type (
-	Metric interface {
-		Path() string
-		Name() string
-		Type() MetricType
-		Desc() string
-		Units() string
-		FloatValue() float64
-		String() string
-	}
-
-	StatsMetric interface {
-		Metric
-		FloatMin() float64
-		FloatMax() float64
-		FloatSum() float64
-		Mean() float64
-		StdDev() float64
-		SampleSize() uint64
-	}
+// Compile-time checks that metric type values and the API version match the
+// gurt definitions.
+var (
+	_ = [1]int{}[MetricTypeDirectory-MetricType(C.D_TM_DIRECTORY)]
+	_ = [1]int{}[MetricTypeCounter-MetricType(C.D_TM_COUNTER)]
+	_ = [1]int{}[MetricTypeTimestamp-MetricType(C.D_TM_TIMESTAMP)]
+	_ = [1]int{}[MetricTypeSnapshot-MetricType(C.D_TM_TIMER_SNAPSHOT)]
+	_ = [1]int{}[MetricTypeDuration-MetricType(C.D_TM_DURATION)]
+	_ = [1]int{}[MetricTypeGauge-MetricType(C.D_TM_GAUGE)]
+	_ = [1]int{}[APIVersion-C.D_TM_VERSION]
 )
 
 type (
@@ -74,6 +48,13 @@ type (
 		rank *uint32
 		ctx  *C.struct_d_tm_context
 		root *C.struct_d_tm_node_t
+		refs int // contexts attached to the segment, protected by handles
+	}
+
+	// attachment records a single Init so that detaching the returned
+	// context more than once only releases the segment once.
+	attachment struct {
+		once sync.Once
 	}
 
 	metricBase struct {
@@ -95,9 +76,20 @@ type (
 )
 
 const (
-	handleKey telemetryKey = "handle"
+	handleKey     telemetryKey = "handle"
+	attachmentKey telemetryKey = "attachment"
 )
 
+// handles holds the telemetry segments attached by this process, keyed by
+// index, so that initializing an index that is already attached reuses the
+// existing context rather than opening another one.
+var handles = struct {
+	sync.Mutex
+	byIdx map[uint32]*handle
+}{
+	byIdx: make(map[uint32]*handle),
+}
+
 func getHandle(ctx context.Context) (*handle, error) {
 	handle, ok := ctx.Value(handleKey).(*handle)
 	if !ok {
@@ -110,6 +102,9 @@ func findNode(hdl *handle, name string) (*C.struct_d_tm_node_t, error) {
 	if hdl == nil {
 		return nil, errors.New("nil handle")
 	}
+	if hdl.ctx == nil {
+		return nil, errors.New("telemetry segment detached")
+	}
 
 	node := C.d_tm_find_metric(hdl.ctx, C.CString(name))
 	if node == nil {
@@ -130,10 +125,38 @@ func (mb *metricBase) Path() string {
 	return mb.path
 }
 
+// isValid returns true if the metric is attached to a node in a telemetry
+// segment and can be read. The handle must be locked by the caller.
+func (mb *metricBase) isValid() bool {
+	return mb != nil && mb.handle != nil && mb.handle.ctx != nil && mb.node != nil
+}
+
+// rLock read-locks the handle so that the segment can't be detached while the
+// metric is being read, returning false without holding the lock if the metric
+// can't be read. The lock is released with rUnlock.
+func (mb *metricBase) rLock() bool {
+	if mb == nil || mb.handle == nil {
+		return false
+	}
+
+	mb.handle.RLock()
+	if !mb.isValid() {
+		mb.handle.RUnlock()
+		return false
+	}
+
+	return true
+}
+
+func (mb *metricBase) rUnlock() {
+	mb.handle.RUnlock()
+}
+
 func (mb *metricBase) Name() string {
-	if mb == nil || mb.handle == nil || mb.node == nil {
+	if !mb.rLock() {
 		return "<nil>"
 	}
+	defer mb.rUnlock()
 
 	if mb.name == nil {
 		name := C.GoString((*C.char)(C.d_tm_conv_ptr(mb.handle.ctx, unsafe.Pointer(mb.node.dtn_name))))
@@ -143,8 +166,35 @@ func (mb *metricBase) Name() string {
 	return *mb.name
 }
 
+// EngineIndex returns the index of the engine segment the metric was read
+// from, or 0 if the metric isn't attached to a segment.
+func (mb *metricBase) EngineIndex() uint32 {
+	if mb == nil || mb.handle == nil {
+		return 0
+	}
+	return mb.handle.idx
+}
+
+func (mb *metricBase) IsStats() bool {
+	return false
+}
+
+// Labels returns the dimensions encoded in the metric path as labels, an
+// empty map is returned if the path doesn't contain any known patterns.
+func (mb *metricBase) Labels() map[string]string {
+	if mb == nil {
+		return map[string]string{}
+	}
+	return pathLabels(mb.path)
+}
+
 func (mb *metricBase) fillMetadata() {
-	if mb == nil || mb.handle == nil || mb.handle.root == nil {
+	if !mb.rLock() {
+		return
+	}
+	defer mb.rUnlock()
+
+	if mb.handle.root == nil {
 		return
 	}
 
@@ -167,22 +217,39 @@ func (mb *metricBase) fillMetadata() {
 }
 
 func (mb *metricBase) Desc() string {
+	if mb == nil {
+		return "<nil>"
+	}
 	if mb.desc == nil {
 		mb.fillMetadata()
 	}
+	if mb.desc == nil {
+		return "<nil>"
+	}
 
 	return *mb.desc
 }
 
 func (mb *metricBase) Units() string {
+	if mb == nil {
+		return "<nil>"
+	}
 	if mb.units == nil {
 		mb.fillMetadata()
 	}
+	if mb.units == nil {
+		return "<nil>"
+	}
 
 	return *mb.units
 }
 
 func (mb *metricBase) String() string {
+	if !mb.rLock() {
+		return "<nil>"
+	}
+	defer mb.rUnlock()
+
 	r, w, err := os.Pipe()
 	if err != nil {
 		return err.Error()
@@ -195,7 +262,11 @@ func (mb *metricBase) String() string {
 		return "fdopen() failed"
 	}
 
+	// the segment must stay attached until the node has been printed
+	done := make(chan struct{})
+	defer func() { <-done }()
 	go func() {
+		defer close(done)
 		C.d_tm_print_node(mb.handle.ctx, mb.node, C.int(0), C.CString(""), C.D_TM_STANDARD, C.int(0), f)
 		C.fclose(f)
 	}()
@@ -210,32 +281,66 @@ func (mb *metricBase) String() string {
 	return strings.TrimSpace(string(buf[:bytes.Index(buf, []byte{0})]))
 }
 
+func (sm *statsMetric) IsStats() bool {
+	return true
+}
+
 func (sm *statsMetric) FloatMin() float64 {
+	if sm == nil {
+		return BadFloatVal
+	}
 	return float64(sm.stats.dtm_min)
 }
 
 func (sm *statsMetric) FloatMax() float64 {
+	if sm == nil {
+		return BadFloatVal
+	}
 	return float64(sm.stats.dtm_max)
 }
 
 func (sm *statsMetric) FloatSum() float64 {
+	if sm == nil {
+		return BadFloatVal
+	}
 	return float64(sm.stats.dtm_sum)
 }
 
 func (sm *statsMetric) Mean() float64 {
+	if sm == nil {
+		return BadFloatVal
+	}
 	return float64(sm.stats.mean)
 }
 
 func (sm *statsMetric) StdDev() float64 {
+	if sm == nil {
+		return BadFloatVal
+	}
 	return float64(sm.stats.std_dev)
 }
 
 func (sm *statsMetric) SampleSize() uint64 {
+	if sm == nil {
+		return 0
+	}
 	return uint64(sm.stats.sample_size)
 }
 
-// Init initializes the telemetry bindings
-func Init(parent context.Context, idx uint32) (context.Context, error) {
+// getAPIVersion is a variable so that the version check can be tested.
+var getAPIVersion = GetAPIVersion
+
+// acquireHandle returns the handle for the segment with the given index,
+// attaching to the segment if it isn't already attached by this process.
+func acquireHandle(idx uint32) (*handle, error) {
+	handles.Lock()
+	defer handles.Unlock()
+
+	if hdl, found := handles.byIdx[idx]; found {
+		hdl.refs++
+		return hdl, nil
+	}
+
 	tmCtx := C.d_tm_open(C.int(idx))
 	if tmCtx == nil {
 		return nil, errors.Errorf("no shared memory segment found for idx: %d", idx)
@@ -243,54 +348,133 @@ func Init(parent context.Context, idx uint32) (context.Context, error) {
 
 	root := C.d_tm_get_root(tmCtx)
 	if root == nil {
+		C.d_tm_close(&tmCtx)
 		return nil, errors.Errorf("no root node found in shared memory segment for idx: %d", idx)
 	}
 
-	handle := &handle{
+	hdl := &handle{
 		idx:  idx,
 		ctx:  tmCtx,
 		root: root,
+		refs: 1,
 	}
+	handles.byIdx[idx] = hdl
 
-	return context.WithValue(parent, handleKey, handle), nil
+	return hdl, nil
 }
 
-// Detach detaches from the telemetry handle
+// releaseHandle drops a reference to the handle, detaching from the segment
+// once no references remain.
+func releaseHandle(hdl *handle) {
+	handles.Lock()
+	defer handles.Unlock()
+
+	hdl.refs--
+	if hdl.refs > 0 {
+		return
+	}
+	delete(handles.byIdx, hdl.idx)
+
+	hdl.Lock()
+	defer hdl.Unlock()
+
+	C.d_tm_close(&hdl.ctx)
+	hdl.ctx = nil
+	hdl.root = nil
+}
+
+// Init initializes the telemetry bindings for the segment with the given
+// index. If the segment is already attached by this process, the existing
+// attachment is shared rather than opening the segment again. Each context
+// returned must be released with Detach, the segment is detached once all
+// have been released.
+func Init(parent context.Context, idx uint32) (context.Context, error) {
+	if err := checkAPIVersion(getAPIVersion()); err != nil {
+		return nil, err
+	}
+
+	hdl, err := acquireHandle(idx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(parent, handleKey, hdl)
+	return context.WithValue(ctx, attachmentKey, &attachment{}), nil
+}
+
+// Detach releases the telemetry segment attached by the Init call that
+// returned the context. Detaching the same context again has no effect.
 func Detach(ctx context.Context) {
-	if hdl, err := getHandle(ctx); err != nil {
-		C.d_tm_close(&hdl.ctx)
+	hdl, err := getHandle(ctx)
+	if err != nil {
+		return
+	}
+	att, ok := ctx.Value(attachmentKey).(*attachment)
+	if !ok {
+		return
 	}
+
+	att.once.Do(func() {
+		releaseHandle(hdl)
+	})
 }
 
-func visit(hdl *handle, node *C.struct_d_tm_node_t, pathComps []string, out chan<- Metric) {
+func visit(hdl *handle, node *C.struct_d_tm_node_t, pathComps []string, joinPath func([]string) string, dirs bool, emit func(Metric) bool) bool {
 	var next *C.struct_d_tm_node_t
 
 	if node == nil {
-		return
+		return true
 	}
-	path := strings.Join(pathComps, "/")
+	path := joinPath(pathComps)
 	name := C.GoString((*C.char)(C.d_tm_conv_ptr(hdl.ctx, unsafe.Pointer(node.dtn_name))))
 
+	more := true
 	switch node.dtn_type {
 	case C.D_TM_DIRECTORY:
+		if dirs {
+			more = emit(newDirectory(hdl, path, &name, node))
+		}
 		next = (*C.struct_d_tm_node_t)(C.d_tm_conv_ptr(hdl.ctx, unsafe.Pointer(node.dtn_child)))
-		if next != nil {
-			visit(hdl, next, append(pathComps, name), out)
+		if more && next != nil {
+			more = visit(hdl, next, append(pathComps, name), joinPath, dirs, emit)
 		}
 	case C.D_TM_GAUGE:
-		out <- newGauge(hdl, path, &name, node)
+		more = emit(newGauge(hdl, path, &name, node))
 	case C.D_TM_COUNTER:
-		out <- newCounter(hdl, path, &name, node)
+		more = emit(newCounter(hdl, path, &name, node))
+	case C.D_TM_TIMESTAMP:
+		more = emit(newTimestamp(hdl, path, &name, node))
 	default:
 	}
+	if !more {
+		return false
+	}
 
 	next = (*C.struct_d_tm_node_t)(C.d_tm_conv_ptr(hdl.ctx, unsafe.Pointer(node.dtn_sibling)))
 	if next != nil && next != node {
-		visit(hdl, next, pathComps, out)
+		return visit(hdl, next, pathComps, joinPath, dirs, emit)
 	}
+	return true
 }
 
+// CollectMetrics walks the subtree at dirname and sends the metrics found to
+// the output channel without buffering, closing it when done. See
+// CollectMetricsWithOptions for details.
 func CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	return CollectMetricsWithOptions(ctx, dirname, out, CollectOptions{})
+}
+
+// CollectMetricsWithOptions walks the subtree at dirname and sends the metrics
+// found to the output channel, closing it when done. Sends are abandoned if
+// the context is cancelled, in which case the walk stops early and the context
+// error is returned. The output channel is not closed if the subtree can't be
+// found. See CollectOptions for control over buffering and the form of the
+// paths of the metrics sent.
+func CollectMetricsWithOptions(ctx context.Context, dirname string, out chan<- Metric, opts CollectOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
 	hdl, err := getHandle(ctx)
 	if err != nil {
 		return err
@@ -317,17 +501,58 @@ func CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) erro
 	if rc != C.DER_SUCCESS {
 		return errors.Errorf("unable to find entry for %s.  rc = %d\n", dirname, rc)
 	}
+	defer C.d_tm_list_free(nl)
+
+	joinPath := func(pathComps []string) string {
+		return opts.metricDir(dirname, pathComps)
+	}
+
+	return sendMetrics(ctx, opts, func(emit func(Metric) bool) {
+		visit(hdl, nl.dtnl_node, nil, joinPath, opts.Directories, emit)
+	}, out)
+}
+
+func newMetric(hdl *handle, name string, node *C.struct_d_tm_node_t) (Metric, error) {
+	switch node.dtn_type {
+	case C.D_TM_COUNTER:
+		return newCounter(hdl, "", &name, node), nil
+	case C.D_TM_GAUGE:
+		return newGauge(hdl, "", &name, node), nil
+	case C.D_TM_TIMESTAMP:
+		return newTimestamp(hdl, "", &name, node), nil
+	default:
+		return nil, errors.Errorf("metric named %q has unsupported type %d", name, node.dtn_type)
+	}
+}
 
-	var pathComps []string
-	if dirname != "" {
-		pathComps = append(pathComps, dirname)
+// GetMetrics resolves the metrics at the given paths in a single pass. The
+// returned map contains an entry for each metric found and, if any paths could
+// not be resolved, the returned error is a PathErrors with an entry for each.
+func GetMetrics(ctx context.Context, paths []string) (map[string]Metric, error) {
+	hdl, err := getHandle(ctx)
+	if err != nil {
+		return nil, err
 	}
-	visit(hdl, nl.dtnl_node, pathComps, out)
 
-	close(out)
-	C.d_tm_list_free(nl)
+	hdl.RLock()
+	defer hdl.RUnlock()
 
-	return nil
+	metrics := make(map[string]Metric)
+	pathErrs := make(PathErrors)
+	for _, path := range paths {
+		node, err := findNode(hdl, path)
+		if err == nil {
+			metrics[path], err = newMetric(hdl, path, node)
+		}
+		if err != nil {
+			pathErrs[path] = err
+		}
+	}
+
+	if len(pathErrs) > 0 {
+		return metrics, pathErrs
+	}
+	return metrics, nil
 }
 
 func GetRank(ctx context.Context) (uint32, error) {
@@ -336,22 +561,138 @@ func GetRank(ctx context.Context) (uint32, error) {
 		return 0, err
 	}
 
+	hdl.RLock()
+	rank := hdl.rank
+	hdl.RUnlock()
+	if rank != nil {
+		return *rank, nil
+	}
+
+	// the gauge takes the handle lock when read so it can't be held here
+	g, err := GetGauge(ctx, "/rank")
+	if err != nil {
+		return 0, err
+	}
+	r := uint32(g.Value())
+
 	hdl.Lock()
-	defer hdl.Unlock()
+	hdl.rank = &r
+	hdl.Unlock()
 
-	if hdl.rank == nil {
-		g, err := GetGauge(ctx, "/rank")
-		if err != nil {
-			return 0, err
-		}
-		r := uint32(g.Value())
-		hdl.rank = &r
+	return r, nil
+}
+
+// GetRankOrUnknown returns the rank published in the telemetry segment
+// attached to the context, or UnknownRank if the segment has no /rank gauge.
+// Other failures are returned as with GetRank.
+func GetRankOrUnknown(ctx context.Context) (uint32, error) {
+	hdl, err := getHandle(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	hdl.RLock()
+	_, err = findNode(hdl, "/rank")
+	hdl.RUnlock()
+	if err != nil {
+		return UnknownRank, nil
 	}
 
-	return *hdl.rank, nil
+	return GetRank(ctx)
 }
 
 func GetAPIVersion() int {
 	version := C.d_tm_get_version()
 	return int(version)
 }
+
+type defaultSource struct{}
+
+func (defaultSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	return CollectMetrics(ctx, dirname, out)
+}
+
+func (defaultSource) GetRank(ctx context.Context) (uint32, error) {
+	return GetRank(ctx)
+}
+
+// DefaultSource returns a Source which reads from the shared memory
+// telemetry segment attached to the supplied context.
+func DefaultSource() Source {
+	return defaultSource{}
+}
+
+// Watch walks the subtree at dirname of the telemetry attached to the context
+// on every interval and sends the resulting samples to the output channel,
+// until the context is cancelled. See WatchSource for details.
+func Watch(ctx context.Context, dirname string, interval time.Duration, out chan<- MetricSample) {
+	WatchSource(ctx, DefaultSource(), dirname, interval, out)
+}
+
+func openSegment(parent context.Context, idx uint32) (context.Context, func(), error) {
+	ctx, err := Init(parent, idx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ctx, func() { Detach(ctx) }, nil
+}
+
+// attachedSource is a Source which reads from the telemetry segment attached
+// to its context, regardless of the context supplied by the caller.
+type attachedSource struct {
+	seg context.Context
+}
+
+func (as attachedSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	hdl, err := getHandle(as.seg)
+	if err != nil {
+		return err
+	}
+
+	return CollectMetrics(context.WithValue(ctx, handleKey, hdl), dirname, out)
+}
+
+func (as attachedSource) GetRank(_ context.Context) (uint32, error) {
+	return GetRank(as.seg)
+}
+
+// OpenSource attaches to the telemetry segment of the engine with the given
+// index and returns a Source which reads from it, along with a function that
+// detaches from the segment.
+func OpenSource(parent context.Context, idx uint32) (Source, func(), error) {
+	ctx, cleanup, err := openSegment(parent, idx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachedSource{seg: ctx}, cleanup, nil
+}
+
+// CollectAll collects the metrics from the telemetry segments of each of the
+// given engine indices, merging them into the output channel with paths
+// prefixed by the rank of the engine. See collectSegments for details.
+func CollectAll(ctx context.Context, indices []uint32, out chan<- Metric) error {
+	return collectSegments(ctx, DefaultSource(), openSegment, indices, out)
+}
+
+// CollectMap returns the values of the scalar metrics in the subtree at
+// dirname of the telemetry attached to the context, keyed by path. See
+// CollectSourceMap for details.
+func CollectMap(ctx context.Context, dirname string) (map[string]float64, error) {
+	return CollectSourceMap(ctx, DefaultSource(), dirname)
+}
+
+// FindMetrics returns the metrics in the telemetry attached to the context
+// whose paths match the given glob or anchored regular expression. See
+// FindSourceMetrics for details.
+func FindMetrics(ctx context.Context, pattern string) ([]Metric, error) {
+	return FindSourceMetrics(ctx, DefaultSource(), pattern)
+}
+
+// Rates reads the subtree at dirname of the telemetry attached to the context
+// twice, interval apart, and returns the per-second rate of each counter. See
+// RatesSource for details.
+func Rates(ctx context.Context, dirname string, interval time.Duration) ([]MetricRate, error) {
+	return RatesSource(ctx, DefaultSource(), dirname, interval)
+}
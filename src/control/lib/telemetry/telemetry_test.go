@@ -3,11 +3,20 @@
 //
 // SPDX-License-Identifier: BSD-2-Clause-Patent
 //
+// +build linux,amd64
+//
 
 package telemetry
 
 import (
+	"context"
+	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
 )
@@ -25,8 +34,9 @@ func TestTelemetry_Basics(t *testing.T) {
 		common.AssertEqual(t, tm.units, m.Units(), "Units() failed")
 		common.AssertEqual(t, tm.cur, m.FloatValue(), "FloatValue() failed")
 		common.AssertEqual(t, tm.str, m.String(), "String() failed")
+		common.AssertEqual(t, uint32(42), m.EngineIndex(), "EngineIndex() failed")
 
-		if sm, ok := m.(StatsMetric); ok {
+		if sm, ok := AsStats(m); ok {
 			common.AssertEqual(t, tm.min, sm.FloatMin(), "FloatMin() failed")
 			common.AssertEqual(t, tm.max, sm.FloatMax(), "FloatMax() failed")
 			common.AssertEqual(t, tm.sum, sm.FloatSum(), "FloatSum() failed")
@@ -56,3 +66,662 @@ func TestTelemetry_Basics(t *testing.T) {
 		}
 	}
 }
+
+func TestTelemetry_CollectMetrics_EngineIndex(t *testing.T) {
+	ctx, testMetrics := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	out := make(chan Metric)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- CollectMetrics(ctx, "", out)
+	}()
+
+	found := 0
+	for m := range out {
+		common.AssertEqual(t, uint32(42), m.EngineIndex(), m.Name()+": EngineIndex() failed")
+		found++
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	if found < len(testMetrics) {
+		t.Fatalf("expected at least %d metrics, got %d", len(testMetrics), found)
+	}
+}
+
+func TestTelemetry_CollectMetrics_Directories(t *testing.T) {
+	ctx, _ := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	addTestGauge(t, "dirs/a/b/leaf", 1)
+
+	type found struct {
+		Type MetricType
+		Path string
+		Name string
+	}
+
+	for name, tc := range map[string]struct {
+		dirs     bool
+		expFound []found
+	}{
+		"leaves only": {
+			expFound: []found{
+				{MetricTypeGauge, "dirs/a/b", "leaf"},
+			},
+		},
+		"with directories": {
+			dirs: true,
+			expFound: []found{
+				{MetricTypeDirectory, "", "dirs"},
+				{MetricTypeDirectory, "dirs", "a"},
+				{MetricTypeDirectory, "dirs/a", "b"},
+				{MetricTypeGauge, "dirs/a/b", "leaf"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := CollectOptions{
+				StripPrefix: true,
+				Directories: tc.dirs,
+			}
+
+			out := make(chan Metric)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- CollectMetricsWithOptions(ctx, "dirs", out, opts)
+			}()
+
+			var gotFound []found
+			for m := range out {
+				gotFound = append(gotFound, found{m.Type(), m.Path(), m.Name()})
+				if m.Type() == MetricTypeDirectory {
+					common.AssertEqual(t, float64(0), m.FloatValue(), "FloatValue()")
+					common.AssertEqual(t, "", m.Desc(), "Desc()")
+				}
+			}
+			if err := <-errCh; err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expFound, gotFound); diff != "" {
+				t.Fatalf("unexpected metrics (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_IsStats(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metric   Metric
+		expStats bool
+	}{
+		"gauge without stats": {
+			metric: &Gauge{},
+		},
+		"counter": {
+			metric: &Counter{},
+		},
+		"timestamp": {
+			metric: &Timestamp{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.expStats, tc.metric.IsStats(), "IsStats()")
+
+			_, gotStats := AsStats(tc.metric)
+			common.AssertEqual(t, tc.expStats, gotStats, "AsStats()")
+		})
+	}
+}
+
+func TestTelemetry_MetricTypePredicates(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metric Metric
+		check  func(MetricType) bool
+	}{
+		"directory": {
+			metric: &Directory{},
+			check:  MetricType.IsDirectory,
+		},
+		"counter": {
+			metric: &Counter{},
+			check:  MetricType.IsCounter,
+		},
+		"timestamp": {
+			metric: &Timestamp{},
+			check:  MetricType.IsTimestamp,
+		},
+		"gauge": {
+			metric: &Gauge{},
+			check:  MetricType.IsGauge,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mt := tc.metric.Type()
+			common.AssertTrue(t, tc.check(mt), "expected predicate to match "+mt.String())
+
+			matches := 0
+			for _, pred := range []func(MetricType) bool{
+				MetricType.IsDirectory,
+				MetricType.IsCounter,
+				MetricType.IsTimestamp,
+				MetricType.IsSnapshot,
+				MetricType.IsDuration,
+				MetricType.IsGauge,
+			} {
+				if pred(mt) {
+					matches++
+				}
+			}
+			common.AssertEqual(t, 1, matches, "number of matching predicates")
+		})
+	}
+
+	common.AssertTrue(t, (&Duration{}).Type().IsDuration(), "duration")
+}
+
+func TestTelemetry_ZeroValueMetric(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metric  Metric
+		expType MetricType
+	}{
+		"counter": {
+			metric:  &Counter{},
+			expType: MetricTypeCounter,
+		},
+		"gauge": {
+			metric:  &Gauge{},
+			expType: MetricTypeGauge,
+		},
+		"timestamp": {
+			metric:  &Timestamp{},
+			expType: MetricTypeTimestamp,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := tc.metric
+
+			common.AssertEqual(t, "", m.Path(), "Path()")
+			common.AssertEqual(t, "<nil>", m.Name(), "Name()")
+			common.AssertEqual(t, tc.expType, m.Type(), "Type()")
+			common.AssertEqual(t, "<nil>", m.Desc(), "Desc()")
+			common.AssertEqual(t, "<nil>", m.Units(), "Units()")
+			common.AssertEqual(t, 0, len(m.Labels()), "Labels()")
+			common.AssertEqual(t, BadFloatVal, m.FloatValue(), "FloatValue()")
+			common.AssertEqual(t, "<nil>", m.String(), "String()")
+			common.AssertEqual(t, uint32(0), m.EngineIndex(), "EngineIndex()")
+			common.AssertEqual(t, false, m.IsStats(), "IsStats()")
+
+			sm, ok := m.(StatsMetric)
+			if !ok {
+				return
+			}
+			common.AssertEqual(t, BadFloatVal, sm.FloatMin(), "FloatMin()")
+			common.AssertEqual(t, BadFloatVal, sm.FloatMax(), "FloatMax()")
+			common.AssertEqual(t, BadFloatVal, sm.FloatSum(), "FloatSum()")
+			common.AssertEqual(t, BadFloatVal, sm.Mean(), "Mean()")
+			common.AssertEqual(t, BadFloatVal, sm.StdDev(), "StdDev()")
+			common.AssertEqual(t, uint64(0), sm.SampleSize(), "SampleSize()")
+		})
+	}
+}
+
+func TestTelemetry_NilMetric(t *testing.T) {
+	var mb *metricBase
+	common.AssertEqual(t, "<nil>", mb.Path(), "Path()")
+	common.AssertEqual(t, "<nil>", mb.Name(), "Name()")
+	common.AssertEqual(t, "<nil>", mb.Desc(), "Desc()")
+	common.AssertEqual(t, "<nil>", mb.Units(), "Units()")
+	common.AssertEqual(t, "<nil>", mb.String(), "String()")
+	common.AssertEqual(t, 0, len(mb.Labels()), "Labels()")
+	common.AssertEqual(t, uint32(0), mb.EngineIndex(), "EngineIndex()")
+
+	var sm *statsMetric
+	common.AssertEqual(t, BadFloatVal, sm.FloatMin(), "FloatMin()")
+	common.AssertEqual(t, BadFloatVal, sm.FloatMax(), "FloatMax()")
+	common.AssertEqual(t, BadFloatVal, sm.FloatSum(), "FloatSum()")
+	common.AssertEqual(t, BadFloatVal, sm.Mean(), "Mean()")
+	common.AssertEqual(t, BadFloatVal, sm.StdDev(), "StdDev()")
+	common.AssertEqual(t, uint64(0), sm.SampleSize(), "SampleSize()")
+
+	var c *Counter
+	common.AssertEqual(t, BadUintVal, c.Value(), "Counter.Value()")
+	var g *Gauge
+	common.AssertEqual(t, BadUintVal, g.Value(), "Gauge.Value()")
+	var ts *Timestamp
+	common.AssertEqual(t, time.Time{}, ts.Value(), "Timestamp.Value()")
+	var d *Duration
+	common.AssertEqual(t, BadDuration, d.Value(), "Duration.Value()")
+	common.AssertEqual(t, BadDuration, (&Duration{}).Value(), "zero Duration.Value()")
+}
+
+func TestTelemetry_GaugeStats(t *testing.T) {
+	ctx, _ := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	for name, tc := range map[string]struct {
+		vals       []uint64
+		expStats   bool
+		expMin     float64
+		expMax     float64
+		expMean    float64
+		expStdDev  float64
+		expSamples uint64
+	}{
+		"with stats": {
+			vals:       []uint64{2, 4, 4, 4, 5, 5, 7, 9},
+			expStats:   true,
+			expMin:     2,
+			expMax:     9,
+			expMean:    5,
+			expStdDev:  2.138089935299395,
+			expSamples: 8,
+		},
+		"without stats": {
+			expMin:    BadFloatVal,
+			expMax:    BadFloatVal,
+			expMean:   BadFloatVal,
+			expStdDev: BadFloatVal,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gaugeName := "stats_gauge_" + strings.ReplaceAll(name, " ", "_")
+			addTestGauge(t, gaugeName, tc.vals...)
+
+			g, err := GetGauge(ctx, gaugeName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			common.AssertEqual(t, tc.expStats, g.IsStats(), "IsStats()")
+			_, gotStats := AsStats(g)
+			common.AssertEqual(t, tc.expStats, gotStats, "AsStats()")
+
+			common.AssertEqual(t, tc.expMin, g.FloatMin(), "FloatMin()")
+			common.AssertEqual(t, tc.expMax, g.FloatMax(), "FloatMax()")
+			common.AssertEqual(t, tc.expMean, g.Mean(), "Mean()")
+			common.AssertEqual(t, tc.expStdDev, g.StdDev(), "StdDev()")
+			common.AssertEqual(t, tc.expSamples, g.SampleSize(), "SampleSize()")
+		})
+	}
+}
+
+func TestTelemetry_Snapshot_Detached(t *testing.T) {
+	ctx, testMetrics := setupTestMetrics(t)
+
+	tm := testMetrics[MetricTypeGauge]
+	g, err := GetGauge(ctx, tm.name)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+	snap := g.Snapshot()
+
+	// the snapshot must not refer to the segment once detached
+	cleanupTestMetrics(ctx, t)
+
+	expSnap := MetricSnapshot{
+		Name:        tm.name,
+		Type:        MetricTypeGauge,
+		Desc:        tm.desc,
+		Units:       tm.units,
+		Labels:      map[string]string{},
+		EngineIndex: 42,
+		Value:       tm.cur,
+		Stats: &SnapshotStats{
+			Min:        tm.min,
+			Max:        tm.max,
+			Sum:        tm.sum,
+			Mean:       tm.mean,
+			StdDev:     tm.stddev,
+			SampleSize: 3,
+		},
+	}
+	if diff := cmp.Diff(expSnap, snap); diff != "" {
+		t.Fatalf("unexpected snapshot after detach (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestTelemetry_Metric_Detached(t *testing.T) {
+	ctx, testMetrics := setupTestMetrics(t)
+
+	c, err := GetCounter(ctx, testMetrics[MetricTypeCounter].name)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+	g, err := GetGauge(ctx, testMetrics[MetricTypeGauge].name)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+
+	// metrics must not read from the segment once detached
+	cleanupTestMetrics(ctx, t)
+
+	common.AssertEqual(t, BadUintVal, c.Value(), "counter Value()")
+	common.AssertEqual(t, BadUintVal, g.Value(), "gauge Value()")
+	common.AssertEqual(t, "<nil>", c.String(), "counter String()")
+	if _, err := GetGauge(ctx, testMetrics[MetricTypeGauge].name); err == nil {
+		t.Fatal("expected lookup in detached segment to fail")
+	}
+}
+
+func TestTelemetry_Init_Reattach(t *testing.T) {
+	ctx, testMetrics := setupTestMetrics(t)
+
+	hdl, err := getHandle(ctx)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+
+	reCtx, err := Init(context.Background(), 42)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+	reHdl, err := getHandle(reCtx)
+	if err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+
+	common.AssertTrue(t, hdl == reHdl, "expected re-init to reuse the attached segment")
+	common.AssertEqual(t, 2, hdl.refs, "references after re-init")
+	common.AssertEqual(t, 1, len(handles.byIdx), "attached segments after re-init")
+
+	// detaching more than once only releases the segment once, and the
+	// segment remains readable through the other context
+	Detach(reCtx)
+	Detach(reCtx)
+	common.AssertEqual(t, 1, hdl.refs, "references after detach")
+	if _, err := GetGauge(ctx, testMetrics[MetricTypeGauge].name); err != nil {
+		cleanupTestMetrics(ctx, t)
+		t.Fatal(err)
+	}
+
+	cleanupTestMetrics(ctx, t)
+	common.AssertEqual(t, 0, len(handles.byIdx), "attached segments after cleanup")
+	common.AssertTrue(t, hdl.ctx == nil, "expected segment to be detached")
+}
+
+func TestTelemetry_GetRankOrUnknown(t *testing.T) {
+	ctx, _ := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	// test segment doesn't publish a rank
+	if _, err := GetRank(ctx); err == nil {
+		t.Fatal("expected GetRank to fail without /rank gauge")
+	}
+	rank, err := GetRankOrUnknown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, UnknownRank, rank, "rank without /rank gauge")
+
+	addTestGauge(t, "rank", 3)
+	rank, err = GetRankOrUnknown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, uint32(3), rank, "rank with /rank gauge")
+}
+
+func TestTelemetry_Init_VersionMismatch(t *testing.T) {
+	realGetAPIVersion := getAPIVersion
+	defer func() {
+		getAPIVersion = realGetAPIVersion
+	}()
+	getAPIVersion = func() int {
+		return APIVersion + 1
+	}
+
+	_, err := Init(context.Background(), 42)
+	if errors.Cause(err) != ErrTelemetryVersionMismatch {
+		t.Fatalf("expected version mismatch error, got %v", err)
+	}
+}
+
+func TestTelemetry_GetMetrics(t *testing.T) {
+	ctx, testMetrics := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	gaugeName := testMetrics[MetricTypeGauge].name
+	counterName := testMetrics[MetricTypeCounter].name
+
+	for name, tc := range map[string]struct {
+		paths      []string
+		expFound   map[string]MetricType
+		expMissing []string
+	}{
+		"all found": {
+			paths: []string{gaugeName, counterName},
+			expFound: map[string]MetricType{
+				gaugeName:   MetricTypeGauge,
+				counterName: MetricTypeCounter,
+			},
+		},
+		"some missing": {
+			paths: []string{gaugeName, "missing_one", counterName, "missing_two"},
+			expFound: map[string]MetricType{
+				gaugeName:   MetricTypeGauge,
+				counterName: MetricTypeCounter,
+			},
+			expMissing: []string{"missing_one", "missing_two"},
+		},
+		"all missing": {
+			paths:      []string{"missing_one"},
+			expFound:   map[string]MetricType{},
+			expMissing: []string{"missing_one"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotMetrics, gotErr := GetMetrics(ctx, tc.paths)
+
+			gotFound := make(map[string]MetricType)
+			for path, m := range gotMetrics {
+				gotFound[path] = m.Type()
+			}
+			if diff := cmp.Diff(tc.expFound, gotFound); diff != "" {
+				t.Fatalf("unexpected metrics (-want, +got):\n%s\n", diff)
+			}
+
+			if len(tc.expMissing) == 0 {
+				if gotErr != nil {
+					t.Fatal(gotErr)
+				}
+				return
+			}
+
+			pathErrs, ok := gotErr.(PathErrors)
+			if !ok {
+				t.Fatalf("expected PathErrors, got %T (%v)", gotErr, gotErr)
+			}
+			for _, path := range tc.expMissing {
+				common.CmpErr(t, errors.Errorf("unable to find metric named %q", path), pathErrs[path])
+			}
+			common.AssertEqual(t, len(tc.expMissing), len(pathErrs), "unexpected number of path errors")
+		})
+	}
+}
+
+func TestTelemetry_pathLabels(t *testing.T) {
+	poolUUID := "3c34a1cc-5bd2-4b5e-8fba-c0a8bcfbf7c1"
+
+	for name, tc := range map[string]struct {
+		path      string
+		expLabels map[string]string
+	}{
+		"empty path": {
+			expLabels: map[string]string{},
+		},
+		"unknown pattern": {
+			path:      "/some/other/metric",
+			expLabels: map[string]string{},
+		},
+		"pool and target": {
+			path: "/pool/" + poolUUID + "/target/3/ops",
+			expLabels: map[string]string{
+				"pool":   poolUUID,
+				"target": "3",
+			},
+		},
+		"io target": {
+			path: "io/12/latency",
+			expLabels: map[string]string{
+				"target": "12",
+			},
+		},
+		"net rank and context": {
+			path: "/net/1/4/req_timeout",
+			expLabels: map[string]string{
+				"rank":    "1",
+				"context": "4",
+			},
+		},
+		"invalid pool uuid": {
+			path:      "/pool/not-a-uuid/target/3",
+			expLabels: map[string]string{"target": "3"},
+		},
+		"missing value": {
+			path:      "/pool",
+			expLabels: map[string]string{},
+		},
+		"incomplete net dimensions": {
+			path:      "/net/1",
+			expLabels: map[string]string{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotLabels := pathLabels(tc.path)
+			if diff := cmp.Diff(tc.expLabels, gotLabels); diff != "" {
+				t.Fatalf("unexpected labels (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTelemetry_counterDelta(t *testing.T) {
+	for name, tc := range map[string]struct {
+		previous float64
+		current  float64
+		expDelta float64
+		expReset bool
+	}{
+		"no change": {
+			previous: 5,
+			current:  5,
+		},
+		"increment": {
+			previous: 5,
+			current:  12,
+			expDelta: 7,
+		},
+		"first read": {
+			current:  3,
+			expDelta: 3,
+		},
+		"reset": {
+			previous: 12,
+			current:  4,
+			expDelta: 4,
+			expReset: true,
+		},
+		"reset to zero": {
+			previous: 12,
+			expReset: true,
+		},
+		"bad current value": {
+			previous: 12,
+			current:  BadFloatVal,
+		},
+		"bad previous value": {
+			previous: BadFloatVal,
+			current:  12,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotDelta, gotReset := counterDelta(tc.previous, tc.current)
+
+			common.AssertEqual(t, tc.expDelta, gotDelta, "unexpected delta")
+			common.AssertEqual(t, tc.expReset, gotReset, "unexpected reset")
+		})
+	}
+}
+
+func TestTelemetry_timestampConversion(t *testing.T) {
+	for name, tc := range map[string]struct {
+		raw      uint64
+		expTime  time.Time
+		expFloat float64
+	}{
+		"epoch": {
+			raw:      0,
+			expTime:  time.Unix(0, 0),
+			expFloat: 0,
+		},
+		"boot time": {
+			raw:      1617235200,
+			expTime:  time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expFloat: 1617235200,
+		},
+		"bad value": {
+			raw:      BadUintVal,
+			expTime:  time.Time{},
+			expFloat: BadFloatVal,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotTime := timestampTime(tc.raw)
+			if !gotTime.Equal(tc.expTime) {
+				t.Fatalf("expected time %s, got %s", tc.expTime, gotTime)
+			}
+			common.AssertEqual(t, tc.expFloat, timestampFloat(tc.raw), "unexpected float value")
+		})
+	}
+}
+
+func TestTelemetry_FindClientSegments_Producer(t *testing.T) {
+	ctx, _ := setupTestMetrics(t)
+	defer cleanupTestMetrics(ctx, t)
+
+	common.AssertEqual(t, libSegmentKeyBase(), int64(segmentKeyBase), "segment key base")
+
+	segments, err := FindClientSegments(DefaultSysvShmPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, seg := range segments {
+		if seg.PID == os.Getpid() && seg.Idx == 42 {
+			found = true
+		}
+	}
+	common.AssertTrue(t, found, "segment created by this process not found")
+
+	src, detach, err := OpenClientSource(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer detach()
+
+	out := make(chan Metric)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- src.CollectMetrics(context.Background(), "", out)
+	}()
+
+	found = false
+	for m := range out {
+		if m.Name() == "test_counter" {
+			found = true
+		}
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	common.AssertTrue(t, found, "metric not read from client source")
+}
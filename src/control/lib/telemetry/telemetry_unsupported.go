@@ -0,0 +1,147 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+// +build !linux !amd64
+//
+
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// The telemetry bindings read the shared memory segments created by the gurt
+// library, which is only available on linux/amd64. The functions below stand
+// in for them elsewhere so that tools which optionally consume telemetry can
+// still be built, and fail with ErrTelemetryUnsupported when it is requested.
+
+// Init returns ErrTelemetryUnsupported.
+func Init(parent context.Context, idx uint32) (context.Context, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// Detach does nothing as there is never a telemetry handle to detach from.
+func Detach(ctx context.Context) {}
+
+// CollectMetrics returns ErrTelemetryUnsupported.
+func CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	return ErrTelemetryUnsupported
+}
+
+// CollectMetricsWithOptions returns ErrTelemetryUnsupported.
+func CollectMetricsWithOptions(ctx context.Context, dirname string, out chan<- Metric, opts CollectOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	return ErrTelemetryUnsupported
+}
+
+// GetMetrics returns ErrTelemetryUnsupported.
+func GetMetrics(ctx context.Context, paths []string) (map[string]Metric, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// GetRank returns ErrTelemetryUnsupported.
+func GetRank(ctx context.Context) (uint32, error) {
+	return 0, ErrTelemetryUnsupported
+}
+
+// GetRankOrUnknown returns ErrTelemetryUnsupported.
+func GetRankOrUnknown(ctx context.Context) (uint32, error) {
+	return 0, ErrTelemetryUnsupported
+}
+
+// GetAPIVersion returns 0, which no version check will accept.
+func GetAPIVersion() int {
+	return 0
+}
+
+type defaultSource struct{}
+
+func (defaultSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	return CollectMetrics(ctx, dirname, out)
+}
+
+func (defaultSource) GetRank(ctx context.Context) (uint32, error) {
+	return GetRank(ctx)
+}
+
+// DefaultSource returns a Source whose methods return ErrTelemetryUnsupported.
+func DefaultSource() Source {
+	return defaultSource{}
+}
+
+// Watch closes the output channel and returns immediately.
+func Watch(ctx context.Context, dirname string, interval time.Duration, out chan<- MetricSample) {
+	close(out)
+}
+
+// OpenSource returns ErrTelemetryUnsupported.
+func OpenSource(parent context.Context, idx uint32) (Source, func(), error) {
+	return nil, nil, ErrTelemetryUnsupported
+}
+
+// CollectAll returns ErrTelemetryUnsupported.
+func CollectAll(ctx context.Context, indices []uint32, out chan<- Metric) error {
+	return ErrTelemetryUnsupported
+}
+
+// CollectMap returns ErrTelemetryUnsupported.
+func CollectMap(ctx context.Context, dirname string) (map[string]float64, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// FindMetrics returns ErrTelemetryUnsupported.
+func FindMetrics(ctx context.Context, pattern string) ([]Metric, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// Rates returns ErrTelemetryUnsupported.
+func Rates(ctx context.Context, dirname string, interval time.Duration) ([]MetricRate, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+type (
+	// ProducerCounter is a counter metric published by this process.
+	ProducerCounter struct{}
+
+	// ProducerGauge is a gauge metric published by this process.
+	ProducerGauge struct{}
+)
+
+// InitProducer returns ErrTelemetryUnsupported.
+func InitProducer(id uint32, memSize uint64, retain bool) error {
+	return ErrTelemetryUnsupported
+}
+
+// FiniProducer does nothing as there is never a producer segment to release.
+func FiniProducer() {}
+
+// AddCounter returns ErrTelemetryUnsupported.
+func AddCounter(path, desc, units string) (*ProducerCounter, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// Set does nothing.
+func (pc *ProducerCounter) Set(val uint64) {}
+
+// Inc does nothing.
+func (pc *ProducerCounter) Inc(val uint64) {}
+
+// AddGauge returns ErrTelemetryUnsupported.
+func AddGauge(path, desc, units string) (*ProducerGauge, error) {
+	return nil, ErrTelemetryUnsupported
+}
+
+// Set does nothing.
+func (pg *ProducerGauge) Set(val uint64) {}
+
+// Inc does nothing.
+func (pg *ProducerGauge) Inc(val uint64) {}
+
+// Dec does nothing.
+func (pg *ProducerGauge) Dec(val uint64) {}
@@ -0,0 +1,90 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+// +build !linux !amd64
+//
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestTelemetry_Unsupported(t *testing.T) {
+	ctx := context.TODO()
+
+	for name, call := range map[string]func() error{
+		"Init": func() error {
+			_, err := Init(ctx, 0)
+			return err
+		},
+		"CollectMetrics": func() error {
+			return CollectMetrics(ctx, "/", make(chan Metric))
+		},
+		"GetRank": func() error {
+			_, err := GetRank(ctx)
+			return err
+		},
+		"GetRankOrUnknown": func() error {
+			_, err := GetRankOrUnknown(ctx)
+			return err
+		},
+		"GetMetrics": func() error {
+			_, err := GetMetrics(ctx, []string{"/rank"})
+			return err
+		},
+		"OpenSource": func() error {
+			_, _, err := OpenSource(ctx, 0)
+			return err
+		},
+		"DefaultSource CollectMetrics": func() error {
+			return DefaultSource().CollectMetrics(ctx, "/", make(chan Metric))
+		},
+		"DefaultSource GetRank": func() error {
+			_, err := DefaultSource().GetRank(ctx)
+			return err
+		},
+		"CollectMap": func() error {
+			_, err := CollectMap(ctx, "/")
+			return err
+		},
+		"InitProducer": func() error {
+			return InitProducer(0, 1024, false)
+		},
+		"AddCounter": func() error {
+			_, err := AddCounter("counter", "", "")
+			return err
+		},
+		"AddGauge": func() error {
+			_, err := AddGauge("gauge", "", "")
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			if !errors.Is(err, ErrTelemetryUnsupported) {
+				t.Fatalf("expected %v, got %v", ErrTelemetryUnsupported, err)
+			}
+		})
+	}
+
+	t.Run("Watch closes output", func(t *testing.T) {
+		out := make(chan MetricSample)
+		go Watch(ctx, "/", time.Second, out)
+
+		select {
+		case _, more := <-out:
+			common.AssertTrue(t, !more, "expected closed channel")
+		case <-time.After(time.Second):
+			t.Fatal("Watch did not close output channel")
+		}
+	})
+}
@@ -106,6 +106,25 @@ func setupTestMetrics(t *testing.T) (context.Context, testMetricsMap) {
 	return ctx, testMetrics
 }
 
+// addTestGauge adds a gauge to the telemetry initialized by setupTestMetrics
+// and sets it to each of the given values in turn.
+func addTestGauge(t *testing.T, name string, vals ...uint64) {
+	var node *C.struct_d_tm_node_t
+	rc := C.add_metric(&node, C.D_TM_GAUGE, C.CString("test"), C.CString(""), C.CString(name))
+	if rc != 0 {
+		t.Fatalf("failed to add %s: %d", name, rc)
+	}
+	for _, val := range vals {
+		C.d_tm_set_gauge(node, C.uint64_t(val))
+	}
+}
+
+// libSegmentKeyBase returns the key at which the library creates telemetry
+// segments for index 0.
+func libSegmentKeyBase() int64 {
+	return int64(C.D_TM_SHARED_MEMORY_KEY)
+}
+
 func cleanupTestMetrics(ctx context.Context, t *testing.T) {
 	Detach(ctx)
 	C.d_tm_fini()
@@ -25,17 +25,58 @@ type Timestamp struct {
 	metricBase
 }
 
+func (t *Timestamp) Type() MetricType {
+	return MetricTypeTimestamp
+}
+
+// Value returns the timestamp as a time.Time, or the zero time if the value
+// can't be read.
 func (t *Timestamp) Value() time.Time {
-	zero := time.Time{}
-	if t.handle == nil || t.node == nil {
-		return zero
+	return timestampTime(t.rawValue())
+}
+
+// Time is an alias for Value.
+func (t *Timestamp) Time() time.Time {
+	return t.Value()
+}
+
+// FloatValue returns the timestamp as seconds since the Unix epoch.
+func (t *Timestamp) FloatValue() float64 {
+	return timestampFloat(t.rawValue())
+}
+
+func (t *Timestamp) Snapshot() MetricSnapshot {
+	return snapshotMetric(t)
+}
+
+func (t *Timestamp) rawValue() uint64 {
+	if t == nil || !t.rLock() {
+		return BadUintVal
 	}
+	defer t.rUnlock()
 	var clk C.time_t
 	res := C.d_tm_get_timestamp(t.handle.ctx, &clk, t.node)
 	if res == C.DER_SUCCESS {
-		return time.Unix(int64(clk), 0)
+		return uint64(clk)
+	}
+	return BadUintVal
+}
+
+// timestampTime converts a raw timestamp value in seconds since the epoch to
+// a time.Time.
+func timestampTime(raw uint64) time.Time {
+	if raw == BadUintVal {
+		return time.Time{}
+	}
+	return time.Unix(int64(raw), 0)
+}
+
+// timestampFloat converts a raw timestamp value to epoch seconds.
+func timestampFloat(raw uint64) float64 {
+	if raw == BadUintVal {
+		return BadFloatVal
 	}
-	return zero
+	return float64(raw)
 }
 
 func newTimestamp(hdl *handle, path string, name *string, node *C.struct_d_tm_node_t) *Timestamp {
@@ -0,0 +1,198 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MetricType values mirror enum d_tm_metric_types in gurt/telemetry_common.h.
+type MetricType int
+
+const (
+	MetricTypeUnknown   MetricType = 0
+	MetricTypeDirectory MetricType = 0x001
+	MetricTypeCounter   MetricType = 0x002
+	MetricTypeTimestamp MetricType = 0x004
+	MetricTypeSnapshot  MetricType = 0x008
+	MetricTypeDuration  MetricType = 0x010
+	MetricTypeGauge     MetricType = 0x020
+
+	BadUintVal  = ^uint64(0)
+	BadFloatVal = float64(BadUintVal)
+	BadIntVal   = int64(BadUintVal >> 1)
+	BadDuration = time.Duration(BadIntVal)
+)
+
+// UnknownRank is returned by GetRankOrUnknown for telemetry segments that
+// don't publish a rank, e.g. those of clients or engines not yet formatted.
+const UnknownRank = ^uint32(0)
+
+// APIVersion is the telemetry API version of the gurt library that these
+// bindings were written against, the library must report the same version.
+const APIVersion = 1
+
+// ErrTelemetryVersionMismatch indicates that the gurt telemetry library is
+// incompatible with these bindings.
+var ErrTelemetryVersionMismatch = errors.New("telemetry API version mismatch")
+
+// ErrTelemetryUnsupported indicates that telemetry can't be read on the
+// platform the bindings were built for.
+var ErrTelemetryUnsupported = errors.New("telemetry is not implemented on this platform")
+
+// checkAPIVersion returns an error if the given telemetry API version doesn't
+// match the version supported by these bindings.
+func checkAPIVersion(version int) error {
+	if version != APIVersion {
+		return errors.Wrapf(ErrTelemetryVersionMismatch, "got version %d, need %d",
+			version, APIVersion)
+	}
+
+	return nil
+}
+
+func (t MetricType) String() string {
+	switch t {
+	case MetricTypeDirectory:
+		return "directory"
+	case MetricTypeCounter:
+		return "counter"
+	case MetricTypeDuration:
+		return "duration"
+	case MetricTypeGauge:
+		return "gauge"
+	case MetricTypeSnapshot:
+		return "snapshot"
+	case MetricTypeTimestamp:
+		return "timestamp"
+	default:
+		return "unknown"
+	}
+}
+
+// IsDirectory returns true if the type is MetricTypeDirectory.
+func (t MetricType) IsDirectory() bool {
+	return t == MetricTypeDirectory
+}
+
+// IsCounter returns true if the type is MetricTypeCounter.
+func (t MetricType) IsCounter() bool {
+	return t == MetricTypeCounter
+}
+
+// IsTimestamp returns true if the type is MetricTypeTimestamp.
+func (t MetricType) IsTimestamp() bool {
+	return t == MetricTypeTimestamp
+}
+
+// IsSnapshot returns true if the type is MetricTypeSnapshot.
+func (t MetricType) IsSnapshot() bool {
+	return t == MetricTypeSnapshot
+}
+
+// IsDuration returns true if the type is MetricTypeDuration.
+func (t MetricType) IsDuration() bool {
+	return t == MetricTypeDuration
+}
+
+// IsGauge returns true if the type is MetricTypeGauge.
+func (t MetricType) IsGauge() bool {
+	return t == MetricTypeGauge
+}
+
+// ParseMetricType returns the MetricType matching the given name.
+func ParseMetricType(name string) (MetricType, error) {
+	for _, mt := range []MetricType{
+		MetricTypeUnknown,
+		MetricTypeDirectory,
+		MetricTypeCounter,
+		MetricTypeDuration,
+		MetricTypeGauge,
+		MetricTypeSnapshot,
+		MetricTypeTimestamp,
+	} {
+		if strings.EqualFold(strings.TrimSpace(name), mt.String()) {
+			return mt, nil
+		}
+	}
+
+	return MetricTypeUnknown, errors.Errorf("invalid metric type %q", name)
+}
+
+type (
+	Metric interface {
+		Path() string
+		Name() string
+		Type() MetricType
+		Desc() string
+		Units() string
+		Labels() map[string]string
+		FloatValue() float64
+		String() string
+		// EngineIndex returns the index of the engine telemetry
+		// segment the metric was read from.
+		EngineIndex() uint32
+		// IsStats returns true if the metric also implements
+		// StatsMetric, see AsStats.
+		IsStats() bool
+		// Snapshot returns a copy of the current state of the
+		// metric that is safe to retain after the telemetry it was
+		// read from has been detached.
+		Snapshot() MetricSnapshot
+	}
+
+	StatsMetric interface {
+		Metric
+		FloatMin() float64
+		FloatMax() float64
+		FloatSum() float64
+		Mean() float64
+		StdDev() float64
+		SampleSize() uint64
+	}
+)
+
+// AsStats returns the metric as a StatsMetric and true if it provides
+// statistics, or nil and false otherwise.
+func AsStats(m Metric) (StatsMetric, bool) {
+	if m == nil || !m.IsStats() {
+		return nil, false
+	}
+	sm, ok := m.(StatsMetric)
+	return sm, ok
+}
+
+// Source is implemented by types that can provide telemetry for an engine.
+// Consumers should depend on this interface rather than the package-level
+// functions so that they can be tested without the telemetry library.
+type Source interface {
+	CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error
+	GetRank(ctx context.Context) (uint32, error)
+}
+
+// PathErrors maps metric paths to the errors encountered when resolving them.
+type PathErrors map[string]error
+
+func (pe PathErrors) Error() string {
+	paths := make([]string, 0, len(pe))
+	for path := range pe {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	msgs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		msgs = append(msgs, pe[path].Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
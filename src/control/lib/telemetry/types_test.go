@@ -0,0 +1,183 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestTelemetry_MetricType_RoundTrip(t *testing.T) {
+	for _, mt := range []MetricType{
+		MetricTypeUnknown,
+		MetricTypeDirectory,
+		MetricTypeCounter,
+		MetricTypeDuration,
+		MetricTypeGauge,
+		MetricTypeSnapshot,
+		MetricTypeTimestamp,
+	} {
+		t.Run(mt.String(), func(t *testing.T) {
+			gotType, err := ParseMetricType(mt.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			common.AssertEqual(t, mt, gotType, "unexpected metric type")
+		})
+	}
+}
+
+func TestTelemetry_ParseMetricType(t *testing.T) {
+	for name, tc := range map[string]struct {
+		in      string
+		expType MetricType
+		expErr  error
+	}{
+		"mixed case": {
+			in:      "Counter",
+			expType: MetricTypeCounter,
+		},
+		"whitespace": {
+			in:      " gauge ",
+			expType: MetricTypeGauge,
+		},
+		"empty": {
+			expErr: errors.New("invalid metric type"),
+		},
+		"invalid": {
+			in:     "histogram",
+			expErr: errors.New("invalid metric type \"histogram\""),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotType, gotErr := ParseMetricType(tc.in)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+			common.AssertEqual(t, tc.expType, gotType, "unexpected metric type")
+		})
+	}
+}
+
+func TestTelemetry_MetricType_String(t *testing.T) {
+	common.AssertEqual(t, "unknown", MetricType(0x40).String(), "undefined type")
+	common.AssertEqual(t, "duration", MetricTypeDuration.String(), "defined type")
+}
+
+func TestTelemetry_MetricType_Predicates(t *testing.T) {
+	predicates := map[string]func(MetricType) bool{
+		"directory": MetricType.IsDirectory,
+		"counter":   MetricType.IsCounter,
+		"timestamp": MetricType.IsTimestamp,
+		"snapshot":  MetricType.IsSnapshot,
+		"duration":  MetricType.IsDuration,
+		"gauge":     MetricType.IsGauge,
+	}
+
+	for _, mt := range []MetricType{
+		MetricTypeUnknown,
+		MetricTypeDirectory,
+		MetricTypeCounter,
+		MetricTypeTimestamp,
+		MetricTypeSnapshot,
+		MetricTypeDuration,
+		MetricTypeGauge,
+	} {
+		t.Run(mt.String(), func(t *testing.T) {
+			for name, pred := range predicates {
+				common.AssertEqual(t, name == mt.String(), pred(mt), name)
+			}
+		})
+	}
+}
+
+func TestTelemetry_checkAPIVersion(t *testing.T) {
+	for name, tc := range map[string]struct {
+		version int
+		expErr  error
+	}{
+		"matching": {
+			version: APIVersion,
+		},
+		"newer": {
+			version: APIVersion + 1,
+			expErr:  ErrTelemetryVersionMismatch,
+		},
+		"older": {
+			version: APIVersion - 1,
+			expErr:  ErrTelemetryVersionMismatch,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotErr := checkAPIVersion(tc.version)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil && errors.Cause(gotErr) != ErrTelemetryVersionMismatch {
+				t.Fatalf("expected version mismatch error, got %v", gotErr)
+			}
+		})
+	}
+}
+
+func TestTelemetry_AsStats(t *testing.T) {
+	stats := NewMockStatsMetric("/io/latency", MetricTypeGauge, 10)
+	stats.Avg = 5
+
+	for name, tc := range map[string]struct {
+		metric   Metric
+		expStats bool
+	}{
+		"nil": {},
+		"counter": {
+			metric: NewMockMetric("/io/ops", MetricTypeCounter, 1),
+		},
+		"timestamp": {
+			metric: NewMockMetric("/started_at", MetricTypeTimestamp, 1),
+		},
+		"gauge without stats": {
+			metric: NewMockMetric("/io/queued", MetricTypeGauge, 1),
+		},
+		"gauge with stats": {
+			metric:   stats,
+			expStats: true,
+		},
+		"duration with stats": {
+			metric:   NewMockStatsMetric("/io/duration", MetricTypeDuration, 1),
+			expStats: true,
+		},
+		"rank counter": {
+			metric: newRankMetric(NewMockMetric("/io/ops", MetricTypeCounter, 1), 0, 1),
+		},
+		"rank gauge with stats": {
+			metric:   newRankMetric(stats, 0, 1),
+			expStats: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.metric != nil {
+				common.AssertEqual(t, tc.expStats, tc.metric.IsStats(), "IsStats()")
+			}
+
+			sm, gotStats := AsStats(tc.metric)
+			common.AssertEqual(t, tc.expStats, gotStats, "AsStats()")
+			if !gotStats {
+				if sm != nil {
+					t.Fatalf("expected nil StatsMetric, got %+v", sm)
+				}
+				return
+			}
+			common.AssertEqual(t, tc.metric.FloatValue(), sm.FloatValue(), "FloatValue()")
+		})
+	}
+
+	// stats are read through the wrapper
+	sm, _ := AsStats(newRankMetric(stats, 0, 1))
+	common.AssertEqual(t, 5.0, sm.Mean(), "Mean()")
+}
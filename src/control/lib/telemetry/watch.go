@@ -0,0 +1,115 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// MetricSample is the value of a metric read at a point in time. For counters
+// the Delta field holds the change since the previous sample and Reset is set
+// if the counter was found to have been reset between samples.
+type MetricSample struct {
+	Path  string
+	Name  string
+	Type  MetricType
+	Value float64
+	Delta float64
+	Reset bool
+	Time  time.Time
+}
+
+func counterDelta(previous, current float64) (float64, bool) {
+	if current == BadFloatVal || previous == BadFloatVal {
+		return 0, false
+	}
+	if current < previous {
+		return current, true
+	}
+
+	return current - previous, false
+}
+
+// collectAll gathers the metrics under dirname from the source. The output
+// channel is only closed by the source on success so errors are watched for
+// while receiving.
+func collectAll(ctx context.Context, src Source, dirname string) ([]Metric, error) {
+	metrics := make(chan Metric)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- src.CollectMetrics(ctx, dirname, metrics)
+	}()
+
+	var out []Metric
+	for {
+		select {
+		case err := <-errCh:
+			if err != nil {
+				return nil, err
+			}
+			errCh = nil
+		case m, more := <-metrics:
+			if !more {
+				return out, nil
+			}
+			out = append(out, m)
+		}
+	}
+}
+
+// WatchSource walks the subtree at dirname in the given source on every
+// interval and sends a sample for each metric found to the output channel,
+// starting immediately. It returns when the context is cancelled, closing the
+// output channel.
+//
+// Intervals where the source can't be read (e.g. while the producer is
+// restarting) are skipped and previous values are discarded so that the
+// deltas of the next samples are calculated from scratch.
+func WatchSource(ctx context.Context, src Source, dirname string, interval time.Duration, out chan<- MetricSample) {
+	defer close(out)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := make(map[string]float64)
+	for {
+		metrics, err := collectAll(ctx, src, dirname)
+		if err != nil {
+			previous = make(map[string]float64)
+		}
+
+		now := time.Now()
+		for _, m := range metrics {
+			sample := MetricSample{
+				Path:  m.Path(),
+				Name:  m.Name(),
+				Type:  m.Type(),
+				Value: m.FloatValue(),
+				Time:  now,
+			}
+
+			key := sample.Path + "/" + sample.Name
+			if sample.Type.IsCounter() {
+				sample.Delta, sample.Reset = counterDelta(previous[key], sample.Value)
+			}
+			previous[key] = sample.Value
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- sample:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,123 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+)
+
+// sequenceSource returns the next set of metrics from a sequence on each
+// call, mimicking a tree whose values change between reads.
+type sequenceSource struct {
+	sync.Mutex
+	ticks [][]Metric
+	errs  []error
+	calls int
+}
+
+func (ss *sequenceSource) CollectMetrics(ctx context.Context, dirname string, out chan<- Metric) error {
+	ss.Lock()
+	idx := ss.calls
+	ss.calls++
+	ss.Unlock()
+
+	if idx >= len(ss.ticks) {
+		idx = len(ss.ticks) - 1
+	}
+	if idx < len(ss.errs) && ss.errs[idx] != nil {
+		return ss.errs[idx]
+	}
+
+	ms := &MockSource{Metrics: ss.ticks[idx]}
+	return ms.CollectMetrics(ctx, dirname, out)
+}
+
+func (ss *sequenceSource) GetRank(_ context.Context) (uint32, error) {
+	return 0, nil
+}
+
+func TestTelemetry_WatchSource(t *testing.T) {
+	counterTick := func(val float64) []Metric {
+		return []Metric{
+			NewMockMetric("/io/ops", MetricTypeCounter, val),
+			NewMockMetric("/io/queued", MetricTypeGauge, val*2),
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		ticks      [][]Metric
+		errs       []error
+		expSamples []MetricSample
+	}{
+		"values change between ticks": {
+			ticks: [][]Metric{counterTick(1), counterTick(4), counterTick(10)},
+			expSamples: []MetricSample{
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 1, Delta: 1},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 2},
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 4, Delta: 3},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 8},
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 10, Delta: 6},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 20},
+			},
+		},
+		"counter reset": {
+			ticks: [][]Metric{counterTick(5), counterTick(2), counterTick(3)},
+			expSamples: []MetricSample{
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 5, Delta: 5},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 10},
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 2, Delta: 2, Reset: true},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 4},
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 3, Delta: 1},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 6},
+			},
+		},
+		"producer restarts": {
+			ticks: [][]Metric{counterTick(5), nil, counterTick(2)},
+			errs:  []error{nil, errors.New("no shared memory segment")},
+			expSamples: []MetricSample{
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 5, Delta: 5},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 10},
+				{Path: "/io", Name: "ops", Type: MetricTypeCounter, Value: 2, Delta: 2},
+				{Path: "/io", Name: "queued", Type: MetricTypeGauge, Value: 4},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := &sequenceSource{ticks: tc.ticks, errs: tc.errs}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			out := make(chan MetricSample)
+			go WatchSource(ctx, src, "/io", time.Millisecond, out)
+
+			var gotSamples []MetricSample
+			for sample := range out {
+				if sample.Time.IsZero() {
+					t.Fatal("sample has no timestamp")
+				}
+				gotSamples = append(gotSamples, sample)
+				if len(gotSamples) == len(tc.expSamples) {
+					cancel()
+					break
+				}
+			}
+
+			if diff := cmp.Diff(tc.expSamples, gotSamples,
+				cmpopts.IgnoreFields(MetricSample{}, "Time")); diff != "" {
+				t.Fatalf("unexpected samples (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -34,6 +34,10 @@ var methodAuthorizations = map[string][]Component{
 	"/ctl.CtlSvc/PingRanks":          {ComponentServer},
 	"/ctl.CtlSvc/ResetFormatRanks":   {ComponentServer},
 	"/ctl.CtlSvc/StartRanks":         {ComponentServer},
+	"/ctl.CtlSvc/ProbeRanks":         {ComponentServer},
+	"/ctl.CtlSvc/RestartRanks":       {ComponentServer},
+	"/ctl.CtlSvc/NvmeSelfTest":       {ComponentAdmin},
+	"/ctl.CtlSvc/NvmeSelfTestQuery":  {ComponentAdmin},
 	"/mgmt.MgmtSvc/Join":             {ComponentServer},
 	"/mgmt.MgmtSvc/ClusterEvent":     {ComponentServer},
 	"/mgmt.MgmtSvc/LeaderQuery":      {ComponentAdmin},
@@ -25,6 +25,7 @@ import (
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/security"
 	"github.com/daos-stack/daos/src/control/server/engine"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 const (
@@ -60,6 +61,7 @@ type Server struct {
 	BdevExclude         []string         `yaml:"bdev_exclude,omitempty"`
 	DisableVFIO         bool             `yaml:"disable_vfio"`
 	DisableVMD          bool             `yaml:"disable_vmd"`
+	ConfiguredBdevScan  bool             `yaml:"configured_bdev_scan,omitempty"`
 	NrHugepages         int              `yaml:"nr_hugepages"`
 	SetHugepages        bool             `yaml:"set_hugepages"`
 	ControlLogMask      ControlLogLevel  `yaml:"control_log_mask"`
@@ -70,6 +72,8 @@ type Server struct {
 	RecreateSuperblocks bool             `yaml:"recreate_superblocks"`
 	FaultPath           string           `yaml:"fault_path"`
 	TelemetryPort       int              `yaml:"telemetry_port"`
+	AuditRankOps        bool             `yaml:"audit_rank_ops,omitempty"`
+	LeaderRanks         []system.Rank    `yaml:"leader_ranks,omitempty"`
 
 	// duplicated in engine.Config
 	SystemName string              `yaml:"name"`
@@ -102,6 +106,20 @@ func (cfg *Server) WithRecreateSuperblocks() *Server {
 	return cfg
 }
 
+// WithAuditRankOps indicates that an event should be published on completion
+// of each state-changing operation on the ranks hosted by the server.
+func (cfg *Server) WithAuditRankOps() *Server {
+	cfg.AuditRankOps = true
+	return cfg
+}
+
+// WithLeaderRanks sets the local ranks that are started, and become ready,
+// before any other local ranks when ranks are started on request.
+func (cfg *Server) WithLeaderRanks(ranks ...system.Rank) *Server {
+	cfg.LeaderRanks = ranks
+	return cfg
+}
+
 // WithProviderValidator sets the function that validates the provider
 func (cfg *Server) WithProviderValidator(fn networkProviderValidation) *Server {
 	cfg.validateProviderFn = fn
@@ -273,6 +291,13 @@ func (cfg *Server) WithDisableVMD(disabled bool) *Server {
 	return cfg
 }
 
+// WithConfiguredBdevScan restricts the NVMe scan performed on start-up to the
+// devices listed in the engine configs.
+func (cfg *Server) WithConfiguredBdevScan(enabled bool) *Server {
+	cfg.ConfiguredBdevScan = enabled
+	return cfg
+}
+
 // WithHyperthreads enables or disables hyperthread support.
 func (cfg *Server) WithHyperthreads(enabled bool) *Server {
 	cfg.Hyperthreads = enabled
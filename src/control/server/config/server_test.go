@@ -212,11 +212,14 @@ func TestServerConfig_Constructed(t *testing.T) {
 		WithBdevExclude("0000:81:00.1").
 		WithDisableVFIO(true). // vfio enabled by default
 		WithDisableVMD(false). // vmd disabled by default
+		WithConfiguredBdevScan(true).
 		WithNrHugePages(4096).
 		WithControlLogMask(ControlLogLevelError).
 		WithControlLogFile("/tmp/daos_server.log").
 		WithHelperLogFile("/tmp/daos_admin.log").
 		WithFirmwareHelperLogFile("/tmp/daos_firmware.log").
+		WithAuditRankOps().
+		WithLeaderRanks(0, 1).
 		WithSystemName("daos_server").
 		WithSocketDir("./.daos/daos_server").
 		WithFabricProvider("ofi+verbs;ofi_rxm").
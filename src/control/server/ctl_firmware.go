@@ -15,6 +15,7 @@ import (
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 // FirmwareQuery implements the method defined for the control service if
@@ -108,15 +109,19 @@ func (svc *ControlService) FirmwareUpdate(parent context.Context, pbReq *ctlpb.F
 	svc.log.Debug("received FirmwareUpdate RPC")
 
 	instances := svc.harness.Instances()
+	var running []system.Rank
 	for _, srv := range instances {
 		if srv.isStarted() {
 			rank, err := srv.GetRank()
 			if err != nil {
 				return nil, errors.New("unidentified server rank is running")
 			}
-			return nil, FaultInstancesNotStopped("firmware update", rank)
+			running = append(running, rank)
 		}
 	}
+	if len(running) > 0 {
+		return nil, FaultInstancesNotStopped("firmware update", running...)
+	}
 
 	pbResp := new(ctlpb.FirmwareUpdateResp)
 	var err error
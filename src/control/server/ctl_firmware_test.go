@@ -529,7 +529,7 @@ func TestCtlSvc_FirmwareUpdate(t *testing.T) {
 				FirmwarePath: "/some/path",
 			},
 			enginesRunning: true,
-			expErr:         FaultInstancesNotStopped("firmware update", 0),
+			expErr:         FaultInstancesNotStopped("firmware update", 0, 1),
 		},
 		"IO engines running with no rank": {
 			req: ctlpb.FirmwareUpdateReq{
@@ -8,6 +8,10 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,16 +19,40 @@ import (
 
 	"github.com/daos-stack/daos/src/control/common/proto/convert"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	sharedpb "github.com/daos-stack/daos/src/control/common/proto/shared"
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/events"
+	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/system"
 )
 
 const (
 	// instanceUpdateDelay is the polling time period
 	instanceUpdateDelay = 500 * time.Millisecond
+	// deadlineMargin is the time left before a context deadline for
+	// gathering and returning results
+	deadlineMargin = 250 * time.Millisecond
 )
 
+// boundTimeout returns the given timeout, reduced if necessary so that it
+// expires before the context deadline with time left to return results.
+func boundTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+
+	remaining := time.Until(deadline) - deadlineMargin
+	if remaining < 0 {
+		remaining = 0
+	}
+	if remaining < timeout {
+		return remaining
+	}
+
+	return timeout
+}
+
 // pollInstanceState waits for either context to be cancelled/timeout or for the
 // provided validate function to return true for each of the provided instances.
 //
@@ -65,10 +93,32 @@ func pollInstanceState(ctx context.Context, instances []*EngineInstance, validat
 	}
 }
 
+// unresponsiveResult returns a result indicating that the rank failed to
+// respond before the context deadline.
+func unresponsiveResult(rank system.Rank, err error) *system.MemberResult {
+	return &system.MemberResult{
+		Rank: rank, Msg: err.Error(),
+		State: system.MemberStateUnresponsive,
+	}
+}
+
 // drpcOnLocalRanks iterates over local instances issuing dRPC requests in
 // parallel and returning system member results when all have been received.
-func (svc *ControlService) drpcOnLocalRanks(parent context.Context, req *ctlpb.RanksReq, method drpc.Method) ([]*system.MemberResult, error) {
-	ctx, cancel := context.WithTimeout(parent, svc.harness.rankReqTimeout)
+// If maxInflight is non-zero, no more than that number of requests will be
+// in flight at once.
+//
+// The rank request timeout and any deadline on the parent context cap the
+// total time taken across all ranks. Once the deadline is reached, ranks that
+// have yet to return a result are marked as unresponsive. A zero rank request
+// timeout leaves only the deadline of the parent context, if any.
+func (svc *ControlService) drpcOnLocalRanks(parent context.Context, req *ctlpb.RanksReq, method drpc.Method, maxInflight int) ([]*system.MemberResult, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if svc.harness.rankReqTimeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, svc.harness.rankReqTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
 	defer cancel()
 
 	instances, err := svc.harness.FilterInstancesByRankSet(req.GetRanks())
@@ -76,50 +126,161 @@ func (svc *ControlService) drpcOnLocalRanks(parent context.Context, req *ctlpb.R
 		return nil, errors.Wrap(err, "sending request over dRPC to local ranks")
 	}
 
-	inflight := 0
-	ch := make(chan *system.MemberResult)
+	type instanceResult struct {
+		idx    uint32
+		result *system.MemberResult
+	}
+
+	var sem chan struct{}
+	if maxInflight > 0 {
+		sem = make(chan struct{}, maxInflight)
+	}
+
+	pending := make(map[uint32]system.Rank) // instance idx to system rank
+	// buffered so that late results don't block after the deadline is hit
+	ch := make(chan instanceResult, len(instances))
 	for _, srv := range instances {
-		inflight++
-		go func(s *EngineInstance) {
-			ch <- s.TryDrpc(ctx, method)
-		}(srv)
+		rank, err := srv.GetRank()
+		if err != nil {
+			continue // no rank to return result for
+		}
+		pending[srv.Index()] = rank
+
+		go func(s *EngineInstance, r system.Rank) {
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					var result *system.MemberResult
+					if ctx.Err() == context.DeadlineExceeded {
+						result = unresponsiveResult(r, ctx.Err())
+					}
+					ch <- instanceResult{idx: s.Index(), result: result}
+					return
+				}
+			}
+			ch <- instanceResult{idx: s.Index(), result: s.TryDrpc(ctx, method, req.GetRetryTransient())}
+		}(srv, rank)
+	}
+
+	results := make(system.MemberResults, 0, len(pending))
+	for len(pending) > 0 {
+		var ir instanceResult
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				for _, rank := range pending {
+					results = append(results, unresponsiveResult(rank, ctx.Err()))
+				}
+				return results, nil
+			}
+			// on cancellation wait for instances to return
+			ir = <-ch
+		case ir = <-ch:
+		}
+
+		if ir.result == nil {
+			return nil, FaultRankDrpcNoResult
+		}
+		delete(pending, ir.idx)
+		results = append(results, ir.result)
+	}
+
+	return results, nil
+}
+
+// sortRankResults orders results by rank so that responses are stable
+// regardless of the order in which concurrent requests complete.
+func sortRankResults(results system.MemberResults) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Rank < results[j].Rank
+	})
+}
+
+// msgRankNotLocal is the message of a result for a rank not hosted locally.
+const msgRankNotLocal = "rank not hosted on this server"
+
+// skippedResults returns results for the requested ranks that aren't hosted
+// locally, flagged as skipped, if the request asks for them to be reported.
+func (svc *ControlService) skippedResults(req *ctlpb.RanksReq) (system.MemberResults, error) {
+	if !req.GetReportSkipped() {
+		return nil, nil
 	}
 
-	results := make(system.MemberResults, 0, inflight)
-	for inflight > 0 {
-		result := <-ch
-		inflight--
-		if result == nil {
-			return nil, errors.New("sending request over dRPC to local ranks: nil result")
+	reqRanks, err := system.ParseRanks(req.GetRanks())
+	if err != nil {
+		return nil, err
+	}
+	localRanks, err := svc.harness.LocalRanks()
+	if err != nil {
+		return nil, err
+	}
+
+	var results system.MemberResults
+	for _, rank := range reqRanks {
+		if rank.InList(localRanks) {
+			continue
 		}
-		results = append(results, result)
+		results = append(results, &system.MemberResult{
+			Rank: rank, Msg: msgRankNotLocal, Skipped: true,
+		})
 	}
 
 	return results, nil
 }
 
+// newRanksResp converts member results into a ranks response. If there are no
+// results then none of the requested ranks are hosted locally and the response
+// is flagged so that the caller can distinguish this from an empty success.
+// Requested ranks not hosted locally are reported as skipped if the request
+// asks for it. A summary of the results is logged for debugging.
+func (svc *ControlService) newRanksResp(req *ctlpb.RanksReq, results system.MemberResults) (*ctlpb.RanksResp, error) {
+	resp := &ctlpb.RanksResp{NoLocalRanks: len(results) == 0}
+
+	skipped, err := svc.skippedResults(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		results = append(append(system.MemberResults{}, results...), skipped...)
+		sortRankResults(results)
+	}
+
+	if err := convert.Types(results, &resp.Results); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) > 0 {
+		svc.log.Debugf("ranks results: %s", sharedpb.SummarizeRankResults(resp.Results))
+	}
+
+	return resp, nil
+}
+
 // PrepShutdownRanks implements the method defined for the Management Service.
 //
 // Prepare data-plane instance(s) managed by control-plane for a controlled shutdown,
 // identified by unique rank(s).
 //
 // Iterate over local instances, issuing PrepShutdown dRPCs and record results.
+// Results are ordered by rank.
 func (svc *ControlService) PrepShutdownRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
 	if req == nil {
-		return nil, errors.New("nil request")
+		return nil, FaultNilRequest
 	}
 	if len(req.GetRanks()) == 0 {
-		return nil, errors.New("no ranks specified in request")
+		return nil, FaultNoRanksSpecified
 	}
 	svc.log.Debugf("MgmtSvc.PrepShutdownRanks dispatch, req:%+v\n", *req)
 
-	results, err := svc.drpcOnLocalRanks(ctx, req, drpc.MethodPrepShutdown)
+	results, err := svc.drpcOnLocalRanks(ctx, req, drpc.MethodPrepShutdown, 0)
 	if err != nil {
 		return nil, err
 	}
+	sortRankResults(results)
 
-	resp := &ctlpb.RanksResp{}
-	if err := convert.Types(results, &resp.Results); err != nil {
+	resp, err := svc.newRanksResp(req, results)
+	if err != nil {
 		return nil, err
 	}
 
@@ -154,20 +315,109 @@ func (svc *ControlService) memberStateResults(instances []*EngineInstance, tgtSt
 	return results, nil
 }
 
+// updateMembership applies the states in the given successful rank results
+// to the system membership so that subsequent queries reflect them without
+// waiting for a refresh. Errored results are left for the management service
+// fanout to record. Results are applied individually so that a rank missing
+// from the membership, or an update rejected because this server isn't the
+// management service leader, doesn't prevent the others from being recorded.
+func (svc *ControlService) updateMembership(results system.MemberResults) {
+	if svc.membership == nil {
+		return
+	}
+
+	for _, result := range results {
+		if result.Errored || result.Skipped {
+			continue
+		}
+		// copy so that the membership doesn't modify the result
+		mr := *result
+		if err := svc.membership.UpdateMemberStates(system.MemberResults{&mr}, false); err != nil {
+			svc.log.Debugf("rank %d: membership not updated: %s", result.Rank, err)
+		}
+	}
+}
+
+// addExitDetails annotates the results for ranks that have stopped with the
+// exit code of their engine process, and the exit error if the process exited
+// abnormally, so that a clean stop can be distinguished from a failure.
+func addExitDetails(instances []*EngineInstance, results system.MemberResults) {
+	byRank := make(map[system.Rank]*EngineInstance)
+	for _, srv := range instances {
+		rank, err := srv.GetRank()
+		if err != nil {
+			continue
+		}
+		byRank[rank] = srv
+	}
+
+	for _, result := range results {
+		srv, found := byRank[result.Rank]
+		if !found || result.State != system.MemberStateStopped {
+			continue
+		}
+
+		exitErr := srv.lastExitErr()
+		result.ExitCode = exitCode(exitErr)
+		if result.ExitCode != 0 {
+			result.Msg = fmt.Sprintf("%s: %s", result.Msg, exitErr)
+		}
+	}
+}
+
+// publishRankOperation publishes an audit event summarising the outcome of a
+// state-changing operation on local ranks, if enabled in the server config.
+func (svc *ControlService) publishRankOperation(op string, start time.Time, results system.MemberResults) {
+	if svc.events == nil || svc.srvCfg == nil || !svc.srvCfg.AuditRankOps {
+		return
+	}
+
+	var ranks []system.Rank
+	var failed int
+	for _, r := range results {
+		if r.Skipped {
+			continue
+		}
+		ranks = append(ranks, r.Rank)
+		if r.Errored {
+			failed++
+		}
+	}
+	if len(ranks) == 0 {
+		return
+	}
+
+	svc.events.Publish(events.NewRankOperationEvent(hostname(), op,
+		system.RankSetFromRanks(ranks).String(), len(ranks)-failed, failed,
+		time.Since(start)))
+}
+
 // StopRanks implements the method defined for the Management Service.
 //
 // Stop data-plane instance(s) managed by control-plane identified by unique
 // rank(s). After attempting to stop instances through harness (when either all
 // instances are stopped or timeout has occurred), populate response results
 // based on local instance state.
+//
+// If escalation is requested, instances that are still running after the grace
+// period following the graceful stop signal are sent SIGKILL and their ranks
+// are recorded in the response. Otherwise the grace period is how long
+// instances are given to exit after being signalled before their ranks are
+// marked errored. The grace period defaults to the rank request timeout.
+//
+// Waits are shortened to fit within any deadline on the context so that
+// results are returned, rather than an error, when little time remains.
+//
+// Results for stopped ranks include the exit code of the engine process.
 func (svc *ControlService) StopRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
 	if req == nil {
-		return nil, errors.New("nil request")
+		return nil, FaultNilRequest
 	}
 	if len(req.GetRanks()) == 0 {
-		return nil, errors.New("no ranks specified in request")
+		return nil, FaultNoRanksSpecified
 	}
 	svc.log.Debugf("MgmtSvc.StopRanks dispatch, req:%+v\n", *req)
+	start := time.Now()
 
 	signal := syscall.SIGINT
 	if req.Force {
@@ -192,23 +442,62 @@ func (svc *ControlService) StopRanks(ctx context.Context, req *ctlpb.RanksReq) (
 		}
 	}
 
-	// ignore poll results as we gather state immediately after
-	if _, err = pollInstanceState(ctx, instances,
-		func(s *EngineInstance) bool { return !s.isStarted() },
-		svc.harness.rankReqTimeout); err != nil {
+	isStopped := func(s *EngineInstance) bool { return !s.isStarted() }
+
+	grace := svc.harness.rankReqTimeout
+	if req.GetGracePeriodMs() > 0 {
+		grace = time.Duration(req.GetGracePeriodMs()) * time.Millisecond
+	}
+
+	// time allowed for instances to exit after the final signal is sent
+	exitTimeout := grace
+	var escalated system.RankList
+	if req.GetEscalate() && !req.GetForce() {
+		exitTimeout = svc.harness.rankReqTimeout
 
+		grace = boundTimeout(ctx, grace)
+		// ignore poll results as survivors are identified immediately after
+		if _, err = pollInstanceState(ctx, instances, isStopped, grace); err != nil {
+			return nil, err
+		}
+
+		for _, srv := range instances {
+			if !srv.isStarted() {
+				continue
+			}
+			rank, err := srv.GetRank()
+			if err != nil {
+				// shouldn't happen, instances already filtered by ranks
+				return nil, err
+			}
+			svc.log.Debugf("rank %d still running after %s, sending %s",
+				rank, grace, syscall.SIGKILL)
+			if err := srv.Stop(syscall.SIGKILL); err != nil {
+				return nil, errors.Wrapf(err, "sending %s", syscall.SIGKILL)
+			}
+			escalated = append(escalated, rank)
+		}
+	}
+
+	exitTimeout = boundTimeout(ctx, exitTimeout)
+	// ignore poll results as we gather state immediately after
+	if _, err = pollInstanceState(ctx, instances, isStopped, exitTimeout); err != nil {
 		return nil, err
 	}
 
 	results, err := svc.memberStateResults(instances, system.MemberStateStopped, "system stop",
-		"system stop: rank failed to stop within "+svc.harness.rankReqTimeout.String())
+		"system stop: rank failed to stop within "+exitTimeout.String())
 	if err != nil {
 		return nil, err
 	}
-	resp := &ctlpb.RanksResp{}
-	if err := convert.Types(results, &resp.Results); err != nil {
+	addExitDetails(instances, results)
+	svc.publishRankOperation("stop", start, results)
+
+	resp, err := svc.newRanksResp(req, results)
+	if err != nil {
 		return nil, err
 	}
+	resp.EscalatedRanks = system.RankSetFromRanks(escalated).String()
 
 	svc.log.Debugf("MgmtSvc.StopRanks dispatch, resp:%+v\n", *resp)
 
@@ -217,7 +506,8 @@ func (svc *ControlService) StopRanks(ctx context.Context, req *ctlpb.RanksReq) (
 
 func (svc *ControlService) queryLocalRanks(ctx context.Context, req *ctlpb.RanksReq) ([]*system.MemberResult, error) {
 	if req.Force {
-		return svc.drpcOnLocalRanks(ctx, req, drpc.MethodPingRank)
+		return svc.drpcOnLocalRanks(ctx, req, drpc.MethodPingRank,
+			svc.harness.maxPingsInflight)
 	}
 
 	instances, err := svc.harness.FilterInstancesByRankSet(req.GetRanks())
@@ -248,13 +538,14 @@ func (svc *ControlService) queryLocalRanks(ctx context.Context, req *ctlpb.Ranks
 // is not set in request then perform non-invasive ping by retrieving rank
 // instance state (AwaitFormat/Stopped/Starting/Started) from harness.
 //
-// Iterate over local instances, ping and record results.
+// Iterate over local instances, ping and record results. Results are ordered
+// by rank.
 func (svc *ControlService) PingRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
 	if req == nil {
-		return nil, errors.New("nil request")
+		return nil, FaultNilRequest
 	}
 	if len(req.GetRanks()) == 0 {
-		return nil, errors.New("no ranks specified in request")
+		return nil, FaultNoRanksSpecified
 	}
 
 	svc.log.Debugf("MgmtSvc.PingRanks dispatch, req:%+v\n", *req)
@@ -263,9 +554,10 @@ func (svc *ControlService) PingRanks(ctx context.Context, req *ctlpb.RanksReq) (
 	if err != nil {
 		return nil, err
 	}
+	sortRankResults(results)
 
-	resp := &ctlpb.RanksResp{}
-	if err := convert.Types(results, &resp.Results); err != nil {
+	resp, err := svc.newRanksResp(req, results)
+	if err != nil {
 		return nil, err
 	}
 
@@ -274,6 +566,99 @@ func (svc *ControlService) PingRanks(ctx context.Context, req *ctlpb.RanksReq) (
 	return resp, nil
 }
 
+// rankReadiness combines the local state of an instance with the result of a
+// dRPC ping to determine whether the rank is ready to service requests.
+func rankReadiness(srv *EngineInstance, rank system.Rank, result *system.MemberResult) *ctlpb.RankReadiness {
+	rr := &ctlpb.RankReadiness{
+		Rank:      rank.Uint32(),
+		Alive:     srv.isStarted(),
+		Formatted: srv.hasSuperblock() && !srv.isAwaitingFormat(),
+	}
+
+	state := srv.LocalState()
+	if result != nil {
+		state = result.State
+		rr.Msg = result.Msg
+	}
+	rr.State = strings.ToLower(state.String())
+	rr.Ready = srv.isReady() && state == system.MemberStateReady &&
+		(result == nil || !result.Errored)
+
+	if rr.Ready || rr.Msg != "" {
+		return rr
+	}
+	switch {
+	case !rr.Formatted:
+		rr.Msg = "storage not formatted"
+	case !rr.Alive:
+		rr.Msg = "engine not running"
+	default:
+		rr.Msg = "engine not ready"
+	}
+
+	return rr
+}
+
+// ProbeRanks implements the method defined for the Management Service.
+//
+// Probe readiness of data-plane ranks (DAOS system members) managed by harness.
+// Unlike PingRanks, which only reports whether a rank is responsive, report
+// whether each rank is alive, has formatted storage and has completed startup.
+// Ranks that have completed startup are pinged over dRPC to verify that they
+// are able to service requests.
+func (svc *ControlService) ProbeRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.ProbeRanksResp, error) {
+	if req == nil {
+		return nil, FaultNilRequest
+	}
+	if len(req.GetRanks()) == 0 {
+		return nil, FaultNoRanksSpecified
+	}
+
+	svc.log.Debugf("MgmtSvc.ProbeRanks dispatch, req:%+v\n", *req)
+
+	instances, err := svc.harness.FilterInstancesByRankSet(req.GetRanks())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := svc.drpcOnLocalRanks(ctx, req, drpc.MethodPingRank,
+		svc.harness.maxPingsInflight)
+	if err != nil {
+		return nil, err
+	}
+	rankResults := make(map[system.Rank]*system.MemberResult)
+	for _, result := range results {
+		rankResults[result.Rank] = result
+	}
+
+	resp := new(ctlpb.ProbeRanksResp)
+	for _, srv := range instances {
+		rank, err := srv.GetRank()
+		if err != nil {
+			// shouldn't happen, instances already filtered by ranks
+			return nil, err
+		}
+		resp.Results = append(resp.Results,
+			rankReadiness(srv, rank, rankResults[rank]))
+	}
+	resp.NoLocalRanks = len(resp.Results) == 0
+
+	svc.log.Debugf("MgmtSvc.ProbeRanks dispatch, resp:%+v\n", *resp)
+
+	return resp, nil
+}
+
+// publishResetFormat publishes an event describing the progress of the rank of
+// an instance being reset towards awaiting format.
+func (svc *ControlService) publishResetFormat(srv *EngineInstance, rank system.Rank, state system.MemberState, err error) {
+	if svc.events == nil {
+		return
+	}
+
+	svc.events.Publish(events.NewEngineResetFormatEvent(hostname(), srv.Index(),
+		rank.Uint32(), state.String(), err))
+}
+
 // ResetFormatRanks implements the method defined for the Management Service.
 //
 // Reset storage format of data-plane instances (DAOS system members) managed
@@ -283,14 +668,19 @@ func (svc *ControlService) PingRanks(ctx context.Context, req *ctlpb.RanksReq) (
 // identified by unique rank(s). After attempting to reset instances through
 // harness (when either all instances are awaiting format or timeout has
 // occurred), populate response results based on local instance state.
+//
+// As resetting the format discards any existing data, instances with formatted
+// storage are only reset if force is set in the request. The check is made for
+// all instances before any are reset.
 func (svc *ControlService) ResetFormatRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
 	if req == nil {
-		return nil, errors.New("nil request")
+		return nil, FaultNilRequest
 	}
 	if len(req.GetRanks()) == 0 {
-		return nil, errors.New("no ranks specified in request")
+		return nil, FaultNoRanksSpecified
 	}
 	svc.log.Debugf("MgmtSvc.ResetFormatRanks dispatch, req:%+v\n", *req)
+	start := time.Now()
 
 	instances, err := svc.harness.FilterInstancesByRankSet(req.GetRanks())
 	if err != nil {
@@ -298,6 +688,7 @@ func (svc *ControlService) ResetFormatRanks(ctx context.Context, req *ctlpb.Rank
 	}
 
 	savedRanks := make(map[uint32]system.Rank) // instance idx to system rank
+	var running []system.Rank
 	for _, srv := range instances {
 		rank, err := srv.GetRank()
 		if err != nil {
@@ -306,16 +697,59 @@ func (svc *ControlService) ResetFormatRanks(ctx context.Context, req *ctlpb.Rank
 		savedRanks[srv.Index()] = rank
 
 		if srv.isStarted() {
-			return nil, FaultInstancesNotStopped("reset format", rank)
+			running = append(running, rank)
 		}
+	}
+	if len(running) > 0 {
+		return nil, FaultInstancesNotStopped("reset format", running...)
+	}
+
+	for _, srv := range instances {
+		rank := savedRanks[srv.Index()]
+		if req.GetForce() {
+			continue
+		}
+		formatted, err := srv.hasFormattedStorage()
+		if err != nil {
+			return nil, err
+		}
+		if formatted {
+			return nil, FaultInstancesFormatted("reset format", rank)
+		}
+	}
+
+	for _, srv := range instances {
 		if err := srv.RemoveSuperblock(); err != nil {
 			return nil, err
 		}
 		srv.requestStart(ctx)
+		svc.publishResetFormat(srv, savedRanks[srv.Index()], system.MemberStateStarting, nil)
+	}
+
+	// publish progress once for each rank as it reaches the awaiting
+	// format state, either whilst polling or when gathering results
+	var publishedMu sync.Mutex
+	published := make(map[uint32]bool)
+	publishAwaitFormat := func(srv *EngineInstance) {
+		publishedMu.Lock()
+		defer publishedMu.Unlock()
+
+		if published[srv.Index()] {
+			return
+		}
+		published[srv.Index()] = true
+		svc.publishResetFormat(srv, savedRanks[srv.Index()], system.MemberStateAwaitFormat, nil)
+	}
+	awaitingFormat := func(srv *EngineInstance) bool {
+		if !srv.isAwaitingFormat() {
+			return false
+		}
+		publishAwaitFormat(srv)
+		return true
 	}
 
 	// ignore poll results as we gather state immediately after
-	if _, err = pollInstanceState(ctx, instances, (*EngineInstance).isAwaitingFormat,
+	if _, err = pollInstanceState(ctx, instances, awaitingFormat,
 		svc.harness.rankStartTimeout); err != nil {
 
 		return nil, err
@@ -328,13 +762,18 @@ func (svc *ControlService) ResetFormatRanks(ctx context.Context, req *ctlpb.Rank
 		state := srv.LocalState()
 		if state != system.MemberStateAwaitFormat {
 			err = errors.Errorf("want %s, got %s", system.MemberStateAwaitFormat, state)
+			svc.publishResetFormat(srv, savedRanks[srv.Index()], state, err)
+		} else {
+			publishAwaitFormat(srv)
 		}
 
 		results = append(results, system.NewMemberResult(savedRanks[srv.Index()], err, state))
 	}
 
-	resp := &ctlpb.RanksResp{}
-	if err := convert.Types(results, &resp.Results); err != nil {
+	svc.publishRankOperation("reset-format", start, results)
+
+	resp, err := svc.newRanksResp(req, results)
+	if err != nil {
 		return nil, err
 	}
 
@@ -348,32 +787,50 @@ func (svc *ControlService) ResetFormatRanks(ctx context.Context, req *ctlpb.Rank
 // Start data-plane instance(s) managed by control-plane identified by unique
 // rank(s). After attempting to start instances through harness (when either all
 // instances are in ready state or timeout has occurred), populate response results
-// based on local instance state.
+// based on local instance state. Successful results are also applied to the
+// system membership, if available.
+//
+// If the harness has a RankStartPolicy, instances are started in the groups it
+// specifies and each group waits for the previous one to be ready. The start
+// timeout applies to all groups together and groups following one that isn't
+// ready within it are not started.
+//
+// If WaitPoolServices is set, ready ranks are additionally polled until they
+// report that their hosted pool services are up, ranks that don't within the
+// start timeout are reported as errored.
 func (svc *ControlService) StartRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
 	if req == nil {
-		return nil, errors.New("nil request")
+		return nil, FaultNilRequest
 	}
 	if len(req.GetRanks()) == 0 {
-		return nil, errors.New("no ranks specified in request")
+		return nil, FaultNoRanksSpecified
 	}
 	svc.log.Debugf("MgmtSvc.StartRanks dispatch, req:%+v\n", *req)
+	start := time.Now()
 
 	instances, err := svc.harness.FilterInstancesByRankSet(req.GetRanks())
 	if err != nil {
 		return nil, err
 	}
-	for _, srv := range instances {
-		if srv.isStarted() {
-			continue
+	deadline := time.Now().Add(svc.harness.rankStartTimeout)
+	for _, group := range svc.harness.rankStartGroups(instances) {
+		for _, srv := range group {
+			if srv.isStarted() {
+				continue
+			}
+			srv.requestStart(ctx)
 		}
-		srv.requestStart(ctx)
-	}
-
-	// ignore poll results as we gather state immediately after
-	if _, err = pollInstanceState(ctx, instances, (*EngineInstance).isReady,
-		svc.harness.rankStartTimeout); err != nil {
 
-		return nil, err
+		// state is gathered after polling so only check whether the
+		// group is ready before starting the next one
+		ready, err := pollInstanceState(ctx, group, (*EngineInstance).isReady,
+			time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			break
+		}
 	}
 
 	// instances will update state to "Started" through join or
@@ -383,8 +840,18 @@ func (svc *ControlService) StartRanks(ctx context.Context, req *ctlpb.RanksReq)
 	if err != nil {
 		return nil, err
 	}
-	resp := &ctlpb.RanksResp{}
-	if err := convert.Types(results, &resp.Results); err != nil {
+	svc.updateMembership(results)
+
+	if req.GetWaitPoolServices() {
+		if err := svc.checkPoolServices(ctx, instances, results); err != nil {
+			return nil, err
+		}
+	}
+
+	svc.publishRankOperation("start", start, results)
+
+	resp, err := svc.newRanksResp(req, results)
+	if err != nil {
 		return nil, err
 	}
 
@@ -392,3 +859,154 @@ func (svc *ControlService) StartRanks(ctx context.Context, req *ctlpb.RanksReq)
 
 	return resp, nil
 }
+
+// waitPoolServices polls each of the provided instances over dRPC until it
+// reports that its hosted pool services are up or the timeout elapses.
+//
+// Returns the indices of instances with pool services up. Error is returned if
+// parent context is cancelled or times out.
+func waitPoolServices(ctx context.Context, log logging.Logger, instances []*EngineInstance, timeout time.Duration) (map[uint32]bool, error) {
+	up := make(map[uint32]bool)
+	deadline := time.After(timeout)
+
+	for {
+		for _, srv := range instances {
+			if up[srv.Index()] {
+				continue
+			}
+			ok, err := srv.poolSvcsUp(ctx)
+			if err != nil {
+				log.Debugf("instance %d: checking pool services: %s", srv.Index(), err)
+				continue
+			}
+			if ok {
+				up[srv.Index()] = true
+			}
+		}
+		if len(up) == len(instances) {
+			return up, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return up, nil
+		case <-time.After(instanceUpdateDelay):
+		}
+	}
+}
+
+// checkPoolServices waits for the pool services hosted by ready instances to
+// come up and replaces the results of ranks whose pool services aren't up
+// within the rank start timeout with errored results.
+func (svc *ControlService) checkPoolServices(ctx context.Context, instances []*EngineInstance, results system.MemberResults) error {
+	rankResults := make(map[system.Rank]int)
+	for i, result := range results {
+		if result.State == system.MemberStateReady {
+			rankResults[result.Rank] = i
+		}
+	}
+
+	var ready []*EngineInstance
+	for _, srv := range instances {
+		rank, err := srv.GetRank()
+		if err != nil {
+			continue
+		}
+		if _, exists := rankResults[rank]; exists {
+			ready = append(ready, srv)
+		}
+	}
+
+	up, err := waitPoolServices(ctx, svc.log, ready, svc.harness.rankStartTimeout)
+	if err != nil {
+		return err
+	}
+
+	for _, srv := range ready {
+		if up[srv.Index()] {
+			continue
+		}
+		rank, err := srv.GetRank()
+		if err != nil {
+			continue
+		}
+		results[rankResults[rank]] = system.NewMemberResult(rank,
+			errors.New("system start: pool services not up within "+
+				svc.harness.rankStartTimeout.String()),
+			system.MemberStateErrored)
+	}
+
+	return nil
+}
+
+// RestartRanks implements the method defined for the Management Service.
+//
+// Restart data-plane instance(s) managed by control-plane identified by unique
+// rank(s) as a single operation. Instances are stopped gracefully, escalating
+// to SIGKILL after the grace period for any that fail to exit (or killed
+// immediately if force is set), then those that stopped are started and waited
+// on until ready.
+//
+// Results reflect the whole cycle, ranks that failed to stop are not started
+// and their stop failure is reported. Context cancellation or deadline expiry
+// in either phase aborts the operation.
+func (svc *ControlService) RestartRanks(ctx context.Context, req *ctlpb.RanksReq) (*ctlpb.RanksResp, error) {
+	if req == nil {
+		return nil, FaultNilRequest
+	}
+	if len(req.GetRanks()) == 0 {
+		return nil, FaultNoRanksSpecified
+	}
+	svc.log.Debugf("MgmtSvc.RestartRanks dispatch, req:%+v\n", *req)
+
+	stopResp, err := svc.StopRanks(ctx, &ctlpb.RanksReq{
+		Ranks:         req.GetRanks(),
+		Force:         req.GetForce(),
+		Escalate:      true,
+		GracePeriodMs: req.GetGracePeriodMs(),
+		ReportSkipped: req.GetReportSkipped(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "restart: stopping ranks")
+	}
+
+	var stopped system.RankList
+	for _, result := range stopResp.Results {
+		if !result.Errored && !result.Skipped {
+			stopped = append(stopped, system.Rank(result.Rank))
+		}
+	}
+
+	started := make(map[uint32]*sharedpb.RankResult)
+	if len(stopped) > 0 {
+		startResp, err := svc.StartRanks(ctx, &ctlpb.RanksReq{
+			Ranks: system.RankSetFromRanks(stopped).String(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "restart: starting ranks")
+		}
+		for _, result := range startResp.Results {
+			if !result.Errored {
+				result.Msg = "system restart"
+			}
+			started[result.Rank] = result
+		}
+	}
+
+	resp := &ctlpb.RanksResp{
+		NoLocalRanks:   stopResp.NoLocalRanks,
+		EscalatedRanks: stopResp.EscalatedRanks,
+	}
+	for _, result := range stopResp.Results {
+		if startResult, found := started[result.Rank]; found {
+			result = startResult
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	svc.log.Debugf("MgmtSvc.RestartRanks dispatch, resp:%+v\n", *resp)
+
+	return resp, nil
+}
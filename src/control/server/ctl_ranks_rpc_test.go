@@ -8,7 +8,10 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
@@ -37,6 +40,7 @@ var (
 	msWaitFormat = stateString(system.MemberStateAwaitFormat)
 	msStopped    = stateString(system.MemberStateStopped)
 	msErrored    = stateString(system.MemberStateErrored)
+	msUnknown    = stateString(system.MemberStateUnknown)
 
 	defRankCmpOpts = append(common.DefaultCmpOpts(),
 		protocmp.IgnoreFields(&sharedpb.RankResult{}, "msg"),
@@ -81,20 +85,22 @@ func TestServer_CtlSvc_PrepShutdownRanks(t *testing.T) {
 		ctxTimeout       time.Duration
 		ctxCancel        time.Duration
 		expResults       []*sharedpb.RankResult
+		expNoLocalRanks  bool
 		expErr           error
 	}{
 		"nil request": {
-			expErr: errors.New("nil request"),
+			expErr: FaultNilRequest,
 		},
 		"no ranks specified": {
 			req:    &ctlpb.RanksReq{},
-			expErr: errors.New("no ranks specified in request"),
+			expErr: FaultNoRanksSpecified,
 		},
 		"missing superblock": {
 			req:       &ctlpb.RanksReq{Ranks: "0-3"},
 			missingSB: true,
 			// no results as rank cannot be read from superblock
-			expResults: []*sharedpb.RankResult{},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"instances stopped": {
 			req:              &ctlpb.RanksReq{Ranks: "0-3"},
@@ -157,7 +163,7 @@ func TestServer_CtlSvc_PrepShutdownRanks(t *testing.T) {
 				&mgmtpb.DaosResp{Status: 0},
 				&mgmtpb.DaosResp{Status: 0},
 			},
-			expErr: errors.New("nil result"), // parent ctx cancel
+			expErr: FaultRankDrpcNoResult, // parent ctx cancel
 		},
 		"unsuccessful call": {
 			req: &ctlpb.RanksReq{Ranks: "0-3"},
@@ -247,10 +253,63 @@ func TestServer_CtlSvc_PrepShutdownRanks(t *testing.T) {
 
 			// order of results nondeterministic as dPrepShutdown run async
 			checkUnorderedRankResults(t, tc.expResults, gotResp.Results)
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
 		})
 	}
 }
 
+func TestServer_CtlSvc_PrepShutdownRanks_Deadline(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	engineCount := 4
+	engineCfgs := make([]*engine.Config, 0, engineCount)
+	for i := 0; i < engineCount; i++ {
+		engineCfgs = append(engineCfgs, engine.NewConfig().WithTargetCount(1))
+	}
+	svc := mockControlService(t, log, config.DefaultServer().WithEngines(engineCfgs...),
+		nil, nil, nil)
+
+	expResults := make([]*sharedpb.RankResult, 0, engineCount)
+	for i, srv := range svc.harness.instances {
+		trc := &engine.TestRunnerConfig{}
+		trc.Running.SetTrue()
+		srv.ready.SetTrue()
+		srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+		srv.setIndex(uint32(i))
+
+		srv._superblock.Rank = new(system.Rank)
+		*srv._superblock.Rank = system.Rank(i + 1)
+
+		// each rank would take far longer than the overall deadline
+		cfg := new(mockDrpcClientConfig)
+		rb, _ := proto.Marshal(&mgmtpb.DaosResp{Status: 0})
+		cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, nil)
+		cfg.setResponseDelay(time.Second)
+		srv.setDrpcClient(newMockDrpcClient(cfg))
+
+		expResults = append(expResults, &sharedpb.RankResult{
+			Rank: uint32(i + 1), State: stateString(system.MemberStateUnresponsive),
+		})
+	}
+	svc.harness.rankReqTimeout = 10 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	gotResp, gotErr := svc.PrepShutdownRanks(ctx, &ctlpb.RanksReq{Ranks: "0-7"})
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected return shortly after deadline, took %s", elapsed)
+	}
+
+	checkUnorderedRankResults(t, expResults, gotResp.Results)
+}
+
 func TestServer_CtlSvc_StopRanks(t *testing.T) {
 	for name, tc := range map[string]struct {
 		setupAP          bool
@@ -263,24 +322,27 @@ func TestServer_CtlSvc_StopRanks(t *testing.T) {
 		ctxTimeout       time.Duration
 		expSignalsSent   map[uint32]os.Signal
 		expResults       []*sharedpb.RankResult
+		expNoLocalRanks  bool
 		expErr           error
 	}{
 		"nil request": {
-			expErr: errors.New("nil request"),
+			expErr: FaultNilRequest,
 		},
 		"no ranks specified": {
 			req:    &ctlpb.RanksReq{},
-			expErr: errors.New("no ranks specified in request"),
+			expErr: FaultNoRanksSpecified,
 		},
 		"missing superblock": {
 			req:       &ctlpb.RanksReq{Ranks: "0-3"},
 			missingSB: true,
 			// no results as rank cannot be read from superblock
-			expResults: []*sharedpb.RankResult{},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"missing ranks": {
-			req:        &ctlpb.RanksReq{Ranks: "0,3"},
-			expResults: []*sharedpb.RankResult{},
+			req:             &ctlpb.RanksReq{Ranks: "0,3"},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"kill signal send error": {
 			req: &ctlpb.RanksReq{
@@ -292,7 +354,12 @@ func TestServer_CtlSvc_StopRanks(t *testing.T) {
 		"context timeout": { // near-immediate parent context Timeout
 			req:        &ctlpb.RanksReq{Ranks: "0-3"},
 			ctxTimeout: time.Millisecond,
-			expErr:     context.DeadlineExceeded, // parent ctx timeout
+			// wait is shortened to fit the deadline and results returned
+			expSignalsSent: map[uint32]os.Signal{0: syscall.SIGINT, 1: syscall.SIGINT},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
 		},
 		"instances started": { // unsuccessful result for kill
 			req:            &ctlpb.RanksReq{Ranks: "0-3"},
@@ -408,6 +475,8 @@ func TestServer_CtlSvc_StopRanks(t *testing.T) {
 			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
 				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
 			}
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
 
 			var numSignalsSent int
 			signalsSent.Range(func(_, _ interface{}) bool {
@@ -429,142 +498,140 @@ func TestServer_CtlSvc_StopRanks(t *testing.T) {
 	}
 }
 
-func TestServer_CtlSvc_PingRanks(t *testing.T) {
+func TestServer_CtlSvc_StopRanks_Audit(t *testing.T) {
 	for name, tc := range map[string]struct {
-		setupAP          bool
-		missingSB        bool
-		instancesStopped bool
-		req              *ctlpb.RanksReq
-		drpcRet          error
-		junkResp         bool
-		drpcResps        []proto.Message
-		responseDelay    time.Duration
-		ctxTimeout       time.Duration
-		ctxCancel        time.Duration
-		expResults       []*sharedpb.RankResult
-		expErr           error
+		auditRankOps bool
+		expInfo      string
 	}{
-		"nil request": {
-			expErr: errors.New("nil request"),
-		},
-		"no ranks specified": {
-			req:    &ctlpb.RanksReq{},
-			expErr: errors.New("no ranks specified in request"),
-		},
-		"missing superblock": {
-			req:       &ctlpb.RanksReq{Ranks: "0-3"},
-			missingSB: true,
-			// no results as rank can't be read from superblock
-			expResults: []*sharedpb.RankResult{},
-		},
-		"instances stopped": {
-			req:              &ctlpb.RanksReq{Ranks: "0-3"},
-			instancesStopped: true,
-			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msStopped},
-				{Rank: 2, State: msStopped},
-			},
-		},
-		"instances started": {
-			req: &ctlpb.RanksReq{Ranks: "0-3"},
-			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msReady},
-				{Rank: 2, State: msReady},
-			},
+		"audit disabled": {},
+		"audit enabled": {
+			auditRankOps: true,
+			expInfo:      "operation=stop ranks=1-2 succeeded=2 failed=0 ",
 		},
-		"dRPC resp fails": {
-			// force flag in request triggers dRPC ping
-			req:     &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			drpcRet: errors.New("call failed"),
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
-			},
-			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msErrored, Errored: true},
-				{Rank: 2, State: msErrored, Errored: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			if tc.auditRankOps {
+				cfg = cfg.WithAuditRankOps()
+			}
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
+
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			svc.events = ps
+
+			dispatched := &eventsDispatched{cancel: cancel}
+			svc.events.Subscribe(events.RASTypeInfoOnly, dispatched)
+
+			for i, srv := range svc.harness.instances {
+				srv.runner = engine.NewTestRunner(&engine.TestRunnerConfig{},
+					engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+			}
+
+			if _, err := svc.StopRanks(ctx, &ctlpb.RanksReq{Ranks: "0-3"}); err != nil {
+				t.Fatal(err)
+			}
+
+			<-ctx.Done()
+			if tc.expInfo == "" {
+				common.AssertEqual(t, 0, len(dispatched.rx), "number of events published")
+				return
+			}
+			common.AssertEqual(t, 1, len(dispatched.rx), "number of events published")
+
+			evt := dispatched.rx[0]
+			common.AssertEqual(t, events.RASRankOperation, evt.ID, "unexpected event ID")
+			common.AssertEqual(t, events.RASSeverityNotice, evt.Severity,
+				"unexpected event severity")
+			gotInfo := string(*evt.GetStrInfo())
+			common.AssertTrue(t, strings.HasPrefix(gotInfo, tc.expInfo),
+				fmt.Sprintf("unexpected event info %q", gotInfo))
+		})
+	}
+}
+
+func TestServer_CtlSvc_StopRanks_Escalation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req            *ctlpb.RanksReq
+		ignoreSIGINT   map[uint32]bool // instance indexes that don't exit on SIGINT
+		expSignalsSent map[uint32][]os.Signal
+		expResults     []*sharedpb.RankResult
+		expEscalated   string
+	}{
+		"no escalation requested": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3"},
+			ignoreSIGINT: map[uint32]bool{1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT},
 			},
-		},
-		"dRPC resp junk": {
-			// force flag in request triggers dRPC ping
-			req:      &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			junkResp: true,
 			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 1, State: msStopped},
 				{Rank: 2, State: msErrored, Errored: true},
 			},
 		},
-		"dRPC ping timeout": { // dRPC req-resp duration > rankReqTimeout
-			// force flag in request triggers dRPC ping
-			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			responseDelay: 200 * time.Millisecond,
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
-			},
-			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: stateString(system.MemberStateUnresponsive)},
-				{Rank: 2, State: stateString(system.MemberStateUnresponsive)},
-			},
-		},
-		"dRPC context timeout": { // dRPC req-resp duration > parent context Timeout
-			// force flag in request triggers dRPC ping
-			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			responseDelay: 40 * time.Millisecond,
-			ctxTimeout:    10 * time.Millisecond,
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
+		"all ranks exit gracefully": {
+			req: &ctlpb.RanksReq{Ranks: "0-3", Escalate: true, GracePeriodMs: 20},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT},
 			},
 			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: stateString(system.MemberStateUnresponsive)},
-				{Rank: 2, State: stateString(system.MemberStateUnresponsive)},
-			},
-		},
-		"dRPC context cancel": { // dRPC req-resp duration > when parent context is canceled
-			// force flag in request triggers dRPC ping
-			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			responseDelay: 40 * time.Millisecond,
-			ctxCancel:     10 * time.Millisecond,
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
 			},
-			expErr: errors.New("nil result"), // parent ctx cancel
 		},
-		"dRPC unsuccessful call": {
-			// force flag in request triggers dRPC ping
-			req: &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: -1},
-				&mgmtpb.DaosResp{Status: -1},
+		"escalate non-exiting rank": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3", Escalate: true, GracePeriodMs: 20},
+			ignoreSIGINT: map[uint32]bool{1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT, syscall.SIGKILL},
 			},
 			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msErrored, Errored: true},
-				{Rank: 2, State: msErrored, Errored: true},
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
 			},
+			expEscalated: "2",
 		},
-		"dRPC successful call": {
-			// force flag in request triggers dRPC ping
-			req: &ctlpb.RanksReq{Ranks: "0-3", Force: true},
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
+		"escalate all ranks": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3", Escalate: true},
+			ignoreSIGINT: map[uint32]bool{0: true, 1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT, syscall.SIGKILL},
+				1: {syscall.SIGINT, syscall.SIGKILL},
 			},
 			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msReady},
-				{Rank: 2, State: msReady},
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
 			},
+			expEscalated: "1-2",
 		},
-		"dRPC filtered ranks": {
-			// force flag in request triggers dRPC ping
-			req: &ctlpb.RanksReq{Ranks: "0-1,3", Force: true},
-			drpcResps: []proto.Message{
-				&mgmtpb.DaosResp{Status: 0},
-				&mgmtpb.DaosResp{Status: 0},
+		"force ignores escalation": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3", Force: true, Escalate: true},
+			ignoreSIGINT: map[uint32]bool{0: true, 1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGKILL},
+				1: {syscall.SIGKILL},
 			},
 			expResults: []*sharedpb.RankResult{
-				{Rank: 1, State: msReady},
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
 			},
 		},
 	} {
@@ -578,114 +645,995 @@ func TestServer_CtlSvc_PingRanks(t *testing.T) {
 			)
 			svc := mockControlService(t, log, cfg, nil, nil, nil)
 
-			for i, srv := range svc.harness.instances {
-				if tc.missingSB {
-					srv._superblock = nil
-					continue
-				}
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
 
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			svc.events = ps
+
+			var mu sync.Mutex
+			signalsSent := make(map[uint32][]os.Signal)
+
+			for i, srv := range svc.harness.instances {
 				trc := &engine.TestRunnerConfig{}
-				if !tc.instancesStopped {
-					trc.Running.SetTrue()
-					srv.ready.SetTrue()
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				trc.SignalCb = func(idx uint32, sig os.Signal) {
+					mu.Lock()
+					defer mu.Unlock()
+					signalsSent[idx] = append(signalsSent[idx], sig)
+				}
+				// simulate process exit unless the instance is
+				// ignoring the graceful stop signal
+				trc.ExitOnSignal = func(idx uint32, sig os.Signal) bool {
+					return sig == syscall.SIGKILL || !tc.ignoreSIGINT[idx]
 				}
 				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
 				srv.setIndex(uint32(i))
 
 				srv._superblock.Rank = new(system.Rank)
 				*srv._superblock.Rank = system.Rank(i + 1)
-
-				cfg := new(mockDrpcClientConfig)
-				if tc.drpcRet != nil {
-					cfg.setSendMsgResponse(drpc.Status_FAILURE, nil, nil)
-				} else if tc.junkResp {
-					cfg.setSendMsgResponse(drpc.Status_SUCCESS, makeBadBytes(42), nil)
-				} else if len(tc.drpcResps) > i {
-					rb, _ := proto.Marshal(tc.drpcResps[i])
-					cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, tc.expErr)
-
-					if tc.responseDelay != time.Duration(0) {
-						cfg.setResponseDelay(tc.responseDelay)
-					}
-				}
-				srv.setDrpcClient(newMockDrpcClient(cfg))
 			}
 
-			svc.harness.rankReqTimeout = 50 * time.Millisecond
-
-			var cancel context.CancelFunc
-			ctx := context.Background()
-			if tc.ctxTimeout != 0 {
-				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
-				defer cancel()
-			} else if tc.ctxCancel != 0 {
-				ctx, cancel = context.WithCancel(ctx)
-				go func() {
-					<-time.After(tc.ctxCancel)
-					cancel()
-				}()
+			gotResp, gotErr := svc.StopRanks(ctx, tc.req)
+			if gotErr != nil {
+				t.Fatal(gotErr)
 			}
 
-			gotResp, gotErr := svc.PingRanks(ctx, tc.req)
-			common.CmpErr(t, tc.expErr, gotErr)
-			if tc.expErr != nil {
-				return
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
 			}
+			common.AssertEqual(t, tc.expEscalated, gotResp.EscalatedRanks,
+				"escalated ranks")
 
-			// order of results nondeterministic as dPing run async
-			checkUnorderedRankResults(t, tc.expResults, gotResp.Results)
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(tc.expSignalsSent, signalsSent); diff != "" {
+				t.Fatalf("unexpected signals sent (-want, +got):\n%s\n", diff)
+			}
 		})
 	}
 }
 
-func TestServer_CtlSvc_ResetFormatRanks(t *testing.T) {
+func TestServer_CtlSvc_StopRanks_GraceTimeout(t *testing.T) {
 	for name, tc := range map[string]struct {
-		setupAP          bool
-		missingSB        bool
-		engineCount      int
-		instancesStarted bool
-		startFails       bool
-		req              *ctlpb.RanksReq
-		ctxTimeout       time.Duration
-		expResults       []*sharedpb.RankResult
-		expErr           error
+		req        *ctlpb.RanksReq
+		exitDelay  time.Duration
+		expResults []*sharedpb.RankResult
+		expMsgs    []string
 	}{
-		"nil request": {
-			expErr: errors.New("nil request"),
-		},
-		"no ranks specified": {
-			req:    &ctlpb.RanksReq{},
-			expErr: errors.New("no ranks specified in request"),
+		"exit within default timeout": {
+			req: &ctlpb.RanksReq{Ranks: "0-3"},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
+			},
 		},
-		"missing superblock": {
+		"delayed exit after default timeout": {
 			req:       &ctlpb.RanksReq{Ranks: "0-3"},
-			missingSB: true,
-			// no results as rank can't be read from superblock
-			expResults: []*sharedpb.RankResult{},
-		},
+			exitDelay: 50 * time.Millisecond,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+			expMsgs: []string{
+				"system stop: rank failed to stop within 10ms",
+				"system stop: rank failed to stop within 10ms",
+			},
+		},
+		"delayed exit within grace timeout": {
+			req:       &ctlpb.RanksReq{Ranks: "0-3", GracePeriodMs: 300},
+			exitDelay: 50 * time.Millisecond,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
+			},
+		},
+		"delayed exit after grace timeout": {
+			req:       &ctlpb.RanksReq{Ranks: "0-3", GracePeriodMs: 5},
+			exitDelay: 50 * time.Millisecond,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+			expMsgs: []string{
+				"system stop: rank failed to stop within 5ms",
+				"system stop: rank failed to stop within 5ms",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			svc.harness.rankReqTimeout = 10 * time.Millisecond
+
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			svc.events = ps
+
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{
+					ExitOnSignal: func(uint32, os.Signal) bool { return true },
+					ExitDelay:    tc.exitDelay,
+				}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+			}
+
+			gotResp, gotErr := svc.StopRanks(ctx, tc.req)
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+			}
+
+			var gotMsgs []string
+			for _, r := range gotResp.Results {
+				if r.Errored {
+					gotMsgs = append(gotMsgs, r.Msg)
+				}
+			}
+			if diff := cmp.Diff(tc.expMsgs, gotMsgs); diff != "" {
+				t.Fatalf("unexpected error messages (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_StopRanks_Deadline(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cfg := config.DefaultServer().WithEngines(
+		engine.NewConfig().WithTargetCount(1),
+		engine.NewConfig().WithTargetCount(1),
+	)
+	svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+	// the remaining budget is shorter than the default grace period
+	budget := time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	ps := events.NewPubSub(ctx, log)
+	defer ps.Close()
+	svc.events = ps
+
+	for i, srv := range svc.harness.instances {
+		// instances ignore the stop signal
+		trc := &engine.TestRunnerConfig{}
+		trc.Running.SetTrue()
+		srv.ready.SetTrue()
+		srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+		srv.setIndex(uint32(i))
+
+		srv._superblock.Rank = new(system.Rank)
+		*srv._superblock.Rank = system.Rank(i + 1)
+	}
+
+	gotResp, gotErr := svc.StopRanks(ctx, &ctlpb.RanksReq{Ranks: "0-3"})
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("results returned after context deadline")
+	}
+
+	expResults := []*sharedpb.RankResult{
+		{Rank: 1, State: msErrored, Errored: true},
+		{Rank: 2, State: msErrored, Errored: true},
+	}
+	if diff := cmp.Diff(expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+		t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+	}
+
+	prefix := "system stop: rank failed to stop within "
+	for _, r := range gotResp.Results {
+		if !strings.HasPrefix(r.Msg, prefix) {
+			t.Fatalf("unexpected message %q", r.Msg)
+		}
+		timeout, err := time.ParseDuration(strings.TrimPrefix(r.Msg, prefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		common.AssertTrue(t, timeout < budget,
+			fmt.Sprintf("timeout %s not reduced to fit within %s", timeout, budget))
+	}
+}
+
+// mockExitErr returns the error from a process exiting with the given code,
+// wrapped in the same way as an engine exit error.
+func mockExitErr(t *testing.T, code int) error {
+	t.Helper()
+
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("expected exit error, got %v", err)
+	}
+
+	return errors.Wrap(err, "daos_engine exited")
+}
+
+func TestServer_CtlSvc_StopRanks_ExitDetails(t *testing.T) {
+	for name, tc := range map[string]struct {
+		exitErrs   func(*testing.T) []error // per-instance exit errors
+		expResults []*sharedpb.RankResult
+		expMsgs    []string
+	}{
+		"clean exit": {
+			exitErrs: func(*testing.T) []error {
+				return []error{
+					errors.Wrap(common.NormalExit, "daos_engine exited"),
+					errors.Wrap(common.NormalExit, "daos_engine exited"),
+				}
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
+			},
+			expMsgs: []string{"system stop", "system stop"},
+		},
+		"abnormal exit code": {
+			exitErrs: func(t *testing.T) []error {
+				return []error{
+					errors.Wrap(common.NormalExit, "daos_engine exited"),
+					mockExitErr(t, 3),
+				}
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped, ExitCode: 3},
+			},
+			expMsgs: []string{
+				"system stop",
+				"system stop: daos_engine exited: exit status 3",
+			},
+		},
+		"exit code unavailable": {
+			exitErrs: func(*testing.T) []error {
+				return []error{errors.New("killed"), nil}
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped, ExitCode: -1},
+				{Rank: 2, State: msStopped},
+			},
+			expMsgs: []string{"system stop: killed", "system stop"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
+
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			svc.events = ps
+
+			exitErrs := tc.exitErrs(t)
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{
+					ExitOnSignal: func(uint32, os.Signal) bool { return true },
+				}
+				trc.SignalCb = func(idx uint32, _ os.Signal) {
+					// simulate process exit which will record the
+					// exit error, a nil error means no exit seen
+					if exitErrs[idx] != nil {
+						svc.harness.instances[idx].exit(context.TODO(),
+							exitErrs[idx])
+					}
+				}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+			}
+
+			gotResp, gotErr := svc.StopRanks(ctx, &ctlpb.RanksReq{Ranks: "0-3"})
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+			}
+
+			var gotMsgs []string
+			for _, r := range gotResp.Results {
+				gotMsgs = append(gotMsgs, r.Msg)
+			}
+			if diff := cmp.Diff(tc.expMsgs, gotMsgs); diff != "" {
+				t.Fatalf("unexpected messages (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_PingRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		setupAP          bool
+		missingSB        bool
+		instancesStopped bool
+		req              *ctlpb.RanksReq
+		drpcRet          error
+		junkResp         bool
+		drpcResps        []proto.Message
+		responseDelay    time.Duration
+		ctxTimeout       time.Duration
+		ctxCancel        time.Duration
+		expResults       []*sharedpb.RankResult
+		expNoLocalRanks  bool
+		expErr           error
+	}{
+		"nil request": {
+			expErr: FaultNilRequest,
+		},
+		"no ranks specified": {
+			req:    &ctlpb.RanksReq{},
+			expErr: FaultNoRanksSpecified,
+		},
+		"missing superblock": {
+			req:       &ctlpb.RanksReq{Ranks: "0-3"},
+			missingSB: true,
+			// no results as rank can't be read from superblock
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
+		},
+		"missing ranks": {
+			req:             &ctlpb.RanksReq{Ranks: "0,3", Force: true},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
+		},
+		"missing ranks reported as skipped": {
+			req: &ctlpb.RanksReq{Ranks: "0,3", ReportSkipped: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 0, State: msUnknown, Skipped: true},
+				{Rank: 3, State: msUnknown, Skipped: true},
+			},
+			expNoLocalRanks: true,
+		},
+		"non-local ranks reported as skipped": {
+			req: &ctlpb.RanksReq{Ranks: "0-3", ReportSkipped: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 0, State: msUnknown, Skipped: true},
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+				{Rank: 3, State: msUnknown, Skipped: true},
+			},
+		},
+		"instances stopped": {
+			req:              &ctlpb.RanksReq{Ranks: "0-3"},
+			instancesStopped: true,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msStopped},
+				{Rank: 2, State: msStopped},
+			},
+		},
+		"instances started": {
+			req: &ctlpb.RanksReq{Ranks: "0-3"},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"dRPC resp fails": {
+			// force flag in request triggers dRPC ping
+			req:     &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			drpcRet: errors.New("call failed"),
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+		},
+		"dRPC resp junk": {
+			// force flag in request triggers dRPC ping
+			req:      &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			junkResp: true,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+		},
+		"dRPC ping timeout": { // dRPC req-resp duration > rankReqTimeout
+			// force flag in request triggers dRPC ping
+			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			responseDelay: 200 * time.Millisecond,
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: stateString(system.MemberStateUnresponsive)},
+				{Rank: 2, State: stateString(system.MemberStateUnresponsive)},
+			},
+		},
+		"dRPC context timeout": { // dRPC req-resp duration > parent context Timeout
+			// force flag in request triggers dRPC ping
+			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			responseDelay: 40 * time.Millisecond,
+			ctxTimeout:    10 * time.Millisecond,
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: stateString(system.MemberStateUnresponsive)},
+				{Rank: 2, State: stateString(system.MemberStateUnresponsive)},
+			},
+		},
+		"dRPC context cancel": { // dRPC req-resp duration > when parent context is canceled
+			// force flag in request triggers dRPC ping
+			req:           &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			responseDelay: 40 * time.Millisecond,
+			ctxCancel:     10 * time.Millisecond,
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expErr: FaultRankDrpcNoResult, // parent ctx cancel
+		},
+		"dRPC unsuccessful call": {
+			// force flag in request triggers dRPC ping
+			req: &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: -1},
+				&mgmtpb.DaosResp{Status: -1},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+		},
+		"dRPC successful call": {
+			// force flag in request triggers dRPC ping
+			req: &ctlpb.RanksReq{Ranks: "0-3", Force: true},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"dRPC filtered ranks": {
+			// force flag in request triggers dRPC ping
+			req: &ctlpb.RanksReq{Ranks: "0-1,3", Force: true},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			for i, srv := range svc.harness.instances {
+				if tc.missingSB {
+					srv._superblock = nil
+					continue
+				}
+
+				trc := &engine.TestRunnerConfig{}
+				if !tc.instancesStopped {
+					trc.Running.SetTrue()
+					srv.ready.SetTrue()
+				}
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+
+				cfg := new(mockDrpcClientConfig)
+				if tc.drpcRet != nil {
+					cfg.setSendMsgResponse(drpc.Status_FAILURE, nil, nil)
+				} else if tc.junkResp {
+					cfg.setSendMsgResponse(drpc.Status_SUCCESS, makeBadBytes(42), nil)
+				} else if len(tc.drpcResps) > i {
+					rb, _ := proto.Marshal(tc.drpcResps[i])
+					cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, tc.expErr)
+
+					if tc.responseDelay != time.Duration(0) {
+						cfg.setResponseDelay(tc.responseDelay)
+					}
+				}
+				srv.setDrpcClient(newMockDrpcClient(cfg))
+			}
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
+
+			var cancel context.CancelFunc
+			ctx := context.Background()
+			if tc.ctxTimeout != 0 {
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
+				defer cancel()
+			} else if tc.ctxCancel != 0 {
+				ctx, cancel = context.WithCancel(ctx)
+				go func() {
+					<-time.After(tc.ctxCancel)
+					cancel()
+				}()
+			}
+
+			gotResp, gotErr := svc.PingRanks(ctx, tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			// order of results nondeterministic as dPing run async
+			checkUnorderedRankResults(t, tc.expResults, gotResp.Results)
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
+		})
+	}
+}
+
+func TestServer_CtlSvc_PingRanks_TransientRetry(t *testing.T) {
+	busy := &mgmtpb.DaosResp{Status: int32(drpc.DaosBusy)}
+	invalid := &mgmtpb.DaosResp{Status: int32(drpc.DaosInvalidInput)}
+	success := &mgmtpb.DaosResp{}
+
+	for name, tc := range map[string]struct {
+		retry      bool
+		drpcResps  [][]proto.Message // per-instance sequence of responses
+		expResults []*sharedpb.RankResult
+		expCalls   []int
+	}{
+		"transient failure not retried": {
+			drpcResps: [][]proto.Message{
+				{busy, success},
+				{invalid, success},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true, Transient: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+			expCalls: []int{1, 1},
+		},
+		"transient failure succeeds on retry": {
+			retry: true,
+			drpcResps: [][]proto.Message{
+				{busy, busy, success},
+				{success},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+			expCalls: []int{3, 1},
+		},
+		"permanent failure fails fast": {
+			retry: true,
+			drpcResps: [][]proto.Message{
+				{busy, success},
+				{invalid, success},
+			},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+			expCalls: []int{2, 1},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+			svc.harness.rankReqTimeout = 5 * time.Second
+
+			clients := make([]*mockDrpcClient, 0, len(svc.harness.instances))
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+				srv._superblock.Rank = system.NewRankPtr(uint32(i + 1))
+
+				dcc := new(mockDrpcClientConfig)
+				for _, msg := range tc.drpcResps[i] {
+					dcc.setSendMsgResponseList(t, &mockDrpcResponse{
+						Status:  drpc.Status_SUCCESS,
+						Message: msg,
+					})
+				}
+				client := newMockDrpcClient(dcc)
+				srv.setDrpcClient(client)
+				clients = append(clients, client)
+			}
+
+			// force flag in request triggers dRPC ping
+			gotResp, gotErr := svc.PingRanks(context.TODO(), &ctlpb.RanksReq{
+				Ranks: "0-3", Force: true, RetryTransient: tc.retry,
+			})
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+
+			checkUnorderedRankResults(t, tc.expResults, gotResp.Results)
+			for i, client := range clients {
+				common.AssertEqual(t, tc.expCalls[i], len(client.calls),
+					fmt.Sprintf("dRPC calls to instance %d", i))
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_PingRanks_MaxInflight(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	engineCount := 5
+	maxInflight := 2
+	respDelay := 30 * time.Millisecond
+
+	engineCfgs := make([]*engine.Config, 0, engineCount)
+	for i := 0; i < engineCount; i++ {
+		engineCfgs = append(engineCfgs, engine.NewConfig().WithTargetCount(1))
+	}
+	svc := mockControlService(t, log, config.DefaultServer().WithEngines(engineCfgs...),
+		nil, nil, nil)
+	svc.harness.WithMaxPingsInflight(maxInflight)
+	svc.harness.rankReqTimeout = time.Second
+
+	expResults := make([]*sharedpb.RankResult, 0, engineCount)
+	for i, srv := range svc.harness.instances {
+		trc := &engine.TestRunnerConfig{}
+		trc.Running.SetTrue()
+		srv.ready.SetTrue()
+		srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+		srv.setIndex(uint32(i))
+
+		srv._superblock.Rank = new(system.Rank)
+		*srv._superblock.Rank = system.Rank(i + 1)
+
+		cfg := new(mockDrpcClientConfig)
+		rb, _ := proto.Marshal(&mgmtpb.DaosResp{Status: 0})
+		cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, nil)
+		cfg.setResponseDelay(respDelay)
+		srv.setDrpcClient(newMockDrpcClient(cfg))
+
+		expResults = append(expResults, &sharedpb.RankResult{
+			Rank: uint32(i + 1), State: msReady,
+		})
+	}
+
+	start := time.Now()
+	gotResp, gotErr := svc.PingRanks(context.Background(),
+		&ctlpb.RanksReq{Ranks: "0-7", Force: true})
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+
+	// with pings limited, requests are issued in batches of maxInflight
+	batches := (engineCount + maxInflight - 1) / maxInflight
+	if elapsed := time.Since(start); elapsed < time.Duration(batches)*respDelay {
+		t.Fatalf("expected pings to be limited to %d in flight, took %s",
+			maxInflight, elapsed)
+	}
+
+	if diff := cmp.Diff(expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+		t.Fatalf("unexpected results (-want, +got)\n%s\n", diff)
+	}
+}
+
+func TestServer_CtlSvc_RankResultsSorted(t *testing.T) {
+	const engineCount = 4
+
+	for name, tc := range map[string]struct {
+		call     func(*ControlService, context.Context, *ctlpb.RanksReq) (*ctlpb.RanksResp, error)
+		req      *ctlpb.RanksReq
+		expState string
+	}{
+		"forced ping": {
+			call:     (*ControlService).PingRanks,
+			req:      &ctlpb.RanksReq{Ranks: "0-7", Force: true},
+			expState: msReady,
+		},
+		"ping": {
+			call:     (*ControlService).PingRanks,
+			req:      &ctlpb.RanksReq{Ranks: "0-7"},
+			expState: msReady,
+		},
+		"prep shutdown": {
+			call:     (*ControlService).PrepShutdownRanks,
+			req:      &ctlpb.RanksReq{Ranks: "0-7"},
+			expState: stateString(system.MemberStateStopping),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			engineCfgs := make([]*engine.Config, 0, engineCount)
+			for i := 0; i < engineCount; i++ {
+				engineCfgs = append(engineCfgs, engine.NewConfig().WithTargetCount(1))
+			}
+			svc := mockControlService(t, log,
+				config.DefaultServer().WithEngines(engineCfgs...), nil, nil, nil)
+			svc.harness.rankReqTimeout = time.Second
+
+			expResults := make([]*sharedpb.RankResult, 0, engineCount)
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				// ranks assigned in reverse order of instance index
+				// with higher ranks responding first
+				rank := uint32(engineCount - i)
+				srv._superblock.Rank = system.NewRankPtr(rank)
+
+				cfg := new(mockDrpcClientConfig)
+				rb, _ := proto.Marshal(&mgmtpb.DaosResp{Status: 0})
+				cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, nil)
+				cfg.setResponseDelay(time.Duration(i) * 10 * time.Millisecond)
+				srv.setDrpcClient(newMockDrpcClient(cfg))
+
+				expResults = append(expResults, &sharedpb.RankResult{
+					Rank: uint32(i + 1), State: tc.expState,
+				})
+			}
+
+			gotResp, gotErr := tc.call(svc, context.Background(), tc.req)
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+
+			if diff := cmp.Diff(expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("results not sorted by rank (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_ProbeRanks(t *testing.T) {
+	msStarting := stateString(system.MemberStateStarting)
+
+	for name, tc := range map[string]struct {
+		instancesStopped bool
+		awaitFormat      bool
+		notReady         bool
+		req              *ctlpb.RanksReq
+		drpcResps        []proto.Message
+		expResults       []*ctlpb.RankReadiness
+		expNoLocalRanks  bool
+		expErr           error
+	}{
+		"nil request": {
+			expErr: FaultNilRequest,
+		},
+		"no ranks specified": {
+			req:    &ctlpb.RanksReq{},
+			expErr: FaultNoRanksSpecified,
+		},
+		"no local ranks": {
+			req:             &ctlpb.RanksReq{Ranks: "5-8"},
+			expNoLocalRanks: true,
+		},
+		"instances stopped": {
+			req:              &ctlpb.RanksReq{Ranks: "0-3"},
+			instancesStopped: true,
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, Formatted: true, State: msStopped, Msg: "engine not running"},
+				{Rank: 2, Formatted: true, State: msStopped, Msg: "engine not running"},
+			},
+		},
+		"instances awaiting format": {
+			req:              &ctlpb.RanksReq{Ranks: "0-3"},
+			instancesStopped: true,
+			awaitFormat:      true,
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, State: msWaitFormat, Msg: "storage not formatted"},
+				{Rank: 2, State: msWaitFormat, Msg: "storage not formatted"},
+			},
+		},
+		"alive but not ready": {
+			req:      &ctlpb.RanksReq{Ranks: "0-3"},
+			notReady: true,
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, Alive: true, Formatted: true, State: msStarting, Msg: "engine not ready"},
+				{Rank: 2, Alive: true, Formatted: true, State: msStarting, Msg: "engine not ready"},
+			},
+		},
+		"ready but dRPC unsuccessful": {
+			req: &ctlpb.RanksReq{Ranks: "0-3"},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: -1, Msg: "uh oh"},
+			},
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, Alive: true, Formatted: true, Ready: true, State: msReady},
+				{Rank: 2, Alive: true, Formatted: true, State: msErrored,
+					Msg: "rank 2: DER_UNKNOWN(-1): Unknown error code -1: uh oh"},
+			},
+		},
+		"fully ready": {
+			req: &ctlpb.RanksReq{Ranks: "0-3"},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, Alive: true, Formatted: true, Ready: true, State: msReady},
+				{Rank: 2, Alive: true, Formatted: true, Ready: true, State: msReady},
+			},
+		},
+		"filtered ranks": {
+			req: &ctlpb.RanksReq{Ranks: "0-1,3"},
+			drpcResps: []proto.Message{
+				&mgmtpb.DaosResp{Status: 0},
+				&mgmtpb.DaosResp{Status: 0},
+			},
+			expResults: []*ctlpb.RankReadiness{
+				{Rank: 1, Alive: true, Formatted: true, Ready: true, State: msReady},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				if !tc.instancesStopped {
+					trc.Running.SetTrue()
+				}
+				if !tc.instancesStopped && !tc.notReady {
+					srv.ready.SetTrue()
+				}
+				if tc.awaitFormat {
+					srv.waitFormat.SetTrue()
+				}
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+
+				cfg := new(mockDrpcClientConfig)
+				if len(tc.drpcResps) > i {
+					rb, _ := proto.Marshal(tc.drpcResps[i])
+					cfg.setSendMsgResponse(drpc.Status_SUCCESS, rb, nil)
+				}
+				srv.setDrpcClient(newMockDrpcClient(cfg))
+			}
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
+
+			gotResp, gotErr := svc.ProbeRanks(context.Background(), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected results (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
+		})
+	}
+}
+
+func TestServer_CtlSvc_ResetFormatRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		setupAP          bool
+		missingSB        bool
+		engineCount      int
+		instancesStarted bool
+		startFails       bool
+		req              *ctlpb.RanksReq
+		ctxTimeout       time.Duration
+		expResults       []*sharedpb.RankResult
+		expNoLocalRanks  bool
+		expFormatted     bool // storage expected to remain formatted
+		expErr           error
+	}{
+		"nil request": {
+			expErr: FaultNilRequest,
+		},
+		"no ranks specified": {
+			req:    &ctlpb.RanksReq{},
+			expErr: FaultNoRanksSpecified,
+		},
+		"missing superblock": {
+			req:       &ctlpb.RanksReq{Ranks: "0-3"},
+			missingSB: true,
+			// no results as rank can't be read from superblock
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
+		},
 		"missing ranks": {
-			req:        &ctlpb.RanksReq{Ranks: "0,3"},
-			expResults: []*sharedpb.RankResult{},
+			req:             &ctlpb.RanksReq{Ranks: "0,3"},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"context timeout": { // near-immediate parent context Timeout
-			req:        &ctlpb.RanksReq{Ranks: "0-3"},
+			req:        &ctlpb.RanksReq{Ranks: "0-3", Force: true},
 			ctxTimeout: time.Nanosecond,
 			expErr:     context.DeadlineExceeded, // parent ctx timeout
 		},
 		"instances already started": {
 			req:              &ctlpb.RanksReq{Ranks: "0-3"},
 			instancesStarted: true,
-			expErr:           FaultInstancesNotStopped("reset format", 1),
+			expErr:           FaultInstancesNotStopped("reset format", 1, 2),
 		},
-		"instances reach wait format": {
-			req: &ctlpb.RanksReq{Ranks: "0-3"},
+		"formatted instances refused": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3"},
+			expFormatted: true,
+			expErr:       FaultInstancesFormatted("reset format", 1),
+		},
+		"formatted instances forced to wait format": {
+			req: &ctlpb.RanksReq{Ranks: "0-3", Force: true},
 			expResults: []*sharedpb.RankResult{
 				{Rank: 1, State: msWaitFormat},
 				{Rank: 2, State: msWaitFormat},
 			},
 		},
 		"instances stay stopped": {
-			req:        &ctlpb.RanksReq{Ranks: "0-3"},
+			req:        &ctlpb.RanksReq{Ranks: "0-3", Force: true},
 			startFails: true,
 			expResults: []*sharedpb.RankResult{
 				{Rank: 1, State: msStopped, Errored: true},
@@ -760,6 +1708,17 @@ func TestServer_CtlSvc_ResetFormatRanks(t *testing.T) {
 
 			gotResp, gotErr := svc.ResetFormatRanks(ctx, tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expFormatted {
+				for _, srv := range svc.harness.instances {
+					formatted, err := srv.hasFormattedStorage()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !formatted {
+						t.Fatalf("instance %d: superblock removed", srv.Index())
+					}
+				}
+			}
 			if tc.expErr != nil {
 				return
 			}
@@ -767,6 +1726,199 @@ func TestServer_CtlSvc_ResetFormatRanks(t *testing.T) {
 			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
 				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
 			}
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
+		})
+	}
+}
+
+type resetFormatEvents struct {
+	sync.Mutex
+	rx map[uint32][]string // rank to published event messages
+}
+
+func (rfe *resetFormatEvents) OnEvent(_ context.Context, e *events.RASEvent) {
+	if e.ID != events.RASEngineResetFormat {
+		return
+	}
+
+	rfe.Lock()
+	defer rfe.Unlock()
+	rfe.rx[e.Rank] = append(rfe.rx[e.Rank], e.Msg)
+}
+
+func (rfe *resetFormatEvents) count() int {
+	rfe.Lock()
+	defer rfe.Unlock()
+
+	var n int
+	for _, msgs := range rfe.rx {
+		n += len(msgs)
+	}
+	return n
+}
+
+func TestServer_CtlSvc_ResetFormatRanks_Events(t *testing.T) {
+	for name, tc := range map[string]struct {
+		startFails []bool // per instance, ranks are index + 1
+		nilEvents  bool
+		expEvents  map[uint32][]string
+	}{
+		"nil events": {
+			startFails: []bool{false, false},
+			nilEvents:  true,
+		},
+		"all ranks await format": {
+			startFails: []bool{false, false},
+			expEvents: map[uint32][]string{
+				1: {
+					"DAOS engine 0 (rank 1) reset format: Starting",
+					"DAOS engine 0 (rank 1) reset format: AwaitFormat",
+				},
+				2: {
+					"DAOS engine 1 (rank 2) reset format: Starting",
+					"DAOS engine 1 (rank 2) reset format: AwaitFormat",
+				},
+			},
+		},
+		"one rank stays stopped": {
+			startFails: []bool{false, true},
+			expEvents: map[uint32][]string{
+				1: {
+					"DAOS engine 0 (rank 1) reset format: Starting",
+					"DAOS engine 0 (rank 1) reset format: AwaitFormat",
+				},
+				2: {
+					"DAOS engine 1 (rank 2) reset format: Starting",
+					"DAOS engine 1 (rank 2) reset format: Stopped (want AwaitFormat, got Stopped)",
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			rx := &resetFormatEvents{rx: make(map[uint32][]string)}
+			if tc.nilEvents {
+				svc.events = nil
+			} else {
+				ps := events.NewPubSub(ctx, log)
+				defer ps.Close()
+				svc.events = ps
+				svc.events.Subscribe(events.RASTypeInfoOnly, rx)
+			}
+
+			for i, srv := range svc.harness.instances {
+				testDir, cleanup := common.CreateTestDir(t)
+				defer cleanup()
+				engineCfg := engine.NewConfig().WithScmMountPoint(testDir)
+
+				srv.runner = engine.NewTestRunner(&engine.TestRunnerConfig{}, engineCfg)
+				srv.setIndex(uint32(i))
+
+				superblock := &Superblock{
+					Version: superblockVersion,
+					UUID:    common.MockUUID(),
+					System:  "test",
+				}
+				superblock.Rank = new(system.Rank)
+				*superblock.Rank = system.Rank(i + 1)
+				srv.setSuperblock(superblock)
+				if err := srv.WriteSuperblock(); err != nil {
+					t.Fatal(err)
+				}
+
+				go func(s *EngineInstance, startFails bool) {
+					<-s.startRequested
+					if startFails {
+						return
+					}
+					s.waitFormat.SetTrue()
+				}(srv, tc.startFails[i])
+			}
+			svc.harness.rankStartTimeout = 50 * time.Millisecond
+
+			if _, err := svc.ResetFormatRanks(ctx,
+				&ctlpb.RanksReq{Ranks: "1-2", Force: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			var expCount int
+			for _, msgs := range tc.expEvents {
+				expCount += len(msgs)
+			}
+			deadline := time.Now().Add(time.Second)
+			for rx.count() < expCount && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			rx.Lock()
+			defer rx.Unlock()
+			if tc.expEvents == nil {
+				tc.expEvents = map[uint32][]string{}
+			}
+			if diff := cmp.Diff(tc.expEvents, rx.rx); diff != "" {
+				t.Fatalf("unexpected events (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_ResetFormatRanks_RunningRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		running []bool // per instance, ranks are index + 1
+		expMsg  string
+	}{
+		"single rank running": {
+			running: []bool{false, true, false},
+			expMsg:  "reset format not supported when rank 2 is running",
+		},
+		"multiple ranks running": {
+			running: []bool{true, false, true},
+			expMsg:  "reset format not supported when ranks 1,3 are running",
+		},
+		"all ranks running": {
+			running: []bool{true, true, true},
+			expMsg:  "reset format not supported when ranks 1-3 are running",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			engineCfgs := make([]*engine.Config, 0, len(tc.running))
+			for range tc.running {
+				engineCfgs = append(engineCfgs, engine.NewConfig().WithTargetCount(1))
+			}
+			svc := mockControlService(t, log, config.DefaultServer().WithEngines(engineCfgs...),
+				nil, nil, nil)
+
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				trc.Running.Store(tc.running[i])
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+				srv._superblock.Rank = system.NewRankPtr(uint32(i + 1))
+			}
+
+			_, gotErr := svc.ResetFormatRanks(context.Background(),
+				&ctlpb.RanksReq{Ranks: "1-3", Force: true})
+			if gotErr == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(gotErr.Error(), tc.expMsg) {
+				t.Fatalf("expected error to contain %q, got %q", tc.expMsg, gotErr.Error())
+			}
 		})
 	}
 }
@@ -781,24 +1933,27 @@ func TestServer_CtlSvc_StartRanks(t *testing.T) {
 		req              *ctlpb.RanksReq
 		ctxTimeout       time.Duration
 		expResults       []*sharedpb.RankResult
+		expNoLocalRanks  bool
 		expErr           error
 	}{
 		"nil request": {
-			expErr: errors.New("nil request"),
+			expErr: FaultNilRequest,
 		},
 		"no ranks specified": {
 			req:    &ctlpb.RanksReq{},
-			expErr: errors.New("no ranks specified in request"),
+			expErr: FaultNoRanksSpecified,
 		},
 		"missing superblock": {
 			req:       &ctlpb.RanksReq{Ranks: "0-3"},
 			missingSB: true,
 			// no results as rank cannot be read from superblock
-			expResults: []*sharedpb.RankResult{},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"missing ranks": {
-			req:        &ctlpb.RanksReq{Ranks: "0,3"},
-			expResults: []*sharedpb.RankResult{},
+			req:             &ctlpb.RanksReq{Ranks: "0,3"},
+			expResults:      []*sharedpb.RankResult{},
+			expNoLocalRanks: true,
 		},
 		"context timeout": { // near-immediate parent context Timeout
 			req:        &ctlpb.RanksReq{Ranks: "0-3"},
@@ -898,6 +2053,524 @@ func TestServer_CtlSvc_StartRanks(t *testing.T) {
 			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
 				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
 			}
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
 		})
 	}
 }
+
+func TestServer_CtlSvc_StartRanks_UpdatesMembership(t *testing.T) {
+	for name, tc := range map[string]struct {
+		startFails bool
+		expStates  map[system.Rank]system.MemberState
+	}{
+		"started ranks ready in membership": {
+			expStates: map[system.Rank]system.MemberState{
+				1: system.MemberStateReady,
+				2: system.MemberStateReady,
+				3: system.MemberStateStopped,
+			},
+		},
+		"failed ranks not updated": {
+			startFails: true,
+			expStates: map[system.Rank]system.MemberState{
+				1: system.MemberStateStopped,
+				2: system.MemberStateStopped,
+				3: system.MemberStateStopped,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+			svc.harness.rankStartTimeout = time.Second
+
+			// rank 3 is hosted elsewhere and must be left alone
+			svc.membership, _ = system.MockMembership(t, log, mockTCPResolver)
+			for rank := range tc.expStates {
+				m := system.MockMember(t, rank.Uint32(), system.MemberStateStopped)
+				if _, err := svc.membership.Add(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, srv := range svc.harness.instances {
+				srv.runner = engine.NewTestRunner(&engine.TestRunnerConfig{}, engine.NewConfig())
+				srv.setIndex(uint32(i))
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+
+				// mimic srv.run, set "ready" on startLoop rx
+				go func(s *EngineInstance, startFails bool) {
+					<-s.startRequested
+					if startFails {
+						return
+					}
+
+					ch := make(chan error, 1)
+					if err := s.runner.Start(context.TODO(), ch); err != nil {
+						t.Logf("failed to start runner: %s", err)
+						return
+					}
+					<-ch
+					s.ready.SetTrue()
+				}(srv, tc.startFails)
+			}
+
+			if _, err := svc.StartRanks(context.Background(), &ctlpb.RanksReq{Ranks: "1-3"}); err != nil {
+				t.Fatal(err)
+			}
+
+			for rank, expState := range tc.expStates {
+				m, err := svc.membership.Get(rank)
+				if err != nil {
+					t.Fatal(err)
+				}
+				common.AssertEqual(t, expState, m.State(), fmt.Sprintf("rank %d state", rank))
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_StartRanks_WaitPoolServices(t *testing.T) {
+	busy := &mgmtpb.DaosResp{Status: int32(drpc.DaosBusy)}
+	up := &mgmtpb.DaosResp{}
+
+	for name, tc := range map[string]struct {
+		noWait       bool
+		busyResps    int  // responses reporting services not up before up
+		neverUp      bool // always report services not up
+		startTimeout time.Duration
+		expCalls     int
+		expResults   []*sharedpb.RankResult
+	}{
+		"wait not requested": {
+			noWait:    true,
+			busyResps: 2,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"services up immediately": {
+			expCalls: 1,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"services up after delay": {
+			busyResps: 2,
+			expCalls:  3,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"services not up within timeout": {
+			neverUp:      true,
+			startTimeout: 100 * time.Millisecond,
+			expCalls:     1,
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msErrored, Errored: true},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+			if tc.startTimeout == 0 {
+				tc.startTimeout = 10 * time.Second
+			}
+			svc.harness.rankStartTimeout = tc.startTimeout
+
+			var clients []*mockDrpcClient
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+
+				dcc := new(mockDrpcClientConfig)
+				for j := 0; j < tc.busyResps; j++ {
+					dcc.setSendMsgResponseList(t, &mockDrpcResponse{
+						Status:  drpc.Status_SUCCESS,
+						Message: busy,
+					})
+				}
+				last := up
+				if tc.neverUp {
+					last = busy
+				}
+				rb, err := proto.Marshal(last)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dcc.setSendMsgResponse(drpc.Status_SUCCESS, rb, nil)
+
+				dc := newMockDrpcClient(dcc)
+				srv.setDrpcClient(dc)
+				clients = append(clients, dc)
+			}
+
+			gotResp, err := svc.StartRanks(context.Background(), &ctlpb.RanksReq{
+				Ranks:            "1-2",
+				WaitPoolServices: !tc.noWait,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+			}
+
+			for i, dc := range clients {
+				methods := dc.CalledMethods()
+				if tc.neverUp {
+					// polled until timeout
+					if len(methods) < tc.expCalls {
+						t.Fatalf("instance %d: expected at least %d dRPC calls, got %d",
+							i, tc.expCalls, len(methods))
+					}
+				} else {
+					common.AssertEqual(t, tc.expCalls, len(methods),
+						fmt.Sprintf("instance %d dRPC calls", i))
+				}
+				for _, m := range methods {
+					common.AssertEqual(t, drpc.MethodPoolSvcsUp, m, "unexpected dRPC method")
+				}
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_RestartRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req             *ctlpb.RanksReq
+		ignoreSIGINT    map[uint32]bool // instance indexes that don't exit on SIGINT
+		ignoreSIGKILL   map[uint32]bool // instance indexes that don't exit at all
+		ctxTimeout      time.Duration
+		expSignalsSent  map[uint32][]os.Signal
+		expStarted      map[uint32]bool
+		expResults      []*sharedpb.RankResult
+		expEscalated    string
+		expNoLocalRanks bool
+		expErr          error
+	}{
+		"nil request": {
+			expErr: FaultNilRequest,
+		},
+		"no ranks specified": {
+			req:    &ctlpb.RanksReq{},
+			expErr: FaultNoRanksSpecified,
+		},
+		"missing ranks": {
+			req:             &ctlpb.RanksReq{Ranks: "0,3"},
+			expSignalsSent:  map[uint32][]os.Signal{},
+			expStarted:      map[uint32]bool{},
+			expNoLocalRanks: true,
+		},
+		"context timeout": {
+			req:        &ctlpb.RanksReq{Ranks: "0-3"},
+			ctxTimeout: time.Nanosecond,
+			expErr:     context.DeadlineExceeded,
+		},
+		"restart started instances": {
+			req: &ctlpb.RanksReq{Ranks: "0-3"},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT},
+			},
+			expStarted: map[uint32]bool{0: true, 1: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+		},
+		"restart single instance": {
+			req: &ctlpb.RanksReq{Ranks: "2"},
+			expSignalsSent: map[uint32][]os.Signal{
+				1: {syscall.SIGINT},
+			},
+			expStarted: map[uint32]bool{1: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 2, State: msReady},
+			},
+		},
+		"escalate non-exiting instance": {
+			req:          &ctlpb.RanksReq{Ranks: "0-3", GracePeriodMs: 20},
+			ignoreSIGINT: map[uint32]bool{1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT, syscall.SIGKILL},
+			},
+			expStarted: map[uint32]bool{0: true, 1: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msReady},
+			},
+			expEscalated: "2",
+		},
+		"instance fails to stop": {
+			req:           &ctlpb.RanksReq{Ranks: "0-3", GracePeriodMs: 20},
+			ignoreSIGINT:  map[uint32]bool{1: true},
+			ignoreSIGKILL: map[uint32]bool{1: true},
+			expSignalsSent: map[uint32][]os.Signal{
+				0: {syscall.SIGINT},
+				1: {syscall.SIGINT, syscall.SIGKILL},
+			},
+			expStarted: map[uint32]bool{0: true},
+			expResults: []*sharedpb.RankResult{
+				{Rank: 1, State: msReady},
+				{Rank: 2, State: msErrored, Errored: true},
+			},
+			expEscalated: "2",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().WithTargetCount(1),
+				engine.NewConfig().WithTargetCount(1),
+			)
+			svc := mockControlService(t, log, cfg, nil, nil, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			svc.harness.rankReqTimeout = 50 * time.Millisecond
+			svc.harness.rankStartTimeout = time.Second
+
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			svc.events = ps
+
+			var mu sync.Mutex
+			signalsSent := make(map[uint32][]os.Signal)
+			started := make(map[uint32]bool)
+
+			for i, srv := range svc.harness.instances {
+				trc := &engine.TestRunnerConfig{}
+				trc.Running.SetTrue()
+				srv.ready.SetTrue()
+				trc.SignalCb = func(idx uint32, sig os.Signal) {
+					mu.Lock()
+					defer mu.Unlock()
+					signalsSent[idx] = append(signalsSent[idx], sig)
+				}
+				trc.ExitOnSignal = func(idx uint32, sig os.Signal) bool {
+					if sig == syscall.SIGKILL {
+						return !tc.ignoreSIGKILL[idx]
+					}
+					return !tc.ignoreSIGINT[idx]
+				}
+				srv.runner = engine.NewTestRunner(trc, engine.NewConfig())
+				srv.setIndex(uint32(i))
+
+				srv._superblock.Rank = new(system.Rank)
+				*srv._superblock.Rank = system.Rank(i + 1)
+
+				// mimic srv.run, set "ready" on startLoop rx
+				go func(s *EngineInstance) {
+					<-s.startRequested
+					s.ready.SetFalse()
+
+					mu.Lock()
+					started[s.Index()] = true
+					mu.Unlock()
+
+					ch := make(chan error, 1)
+					if err := s.runner.Start(context.TODO(), ch); err != nil {
+						t.Logf("failed to start runner: %s", err)
+						return
+					}
+					<-ch
+					s.ready.SetTrue()
+				}(srv)
+			}
+
+			if tc.ctxTimeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
+				defer cancel()
+			}
+
+			gotResp, gotErr := svc.RestartRanks(ctx, tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+			}
+			for _, result := range gotResp.Results {
+				if !result.Errored {
+					common.AssertEqual(t, "system restart", result.Msg, "result message")
+				}
+			}
+			common.AssertEqual(t, tc.expEscalated, gotResp.EscalatedRanks,
+				"escalated ranks")
+			common.AssertEqual(t, tc.expNoLocalRanks, gotResp.NoLocalRanks,
+				"no local ranks indicator")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(tc.expSignalsSent, signalsSent); diff != "" {
+				t.Fatalf("unexpected signals sent (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expStarted, started); diff != "" {
+				t.Fatalf("unexpected instances started (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_StartRanks_LeaderRanksFirst(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cfg := config.DefaultServer().WithEngines(
+		engine.NewConfig().WithTargetCount(1),
+		engine.NewConfig().WithTargetCount(1),
+	)
+	svc := mockControlService(t, log, cfg, nil, nil, nil)
+	svc.harness.rankStartTimeout = time.Second
+
+	leaderRank := system.Rank(2)
+	svc.harness.WithRankStartPolicy(LeaderRanksFirst(leaderRank))
+
+	var leader *EngineInstance
+	for i, srv := range svc.harness.instances {
+		srv.runner = engine.NewTestRunner(&engine.TestRunnerConfig{}, engine.NewConfig())
+		srv.setIndex(uint32(i))
+		srv._superblock.Rank = new(system.Rank)
+		*srv._superblock.Rank = system.Rank(i + 1)
+		if *srv._superblock.Rank == leaderRank {
+			leader = srv
+		}
+	}
+
+	var mu sync.Mutex
+	var startOrder []system.Rank
+	var leaderReadyAtFollowerStart bool
+
+	for _, srv := range svc.harness.instances {
+		// mimic srv.run, set "ready" on startLoop rx
+		go func(s *EngineInstance) {
+			<-s.startRequested
+			r, _ := s.GetRank()
+
+			mu.Lock()
+			startOrder = append(startOrder, r)
+			if s != leader {
+				leaderReadyAtFollowerStart = leader.isReady()
+			}
+			mu.Unlock()
+
+			ch := make(chan error, 1)
+			if err := s.runner.Start(context.TODO(), ch); err != nil {
+				t.Logf("failed to start runner: %s", err)
+				return
+			}
+			<-ch
+			// delay so that followers would start first without ordering
+			time.Sleep(10 * time.Millisecond)
+			s.ready.SetTrue()
+		}(srv)
+	}
+
+	gotResp, gotErr := svc.StartRanks(context.Background(), &ctlpb.RanksReq{Ranks: "0-3"})
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+
+	expResults := []*sharedpb.RankResult{
+		{Rank: 1, State: msReady},
+		{Rank: 2, State: msReady},
+	}
+	if diff := cmp.Diff(expResults, gotResp.Results, defRankCmpOpts...); diff != "" {
+		t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([]system.Rank{leaderRank, 1}, startOrder); diff != "" {
+		t.Fatalf("unexpected start order (-want, +got)\n%s\n", diff)
+	}
+	if !leaderReadyAtFollowerStart {
+		t.Fatal("follower received start signal before leader was ready")
+	}
+}
+
+func TestServer_CtlSvc_StartRanks_LeaderRanksNotReady(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cfg := config.DefaultServer().WithEngines(
+		engine.NewConfig().WithTargetCount(1),
+		engine.NewConfig().WithTargetCount(1),
+	)
+	svc := mockControlService(t, log, cfg, nil, nil, nil)
+	svc.harness.rankStartTimeout = 50 * time.Millisecond
+
+	leaderRank := system.Rank(2)
+	svc.harness.WithRankStartPolicy(LeaderRanksFirst(leaderRank))
+
+	var mu sync.Mutex
+	var startOrder []system.Rank
+
+	for i, srv := range svc.harness.instances {
+		srv.runner = engine.NewTestRunner(&engine.TestRunnerConfig{}, engine.NewConfig())
+		srv.setIndex(uint32(i))
+		srv._superblock.Rank = new(system.Rank)
+		*srv._superblock.Rank = system.Rank(i + 1)
+
+		// leader never becomes ready
+		go func(s *EngineInstance) {
+			<-s.startRequested
+			r, _ := s.GetRank()
+
+			mu.Lock()
+			startOrder = append(startOrder, r)
+			mu.Unlock()
+		}(srv)
+	}
+
+	gotResp, gotErr := svc.StartRanks(context.Background(), &ctlpb.RanksReq{Ranks: "0-3"})
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+
+	for _, res := range gotResp.Results {
+		if !res.Errored {
+			t.Fatalf("rank %d: expected start to fail", res.Rank)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([]system.Rank{leaderRank}, startOrder); diff != "" {
+		t.Fatalf("unexpected start order (-want, +got)\n%s\n", diff)
+	}
+}
@@ -0,0 +1,109 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+)
+
+// NvmeSelfTest starts a device self-test on a locally attached SSD.
+//
+// Suitable for commands invoked directly on server, not over gRPC.
+func (c *StorageControlService) NvmeSelfTest(req bdev.SelfTestRequest) (*bdev.SelfTestResponse, error) {
+	return c.bdev.StartSelfTest(req)
+}
+
+// NvmeSelfTestQuery reads the device self-test log of a locally attached SSD.
+//
+// Suitable for commands invoked directly on server, not over gRPC.
+func (c *StorageControlService) NvmeSelfTestQuery(req bdev.SelfTestQueryRequest) (*bdev.SelfTestQueryResponse, error) {
+	return c.bdev.QuerySelfTest(req)
+}
+
+// checkSelfTestTarget verifies that the SSD with the given PCI address isn't
+// in use by a running engine, as SPDK in the engine process holds exclusive
+// access to its devices.
+func (c *ControlService) checkSelfTestTarget(pciAddr string) error {
+	if pciAddr == "" {
+		return errors.New("no NVMe SSD PCI address specified")
+	}
+
+	for _, srv := range c.harness.Instances() {
+		if srv.isStarted() && common.Includes(srv.bdevConfig().DeviceList, pciAddr) {
+			return errors.Errorf("NVMe SSD %s is in use by running engine %d, stop the engine first",
+				pciAddr, srv.Index())
+		}
+	}
+
+	return nil
+}
+
+// NvmeSelfTest implements the method defined for the control service.
+//
+// Start a short or extended device self-test on the requested NVMe SSD. The
+// test runs in the background on the device, progress and result can be
+// retrieved with NvmeSelfTestQuery.
+func (c *ControlService) NvmeSelfTest(ctx context.Context, pbReq *ctlpb.NvmeSelfTestReq) (*ctlpb.NvmeSelfTestResp, error) {
+	c.log.Debugf("received NvmeSelfTest RPC: %+v", pbReq)
+
+	if err := c.checkSelfTestTarget(pbReq.GetPciAddr()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.StorageControlService.NvmeSelfTest(bdev.SelfTestRequest{
+		PciAddr:  pbReq.GetPciAddr(),
+		Extended: pbReq.GetExtended(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ctlpb.NvmeSelfTestResp{Code: resp.Code.String()}, nil
+}
+
+// NvmeSelfTestQuery implements the method defined for the control service.
+//
+// Report the progress of any device self-test running on the requested NVMe
+// SSD along with the result of the most recent completed test.
+func (c *ControlService) NvmeSelfTestQuery(ctx context.Context, pbReq *ctlpb.NvmeSelfTestQueryReq) (*ctlpb.NvmeSelfTestQueryResp, error) {
+	c.log.Debugf("received NvmeSelfTestQuery RPC: %+v", pbReq)
+
+	if err := c.checkSelfTestTarget(pbReq.GetPciAddr()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.StorageControlService.NvmeSelfTestQuery(bdev.SelfTestQueryRequest{
+		PciAddr: pbReq.GetPciAddr(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := resp.Status
+	pbResp := &ctlpb.NvmeSelfTestQueryResp{
+		PciAddr:    pbReq.GetPciAddr(),
+		InProgress: status.InProgress(),
+		Progress:   status.Progress,
+		HasResult:  status.HasResult(),
+		Passed:     status.HasResult() && status.Passed(),
+	}
+	if status.InProgress() {
+		pbResp.Current = status.Current.String()
+	}
+	if status.HasResult() {
+		pbResp.LastCode = status.LastCode.String()
+		pbResp.LastResult = status.LastResult.String()
+	}
+
+	return pbResp, nil
+}
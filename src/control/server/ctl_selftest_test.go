@@ -0,0 +1,210 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/config"
+	"github.com/daos-stack/daos/src/control/server/engine"
+	"github.com/daos-stack/daos/src/control/server/storage"
+	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+)
+
+const (
+	selfTestAddr     = "0000:81:00.0"
+	selfTestFreeAddr = "0000:82:00.0"
+)
+
+func selfTestService(t *testing.T, log logging.Logger, running bool, bmbc *bdev.MockBackendConfig) *ControlService {
+	t.Helper()
+
+	cfg := config.DefaultServer().WithEngines(
+		engine.NewConfig().
+			WithBdevClass("nvme").
+			WithBdevDeviceList(selfTestAddr),
+	)
+	if running {
+		return mockControlService(t, log, cfg, bmbc, nil, nil)
+	}
+
+	return mockControlServiceNoSB(t, log, cfg, bmbc, nil, nil)
+}
+
+func TestServer_CtlSvc_NvmeSelfTest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req     *ctlpb.NvmeSelfTestReq
+		running bool
+		bmbc    *bdev.MockBackendConfig
+		expResp *ctlpb.NvmeSelfTestResp
+		expErr  error
+	}{
+		"missing address": {
+			req:    &ctlpb.NvmeSelfTestReq{},
+			expErr: errors.New("no NVMe SSD PCI address"),
+		},
+		"ssd in use by running engine": {
+			req:     &ctlpb.NvmeSelfTestReq{PciAddr: selfTestAddr},
+			running: true,
+			expErr:  errors.New("in use by running engine 0"),
+		},
+		"unassigned ssd with running engine": {
+			req:     &ctlpb.NvmeSelfTestReq{PciAddr: selfTestFreeAddr},
+			running: true,
+			expResp: &ctlpb.NvmeSelfTestResp{Code: "short"},
+		},
+		"backend fails": {
+			req: &ctlpb.NvmeSelfTestReq{PciAddr: selfTestAddr},
+			bmbc: &bdev.MockBackendConfig{
+				SelfTestErr: errors.New("device says no"),
+			},
+			expErr: errors.New("device says no"),
+		},
+		"extended": {
+			req:     &ctlpb.NvmeSelfTestReq{PciAddr: selfTestAddr, Extended: true},
+			expResp: &ctlpb.NvmeSelfTestResp{Code: "extended"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := selfTestService(t, log, tc.running, tc.bmbc)
+
+			gotResp, gotErr := cs.NvmeSelfTest(context.TODO(), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_NvmeSelfTestQuery(t *testing.T) {
+	inProgress := func(pct uint32) *storage.NvmeSelfTestStatus {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    selfTestAddr,
+			Current:    storage.NvmeSelfTestExtended,
+			Progress:   pct,
+			LastCode:   storage.NvmeSelfTestShort,
+			LastResult: storage.NvmeSelfTestPassed,
+		}
+	}
+	completed := func(result storage.NvmeSelfTestResult) *storage.NvmeSelfTestStatus {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    selfTestAddr,
+			LastCode:   storage.NvmeSelfTestExtended,
+			LastResult: result,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		running  bool
+		states   []*storage.NvmeSelfTestStatus
+		queryErr error
+		expResps []*ctlpb.NvmeSelfTestQueryResp
+		expErr   error
+	}{
+		"ssd in use by running engine": {
+			running: true,
+			expErr:  errors.New("in use by running engine 0"),
+		},
+		"backend fails": {
+			queryErr: errors.New("device says no"),
+			expErr:   errors.New("device says no"),
+		},
+		"never run": {
+			expResps: []*ctlpb.NvmeSelfTestQueryResp{
+				{PciAddr: selfTestAddr},
+			},
+		},
+		"in progress then passed": {
+			states: []*storage.NvmeSelfTestStatus{
+				inProgress(20), inProgress(70),
+				completed(storage.NvmeSelfTestPassed),
+			},
+			expResps: []*ctlpb.NvmeSelfTestQueryResp{
+				{
+					PciAddr: selfTestAddr, InProgress: true, Current: "extended",
+					Progress: 20, HasResult: true, Passed: true,
+					LastCode: "short", LastResult: "passed",
+				},
+				{
+					PciAddr: selfTestAddr, InProgress: true, Current: "extended",
+					Progress: 70, HasResult: true, Passed: true,
+					LastCode: "short", LastResult: "passed",
+				},
+				{
+					PciAddr: selfTestAddr, HasResult: true, Passed: true,
+					LastCode: "extended", LastResult: "passed",
+				},
+			},
+		},
+		"in progress then failed": {
+			states: []*storage.NvmeSelfTestStatus{
+				inProgress(95),
+				completed(storage.NvmeSelfTestFailedSegment),
+			},
+			expResps: []*ctlpb.NvmeSelfTestQueryResp{
+				{
+					PciAddr: selfTestAddr, InProgress: true, Current: "extended",
+					Progress: 95, HasResult: true, Passed: true,
+					LastCode: "short", LastResult: "passed",
+				},
+				{
+					PciAddr: selfTestAddr, HasResult: true,
+					LastCode: "extended", LastResult: "failed segments",
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := selfTestService(t, log, tc.running, &bdev.MockBackendConfig{
+				SelfTestStates: map[string][]*storage.NvmeSelfTestStatus{
+					selfTestAddr: tc.states,
+				},
+				SelfTestErrs: map[string]error{
+					selfTestAddr: tc.queryErr,
+				},
+			})
+			req := &ctlpb.NvmeSelfTestQueryReq{PciAddr: selfTestAddr}
+
+			if tc.expErr != nil {
+				_, gotErr := cs.NvmeSelfTestQuery(context.TODO(), req)
+				common.CmpErr(t, tc.expErr, gotErr)
+				return
+			}
+
+			// poll the device as a caller would until the test completes
+			for i, expResp := range tc.expResps {
+				gotResp, gotErr := cs.NvmeSelfTestQuery(context.TODO(), req)
+				if gotErr != nil {
+					t.Fatal(gotErr)
+				}
+
+				if diff := cmp.Diff(expResp, gotResp, protocmp.Transform()); diff != "" {
+					t.Fatalf("unexpected response for query %d (-want, +got):\n%s\n", i, diff)
+				}
+			}
+		})
+	}
+}
@@ -7,8 +7,16 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
@@ -17,14 +25,174 @@ import (
 	"github.com/daos-stack/daos/src/control/server/storage"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
+type (
+	cachedNvmeScan struct {
+		resp    *bdev.ScanResponse
+		expires time.Time
+	}
+
+	// storageScanCache holds scan results for a period of time so that
+	// repeated scans don't need to access the hardware. The SCM provider
+	// already caches its scan results so only their expiry is tracked.
+	storageScanCache struct {
+		sync.Mutex
+		ttl        time.Duration
+		getTime    func() time.Time
+		nvme       map[string]*cachedNvmeScan
+		scmExpires time.Time
+	}
+
+	cachedHealthScan struct {
+		ctrlrs storage.NvmeControllers
+		read   time.Time
+	}
+
+	// healthThrottle limits how often NVMe health statistics are read from
+	// each engine's devices so that frequent polling doesn't result in
+	// repeated SMART log page reads.
+	healthThrottle struct {
+		sync.Mutex
+		interval time.Duration
+		getTime  func() time.Time
+		last     map[uint32]*cachedHealthScan
+	}
+)
+
+func newStorageScanCache(ttl time.Duration) *storageScanCache {
+	return &storageScanCache{
+		ttl:     ttl,
+		getTime: time.Now,
+		nvme:    make(map[string]*cachedNvmeScan),
+	}
+}
+
+func scanCacheKey(devList []string) string {
+	return strings.Join(devList, ",")
+}
+
+// nvmeScan returns cached results if they haven't expired and the request
+// doesn't specify NoCache, otherwise the scan is performed and results cached.
+func (sc *storageScanCache) nvmeScan(req bdev.ScanRequest, scan func(bdev.ScanRequest) (*bdev.ScanResponse, error)) (*bdev.ScanResponse, error) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	key := scanCacheKey(req.DeviceList)
+	if req.PciFilter != "" || req.SocketID != nil {
+		key += fmt.Sprintf("|%s", req.PciFilter)
+		if req.SocketID != nil {
+			key += fmt.Sprintf("|%d", *req.SocketID)
+		}
+	}
+	if req.Backend != bdev.ScanBackendDefault {
+		key += fmt.Sprintf("|%s", req.Backend)
+	}
+	if entry, exists := sc.nvme[key]; exists && !req.NoCache {
+		if sc.getTime().Before(entry.expires) {
+			return entry.resp, nil
+		}
+	}
+
+	// bypass provider cache so hardware is accessed
+	req.NoCache = true
+	resp, err := scan(req)
+	if err != nil {
+		return nil, err
+	}
+	sc.nvme[key] = &cachedNvmeScan{resp: resp, expires: sc.getTime().Add(sc.ttl)}
+
+	return resp, nil
+}
+
+// scmScan forces a rescan once the results cached by the SCM provider have
+// expired, otherwise the provider returns its cached results unless the
+// request specifies Rescan.
+func (sc *storageScanCache) scmScan(req scm.ScanRequest, scan func(scm.ScanRequest) (*scm.ScanResponse, error)) (*scm.ScanResponse, error) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	if !sc.getTime().Before(sc.scmExpires) {
+		req.Rescan = true
+	}
+
+	resp, err := scan(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Rescan {
+		sc.scmExpires = sc.getTime().Add(sc.ttl)
+	}
+
+	return resp, nil
+}
+
+func newHealthThrottle(interval time.Duration) *healthThrottle {
+	return &healthThrottle{
+		interval: interval,
+		getTime:  time.Now,
+		last:     make(map[uint32]*cachedHealthScan),
+	}
+}
+
+// get returns the controllers from the last health read for the given engine
+// if it was performed within the minimum interval and force isn't set,
+// otherwise read is called and the results stored. Failed reads aren't stored.
+func (ht *healthThrottle) get(idx uint32, force bool, read func() (storage.NvmeControllers, error)) (storage.NvmeControllers, error) {
+	ht.Lock()
+	defer ht.Unlock()
+
+	if entry, exists := ht.last[idx]; exists && !force {
+		if ht.getTime().Before(entry.read.Add(ht.interval)) {
+			return entry.ctrlrs, nil
+		}
+	}
+
+	ctrlrs, err := read()
+	if err != nil {
+		return nil, err
+	}
+	ht.last[idx] = &cachedHealthScan{ctrlrs: ctrlrs, read: ht.getTime()}
+
+	return ctrlrs, nil
+}
+
+// Storage scan types reported to ScanMetrics.
+const (
+	scanTypeNvme = "nvme"
+	scanTypeScm  = "scm"
+)
+
+// defaultScanTimeout is the maximum time to wait for a storage scan to
+// complete before returning an error.
+const defaultScanTimeout = 2 * time.Minute
+
+// defaultHealthInterval is the minimum time between reads of NVMe health
+// statistics from an engine's devices.
+const defaultHealthInterval = 5 * time.Second
+
+// ScanMetrics is implemented by types that record measurements of storage
+// scan operations.
+type ScanMetrics interface {
+	ObserveScan(scanType string, elapsed time.Duration, err error)
+}
+
 // StorageControlService encapsulates the storage part of the control service
 type StorageControlService struct {
 	log             logging.Logger
 	bdev            *bdev.Provider
 	scm             *scm.Provider
 	instanceStorage []*engine.StorageConfig
+	storageMu       *sync.RWMutex // protects instanceStorage
+	scanCache       *storageScanCache
+	healthThrottle  *healthThrottle
+	scanMetrics     ScanMetrics
+	scanTimeout     time.Duration
+	scanCfgBdevs    bool
+	getHugePageInfo getHugePageInfoFn
+	prepareMu       *sync.Mutex     // protects preparing
+	preparing       map[string]bool // prepare types currently running
 }
 
 // NewStorageControlService returns an initialized *StorageControlService
@@ -39,7 +207,63 @@ func NewStorageControlService(log logging.Logger, bdev *bdev.Provider, scm *scm.
 		bdev:            bdev,
 		scm:             scm,
 		instanceStorage: instanceStorage,
+		storageMu:       new(sync.RWMutex),
+		scanTimeout:     defaultScanTimeout,
+		healthThrottle:  newHealthThrottle(defaultHealthInterval),
+		getHugePageInfo: getHugePageInfo,
+		prepareMu:       new(sync.Mutex),
+		preparing:       make(map[string]bool),
+	}
+}
+
+// WithScanCacheTTL enables caching of storage scan results for the given
+// duration. Requests setting NoCache (NVMe) or Rescan (SCM) bypass the cache.
+func (c *StorageControlService) WithScanCacheTTL(ttl time.Duration) *StorageControlService {
+	c.scanCache = nil
+	if ttl > 0 {
+		c.scanCache = newStorageScanCache(ttl)
+	}
+	return c
+}
+
+// WithHealthInterval sets the minimum time between reads of NVMe health
+// statistics, a zero value disables the throttle.
+func (c *StorageControlService) WithHealthInterval(interval time.Duration) *StorageControlService {
+	c.healthThrottle = nil
+	if interval > 0 {
+		c.healthThrottle = newHealthThrottle(interval)
 	}
+	return c
+}
+
+// WithScanTimeout sets the maximum time to wait for a storage scan to complete,
+// a zero value disables the timeout.
+func (c *StorageControlService) WithScanTimeout(timeout time.Duration) *StorageControlService {
+	c.scanTimeout = timeout
+	return c
+}
+
+// WithConfiguredBdevScan restricts the NVMe scan performed during Setup to
+// the devices listed in the engine configs, and the devices behind any VMD
+// addresses listed, rather than enumerating every device on the host.
+func (c *StorageControlService) WithConfiguredBdevScan(enabled bool) *StorageControlService {
+	c.scanCfgBdevs = enabled
+	return c
+}
+
+// WithScanMetrics sets the receiver of storage scan measurements.
+func (c *StorageControlService) WithScanMetrics(metrics ScanMetrics) *StorageControlService {
+	c.scanMetrics = metrics
+	return c
+}
+
+// observeScan reports the duration and result of a storage scan if metrics
+// have been enabled.
+func (c *StorageControlService) observeScan(scanType string, start time.Time, err error) {
+	if c.scanMetrics == nil {
+		return
+	}
+	c.scanMetrics.ObserveScan(scanType, time.Since(start), err)
 }
 
 // findBdevsWithDomain retrieves controllers in scan response that match the
@@ -93,6 +317,20 @@ func substBdevVmdAddrs(cfgBdevs []string, scanResp *bdev.ScanResponse) ([]string
 	return newCfgBdevs, nil
 }
 
+// instanceStorageCfgs returns a copy of the storage config of each engine which
+// is safe to read whilst checkCfgBdevs updates the device lists.
+func (c *StorageControlService) instanceStorageCfgs() []engine.StorageConfig {
+	c.storageMu.RLock()
+	defer c.storageMu.RUnlock()
+
+	cfgs := make([]engine.StorageConfig, 0, len(c.instanceStorage))
+	for _, storageCfg := range c.instanceStorage {
+		cfgs = append(cfgs, *storageCfg)
+	}
+
+	return cfgs
+}
+
 // canAccessBdevs evaluates if any specified Bdevs are not accessible.
 //
 // Specified Bdevs can be VMD addresses.
@@ -119,11 +357,32 @@ func canAccessBdevs(cfgBdevs []string, scanResp *bdev.ScanResponse) ([]string, b
 	return missing, len(missing) == 0
 }
 
+// undersizedBdevs returns the addresses of the config specified NVMe devices
+// whose namespaces have a combined capacity below minSize bytes.
+func undersizedBdevs(cfgBdevs []string, minSize uint64, scanResp *bdev.ScanResponse) []string {
+	var small []string
+
+	for _, pciAddr := range cfgBdevs {
+		for _, ctrlr := range scanResp.Controllers {
+			if ctrlr.PciAddr == pciAddr && ctrlr.Capacity() < minSize {
+				small = append(small, pciAddr)
+				break
+			}
+		}
+	}
+
+	return small
+}
+
 // checkCfgBdevs performs validation on NVMe returned from initial scan.
 func (c *StorageControlService) checkCfgBdevs(scanResp *bdev.ScanResponse) error {
 	if scanResp == nil {
 		return errors.New("received nil scan response")
 	}
+
+	c.storageMu.Lock()
+	defer c.storageMu.Unlock()
+
 	if len(c.instanceStorage) == 0 {
 		return nil
 	}
@@ -153,32 +412,238 @@ func (c *StorageControlService) checkCfgBdevs(scanResp *bdev.ScanResponse) error
 		// fail if config specified nvme devices are inaccessible
 		missing, ok := canAccessBdevs(cfgBdevs, scanResp)
 		if !ok {
-			return FaultBdevNotFound(missing)
+			for _, addr := range missing {
+				c.log.Errorf("engine %d: NVMe SSD %s specified in bdev_list not found",
+					idx, addr)
+			}
+			return FaultEngineBdevNotFound(idx, missing)
+		}
+
+		// fail if config specified nvme devices are below the minimum size
+		if storageCfg.Bdev.MinDevSize > 0 {
+			minSize := uint64(storageCfg.Bdev.MinDevSize) * humanize.GByte
+			small := undersizedBdevs(cfgBdevs, minSize, scanResp)
+			if len(small) != 0 {
+				for _, addr := range small {
+					c.log.Errorf("engine %d: NVMe SSD %s specified in bdev_list smaller than %s",
+						idx, addr, humanize.Bytes(minSize))
+				}
+				return FaultEngineBdevTooSmall(idx, small, minSize)
+			}
 		}
 	}
 
 	return nil
 }
 
+// configuredBdevs returns the union of the NVMe device lists of all engines,
+// in config order.
+func (c *StorageControlService) configuredBdevs() []string {
+	var devs []string
+	for _, storageCfg := range c.instanceStorageCfgs() {
+		for _, dev := range storageCfg.Bdev.GetNvmeDevs() {
+			if !common.Includes(devs, dev) {
+				devs = append(devs, dev)
+			}
+		}
+	}
+
+	return devs
+}
+
+// setupScanRequest returns the request for the NVMe scan performed during
+// Setup, and false if no scan is needed.
+//
+// When restricted to configured devices the provider cache is bypassed so that
+// the partial results don't satisfy later scans of all devices.
+func (c *StorageControlService) setupScanRequest() (bdev.ScanRequest, bool) {
+	if !c.scanCfgBdevs {
+		return bdev.ScanRequest{}, true
+	}
+
+	devs := c.configuredBdevs()
+	if len(devs) == 0 {
+		return bdev.ScanRequest{}, false
+	}
+
+	return bdev.ScanRequest{
+		DeviceList: devs,
+		ExpandVMD:  !c.bdev.IsVMDDisabled(),
+		NoCache:    true,
+	}, true
+}
+
+// engineTarget identifies an engine target and the SSD which backs it.
+type engineTarget struct {
+	EngineIdx uint32
+	Rank      system.Rank
+	PciAddr   string
+	DevUUID   string
+}
+
+// mapEngineTargets resolves the target IDs listed in the SMD devices of each
+// scanned controller to the engine instance whose config lists that
+// controller.
+//
+// Target IDs are relative to an engine so the returned map holds one entry
+// for each engine that hosts a target with a given ID. Controllers that are
+// not assigned to an engine in the config are ignored.
+func (c *StorageControlService) mapEngineTargets(scanResp *bdev.ScanResponse) (map[int32][]*engineTarget, error) {
+	if scanResp == nil {
+		return nil, errors.New("received nil scan response")
+	}
+
+	tgtMap := make(map[int32][]*engineTarget)
+	for idx, storageCfg := range c.instanceStorageCfgs() {
+		cfgBdevs := storageCfg.Bdev.GetNvmeDevs()
+
+		for _, ctrlr := range scanResp.Controllers {
+			if !common.Includes(cfgBdevs, ctrlr.PciAddr) {
+				continue
+			}
+
+			for _, dev := range ctrlr.SmdDevices {
+				for _, tgtID := range dev.TargetIDs {
+					tgtMap[tgtID] = append(tgtMap[tgtID], &engineTarget{
+						EngineIdx: uint32(idx),
+						Rank:      dev.Rank,
+						PciAddr:   ctrlr.PciAddr,
+						DevUUID:   dev.UUID,
+					})
+				}
+			}
+		}
+	}
+
+	return tgtMap, nil
+}
+
+// runWithContext runs the given function and returns its result, or the
+// context error if the context is done before the function completes.
+//
+// The function is not interrupted on cancellation and will run to completion
+// in the background.
+func runWithContext(ctx context.Context, fn func() error) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- fn()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errChan:
+		return err
+	}
+}
+
+// runPrepare runs the given prepare function unless a prepare of the same type
+// is already running, returning the context error if the context is done
+// before it completes.
+//
+// The prepare cannot be interrupted once it has entered the provider's cgo
+// calls, so on cancellation it continues to run in the background and further
+// prepares of the same type are rejected until it completes.
+func (c *StorageControlService) runPrepare(ctx context.Context, prepType string, fn func() error) error {
+	c.prepareMu.Lock()
+	if c.preparing[prepType] {
+		c.prepareMu.Unlock()
+		return errors.Errorf("%s prepare already in progress", prepType)
+	}
+	c.preparing[prepType] = true
+	c.prepareMu.Unlock()
+
+	err := runWithContext(ctx, func() error {
+		defer func() {
+			c.prepareMu.Lock()
+			delete(c.preparing, prepType)
+			c.prepareMu.Unlock()
+		}()
+
+		return fn()
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// runScan runs the given scan function, returning an error if it doesn't
+// complete within the configured scan timeout.
+//
+// The scan cannot be interrupted once it has entered the provider's cgo calls,
+// so on timeout the goroutine running it is abandoned and may persist until
+// the underlying syscall returns. Subsequent cached scans will block on the
+// abandoned scan and also time out until it completes.
+func (c *StorageControlService) runScan(scanType string, fn func() error) error {
+	if c.scanTimeout <= 0 {
+		return fn()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.scanTimeout)
+	defer cancel()
+
+	if err := runWithContext(ctx, fn); err != nil {
+		if err == context.DeadlineExceeded {
+			return errors.Wrapf(err, "%s scan timed out after %s", scanType, c.scanTimeout)
+		}
+		return err
+	}
+
+	return nil
+}
+
 // Setup delegates to Storage implementation's Setup methods.
 func (c *StorageControlService) Setup() error {
-	if _, err := c.ScmScan(scm.ScanRequest{}); err != nil {
+	return c.SetupContext(context.Background())
+}
+
+// SetupContext delegates to Storage implementation's Setup methods and
+// aborts with the context error if the context is cancelled before scanning
+// completes.
+func (c *StorageControlService) SetupContext(ctx context.Context) error {
+	err := runWithContext(ctx, func() error {
+		_, err := c.ScmScan(scm.ScanRequest{})
+		return err
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
 		c.log.Debugf("%s\n", errors.Wrap(err, "Warning, SCM Scan"))
 	}
 
 	// don't scan if using emulated NVMe
-	for _, storageCfg := range c.instanceStorage {
+	for _, storageCfg := range c.instanceStorageCfgs() {
 		if storageCfg.Bdev.Class != storage.BdevClassNvme {
 			return nil
 		}
 	}
 
-	nvmeScanResp, err := c.NvmeScan(bdev.ScanRequest{})
+	scanReq, needScan := c.setupScanRequest()
+	if !needScan {
+		c.log.Debug("no NVMe SSDs in config, skipping NVMe scan")
+		return nil
+	}
+
+	var nvmeScanResp *bdev.ScanResponse
+	err = runWithContext(ctx, func() (err error) {
+		nvmeScanResp, err = c.NvmeScan(scanReq)
+		return
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	if err != nil {
 		c.log.Debugf("%s\n", errors.Wrap(err, "Warning, NVMe Scan"))
 		return nil
 	}
 
+	if err := nvmeScanResp.Controllers.ValidateIdentities(); err != nil {
+		c.log.Infof("Warning, NVMe Scan: %s", err)
+	}
+
 	if err := c.checkCfgBdevs(nvmeScanResp); err != nil {
 		return errors.Wrap(err, "validate server config bdevs")
 	}
@@ -186,17 +651,159 @@ func (c *StorageControlService) Setup() error {
 	return nil
 }
 
+// validateNvmePrepareReq checks the hugepage count and PCI address lists in
+// the request so bad values are rejected before reaching the provider. The
+// allowlist of a reset request is not checked as the provider reports invalid
+// addresses in its per-device results.
+func validateNvmePrepareReq(req bdev.PrepareRequest) error {
+	if req.HugePageCount < 0 {
+		return errors.Errorf("invalid number of hugepages requested: %d",
+			req.HugePageCount)
+	}
+
+	lists := []string{req.PCIBlocklist}
+	if !req.ResetOnly {
+		lists = append(lists, req.PCIAllowlist)
+	}
+	for _, list := range lists {
+		for _, addr := range strings.Fields(list) {
+			if _, _, _, _, err := common.ParsePCIAddress(addr); err != nil {
+				return errors.Wrapf(err, "invalid pci address %q", addr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHugePagesAllocatable verifies that the number of hugepages requested
+// can be allocated on the host. Reset requests and those with a zero count,
+// which use the default, are not checked.
+func (c *StorageControlService) checkHugePagesAllocatable(req bdev.PrepareRequest) error {
+	count := req.HugePageCount
+	if req.ResetOnly || count == 0 {
+		return nil
+	}
+
+	hpi, err := c.getHugePageInfo()
+	if err != nil {
+		return errors.Wrap(err, "unable to read system hugepage info")
+	}
+	if count > hpi.Allocatable() {
+		return FaultInsufficientHugePageMemory(hpi.Allocatable(), count)
+	}
+
+	return nil
+}
+
+// checkHugePageLeak returns the number of hugepages that remain in use after
+// a full reset. Once devices have been released no hugepages should be held, so
+// any still in use indicate that pages allocated by a previous prepare have
+// leaked.
+func (c *StorageControlService) checkHugePageLeak() (int, error) {
+	hpi, err := c.getHugePageInfo()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to read system hugepage info")
+	}
+
+	return hpi.InUse(), nil
+}
+
 // NvmePrepare preps locally attached SSDs and returns error.
 //
+// Following a prepare, the hugepage count in the response is replaced with the
+// resulting allocation reported by the system. Following a full reset, the hugepage allocation is checked and the
+// number of pages still in use is reported in the response so that leaks can
+// be detected. Failure to read the allocation doesn't fail the operation.
+//
 // Suitable for commands invoked directly on server, not over gRPC.
 func (c *StorageControlService) NvmePrepare(req bdev.PrepareRequest) (*bdev.PrepareResponse, error) {
-	return c.bdev.Prepare(req)
+	return c.NvmePrepareContext(context.Background(), req)
+}
+
+// NvmePrepareContext preps locally attached SSDs as NvmePrepare does but
+// returns the context error if the context is done before the prepare
+// completes. A prepare that outlives its context runs to completion and
+// further NVMe prepares fail as in progress until it has finished.
+func (c *StorageControlService) NvmePrepareContext(ctx context.Context, req bdev.PrepareRequest) (*bdev.PrepareResponse, error) {
+	if err := validateNvmePrepareReq(req); err != nil {
+		return nil, err
+	}
+	if err := c.checkHugePagesAllocatable(req); err != nil {
+		return nil, err
+	}
+
+	var resp *bdev.PrepareResponse
+	err := c.runPrepare(ctx, scanTypeNvme, func() (err error) {
+		resp, err = c.bdev.Prepare(req)
+		return
+	})
+	if ctx.Err() != nil {
+		// response may still be written by the running prepare
+		return nil, err
+	}
+	if err != nil || req.DryRun {
+		return resp, err
+	}
+
+	if !req.ResetOnly {
+		hpi, err := c.getHugePageInfo()
+		if err != nil {
+			c.log.Debugf("skipping hugepage allocation check: %s", err)
+			return resp, nil
+		}
+		resp.HugePageCount = hpi.Total
+
+		return resp, nil
+	}
+	if req.PCIAllowlist != "" {
+		return resp, nil
+	}
+
+	inUse, err := c.checkHugePageLeak()
+	if err != nil {
+		c.log.Debugf("skipping hugepage leak check: %s", err)
+		return resp, nil
+	}
+	if inUse > 0 {
+		c.log.Infof("Warning, NVMe Reset: %d hugepages still in use", inUse)
+	}
+	resp.HugePagesInUse = inUse
+
+	return resp, nil
 }
 
 // GetScmState performs required initialization and returns current state
-// of SCM module preparation.
-func (c *StorageControlService) GetScmState() (storage.ScmState, error) {
-	return c.scm.GetPmemState()
+// of SCM module preparation alongside the cause of that state.
+func (c *StorageControlService) GetScmState() (storage.ScmState, storage.ScmStateCause, error) {
+	state, err := c.scm.GetPmemState()
+	if err != nil {
+		return state, storage.ScmCauseUnknown, err
+	}
+
+	ssr, err := c.ScmScan(scm.ScanRequest{})
+	if err != nil {
+		return state, storage.ScmCauseUnknown, err
+	}
+
+	return state, storage.GetScmStateCause(state, len(ssr.Modules)), nil
+}
+
+// GetScmPrepareAction returns the current state of SCM module preparation and
+// the action that ScmPrepare would take for the given request, without
+// performing it.
+func (c *StorageControlService) GetScmPrepareAction(req scm.PrepareRequest) (storage.ScmState, scm.PrepareAction, error) {
+	state, cause, err := c.GetScmState()
+	if err != nil {
+		return state, scm.PrepareActionNone, err
+	}
+	if cause == storage.ScmCauseNoModules {
+		return state, scm.PrepareActionNone, nil
+	}
+
+	action, err := scm.GetPrepareAction(state, req.Reset)
+
+	return state, action, err
 }
 
 // ScmPrepare preps locally attached modules and returns need to reboot message,
@@ -204,16 +811,302 @@ func (c *StorageControlService) GetScmState() (storage.ScmState, error) {
 //
 // Suitable for commands invoked directly on server, not over gRPC.
 func (c *StorageControlService) ScmPrepare(req scm.PrepareRequest) (*scm.PrepareResponse, error) {
-	// transition to the next state in SCM preparation
-	return c.scm.Prepare(req)
+	return c.ScmPrepareContext(context.Background(), req)
+}
+
+// ScmPrepareContext preps locally attached modules as ScmPrepare does but
+// returns the context error if the context is done before the prepare
+// completes. As with NvmePrepareContext, further SCM prepares fail as in
+// progress until a prepare that outlives its context has finished.
+func (c *StorageControlService) ScmPrepareContext(ctx context.Context, req scm.PrepareRequest) (*scm.PrepareResponse, error) {
+	var resp *scm.PrepareResponse
+	if err := c.runPrepare(ctx, scanTypeScm, func() (err error) {
+		// transition to the next state in SCM preparation
+		resp, err = c.scm.Prepare(req)
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
 // NvmeScan scans locally attached SSDs.
-func (c *StorageControlService) NvmeScan(req bdev.ScanRequest) (*bdev.ScanResponse, error) {
-	return c.bdev.Scan(req)
+func (c *StorageControlService) NvmeScan(req bdev.ScanRequest) (resp *bdev.ScanResponse, err error) {
+	defer func(start time.Time) {
+		c.observeScan(scanTypeNvme, start, err)
+	}(time.Now())
+
+	var scanResp *bdev.ScanResponse
+	if err = c.runScan(scanTypeNvme, func() (err error) {
+		if c.scanCache != nil {
+			scanResp, err = c.scanCache.nvmeScan(req, c.bdev.Scan)
+			return
+		}
+		scanResp, err = c.bdev.Scan(req)
+		return
+	}); errors.Cause(err) == context.DeadlineExceeded {
+		return nil, err
+	}
+
+	return scanResp, err
 }
 
 // ScmScan scans locally attached modules, namespaces and state of DCPM config.
-func (c *StorageControlService) ScmScan(req scm.ScanRequest) (*scm.ScanResponse, error) {
-	return c.scm.Scan(req)
+func (c *StorageControlService) ScmScan(req scm.ScanRequest) (resp *scm.ScanResponse, err error) {
+	defer func(start time.Time) {
+		c.observeScan(scanTypeScm, start, err)
+	}(time.Now())
+
+	var scanResp *scm.ScanResponse
+	if err = c.runScan(scanTypeScm, func() (err error) {
+		if c.scanCache != nil {
+			scanResp, err = c.scanCache.scmScan(req, c.scm.Scan)
+			return
+		}
+		scanResp, err = c.scm.Scan(req)
+		return
+	}); errors.Cause(err) == context.DeadlineExceeded {
+		return nil, err
+	}
+
+	return scanResp, err
+}
+
+// StorageHealthSummary describes the overall health of storage on the node.
+type StorageHealthSummary struct {
+	NvmeHealthy  int
+	NvmeWarning  int
+	NvmeCritical int
+	NvmeUnknown  int
+	ScmState     storage.ScmState
+	MissingBdevs []string
+}
+
+// HealthSummary scans locally attached SSDs and SCM modules and returns the
+// number of SSDs at each health severity level, the SCM state and any
+// config specified NVMe devices which are missing from the scan.
+func (c *StorageControlService) HealthSummary() (*StorageHealthSummary, error) {
+	nsr, err := c.NvmeScan(bdev.ScanRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "nvme scan")
+	}
+	ssr, err := c.ScmScan(scm.ScanRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "scm scan")
+	}
+
+	summary := &StorageHealthSummary{
+		ScmState: ssr.State,
+	}
+
+	for _, ctrlr := range nsr.Controllers {
+		switch ctrlr.HealthStats.Severity() {
+		case storage.NvmeHealthHealthy:
+			summary.NvmeHealthy++
+		case storage.NvmeHealthWarning:
+			summary.NvmeWarning++
+		case storage.NvmeHealthCritical:
+			summary.NvmeCritical++
+		default:
+			summary.NvmeUnknown++
+		}
+	}
+
+	for _, storageCfg := range c.instanceStorageCfgs() {
+		missing, ok := canAccessBdevs(storageCfg.Bdev.GetNvmeDevs(), nsr)
+		if !ok {
+			summary.MissingBdevs = append(summary.MissingBdevs, missing...)
+		}
+	}
+
+	return summary, nil
+}
+
+// StorageScanDump contains the combined results of NVMe and SCM scans of the
+// node in a form suitable for serialization and offline analysis.
+type StorageScanDump struct {
+	Nvme *bdev.ScanResponse `json:"nvme"`
+	Scm  *scm.ScanResponse  `json:"scm"`
+}
+
+// DumpScan scans locally attached SSDs and SCM modules and writes the results,
+// including device health and SMD device details, to the given writer as JSON.
+func (c *StorageControlService) DumpScan(w io.Writer) error {
+	if w == nil {
+		return errors.New("nil writer")
+	}
+
+	nsr, err := c.NvmeScan(bdev.ScanRequest{})
+	if err != nil {
+		return errors.Wrap(err, "nvme scan")
+	}
+	ssr, err := c.ScmScan(scm.ScanRequest{})
+	if err != nil {
+		return errors.Wrap(err, "scm scan")
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&StorageScanDump{Nvme: nsr, Scm: ssr}); err != nil {
+		return errors.Wrap(err, "encode storage scan dump")
+	}
+
+	return nil
+}
+
+// LoadScanDump reads storage scan results previously written by DumpScan.
+func LoadScanDump(r io.Reader) (*StorageScanDump, error) {
+	if r == nil {
+		return nil, errors.New("nil reader")
+	}
+
+	dump := new(StorageScanDump)
+	if err := json.NewDecoder(r).Decode(dump); err != nil {
+		return nil, errors.Wrap(err, "decode storage scan dump")
+	}
+	if dump.Nvme == nil {
+		dump.Nvme = new(bdev.ScanResponse)
+	}
+	if dump.Scm == nil {
+		dump.Scm = new(scm.ScanResponse)
+	}
+
+	return dump, nil
+}
+
+type (
+	// DeviceSocketMismatch describes a configured device attached to a
+	// different socket than the engine's SCM.
+	DeviceSocketMismatch struct {
+		Device      string
+		SocketID    uint32
+		ExpSocketID uint32
+	}
+
+	// EngineStorageDiff describes how the storage discovered for an engine
+	// differs from that specified in its config.
+	EngineStorageDiff struct {
+		Index          int
+		MissingBdevs   []string
+		MissingScm     []string
+		SocketMismatch []*DeviceSocketMismatch
+	}
+
+	// StorageConfigDiff describes how the storage discovered on the node
+	// differs from that specified in the config.
+	StorageConfigDiff struct {
+		Engines    []*EngineStorageDiff
+		ExtraBdevs []string
+		ExtraScm   []string
+	}
+)
+
+// IsEmpty returns true if the discovered storage matches the config.
+func (d *StorageConfigDiff) IsEmpty() bool {
+	if len(d.ExtraBdevs) != 0 || len(d.ExtraScm) != 0 {
+		return false
+	}
+	for _, ed := range d.Engines {
+		if len(ed.MissingBdevs) != 0 || len(ed.MissingScm) != 0 ||
+			len(ed.SocketMismatch) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reconcileEngine compares the storage config of an engine with scan results.
+// The socket of the first configured SCM namespace found is taken as that of
+// the engine and configured devices on other sockets are reported.
+func reconcileEngine(idx int, cfg *engine.StorageConfig, nsr *bdev.ScanResponse, ssr *scm.ScanResponse) *EngineStorageDiff {
+	diff := &EngineStorageDiff{Index: idx}
+
+	var socketID *uint32
+	checkSocket := func(dev string, id uint32) {
+		if socketID == nil {
+			socketID = &id
+			return
+		}
+		if id != *socketID {
+			diff.SocketMismatch = append(diff.SocketMismatch, &DeviceSocketMismatch{
+				Device:      dev,
+				SocketID:    id,
+				ExpSocketID: *socketID,
+			})
+		}
+	}
+
+	if cfg.SCM.Class == storage.ScmClassDCPM {
+		for _, dev := range cfg.SCM.DeviceList {
+			var found *storage.ScmNamespace
+			for _, ns := range ssr.Namespaces {
+				if ns.BlockDevice == filepath.Base(dev) {
+					found = ns
+					break
+				}
+			}
+			if found == nil {
+				diff.MissingScm = append(diff.MissingScm, dev)
+				continue
+			}
+			checkSocket(dev, found.NumaNode)
+		}
+	}
+
+	cfgBdevs := cfg.Bdev.GetNvmeDevs()
+	diff.MissingBdevs, _ = canAccessBdevs(cfgBdevs, nsr)
+	for _, ctrlr := range nsr.Controllers {
+		if common.Includes(cfgBdevs, ctrlr.PciAddr) {
+			checkSocket(ctrlr.PciAddr, uint32(ctrlr.SocketID))
+		}
+	}
+
+	return diff
+}
+
+// ReconcileConfig scans locally attached SSDs and SCM namespaces and compares
+// the results with the storage specified in the config of each engine.
+//
+// The returned diff lists configured devices missing from the scan, devices
+// attached to a different socket than the rest of an engine's storage and
+// discovered devices not assigned to any engine.
+func (c *StorageControlService) ReconcileConfig() (*StorageConfigDiff, error) {
+	nsr, err := c.NvmeScan(bdev.ScanRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "nvme scan")
+	}
+	ssr, err := c.ScmScan(scm.ScanRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "scm scan")
+	}
+
+	diff := new(StorageConfigDiff)
+	cfgScm := make(map[string]bool)
+	storageCfgs := c.instanceStorageCfgs()
+	for idx := range storageCfgs {
+		storageCfg := &storageCfgs[idx]
+		diff.Engines = append(diff.Engines, reconcileEngine(idx, storageCfg, nsr, ssr))
+
+		if storageCfg.SCM.Class == storage.ScmClassDCPM {
+			for _, dev := range storageCfg.SCM.DeviceList {
+				cfgScm[filepath.Base(dev)] = true
+			}
+		}
+	}
+
+	cfgBdevs := c.configuredBdevs()
+	for _, ctrlr := range nsr.Controllers {
+		if !common.Includes(cfgBdevs, ctrlr.PciAddr) {
+			diff.ExtraBdevs = append(diff.ExtraBdevs, ctrlr.PciAddr)
+		}
+	}
+	for _, ns := range ssr.Namespaces {
+		if !cfgScm[ns.BlockDevice] {
+			diff.ExtraScm = append(diff.ExtraScm, ns.BlockDevice)
+		}
+	}
+
+	return diff, nil
 }
@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/common/proto"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	"github.com/daos-stack/daos/src/control/server/config"
@@ -27,21 +29,43 @@ import (
 const (
 	msgFormatErr      = "instance %d: failure formatting storage, check RPC response for details"
 	msgNvmeFormatSkip = "NVMe format skipped on instance %d as SCM format did not complete"
+	msgNvmeFormatted  = "NVMe format skipped on %s as it is already formatted, use force to reformat"
+	msgNvmeFormatBusy = "NVMe SSD %s is in use, stop any processes using it and retry"
+	msgHugePagesInUse = "%d hugepages still in use after reset, pages allocated by a previous prepare may have leaked"
 )
 
-// newResponseState creates, populates and returns ResponseState.
-func newResponseState(inErr error, badStatus ctlpb.ResponseStatus, infoMsg string) *ctlpb.ResponseState {
-	rs := new(ctlpb.ResponseState)
-	rs.Info = infoMsg
+// newSuccessState returns a ResponseState indicating success.
+func newSuccessState() *ctlpb.ResponseState {
+	return &ctlpb.ResponseState{
+		Status: ctlpb.ResponseStatus_CTL_SUCCESS,
+	}
+}
 
+// newErrorState returns a ResponseState populated with the given error
+// status and message.
+func newErrorState(inErr error, badStatus ctlpb.ResponseStatus) *ctlpb.ResponseState {
+	rs := &ctlpb.ResponseState{
+		Status: badStatus,
+		Error:  "unknown error",
+	}
 	if inErr != nil {
-		rs.Status = badStatus
 		rs.Error = inErr.Error()
 	}
 
 	return rs
 }
 
+// newResponseState creates, populates and returns ResponseState.
+func newResponseState(inErr error, badStatus ctlpb.ResponseStatus, infoMsg string) *ctlpb.ResponseState {
+	rs := newSuccessState()
+	if inErr != nil {
+		rs = newErrorState(inErr, badStatus)
+	}
+	rs.Info = infoMsg
+
+	return rs
+}
+
 // TODO: de-duplicate logic to populate prepare request from server config after
 //       DAOS-7002 is completed
 func updateNvmePrepareReq(req *bdev.PrepareRequest, cfg *config.Server) {
@@ -67,7 +91,7 @@ func updateNvmePrepareReq(req *bdev.PrepareRequest, cfg *config.Server) {
 }
 
 // doNvmePrepare issues prepare request and returns response.
-func (c *ControlService) doNvmePrepare(pbReq *ctlpb.PrepareNvmeReq) *ctlpb.PrepareNvmeResp {
+func (c *ControlService) doNvmePrepare(ctx context.Context, pbReq *ctlpb.PrepareNvmeReq) *ctlpb.PrepareNvmeResp {
 	c.log.Debugf("performing nvme prep %v", pbReq)
 	pnr := new(ctlpb.PrepareNvmeResp)
 
@@ -83,8 +107,33 @@ func (c *ControlService) doNvmePrepare(pbReq *ctlpb.PrepareNvmeReq) *ctlpb.Prepa
 		updateNvmePrepareReq(&req, c.srvCfg)
 	}
 
-	_, err := c.NvmePrepare(req)
-	pnr.State = newResponseState(err, ctlpb.ResponseStatus_CTL_ERR_NVME, "")
+	resp, err := c.NvmePrepareContext(ctx, req)
+	var info string
+	if err == nil && resp.HugePagesInUse > 0 {
+		info = fmt.Sprintf(msgHugePagesInUse, resp.HugePagesInUse)
+	}
+	pnr.State = newResponseState(err, ctlpb.ResponseStatus_CTL_ERR_NVME, info)
+	if err != nil || len(resp.DeviceResets) == 0 {
+		return pnr
+	}
+
+	// report per-device results of a targeted reset
+	addrs := make([]string, 0, len(resp.DeviceResets))
+	for addr := range resp.DeviceResets {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		var resetErr error
+		if dr := resp.DeviceResets[addr]; dr.Error != nil {
+			resetErr = dr.Error
+		}
+		pnr.Resets = append(pnr.Resets, newNvmeCtrlrResult(addr, resetErr))
+	}
+	if proto.NvmeControllerResults(pnr.Resets).HasErrors() {
+		pnr.State = newErrorState(errors.New("reset failed on one or more devices"),
+			ctlpb.ResponseStatus_CTL_ERR_NVME)
+	}
 
 	return pnr
 }
@@ -92,10 +141,10 @@ func (c *ControlService) doNvmePrepare(pbReq *ctlpb.PrepareNvmeReq) *ctlpb.Prepa
 // newPrepareScmResp sets protobuf SCM prepare response with results.
 func newPrepareScmResp(inResp *scm.PrepareResponse, inErr error) (*ctlpb.PrepareScmResp, error) {
 	outResp := new(ctlpb.PrepareScmResp)
-	outResp.State = new(ctlpb.ResponseState)
+	outResp.State = newSuccessState()
 
 	if inErr != nil {
-		outResp.State = newResponseState(inErr, ctlpb.ResponseStatus_CTL_ERR_SCM, "")
+		outResp.State = newErrorState(inErr, ctlpb.ResponseStatus_CTL_ERR_SCM)
 		return outResp, nil
 	}
 
@@ -112,16 +161,16 @@ func newPrepareScmResp(inResp *scm.PrepareResponse, inErr error) (*ctlpb.Prepare
 	return outResp, nil
 }
 
-func (c *ControlService) doScmPrepare(req *ctlpb.PrepareScmReq) (*ctlpb.PrepareScmResp, error) {
+func (c *ControlService) doScmPrepare(ctx context.Context, req *ctlpb.PrepareScmReq) (*ctlpb.PrepareScmResp, error) {
 	c.log.Debugf("performing scm prep %v", req)
 
-	scmState, err := c.GetScmState()
+	scmState, scmCause, err := c.GetScmState()
 	if err != nil {
 		return newPrepareScmResp(nil, err)
 	}
-	c.log.Debugf("SCM state before prep: %s", scmState)
+	c.log.Debugf("SCM state before prep: %s (%s)", scmState, scmCause)
 
-	resp, err := c.ScmPrepare(scm.PrepareRequest{Reset: req.Reset_})
+	resp, err := c.ScmPrepareContext(ctx, scm.PrepareRequest{Reset: req.Reset_})
 
 	return newPrepareScmResp(resp, err)
 }
@@ -141,10 +190,10 @@ func (c *ControlService) StoragePrepare(ctx context.Context, req *ctlpb.StorageP
 	}
 
 	if req.Nvme != nil {
-		resp.Nvme = c.doNvmePrepare(req.Nvme)
+		resp.Nvme = c.doNvmePrepare(ctx, req.Nvme)
 	}
 	if req.Scm != nil {
-		respScm, err := c.doScmPrepare(req.Scm)
+		respScm, err := c.doScmPrepare(ctx, req.Scm)
 		if err != nil {
 			return nil, err
 		}
@@ -174,59 +223,81 @@ func mapCtrlrs(ctrlrs storage.NvmeControllers) (map[string]*storage.NvmeControll
 // health statistics and stored server meta-data. If I/O Engines are running
 // then query is issued over dRPC as go-spdk bindings cannot be used to access
 // controller claimed by another process. Only update info for controllers
-// assigned to I/O Engines.
-func (c *ControlService) scanInstanceBdevs(ctx context.Context) (*bdev.ScanResponse, error) {
+// assigned to I/O Engines. Results from a recent read are returned unless
+// force is set, see healthThrottle.
+func (c *ControlService) scanInstanceBdevs(ctx context.Context, force bool) (*bdev.ScanResponse, error) {
 	var ctrlrs storage.NvmeControllers
 	instances := c.harness.Instances()
+	storageCfgs := c.instanceStorageCfgs()
 
 	for _, srv := range instances {
-		nvmeDevs := c.instanceStorage[srv.Index()].Bdev.GetNvmeDevs()
+		nvmeDevs := storageCfgs[srv.Index()].Bdev.GetNvmeDevs()
 		if len(nvmeDevs) == 0 {
 			continue
 		}
 
-		// only retrieve results for devices listed in server config
-		bdevReq := bdev.ScanRequest{DeviceList: nvmeDevs}
+		read := func() (storage.NvmeControllers, error) {
+			return c.scanInstanceHealth(ctx, srv, nvmeDevs)
+		}
 
-		c.log.Debugf("instance %d storage scan: only show bdev devices in config %v",
-			srv.Index(), bdevReq.DeviceList)
+		var instCtrlrs storage.NvmeControllers
+		var err error
+		if c.healthThrottle != nil {
+			instCtrlrs, err = c.healthThrottle.get(srv.Index(), force, read)
+		} else {
+			instCtrlrs, err = read()
+		}
+		if err != nil {
+			return nil, err
+		}
 
-		// scan through control-plane to get up-to-date stats if io
-		// server is not active (and therefore has not claimed the
-		// assigned devices), bypass cache to get fresh health stats
-		if !srv.isReady() {
-			bdevReq.NoCache = true
+		ctrlrs = ctrlrs.Update(instCtrlrs...)
+	}
 
-			bsr, err := c.NvmeScan(bdevReq)
-			if err != nil {
-				return nil, errors.Wrap(err, "nvme scan")
-			}
+	return &bdev.ScanResponse{Controllers: ctrlrs}, nil
+}
 
-			ctrlrs = ctrlrs.Update(bsr.Controllers...)
-			continue
-		}
+// scanInstanceHealth reads the details of the given NVMe devices assigned to
+// an I/O Engine including current health statistics.
+func (c *ControlService) scanInstanceHealth(ctx context.Context, srv *EngineInstance, nvmeDevs []string) (storage.NvmeControllers, error) {
+	// only retrieve results for devices listed in server config
+	bdevReq := bdev.ScanRequest{DeviceList: nvmeDevs}
+
+	c.log.Debugf("instance %d storage scan: only show bdev devices in config %v",
+		srv.Index(), bdevReq.DeviceList)
+
+	// scan through control-plane to get up-to-date stats if io
+	// server is not active (and therefore has not claimed the
+	// assigned devices), bypass cache to get fresh health stats
+	if !srv.isReady() {
+		bdevReq.NoCache = true
 
 		bsr, err := c.NvmeScan(bdevReq)
 		if err != nil {
 			return nil, errors.Wrap(err, "nvme scan")
 		}
 
-		ctrlrMap, err := mapCtrlrs(bsr.Controllers)
-		if err != nil {
-			return nil, errors.Wrap(err, "create controller map")
-		}
+		return bsr.Controllers, nil
+	}
 
-		// if io servers are active and have claimed the assigned devices,
-		// query over drpc to update controller details with current health
-		// stats and smd info
-		if err := srv.updateInUseBdevs(ctx, ctrlrMap); err != nil {
-			return nil, errors.Wrap(err, "updating bdev health and smd info")
-		}
+	bsr, err := c.NvmeScan(bdevReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "nvme scan")
+	}
+
+	ctrlrMap, err := mapCtrlrs(bsr.Controllers)
+	if err != nil {
+		return nil, errors.Wrap(err, "create controller map")
+	}
 
-		ctrlrs = ctrlrs.Update(bsr.Controllers...)
+	// if io servers are active and have claimed the assigned devices,
+	// query over drpc to update controller details with current health
+	// stats and smd info
+	if err := srv.updateInUseBdevs(ctx, ctrlrMap); err != nil {
+		return nil, errors.Wrap(err, "updating bdev health and smd info")
 	}
 
-	return &bdev.ScanResponse{Controllers: ctrlrs}, nil
+	return bsr.Controllers, nil
 }
 
 // stripNvmeDetails removes all controller details leaving only PCI address and
@@ -241,10 +312,10 @@ func stripNvmeDetails(pbc *ctlpb.NvmeController) {
 // including health statistics or metadata if requested.
 func newScanNvmeResp(req *ctlpb.ScanNvmeReq, inResp *bdev.ScanResponse, inErr error) (*ctlpb.ScanNvmeResp, error) {
 	outResp := new(ctlpb.ScanNvmeResp)
-	outResp.State = new(ctlpb.ResponseState)
+	outResp.State = newSuccessState()
 
 	if inErr != nil {
-		outResp.State = newResponseState(inErr, ctlpb.ResponseStatus_CTL_ERR_NVME, "")
+		outResp.State = newErrorState(inErr, ctlpb.ResponseStatus_CTL_ERR_NVME)
 		return outResp, nil
 	}
 
@@ -280,7 +351,7 @@ func (c *ControlService) scanBdevs(ctx context.Context, req *ctlpb.ScanNvmeReq)
 
 	if req.Health || req.Meta {
 		// filter results based on config file bdev_list contents
-		resp, err := c.scanInstanceBdevs(ctx)
+		resp, err := c.scanInstanceBdevs(ctx, req.GetForce())
 
 		return newScanNvmeResp(req, resp, err)
 	}
@@ -294,10 +365,10 @@ func (c *ControlService) scanBdevs(ctx context.Context, req *ctlpb.ScanNvmeReq)
 // newScanScmResp sets protobuf SCM scan response with module or namespace info.
 func newScanScmResp(inResp *scm.ScanResponse, inErr error) (*ctlpb.ScanScmResp, error) {
 	outResp := new(ctlpb.ScanScmResp)
-	outResp.State = new(ctlpb.ResponseState)
+	outResp.State = newSuccessState()
 
 	if inErr != nil {
-		outResp.State = newResponseState(inErr, ctlpb.ResponseStatus_CTL_ERR_SCM, "")
+		outResp.State = newErrorState(inErr, ctlpb.ResponseStatus_CTL_ERR_SCM)
 		return outResp, nil
 	}
 
@@ -333,49 +404,79 @@ func findPMemInScan(ssr *scm.ScanResponse, cfg *storage.ScmConfig) *storage.ScmN
 	return nil
 }
 
+// getScmMount returns the mount state of the SCM configured for the instance
+// along with usage statistics if it is mounted. Storage of a running instance
+// is always mounted.
+func getScmMount(srv *EngineInstance, mntPoint string) (*storage.ScmMountPoint, error) {
+	mounted := srv.isReady()
+	if !mounted {
+		var err error
+		if mounted, err = srv.scmProvider.IsMounted(mntPoint); err != nil {
+			return nil, err
+		}
+	}
+	if !mounted {
+		return &storage.ScmMountPoint{Path: mntPoint}, nil
+	}
+
+	mount, err := srv.scmProvider.GetfsUsage(mntPoint)
+	if err != nil {
+		return nil, err
+	}
+	mount.Mounted = true
+	if mount.AvailBytes <= mount.TotalBytes {
+		mount.UsedBytes = mount.TotalBytes - mount.AvailBytes
+	}
+
+	return mount, nil
+}
+
 // getScmUsage will retrieve usage statistics (how much space is available for
 // new DAOS pools) for either PMem namespaces or SCM emulation with ramdisk.
 //
-// Usage is only retrieved for active mountpoints being used by online DAOS I/O
-// Server instances.
+// A namespace is reported for the SCM of each I/O Engine instance along with
+// its mount state. Usage is only reported for namespaces that are mounted,
+// the capacity of unmounted namespaces is still given by their size. Stopped
+// instances whose PMem namespace hasn't been created yet are skipped.
 func (c *ControlService) getScmUsage(ssr *scm.ScanResponse) (*scm.ScanResponse, error) {
 	instances := c.harness.Instances()
 
-	nss := make(storage.ScmNamespaces, len(instances))
-	for idx, srv := range instances {
-		if !srv.isReady() {
-			continue // skip if not running
-		}
-
+	nss := make(storage.ScmNamespaces, 0, len(instances))
+	for _, srv := range instances {
 		cfg := srv.scmConfig()
 
-		mount, err := srv.scmProvider.GetfsUsage(cfg.MountPoint)
-		if err != nil {
-			return nil, err
-		}
-
+		var ns *storage.ScmNamespace
 		switch cfg.Class {
 		case storage.ScmClassRAM: // generate fake namespace for emulated ramdisk mounts
-			nss[idx] = &storage.ScmNamespace{
-				Mount:       mount,
+			ns = &storage.ScmNamespace{
 				BlockDevice: "ramdisk",
 				Size:        uint64(humanize.GiByte * cfg.RamdiskSize),
 			}
-		case storage.ScmClassDCPM: // update namespace mount info for online storage
-			ns := findPMemInScan(ssr, &cfg)
+		case storage.ScmClassDCPM: // update namespace mount info for configured storage
+			ns = findPMemInScan(ssr, &cfg)
+			if ns == nil && !srv.isReady() {
+				c.log.Debugf("instance %d: skipping usage, no pmem namespace for mount %s",
+					srv.Index(), cfg.MountPoint)
+				continue
+			}
 			if ns == nil {
 				return nil, errors.Errorf("instance %d: no pmem namespace for mount %s",
 					srv.Index(), cfg.MountPoint)
 			}
-			ns.Mount = mount
-			nss[idx] = ns
 		default:
 			return nil, errors.Errorf("instance %d: unsupported scm class %q",
 				srv.Index(), cfg.Class)
 		}
 
+		mount, err := getScmMount(srv, cfg.MountPoint)
+		if err != nil {
+			return nil, err
+		}
+		ns.Mount = mount
+		nss = append(nss, ns)
+
 		c.log.Debugf("updated scm fs usage on device %s mounted at %s: %+v",
-			nss[idx].BlockDevice, cfg.MountPoint, nss[idx].Mount)
+			ns.BlockDevice, cfg.MountPoint, ns.Mount)
 	}
 
 	return &scm.ScanResponse{Namespaces: nss}, nil
@@ -424,6 +525,108 @@ func (c *ControlService) StorageScan(ctx context.Context, req *ctlpb.StorageScan
 	return resp, nil
 }
 
+// checkNvmeFormatTargets verifies that the requested controllers exist and are
+// assigned to an engine. A VMD address targets the devices behind it, which are
+// the addresses listed in the engine configs once VMD addresses have been
+// substituted by Setup. Controllers that can be formatted are returned along
+// with error results for those that can't.
+func (c *ControlService) checkNvmeFormatTargets(pciAddrs []string) ([]string, proto.NvmeControllerResults, error) {
+	if len(pciAddrs) == 0 {
+		return nil, nil, nil
+	}
+
+	resp, err := c.NvmeScan(bdev.ScanRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cfgBdevs []string
+	for _, storageCfg := range c.instanceStorageCfgs() {
+		cfgBdevs = append(cfgBdevs, storageCfg.Bdev.DeviceList...)
+	}
+
+	var valid []string
+	var results proto.NvmeControllerResults
+	for _, addr := range pciAddrs {
+		devs := []string{addr}
+		if !c.bdev.IsVMDDisabled() {
+			backing, err := substBdevVmdAddrs(devs, resp)
+			if err != nil {
+				results = append(results, newNvmeFormatResult(addr, err))
+				continue
+			}
+			if len(backing) != 0 {
+				devs = backing
+			}
+		}
+
+		for _, dev := range devs {
+			if _, ok := canAccessBdevs([]string{dev}, resp); !ok {
+				results = append(results, newNvmeFormatResult(dev,
+					FaultBdevNotFound([]string{dev})))
+				continue
+			}
+			if !common.Includes(cfgBdevs, dev) {
+				results = append(results, newNvmeFormatResult(dev,
+					errors.Errorf("NVMe SSD %s is not assigned to an engine", dev)))
+				continue
+			}
+			if !common.Includes(valid, dev) {
+				valid = append(valid, dev)
+			}
+		}
+	}
+
+	return valid, results, nil
+}
+
+// formatNvmeTargets formats only the requested NVMe controllers. SCM and the
+// superblocks of the instances the controllers are assigned to are left as
+// they are, so reformat can't be requested and the instances must be stopped.
+// Controllers that already hold a blobstore are skipped unless force is set.
+func (c *ControlService) formatNvmeTargets(req *ctlpb.StorageFormatReq) (*ctlpb.StorageFormatResp, error) {
+	if req.GetReformat() {
+		return nil, errors.New("reformat can't be requested when formatting specific NVMe SSDs")
+	}
+
+	pciAddrs, badResults, err := c.checkNvmeFormatTargets(req.GetNvme().GetPciAddrs())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ctlpb.StorageFormatResp{
+		Mrets: []*ctlpb.ScmMountResult{},
+		Crets: badResults,
+	}
+
+	storageCfgs := c.instanceStorageCfgs()
+	for _, srv := range c.harness.Instances() {
+		var devs []string
+		for _, dev := range storageCfgs[srv.Index()].Bdev.DeviceList {
+			if common.Includes(pciAddrs, dev) {
+				devs = append(devs, dev)
+			}
+		}
+		if len(devs) == 0 {
+			continue
+		}
+
+		if srv.isStarted() {
+			err := errors.Errorf("instance %d: can't format storage of running instance",
+				srv.Index())
+			for _, dev := range devs {
+				resp.Crets = append(resp.Crets, newNvmeFormatResult(dev, err))
+			}
+			continue
+		}
+
+		resp.Crets = append(resp.Crets,
+			srv.bdevFormat(c.bdev, nil, !req.GetNvme().GetForce(), devs...)...)
+	}
+
+	return resp, nil
+}
+
 // StorageFormat delegates to Storage implementation's Format methods to prepare
 // storage for use by DAOS data plane.
 //
@@ -433,14 +636,18 @@ func (c *ControlService) StorageScan(ctx context.Context, req *ctlpb.StorageScan
 // Send response containing multiple results of format operations on scm mounts
 // and nvme controllers.
 func (c *ControlService) StorageFormat(ctx context.Context, req *ctlpb.StorageFormatReq) (*ctlpb.StorageFormatResp, error) {
+	c.log.Debugf("received StorageFormat RPC %v", req)
+
+	if len(req.GetNvme().GetPciAddrs()) != 0 {
+		return c.formatNvmeTargets(req)
+	}
+
 	instances := c.harness.Instances()
 	resp := new(ctlpb.StorageFormatResp)
 	resp.Mrets = make([]*ctlpb.ScmMountResult, 0, len(instances))
 	resp.Crets = make([]*ctlpb.NvmeControllerResult, 0, len(instances))
 	scmChan := make(chan *ctlpb.ScmMountResult, len(instances))
 
-	c.log.Debugf("received StorageFormat RPC %v", req)
-
 	// TODO: enable per-instance formatting
 	formatting := 0
 	for _, srv := range instances {
@@ -479,8 +686,12 @@ func (c *ControlService) StorageFormat(ctx context.Context, req *ctlpb.StorageFo
 			}
 			continue
 		}
-		// SCM formatted correctly on this instance, format NVMe
-		cResults := srv.StorageFormatNVMe(c.bdev)
+		// SCM formatted correctly on this instance, format NVMe and
+		// report progress as each controller completes
+		cResults := srv.StorageFormatNVMeProgress(c.bdev, func(cr *ctlpb.NvmeControllerResult) {
+			c.log.Infof("instance %d: format of NVMe SSD %s: %s", srv.Index(),
+				cr.GetPciAddr(), cr.GetState().GetStatus())
+		})
 		if cResults.HasErrors() {
 			instanceErrored[srv.Index()] = true
 		}
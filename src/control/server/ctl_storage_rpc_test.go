@@ -410,6 +410,15 @@ func TestServer_CtlSvc_StorageScan_PostIOStart(t *testing.T) {
 		_, s := newCtrlrMeta(idx, smdIndexes...)
 		return s
 	}
+	// expected protobuf output when blobstore usage couldn't be retrieved
+	newCtrlrPBwUnknownUsage := func(idx int32, smdIndexes ...int32) *ctlpb.NvmeController {
+		c, _ := newCtrlrMeta(idx, smdIndexes...)
+		for _, sd := range c.SmdDevices {
+			sd.TotalBytes = 0
+			sd.AvailBytes = 0
+		}
+		return c
+	}
 
 	mockPbScmMount := proto.MockScmMountPoint()
 	mockPbScmNamespace := proto.MockScmNamespace()
@@ -475,6 +484,32 @@ func TestServer_CtlSvc_StorageScan_PostIOStart(t *testing.T) {
 				Scm: &ctlpb.ScanScmResp{State: new(ctlpb.ResponseState)},
 			},
 		},
+		"scan bdev meta with usage unavailable": {
+			req: &ctlpb.StorageScanReq{
+				Scm:  new(ctlpb.ScanScmReq),
+				Nvme: &ctlpb.ScanNvmeReq{Meta: true},
+			},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{newCtrlr(1)},
+				},
+			},
+			drpcResps: map[int][]*mockDrpcResponse{
+				0: {
+					{Message: newSmdDevResp(1)},
+					{Message: &ctlpb.BioHealthResp{
+						Status: int32(drpc.DaosNonexistant),
+					}},
+				},
+			},
+			expResp: ctlpb.StorageScanResp{
+				Nvme: &ctlpb.ScanNvmeResp{
+					Ctrlrs: proto.NvmeControllers{newCtrlrPBwUnknownUsage(1)},
+					State:  new(ctlpb.ResponseState),
+				},
+				Scm: &ctlpb.ScanScmResp{State: new(ctlpb.ResponseState)},
+			},
+		},
 		"scan bdev health with multiple io servers up": {
 			req: &ctlpb.StorageScanReq{
 				Scm:  new(ctlpb.ScanScmReq),
@@ -745,6 +780,8 @@ func TestServer_CtlSvc_StorageScan_PostIOStart(t *testing.T) {
 								Path:       mockPbScmMount.Path,
 								TotalBytes: mockPbScmMount.TotalBytes,
 								AvailBytes: mockPbScmMount.AvailBytes,
+								UsedBytes:  mockPbScmMount.UsedBytes,
+								Mounted:    true,
 							},
 						},
 					},
@@ -824,10 +861,225 @@ func TestServer_CtlSvc_StorageScan_PostIOStart(t *testing.T) {
 	}
 }
 
+func TestServer_CtlSvc_getScmUsage(t *testing.T) {
+	mockMount := storage.MockScmMountPoint()
+
+	for name, tc := range map[string]struct {
+		ready      []bool
+		noPmem1    bool // second instance has no pmem namespace
+		smsc       *scm.MockSysConfig
+		expMounted []bool
+		expErr     error
+	}{
+		"both running": {
+			ready:      []bool{true, true},
+			expMounted: []bool{true, true},
+		},
+		"second stopped and unmounted": {
+			ready:      []bool{true, false},
+			expMounted: []bool{true, false},
+		},
+		"second stopped but mounted": {
+			ready: []bool{true, false},
+			smsc: &scm.MockSysConfig{
+				IsMountedBool: true,
+			},
+			expMounted: []bool{true, true},
+		},
+		"both stopped and unmounted": {
+			ready:      []bool{false, false},
+			expMounted: []bool{false, false},
+		},
+		"second stopped without namespace": {
+			ready:      []bool{true, false},
+			noPmem1:    true,
+			expMounted: []bool{true},
+		},
+		"second running without namespace": {
+			ready:   []bool{true, true},
+			noPmem1: true,
+			expErr:  errors.New("instance 1: no pmem namespace"),
+		},
+		"mount check fails": {
+			ready: []bool{false, false},
+			smsc: &scm.MockSysConfig{
+				IsMountedErr: errors.New("bad mount check"),
+			},
+			expErr: errors.New("bad mount check"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			if tc.smsc == nil {
+				tc.smsc = new(scm.MockSysConfig)
+			}
+			tc.smsc.GetfsUsageTotal = mockMount.TotalBytes
+			tc.smsc.GetfsUsageAvail = mockMount.AvailBytes
+
+			var engineCfgs []*engine.Config
+			for i := 0; i < 2; i++ {
+				engineCfgs = append(engineCfgs, engine.NewConfig().
+					WithScmMountPoint(fmt.Sprintf("/mnt/daos%d", i)).
+					WithScmClass(storage.ScmClassDCPM.String()).
+					WithScmDeviceList(fmt.Sprintf("/dev/pmem%d", i)))
+			}
+			cfg := config.DefaultServer().WithEngines(engineCfgs...)
+
+			cs := mockControlService(t, log, cfg, nil, nil, tc.smsc)
+			for i, srv := range cs.harness.instances {
+				srv.ready.Store(tc.ready[i])
+			}
+
+			ssr := &scm.ScanResponse{
+				Namespaces: storage.ScmNamespaces{
+					storage.MockScmNamespace(0), storage.MockScmNamespace(1),
+				},
+			}
+			if tc.noPmem1 {
+				ssr.Namespaces = ssr.Namespaces[:1]
+			}
+
+			resp, err := cs.getScmUsage(ssr)
+			common.CmpErr(t, tc.expErr, err)
+			if err != nil {
+				return
+			}
+
+			var expNss storage.ScmNamespaces
+			for i, mounted := range tc.expMounted {
+				ns := storage.MockScmNamespace(int32(i))
+				// capacity of unmounted namespaces is reported
+				// without usage
+				ns.Mount = &storage.ScmMountPoint{
+					Path: fmt.Sprintf("/mnt/daos%d", i),
+				}
+				if mounted {
+					ns.Mount = storage.MockScmMountPoint()
+					ns.Mount.Path = fmt.Sprintf("/mnt/daos%d", i)
+				}
+				expNss = append(expNss, ns)
+			}
+
+			if diff := cmp.Diff(expNss, resp.Namespaces); diff != "" {
+				t.Fatalf("unexpected namespaces (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_responseStates(t *testing.T) {
+	testErr := errors.New("failed")
+
+	for name, tc := range map[string]struct {
+		getState  func(t *testing.T) *ctlpb.ResponseState
+		expStatus ctlpb.ResponseStatus
+		expErrMsg string
+	}{
+		"prepare nvme success": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				return newResponseState(nil, ctlpb.ResponseStatus_CTL_ERR_NVME, "")
+			},
+		},
+		"prepare nvme failure": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				return newResponseState(testErr, ctlpb.ResponseStatus_CTL_ERR_NVME, "")
+			},
+			expStatus: ctlpb.ResponseStatus_CTL_ERR_NVME,
+			expErrMsg: testErr.Error(),
+		},
+		"scan nvme success": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newScanNvmeResp(&ctlpb.ScanNvmeReq{},
+					&bdev.ScanResponse{}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+		},
+		"scan nvme failure": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newScanNvmeResp(&ctlpb.ScanNvmeReq{}, nil, testErr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+			expStatus: ctlpb.ResponseStatus_CTL_ERR_NVME,
+			expErrMsg: testErr.Error(),
+		},
+		"nvme controller result success": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				return new(EngineInstance).newCret("0000:80:00.0", nil).State
+			},
+		},
+		"nvme controller result failure": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				return new(EngineInstance).newCret("0000:80:00.0", testErr).State
+			},
+			expStatus: ctlpb.ResponseStatus_CTL_ERR_NVME,
+			expErrMsg: testErr.Error(),
+		},
+		"prepare scm success": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newPrepareScmResp(&scm.PrepareResponse{}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+		},
+		"prepare scm failure": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newPrepareScmResp(nil, testErr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+			expStatus: ctlpb.ResponseStatus_CTL_ERR_SCM,
+			expErrMsg: testErr.Error(),
+		},
+		"scan scm success": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newScanScmResp(&scm.ScanResponse{}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+		},
+		"scan scm failure": {
+			getState: func(t *testing.T) *ctlpb.ResponseState {
+				resp, err := newScanScmResp(nil, testErr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp.State
+			},
+			expStatus: ctlpb.ResponseStatus_CTL_ERR_SCM,
+			expErrMsg: testErr.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := tc.getState(t)
+			if state == nil {
+				t.Fatal("expected non-nil response state")
+			}
+
+			common.AssertEqual(t, tc.expStatus, state.Status, "unexpected status")
+			common.AssertEqual(t, tc.expErrMsg, state.Error, "unexpected error message")
+		})
+	}
+}
+
 func TestServer_CtlSvc_StoragePrepare(t *testing.T) {
 	for name, tc := range map[string]struct {
 		bmbc    *bdev.MockBackendConfig
 		smbc    *scm.MockBackendConfig
+		hpi     *hugePageInfo
 		req     ctlpb.StoragePrepareReq
 		expResp *ctlpb.StoragePrepareResp
 	}{
@@ -930,6 +1182,82 @@ func TestServer_CtlSvc_StoragePrepare(t *testing.T) {
 				},
 			},
 		},
+		"nvme reset": {
+			req: ctlpb.StoragePrepareReq{
+				Nvme: &ctlpb.PrepareNvmeReq{Reset_: true},
+			},
+			expResp: &ctlpb.StoragePrepareResp{
+				Nvme: &ctlpb.PrepareNvmeResp{State: new(ctlpb.ResponseState)},
+			},
+		},
+		"nvme reset with leaked hugepages": {
+			hpi: &hugePageInfo{
+				Total:      1024,
+				Free:       1000,
+				PageSizeKb: 2048,
+			},
+			req: ctlpb.StoragePrepareReq{
+				Nvme: &ctlpb.PrepareNvmeReq{Reset_: true},
+			},
+			expResp: &ctlpb.StoragePrepareResp{
+				Nvme: &ctlpb.PrepareNvmeResp{
+					State: &ctlpb.ResponseState{
+						Info: fmt.Sprintf(msgHugePagesInUse, 24),
+					},
+				},
+			},
+		},
+		"nvme targeted reset": {
+			bmbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			req: ctlpb.StoragePrepareReq{
+				Nvme: &ctlpb.PrepareNvmeReq{
+					Reset_:       true,
+					PciAllowList: "0000:81:00.0 0000:80:00.0",
+				},
+			},
+			expResp: &ctlpb.StoragePrepareResp{
+				Nvme: &ctlpb.PrepareNvmeResp{
+					State: new(ctlpb.ResponseState),
+					Resets: []*ctlpb.NvmeControllerResult{
+						{PciAddr: "0000:80:00.0", State: new(ctlpb.ResponseState)},
+						{PciAddr: "0000:81:00.0", State: new(ctlpb.ResponseState)},
+					},
+				},
+			},
+		},
+		"nvme targeted reset; invalid address": {
+			bmbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			req: ctlpb.StoragePrepareReq{
+				Nvme: &ctlpb.PrepareNvmeReq{
+					Reset_:       true,
+					PciAllowList: "0000:80:00.0 0000:8z:00.0",
+				},
+			},
+			expResp: &ctlpb.StoragePrepareResp{
+				Nvme: &ctlpb.PrepareNvmeResp{
+					State: &ctlpb.ResponseState{
+						Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+						Error:  "reset failed on one or more devices",
+					},
+					Resets: []*ctlpb.NvmeControllerResult{
+						{PciAddr: "0000:80:00.0", State: new(ctlpb.ResponseState)},
+						{
+							PciAddr: "0000:8z:00.0",
+							State: &ctlpb.ResponseState{
+								Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+								Error:  bdev.FaultBadPCIAddr("0000:8z:00.0").Error(),
+								Info: fault.ShowResolutionFor(
+									bdev.FaultBadPCIAddr("0000:8z:00.0")),
+							},
+						},
+					},
+				},
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(t.Name())
@@ -937,6 +1265,11 @@ func TestServer_CtlSvc_StoragePrepare(t *testing.T) {
 
 			config := config.DefaultServer()
 			cs := mockControlService(t, log, config, tc.bmbc, tc.smbc, nil)
+			if tc.hpi != nil {
+				cs.getHugePageInfo = func() (*hugePageInfo, error) {
+					return tc.hpi, nil
+				}
+			}
 			_ = new(ctlpb.StoragePrepareResp)
 
 			// runs discovery for nvme & scm
@@ -958,8 +1291,12 @@ func TestServer_CtlSvc_StoragePrepare(t *testing.T) {
 }
 
 func TestServer_CtlSvc_StorageFormat(t *testing.T) {
+	// no blobstores exist on unformatted controllers
 	mockNvmeController0 := storage.MockNvmeController(0)
+	mockNvmeController0.SmdDevices = nil
 	mockNvmeController1 := storage.MockNvmeController(1)
+	mockNvmeController1.SmdDevices = nil
+	formattedNvmeController := storage.MockNvmeController(2)
 
 	for name, tc := range map[string]struct {
 		scmMounted       bool // if scmMounted we emulate ext4 fs is mounted
@@ -978,10 +1315,13 @@ func TestServer_CtlSvc_StorageFormat(t *testing.T) {
 		bDevs            [][]string
 		bSize            int
 		bmbc             *bdev.MockBackendConfig
+		nvmeDevs         []string // controllers to target in format request
+		forceNvme        bool     // reformat targeted controllers with a blobstore
 		awaitTimeout     time.Duration
 		expAwaitExit     bool
 		expAwaitErr      error
 		expResp          *ctlpb.StorageFormatResp
+		expErr           error
 		isRoot           bool
 		reformat         bool // indicates setting of reformat parameter
 	}{
@@ -1366,6 +1706,296 @@ func TestServer_CtlSvc_StorageFormat(t *testing.T) {
 				},
 			},
 		},
+		"nvme with existing blobstore": {
+			sMounts: []string{"/mnt/daos"},
+			sClass:  storage.ScmClassDCPM,
+			sDevs:   []string{"/dev/pmem0"},
+			bClass:  storage.BdevClassNvme,
+			bDevs:   [][]string{{formattedNvmeController.PciAddr}},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{formattedNvmeController},
+				},
+				// full format never skips devices holding a blobstore
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						formattedNvmeController.PciAddr: &bdev.DeviceFormatResponse{
+							AlreadyFormatted: true,
+						},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: formattedNvmeController.PciAddr,
+						State:   new(ctlpb.ResponseState),
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{
+					{
+						Mntpoint: "/mnt/daos",
+						State:    new(ctlpb.ResponseState),
+					},
+				},
+			},
+		},
+		"targeted nvme format with existing blobstore": {
+			sMounts:  []string{"/mnt/daos"},
+			sClass:   storage.ScmClassDCPM,
+			sDevs:    []string{"/dev/pmem0"},
+			bClass:   storage.BdevClassNvme,
+			bDevs:    [][]string{{formattedNvmeController.PciAddr}},
+			nvmeDevs: []string{formattedNvmeController.PciAddr},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{formattedNvmeController},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						formattedNvmeController.PciAddr: &bdev.DeviceFormatResponse{
+							AlreadyFormatted: true,
+						},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: formattedNvmeController.PciAddr,
+						State: &ctlpb.ResponseState{
+							Status: ctlpb.ResponseStatus_CTL_SUCCESS,
+							Info: fmt.Sprintf(msgNvmeFormatted,
+								formattedNvmeController.PciAddr),
+						},
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format with existing blobstore; force": {
+			sMounts:   []string{"/mnt/daos"},
+			sClass:    storage.ScmClassDCPM,
+			sDevs:     []string{"/dev/pmem0"},
+			bClass:    storage.BdevClassNvme,
+			bDevs:     [][]string{{formattedNvmeController.PciAddr}},
+			nvmeDevs:  []string{formattedNvmeController.PciAddr},
+			forceNvme: true,
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{formattedNvmeController},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						formattedNvmeController.PciAddr: &bdev.DeviceFormatResponse{
+							AlreadyFormatted: true,
+						},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: formattedNvmeController.PciAddr,
+						State:   new(ctlpb.ResponseState),
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format with missing device multi-io": {
+			sMounts: []string{"/mnt/daos0", "/mnt/daos1"},
+			sClass:  storage.ScmClassDCPM,
+			sDevs:   []string{"/dev/pmem0", "/dev/pmem1"},
+			bClass:  storage.BdevClassNvme,
+			bDevs: [][]string{
+				{mockNvmeController0.PciAddr},
+				{mockNvmeController1.PciAddr},
+			},
+			nvmeDevs: []string{mockNvmeController1.PciAddr, "0000:90:00.0"},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{mockNvmeController0, mockNvmeController1},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						mockNvmeController1.PciAddr: &bdev.DeviceFormatResponse{
+							Formatted: true,
+						},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: mockNvmeController1.PciAddr,
+						State:   new(ctlpb.ResponseState),
+					},
+					{
+						PciAddr: "0000:90:00.0",
+						State: &ctlpb.ResponseState{
+							Status: ctlpb.ResponseStatus_CTL_ERR_NOT_FOUND,
+							Error:  FaultBdevNotFound([]string{"0000:90:00.0"}).Error(),
+							Info: fault.ShowResolutionFor(
+								FaultBdevNotFound([]string{"0000:90:00.0"})),
+						},
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format with unassigned device": {
+			sMounts:  []string{"/mnt/daos"},
+			sClass:   storage.ScmClassDCPM,
+			sDevs:    []string{"/dev/pmem0"},
+			bClass:   storage.BdevClassNvme,
+			bDevs:    [][]string{{mockNvmeController0.PciAddr}},
+			nvmeDevs: []string{mockNvmeController0.PciAddr, mockNvmeController1.PciAddr},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{mockNvmeController0, mockNvmeController1},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						mockNvmeController0.PciAddr: &bdev.DeviceFormatResponse{
+							Formatted: true,
+						},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: mockNvmeController0.PciAddr,
+						State:   new(ctlpb.ResponseState),
+					},
+					{
+						PciAddr: mockNvmeController1.PciAddr,
+						State: &ctlpb.ResponseState{
+							Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+							Error: fmt.Sprintf("NVMe SSD %s is not assigned to an engine",
+								mockNvmeController1.PciAddr),
+						},
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format with reformat": {
+			sMounts:  []string{"/mnt/daos"},
+			sClass:   storage.ScmClassDCPM,
+			sDevs:    []string{"/dev/pmem0"},
+			bClass:   storage.BdevClassNvme,
+			bDevs:    [][]string{{mockNvmeController0.PciAddr}},
+			nvmeDevs: []string{mockNvmeController0.PciAddr},
+			reformat: true,
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{mockNvmeController0},
+				},
+				FormatErr: errors.New("unexpected format"),
+			},
+			expErr: errors.New("reformat can't be requested"),
+		},
+		"targeted nvme format leaves formatted scm": {
+			scmMounted:       true,
+			superblockExists: true,
+			sMounts:          []string{"/mnt/daos"},
+			sClass:           storage.ScmClassDCPM,
+			sDevs:            []string{"/dev/pmem0"},
+			bClass:           storage.BdevClassNvme,
+			bDevs:            [][]string{{mockNvmeController0.PciAddr}},
+			nvmeDevs:         []string{mockNvmeController0.PciAddr},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{mockNvmeController0},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						mockNvmeController0.PciAddr: &bdev.DeviceFormatResponse{
+							Formatted: true,
+						},
+					},
+				},
+			},
+			expAwaitExit: true,
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: mockNvmeController0.PciAddr,
+						State:   new(ctlpb.ResponseState),
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format on running instance": {
+			instancesStarted: true,
+			scmMounted:       true,
+			sMounts:          []string{"/mnt/daos"},
+			sClass:           storage.ScmClassRAM,
+			sSize:            6,
+			bClass:           storage.BdevClassNvme,
+			bDevs:            [][]string{{mockNvmeController0.PciAddr}},
+			nvmeDevs:         []string{mockNvmeController0.PciAddr},
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{mockNvmeController0},
+				},
+				FormatErr: errors.New("unexpected format"),
+			},
+			expAwaitExit: true,
+			expAwaitErr:  errors.New("can't wait for storage: instance 0 already started"),
+			awaitTimeout: time.Second,
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: mockNvmeController0.PciAddr,
+						State: &ctlpb.ResponseState{
+							Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+							Error:  "instance 0: can't format storage of running instance",
+						},
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
+		"targeted nvme format of vmd address": {
+			sMounts:  []string{"/mnt/daos"},
+			sClass:   storage.ScmClassDCPM,
+			sDevs:    []string{"/dev/pmem0"},
+			bClass:   storage.BdevClassNvme,
+			bDevs:    [][]string{{"0000:5d:05.5"}},
+			nvmeDevs: []string{"0000:5d:05.5"},
+			bmbc: &bdev.MockBackendConfig{
+				VmdEnabled: true,
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{
+						&storage.NvmeController{PciAddr: "5d0505:01:00.0"},
+						&storage.NvmeController{PciAddr: "5d0505:03:00.0"},
+					},
+				},
+				FormatRes: &bdev.FormatResponse{
+					DeviceResponses: bdev.DeviceFormatResponses{
+						"5d0505:01:00.0": &bdev.DeviceFormatResponse{Formatted: true},
+						"5d0505:03:00.0": &bdev.DeviceFormatResponse{Formatted: true},
+					},
+				},
+			},
+			expResp: &ctlpb.StorageFormatResp{
+				Crets: []*ctlpb.NvmeControllerResult{
+					{
+						PciAddr: "5d0505:01:00.0",
+						State:   new(ctlpb.ResponseState),
+					},
+					{
+						PciAddr: "5d0505:03:00.0",
+						State:   new(ctlpb.ResponseState),
+					},
+				},
+				Mrets: []*ctlpb.ScmMountResult{},
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(t.Name())
@@ -1375,15 +2005,21 @@ func TestServer_CtlSvc_StorageFormat(t *testing.T) {
 			defer cleanup()
 
 			if tc.expResp == nil {
-				t.Fatal("expResp test case parameter required")
+				if tc.expErr == nil {
+					t.Fatal("expResp test case parameter required")
+				}
+				tc.expResp = new(ctlpb.StorageFormatResp)
+			}
+			// SCM is left untouched when formatting specific NVMe SSDs
+			if len(tc.nvmeDevs) == 0 {
+				common.AssertEqual(t, len(tc.sMounts), len(tc.expResp.Mrets), name)
 			}
-			common.AssertEqual(t, len(tc.sMounts), len(tc.expResp.Mrets), name)
 			for i := range tc.sMounts {
 				// Hack to deal with creating the mountpoint in test.
 				// FIXME (DAOS-3471): The tests in this layer really shouldn't be
 				// reaching down far enough to actually interact with the filesystem.
 				tc.sMounts[i] = filepath.Join(testDir, tc.sMounts[i])
-				if len(tc.expResp.Mrets) > 0 {
+				if i < len(tc.expResp.Mrets) {
 					mp := &(tc.expResp.Mrets[i].Mntpoint)
 					if *mp != "" {
 						if strings.HasSuffix(tc.sMounts[i], *mp) {
@@ -1530,9 +2166,16 @@ func TestServer_CtlSvc_StorageFormat(t *testing.T) {
 				}
 			}
 
-			resp, fmtErr := cs.StorageFormat(context.TODO(), &ctlpb.StorageFormatReq{Reformat: tc.reformat})
-			if fmtErr != nil {
-				t.Fatal(fmtErr)
+			resp, fmtErr := cs.StorageFormat(context.TODO(), &ctlpb.StorageFormatReq{
+				Reformat: tc.reformat,
+				Nvme: &ctlpb.FormatNvmeReq{
+					PciAddrs: tc.nvmeDevs,
+					Force:    tc.forceNvme,
+				},
+			})
+			common.CmpErr(t, tc.expErr, fmtErr)
+			if tc.expErr != nil {
+				return
 			}
 
 			common.AssertEqual(t, len(tc.expResp.Crets), len(resp.Crets),
@@ -7,16 +7,26 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/server/config"
 	"github.com/daos-stack/daos/src/control/server/engine"
 	"github.com/daos-stack/daos/src/control/server/storage"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+	"github.com/daos-stack/daos/src/control/server/storage/scm"
 )
 
 func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
@@ -29,14 +39,31 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 	for idx, addr := range scanAddrs {
 		scanCtrlrs[idx] = &storage.NvmeController{PciAddr: addr}
 	}
+	sizedCtrlr := func(addr string, sizes ...uint64) *storage.NvmeController {
+		ctrlr := &storage.NvmeController{PciAddr: addr}
+		for idx, size := range sizes {
+			ctrlr.Namespaces = append(ctrlr.Namespaces,
+				&storage.NvmeNamespace{ID: uint32(idx + 1), Size: size})
+		}
+		return ctrlr
+	}
+	sizedScanResp := &bdev.ScanResponse{
+		Controllers: storage.NvmeControllers{
+			sizedCtrlr("0000:90:00.0", 2*humanize.TByte),
+			sizedCtrlr("0000:d8:00.0", humanize.TByte, humanize.TByte),
+			sizedCtrlr("0000:8e:00.0", humanize.TByte),
+		},
+	}
 
 	for name, tc := range map[string]struct {
 		numEngines      int
 		vmdEnabled      bool
 		inScanResp      *bdev.ScanResponse
 		inCfgBdevLists  [][]string
+		minDevSize      int
 		expCfgBdevLists [][]string
 		expErr          error
+		expLog          string
 	}{
 		"vmd in scan but empty cfg bdev list": {
 			inCfgBdevLists:  [][]string{{}},
@@ -44,7 +71,7 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 		},
 		"vmd in scan with addr in cfg bdev list but vmd disabled": {
 			inCfgBdevLists: [][]string{{"0000:5d:05.5"}},
-			expErr:         FaultBdevNotFound([]string{"0000:5d:05.5"}),
+			expErr:         FaultEngineBdevNotFound(0, []string{"0000:5d:05.5"}),
 		},
 		"vmd in scan with addr in cfg bdev list": {
 			vmdEnabled:      true,
@@ -54,12 +81,12 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 		"vmd with no backing devices with addr in cfg bdev list": {
 			vmdEnabled:     true,
 			inCfgBdevLists: [][]string{{"0000:d7:05.5"}},
-			expErr:         FaultBdevNotFound([]string{"0000:d7:05.5"}),
+			expErr:         FaultEngineBdevNotFound(0, []string{"0000:d7:05.5"}),
 		},
 		"vmd and non vmd with no backing devices with addr in cfg bdev list": {
 			vmdEnabled:     true,
 			inCfgBdevLists: [][]string{{"0000:8a:00.0", "0000:d7:05.5"}},
-			expErr:         FaultBdevNotFound([]string{"0000:d7:05.5"}),
+			expErr:         FaultEngineBdevNotFound(0, []string{"0000:d7:05.5"}),
 		},
 		"vmd and non vmd in scan with addr in cfg bdev list": {
 			vmdEnabled:     true,
@@ -88,7 +115,8 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 		"missing ssd in cfg bdev list": {
 			numEngines:     2,
 			inCfgBdevLists: [][]string{{"0000:90:00.0"}, {"0000:80:00.0"}},
-			expErr:         FaultBdevNotFound([]string{"0000:80:00.0"}),
+			expErr:         FaultEngineBdevNotFound(1, []string{"0000:80:00.0"}),
+			expLog:         "engine 1: NVMe SSD 0000:80:00.0 specified in bdev_list not found",
 		},
 		"present ssds in cfg bdev list": {
 			numEngines: 2,
@@ -101,6 +129,21 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 				{"0000:8d:00.0", "0000:8b:00.0", "0000:8c:00.0", "0000:8f:00.0"},
 			},
 		},
+		"undersized ssd in cfg bdev list": {
+			numEngines:     2,
+			inScanResp:     sizedScanResp,
+			inCfgBdevLists: [][]string{{"0000:90:00.0", "0000:d8:00.0"}, {"0000:8e:00.0"}},
+			minDevSize:     1600,
+			expErr: FaultEngineBdevTooSmall(1, []string{"0000:8e:00.0"},
+				1600*humanize.GByte),
+			expLog: "engine 1: NVMe SSD 0000:8e:00.0 specified in bdev_list smaller than 1.6 TB",
+		},
+		"sufficiently sized ssds in cfg bdev list": {
+			inScanResp:      sizedScanResp,
+			inCfgBdevLists:  [][]string{{"0000:90:00.0", "0000:d8:00.0"}},
+			minDevSize:      2000,
+			expCfgBdevLists: [][]string{{"0000:90:00.0", "0000:d8:00.0"}},
+		},
 		"unexpected scan": {
 			numEngines: 2,
 			inScanResp: &bdev.ScanResponse{
@@ -110,7 +153,7 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 				{"0000:90:00.0", "0000:d8:00.0", "0000:8e:00.0", "0000:8a:00.0"},
 				{"0000:8d:00.0", "0000:8b:00.0", "0000:8c:00.0", "0000:8f:00.0"},
 			},
-			expErr: FaultBdevNotFound([]string{
+			expErr: FaultEngineBdevNotFound(0, []string{
 				"0000:90:00.0", "0000:d8:00.0", "0000:8e:00.0", "0000:8a:00.0",
 			}),
 		},
@@ -132,7 +175,8 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 			for idx := 0; idx < tc.numEngines; idx++ {
 				testCfg.Engines[idx] = engine.NewConfig().
 					WithBdevClass("nvme").
-					WithBdevDeviceList(tc.inCfgBdevLists[idx]...)
+					WithBdevDeviceList(tc.inCfgBdevLists[idx]...).
+					WithBdevMinDevSize(tc.minDevSize)
 			}
 
 			mbc := &bdev.MockBackendConfig{VmdEnabled: tc.vmdEnabled}
@@ -145,6 +189,9 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 			}
 			gotErr := cs.checkCfgBdevs(tc.inScanResp)
 			common.CmpErr(t, tc.expErr, gotErr)
+			if !strings.Contains(buf.String(), tc.expLog) {
+				t.Fatalf("expected log to contain %q", tc.expLog)
+			}
 			if tc.expErr != nil {
 				return
 			}
@@ -164,3 +211,1390 @@ func TestServer_CtlSvc_checkCfgBdevs(t *testing.T) {
 		})
 	}
 }
+
+// Run with -race to detect unsynchronized access to the engine storage
+// configs, which are updated by Setup when VMD addresses are substituted.
+func TestServer_CtlSvc_ConcurrentSetupAndScan(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testCfg := config.DefaultServer().WithEngines(
+		engine.NewConfig().
+			WithBdevClass("nvme").
+			WithBdevDeviceList("0000:8a:00.0", "0000:5d:05.5"),
+		engine.NewConfig().
+			WithBdevClass("nvme").
+			WithBdevDeviceList("0000:8b:00.0"),
+	)
+
+	var ctrlrs storage.NvmeControllers
+	for _, addr := range []string{"0000:8a:00.0", "0000:8b:00.0", "5d0505:01:00.0", "5d0505:03:00.0"} {
+		ctrlrs = append(ctrlrs, &storage.NvmeController{
+			PciAddr:     addr,
+			HealthStats: &storage.NvmeHealth{},
+		})
+	}
+	mbc := &bdev.MockBackendConfig{
+		VmdEnabled: true,
+		ScanRes:    &bdev.ScanResponse{Controllers: ctrlrs},
+	}
+	cs := mockControlService(t, log, testCfg, mbc, &scm.MockBackendConfig{}, nil)
+
+	const iterations = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, iterations*4)
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if err := cs.Setup(); err != nil {
+				errs <- errors.Wrap(err, "setup")
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if _, err := cs.HealthSummary(); err != nil {
+				errs <- errors.Wrap(err, "health summary")
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if _, err := cs.ReconcileConfig(); err != nil {
+				errs <- errors.Wrap(err, "reconcile config")
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			cs.configuredBdevs()
+			if _, err := cs.mapEngineTargets(&bdev.ScanResponse{Controllers: ctrlrs}); err != nil {
+				errs <- errors.Wrap(err, "map engine targets")
+			}
+		}
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	expDevs := [][]string{
+		{"0000:8a:00.0", "5d0505:01:00.0", "5d0505:03:00.0"},
+		{"0000:8b:00.0"},
+	}
+	for idx, storageCfg := range cs.instanceStorageCfgs() {
+		if diff := cmp.Diff(expDevs[idx], storageCfg.Bdev.GetNvmeDevs()); diff != "" {
+			t.Fatalf("engine %d: unexpected device list (-want, +got):\n%s\n", idx, diff)
+		}
+	}
+}
+
+func TestServer_CtlSvc_mapEngineTargets(t *testing.T) {
+	ctrlrs := storage.NvmeControllers{
+		{
+			PciAddr: "0000:80:00.0",
+			SmdDevices: []*storage.SmdDevice{
+				{UUID: common.MockUUID(0), TargetIDs: []int32{0, 1}, Rank: 0},
+			},
+		},
+		{
+			PciAddr: "0000:81:00.0",
+			SmdDevices: []*storage.SmdDevice{
+				{UUID: common.MockUUID(1), TargetIDs: []int32{2, 3}, Rank: 0},
+			},
+		},
+		{
+			PciAddr: "0000:82:00.0",
+			SmdDevices: []*storage.SmdDevice{
+				{UUID: common.MockUUID(2), TargetIDs: []int32{0, 1, 2}, Rank: 1},
+			},
+		},
+		{
+			PciAddr: "0000:83:00.0",
+			SmdDevices: []*storage.SmdDevice{
+				{UUID: common.MockUUID(3), TargetIDs: []int32{4}, Rank: 5},
+			},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		inScanResp     *bdev.ScanResponse
+		inCfgBdevLists [][]string
+		expMap         map[int32][]*engineTarget
+		expErr         error
+	}{
+		"nil scan response": {
+			inCfgBdevLists: [][]string{{}, {}},
+			expErr:         errors.New("nil scan response"),
+		},
+		"no controllers assigned to engines": {
+			inScanResp:     &bdev.ScanResponse{Controllers: ctrlrs},
+			inCfgBdevLists: [][]string{{}, {}},
+			expMap:         map[int32][]*engineTarget{},
+		},
+		"two engines": {
+			inScanResp: &bdev.ScanResponse{Controllers: ctrlrs},
+			inCfgBdevLists: [][]string{
+				{"0000:80:00.0", "0000:81:00.0"},
+				{"0000:82:00.0"},
+			},
+			expMap: map[int32][]*engineTarget{
+				0: {
+					{EngineIdx: 0, Rank: 0, PciAddr: "0000:80:00.0", DevUUID: common.MockUUID(0)},
+					{EngineIdx: 1, Rank: 1, PciAddr: "0000:82:00.0", DevUUID: common.MockUUID(2)},
+				},
+				1: {
+					{EngineIdx: 0, Rank: 0, PciAddr: "0000:80:00.0", DevUUID: common.MockUUID(0)},
+					{EngineIdx: 1, Rank: 1, PciAddr: "0000:82:00.0", DevUUID: common.MockUUID(2)},
+				},
+				2: {
+					{EngineIdx: 0, Rank: 0, PciAddr: "0000:81:00.0", DevUUID: common.MockUUID(1)},
+					{EngineIdx: 1, Rank: 1, PciAddr: "0000:82:00.0", DevUUID: common.MockUUID(2)},
+				},
+				3: {
+					{EngineIdx: 0, Rank: 0, PciAddr: "0000:81:00.0", DevUUID: common.MockUUID(1)},
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			testCfg := config.DefaultServer()
+			testCfg.Engines = make([]*engine.Config, len(tc.inCfgBdevLists))
+			for idx := range tc.inCfgBdevLists {
+				testCfg.Engines[idx] = engine.NewConfig().
+					WithBdevClass("nvme").
+					WithBdevDeviceList(tc.inCfgBdevLists[idx]...)
+			}
+
+			cs := mockControlService(t, log, testCfg, nil, nil, nil)
+
+			gotMap, gotErr := cs.mapEngineTargets(tc.inScanResp)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expMap, gotMap); diff != "" {
+				t.Fatalf("unexpected target map (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_NvmePrepare(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req          bdev.PrepareRequest
+		mbc          *bdev.MockBackendConfig
+		hpi          *hugePageInfo
+		hpiErr       error
+		expInUse     int
+		expHugePages int
+		expErr       error
+	}{
+		"negative hugepages": {
+			req: bdev.PrepareRequest{
+				HugePageCount: -1,
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			expErr: errors.New("invalid number of hugepages requested: -1"),
+		},
+		"malformed allowlist address": {
+			req: bdev.PrepareRequest{
+				HugePageCount: 1024,
+				PCIAllowlist:  "0000:80:00.0 0000:8z:00.0",
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			expErr: errors.New(`invalid pci address "0000:8z:00.0"`),
+		},
+		"truncated blocklist address": {
+			req: bdev.PrepareRequest{
+				PCIBlocklist: "0000:80:00",
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			expErr: errors.New(`invalid pci address "0000:80:00"`),
+		},
+		"zero hugepages uses default": {
+			req: bdev.PrepareRequest{
+				PCIAllowlist: "0000:80:00.0 0000:81:00.0",
+			},
+			hpiErr:       errors.New("should not be called"),
+			expHugePages: 4096,
+		},
+		"hugepage info read fails": {
+			req: bdev.PrepareRequest{
+				HugePageCount: 1024,
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			hpiErr: errors.New("no meminfo"),
+			expErr: errors.New("no meminfo"),
+		},
+		"insufficient hugepages": {
+			req: bdev.PrepareRequest{
+				HugePageCount: 4096,
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				PrepareErr:      errors.New("should not get this far"),
+			},
+			hpi: &hugePageInfo{
+				Total:          1024,
+				PageSizeKb:     2048,
+				MemAvailableKb: 2097152,
+			},
+			expErr: FaultInsufficientHugePageMemory(2048, 4096),
+		},
+		"sufficient hugepages": {
+			req: bdev.PrepareRequest{
+				HugePageCount: 2048,
+			},
+			hpi: &hugePageInfo{
+				Total:          1024,
+				PageSizeKb:     2048,
+				MemAvailableKb: 2097152,
+			},
+			expHugePages: 1024,
+		},
+		"reset fails": {
+			req: bdev.PrepareRequest{
+				ResetOnly: true,
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("reset failed"),
+			},
+			hpiErr: errors.New("should not be called"),
+			expErr: errors.New("reset failed"),
+		},
+		"reset with no hugepages in use": {
+			req: bdev.PrepareRequest{
+				ResetOnly: true,
+			},
+			hpi: &hugePageInfo{
+				Total:      1024,
+				Free:       1024,
+				PageSizeKb: 2048,
+			},
+		},
+		"reset with leaked hugepages": {
+			req: bdev.PrepareRequest{
+				ResetOnly: true,
+			},
+			hpi: &hugePageInfo{
+				Total:      1024,
+				Free:       512,
+				PageSizeKb: 2048,
+			},
+			expInUse: 512,
+		},
+		"reset hugepage info read fails": {
+			req: bdev.PrepareRequest{
+				ResetOnly: true,
+			},
+			hpiErr: errors.New("no meminfo"),
+		},
+		"targeted reset not checked": {
+			req: bdev.PrepareRequest{
+				ResetOnly:    true,
+				PCIAllowlist: "0000:80:00.0",
+			},
+			hpi: &hugePageInfo{
+				Total:      1024,
+				PageSizeKb: 2048,
+			},
+		},
+		"dry-run reports requested hugepages": {
+			req: bdev.PrepareRequest{
+				HugePageCount: 2048,
+				DryRun:        true,
+			},
+			mbc: &bdev.MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				PrepareErr:      errors.New("should not get this far"),
+			},
+			hpi: &hugePageInfo{
+				Total:          1024,
+				PageSizeKb:     2048,
+				MemAvailableKb: 4194304,
+			},
+			expHugePages: 2048,
+		},
+		"dry-run reset not checked": {
+			req: bdev.PrepareRequest{
+				ResetOnly: true,
+				DryRun:    true,
+			},
+			hpi: &hugePageInfo{
+				Total:      1024,
+				PageSizeKb: 2048,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockControlService(t, log, nil, tc.mbc, nil, nil)
+			cs.getHugePageInfo = func() (*hugePageInfo, error) {
+				if tc.hpi == nil {
+					return &hugePageInfo{}, tc.hpiErr
+				}
+				return tc.hpi, tc.hpiErr
+			}
+
+			gotResp, gotErr := cs.NvmePrepare(tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expInUse, gotResp.HugePagesInUse,
+				"hugepages in use after reset")
+			common.AssertEqual(t, tc.expHugePages, gotResp.HugePageCount,
+				"hugepages allocated after prepare")
+		})
+	}
+}
+
+func TestServer_CtlSvc_PrepareContext(t *testing.T) {
+	for name, tc := range map[string]struct {
+		scmPrep   bool
+		blockPrep bool
+		expErr    error
+	}{
+		"nvme prepare completes": {},
+		"nvme prepare blocks past deadline": {
+			blockPrep: true,
+			expErr:    context.DeadlineExceeded,
+		},
+		"scm prepare completes": {
+			scmPrep: true,
+		},
+		"scm prepare blocks past deadline": {
+			scmPrep:   true,
+			blockPrep: true,
+			expErr:    context.DeadlineExceeded,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bmbc := &bdev.MockBackendConfig{}
+			smbc := &scm.MockBackendConfig{
+				DiscoverRes: storage.ScmModules{storage.MockScmModule()},
+			}
+			if tc.blockPrep {
+				wait := make(chan struct{})
+				defer close(wait)
+				bmbc.PrepareWait = wait
+				smbc.PrepWait = wait
+			}
+			cs := mockControlService(t, log, nil, bmbc, smbc, nil)
+
+			timeout := 50 * time.Millisecond
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			var gotErr error
+			if tc.scmPrep {
+				_, gotErr = cs.ScmPrepareContext(ctx, scm.PrepareRequest{})
+			} else {
+				_, gotErr = cs.NvmePrepareContext(ctx, bdev.PrepareRequest{})
+			}
+			common.CmpErr(t, tc.expErr, gotErr)
+
+			if elapsed := time.Since(start); elapsed > 10*timeout {
+				t.Fatalf("prepare returned after %s, expected cancellation after %s",
+					elapsed, timeout)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_PrepareContext_InProgress(t *testing.T) {
+	for name, tc := range map[string]struct {
+		scmPrep bool
+	}{
+		"nvme": {},
+		"scm": {
+			scmPrep: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			wait := make(chan struct{})
+			bmbc := &bdev.MockBackendConfig{PrepareWait: wait}
+			smbc := &scm.MockBackendConfig{
+				DiscoverRes: storage.ScmModules{storage.MockScmModule()},
+				PrepWait:    wait,
+			}
+			cs := mockControlService(t, log, nil, bmbc, smbc, nil)
+
+			prepare := func(ctx context.Context) (err error) {
+				if tc.scmPrep {
+					_, err = cs.ScmPrepareContext(ctx, scm.PrepareRequest{})
+					return
+				}
+				_, err = cs.NvmePrepareContext(ctx, bdev.PrepareRequest{})
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			common.CmpErr(t, context.DeadlineExceeded, prepare(ctx))
+
+			// the timed out prepare is still running
+			common.CmpErr(t, errors.New("prepare already in progress"),
+				prepare(context.Background()))
+
+			close(wait)
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				err := prepare(context.Background())
+				if err == nil {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("prepare still in progress after completion: %s", err)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_GetScmPrepareAction(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req       scm.PrepareRequest
+		smbc      *scm.MockBackendConfig
+		expState  storage.ScmState
+		expAction scm.PrepareAction
+		expErr    error
+	}{
+		"discover fails": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverErr: errors.New("discover failed"),
+			},
+			expErr: errors.New("discover failed"),
+		},
+		"no modules": {
+			smbc:      &scm.MockBackendConfig{},
+			expAction: scm.PrepareActionNone,
+		},
+		"unknown state": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes: storage.ScmModules{storage.MockScmModule()},
+			},
+			expErr: errors.New("unknown scm state"),
+		},
+		"no regions": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoRegions,
+			},
+			expState:  storage.ScmStateNoRegions,
+			expAction: scm.PrepareActionCreateRegions,
+		},
+		"free capacity": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateFreeCapacity,
+			},
+			expState:  storage.ScmStateFreeCapacity,
+			expAction: scm.PrepareActionCreateNamespaces,
+		},
+		"no capacity": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoCapacity,
+			},
+			expState:  storage.ScmStateNoCapacity,
+			expAction: scm.PrepareActionNone,
+		},
+		"no capacity; reset": {
+			req: scm.PrepareRequest{Reset: true},
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoCapacity,
+			},
+			expState:  storage.ScmStateNoCapacity,
+			expAction: scm.PrepareActionRemoveRegions,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			// fail if prepare is actually attempted
+			tc.smbc.PrepErr = errors.New("should not get this far")
+			cs := mockControlService(t, log, nil, nil, tc.smbc, nil)
+
+			gotState, gotAction, gotErr := cs.GetScmPrepareAction(tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expState, gotState, "unexpected scm state")
+			common.AssertEqual(t, tc.expAction, gotAction, "unexpected prepare action")
+		})
+	}
+}
+
+func TestServer_CtlSvc_GetScmState(t *testing.T) {
+	for name, tc := range map[string]struct {
+		smbc     *scm.MockBackendConfig
+		expState storage.ScmState
+		expCause storage.ScmStateCause
+		expErr   error
+	}{
+		"discover fails": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverErr: errors.New("discover failed"),
+			},
+			expErr: errors.New("discover failed"),
+		},
+		"no modules present": {
+			smbc:     &scm.MockBackendConfig{},
+			expCause: storage.ScmCauseNoModules,
+		},
+		"state unknown": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes: storage.ScmModules{storage.MockScmModule()},
+			},
+			expCause: storage.ScmCauseUnknown,
+		},
+		"regions not configured": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoRegions,
+			},
+			expState: storage.ScmStateNoRegions,
+			expCause: storage.ScmCauseNoRegions,
+		},
+		"namespaces not created": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateFreeCapacity,
+			},
+			expState: storage.ScmStateFreeCapacity,
+			expCause: storage.ScmCauseNoNamespaces,
+		},
+		"ready": {
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoCapacity,
+			},
+			expState: storage.ScmStateNoCapacity,
+			expCause: storage.ScmCauseReady,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockControlService(t, log, nil, nil, tc.smbc, nil)
+
+			gotState, gotCause, gotErr := cs.GetScmState()
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expState, gotState, "unexpected scm state")
+			common.AssertEqual(t, tc.expCause, gotCause, "unexpected scm state cause")
+			common.AssertEqual(t, tc.expCause.String(), gotCause.String(), "unexpected reason")
+		})
+	}
+}
+
+func TestServer_CtlSvc_scanCache(t *testing.T) {
+	type scanStep struct {
+		advance time.Duration
+		force   bool
+		devList []string
+	}
+
+	for name, tc := range map[string]struct {
+		ttl          time.Duration
+		steps        []scanStep
+		expNvmeCalls int
+		expScmCalls  int
+	}{
+		"cache disabled": {
+			steps:        []scanStep{{}, {}, {}},
+			expNvmeCalls: 3,
+			// scm provider caches discovery results indefinitely
+			expScmCalls: 1,
+		},
+		"cache disabled; rescan": {
+			steps:        []scanStep{{force: true}, {force: true}, {force: true}},
+			expNvmeCalls: 3,
+			expScmCalls:  3,
+		},
+		"repeat scans within ttl": {
+			ttl: time.Minute,
+			steps: []scanStep{
+				{}, {advance: time.Second}, {advance: 30 * time.Second},
+			},
+			expNvmeCalls: 1,
+			expScmCalls:  1,
+		},
+		"scan after ttl expiry": {
+			ttl: time.Minute,
+			steps: []scanStep{
+				{}, {advance: time.Second}, {advance: time.Minute}, {},
+			},
+			expNvmeCalls: 2,
+			expScmCalls:  2,
+		},
+		"forced rescan within ttl": {
+			ttl: time.Minute,
+			steps: []scanStep{
+				{}, {force: true}, {},
+			},
+			expNvmeCalls: 2,
+			expScmCalls:  2,
+		},
+		"different device lists cached separately": {
+			ttl: time.Minute,
+			steps: []scanStep{
+				{}, {devList: []string{"0000:80:00.0"}},
+				{}, {devList: []string{"0000:80:00.0"}},
+			},
+			expNvmeCalls: 2,
+			// scm scan results don't depend on the device list
+			expScmCalls: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bmb := bdev.NewMockBackend(&bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.MockNvmeControllers(2),
+				},
+			})
+			smb := scm.NewMockBackend(&scm.MockBackendConfig{
+				DiscoverRes: storage.ScmModules{storage.MockScmModule()},
+			})
+			cs := NewStorageControlService(log,
+				bdev.NewProvider(log, bmb).WithForwardingDisabled(),
+				scm.NewProvider(log, smb, scm.DefaultMockSysProvider()).WithForwardingDisabled(),
+				nil).WithScanCacheTTL(tc.ttl)
+
+			now := time.Now()
+			if cs.scanCache != nil {
+				cs.scanCache.getTime = func() time.Time { return now }
+			}
+
+			for _, step := range tc.steps {
+				now = now.Add(step.advance)
+
+				if _, err := cs.NvmeScan(bdev.ScanRequest{
+					DeviceList: step.devList,
+					NoCache:    step.force,
+				}); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cs.ScmScan(scm.ScanRequest{
+					DeviceList: step.devList,
+					Rescan:     step.force,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			common.AssertEqual(t, tc.expNvmeCalls, bmb.ScanCalls, "unexpected nvme scan calls")
+			common.AssertEqual(t, tc.expScmCalls, smb.DiscoverCalls, "unexpected scm scan calls")
+		})
+	}
+}
+
+func TestServer_CtlSvc_HealthSummary(t *testing.T) {
+	healthy := &storage.NvmeController{
+		PciAddr:     "0000:80:00.0",
+		HealthStats: &storage.NvmeHealth{},
+	}
+	warning := &storage.NvmeController{
+		PciAddr:     "0000:81:00.0",
+		HealthStats: &storage.NvmeHealth{TempWarn: true},
+	}
+	critical := &storage.NvmeController{
+		PciAddr:     "0000:82:00.0",
+		HealthStats: &storage.NvmeHealth{ReadOnlyWarn: true, TempWarn: true},
+	}
+	unknown := &storage.NvmeController{
+		PciAddr: "0000:83:00.0",
+	}
+
+	for name, tc := range map[string]struct {
+		bmbc       *bdev.MockBackendConfig
+		smbc       *scm.MockBackendConfig
+		cfgBdevs   []string
+		expSummary *StorageHealthSummary
+		expErr     error
+	}{
+		"nvme scan fails": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanErr: errors.New("failed"),
+			},
+			smbc:   &scm.MockBackendConfig{},
+			expErr: errors.New("failed"),
+		},
+		"scm scan fails": {
+			bmbc: &bdev.MockBackendConfig{},
+			smbc: &scm.MockBackendConfig{
+				DiscoverErr: errors.New("failed"),
+			},
+			expErr: errors.New("failed"),
+		},
+		"mixed health": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{
+						healthy, warning, critical, unknown, healthy,
+					},
+				},
+			},
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:   storage.ScmModules{storage.MockScmModule()},
+				StartingState: storage.ScmStateNoCapacity,
+			},
+			cfgBdevs: []string{healthy.PciAddr, critical.PciAddr},
+			expSummary: &StorageHealthSummary{
+				NvmeHealthy:  2,
+				NvmeWarning:  1,
+				NvmeCritical: 1,
+				NvmeUnknown:  1,
+				ScmState:     storage.ScmStateNoCapacity,
+			},
+		},
+		"missing device": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{healthy, warning},
+				},
+			},
+			smbc:     &scm.MockBackendConfig{},
+			cfgBdevs: []string{healthy.PciAddr, critical.PciAddr},
+			expSummary: &StorageHealthSummary{
+				NvmeHealthy:  1,
+				NvmeWarning:  1,
+				MissingBdevs: []string{critical.PciAddr},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			testCfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().
+					WithBdevClass("nvme").
+					WithBdevDeviceList(tc.cfgBdevs...),
+			)
+
+			cs := mockControlService(t, log, testCfg, tc.bmbc, tc.smbc, nil)
+
+			gotSummary, gotErr := cs.HealthSummary()
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expSummary, gotSummary); diff != "" {
+				t.Fatalf("unexpected summary (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_DumpScan(t *testing.T) {
+	for name, tc := range map[string]struct {
+		bmbc   *bdev.MockBackendConfig
+		smbc   *scm.MockBackendConfig
+		expErr error
+	}{
+		"nvme scan fails": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanErr: errors.New("failed"),
+			},
+			smbc:   &scm.MockBackendConfig{},
+			expErr: errors.New("failed"),
+		},
+		"scm scan fails": {
+			bmbc: &bdev.MockBackendConfig{},
+			smbc: &scm.MockBackendConfig{
+				DiscoverErr: errors.New("failed"),
+			},
+			expErr: errors.New("failed"),
+		},
+		"empty scan": {
+			bmbc: &bdev.MockBackendConfig{},
+			smbc: &scm.MockBackendConfig{},
+		},
+		"health and smd devices": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.MockNvmeControllers(2),
+				},
+			},
+			smbc: &scm.MockBackendConfig{
+				DiscoverRes:         storage.ScmModules{storage.MockScmModule()},
+				GetPmemNamespaceRes: storage.ScmNamespaces{storage.MockScmNamespace()},
+				StartingState:       storage.ScmStateNoCapacity,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockControlService(t, log, config.DefaultServer(), tc.bmbc, tc.smbc, nil)
+
+			var dumpBuf bytes.Buffer
+			gotErr := cs.DumpScan(&dumpBuf)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			expNvme, err := cs.NvmeScan(bdev.ScanRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expScm, err := cs.ScmScan(scm.ScanRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotDump, err := LoadScanDump(&dumpBuf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(expNvme, gotDump.Nvme, storage.NvmeControllerCmpOpt()); diff != "" {
+				t.Fatalf("unexpected nvme scan (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(expScm, gotDump.Scm); diff != "" {
+				t.Fatalf("unexpected scm scan (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_LoadScanDump(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input   string
+		expDump *StorageScanDump
+		expErr  error
+	}{
+		"malformed": {
+			input:  "{nvme:",
+			expErr: errors.New("decode storage scan dump"),
+		},
+		"empty object": {
+			input: "{}",
+			expDump: &StorageScanDump{
+				Nvme: new(bdev.ScanResponse),
+				Scm:  new(scm.ScanResponse),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotDump, gotErr := LoadScanDump(strings.NewReader(tc.input))
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expDump, gotDump); diff != "" {
+				t.Fatalf("unexpected dump (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_SetupContext(t *testing.T) {
+	ctrlr := storage.MockNvmeController()
+	clone := storage.MockNvmeController(1)
+	clone.Serial = ctrlr.Serial
+
+	for name, tc := range map[string]struct {
+		blockScan  bool
+		duplicates bool
+		expLog     string
+		expErr     error
+	}{
+		"scan completes": {},
+		"cancelled during nvme scan": {
+			blockScan: true,
+			expErr:    context.DeadlineExceeded,
+		},
+		"duplicate serials": {
+			duplicates: true,
+			expLog: fmt.Sprintf("serial %q shared by controllers %s, %s",
+				ctrlr.Serial, ctrlr.PciAddr, clone.PciAddr),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bmbc := &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{ctrlr},
+				},
+			}
+			if tc.blockScan {
+				bmbc.ScanWait = make(chan struct{})
+				defer close(bmbc.ScanWait)
+			}
+			if tc.duplicates {
+				bmbc.ScanRes.Controllers = append(bmbc.ScanRes.Controllers, clone)
+			}
+
+			testCfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().
+					WithBdevClass("nvme").
+					WithBdevDeviceList(ctrlr.PciAddr),
+			)
+			cs := mockControlService(t, log, testCfg, bmbc, nil, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			gotErr := cs.SetupContext(ctx)
+			common.CmpErr(t, tc.expErr, gotErr)
+
+			if tc.expLog != "" && !strings.Contains(buf.String(), tc.expLog) {
+				t.Fatalf("expected log to contain %q, got:\n%s", tc.expLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_SetupContext_ConfiguredBdevs(t *testing.T) {
+	scanAddrs := []string{
+		"0000:90:00.0", "0000:d8:00.0", "5d0505:01:00.0", "0000:8e:00.0",
+		"0000:8a:00.0", "5d0505:03:00.0",
+	}
+	scanCtrlrs := make(storage.NvmeControllers, len(scanAddrs))
+	for idx, addr := range scanAddrs {
+		scanCtrlrs[idx] = &storage.NvmeController{PciAddr: addr}
+	}
+
+	for name, tc := range map[string]struct {
+		cfgOnly         bool
+		vmdEnabled      bool
+		inCfgBdevLists  [][]string
+		expScanReqs     []bdev.ScanRequest
+		expCfgBdevLists [][]string
+		expErr          error
+	}{
+		"all devices scanned by default": {
+			inCfgBdevLists:  [][]string{{"0000:90:00.0"}},
+			expScanReqs:     []bdev.ScanRequest{{}},
+			expCfgBdevLists: [][]string{{"0000:90:00.0"}},
+		},
+		"only configured devices scanned": {
+			cfgOnly:        true,
+			inCfgBdevLists: [][]string{{"0000:90:00.0", "0000:d8:00.0"}, {"0000:8a:00.0", "0000:90:00.0"}},
+			expScanReqs: []bdev.ScanRequest{
+				{DeviceList: []string{"0000:90:00.0", "0000:d8:00.0", "0000:8a:00.0"}, NoCache: true},
+			},
+			expCfgBdevLists: [][]string{{"0000:90:00.0", "0000:d8:00.0"}, {"0000:8a:00.0", "0000:90:00.0"}},
+		},
+		"configured vmd devices expanded": {
+			cfgOnly:        true,
+			vmdEnabled:     true,
+			inCfgBdevLists: [][]string{{"0000:8a:00.0", "0000:5d:05.5"}},
+			expScanReqs: []bdev.ScanRequest{
+				{DeviceList: []string{"0000:8a:00.0", "0000:5d:05.5"}, ExpandVMD: true, NoCache: true},
+			},
+			expCfgBdevLists: [][]string{{"0000:8a:00.0", "5d0505:01:00.0", "5d0505:03:00.0"}},
+		},
+		"missing configured device detected": {
+			cfgOnly:        true,
+			inCfgBdevLists: [][]string{{"0000:90:00.0"}, {"0000:80:00.0"}},
+			expScanReqs: []bdev.ScanRequest{
+				{DeviceList: []string{"0000:90:00.0", "0000:80:00.0"}, NoCache: true},
+			},
+			expErr: FaultEngineBdevNotFound(1, []string{"0000:80:00.0"}),
+		},
+		"no configured devices": {
+			cfgOnly:        true,
+			inCfgBdevLists: [][]string{{}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			testCfg := config.DefaultServer()
+			for _, devs := range tc.inCfgBdevLists {
+				testCfg.Engines = append(testCfg.Engines, engine.NewConfig().
+					WithBdevClass("nvme").
+					WithBdevDeviceList(devs...))
+			}
+
+			mb := bdev.NewMockBackend(&bdev.MockBackendConfig{
+				ScanRes:    &bdev.ScanResponse{Controllers: scanCtrlrs},
+				VmdEnabled: tc.vmdEnabled,
+			})
+			cs := mockControlService(t, log, testCfg, nil, nil, nil)
+			cs.bdev = bdev.NewProvider(log, mb).WithForwardingDisabled()
+			cs.WithConfiguredBdevScan(tc.cfgOnly)
+
+			gotErr := cs.SetupContext(context.Background())
+			common.CmpErr(t, tc.expErr, gotErr)
+
+			if diff := cmp.Diff(tc.expScanReqs, mb.ScanReqs); diff != "" {
+				t.Fatalf("unexpected scan requests (-want, +got):\n%s\n", diff)
+			}
+			if tc.expErr != nil {
+				return
+			}
+
+			for idx, expDevs := range tc.expCfgBdevLists {
+				gotDevs := cs.instanceStorage[idx].Bdev.GetNvmeDevs()
+				if diff := cmp.Diff(expDevs, gotDevs); diff != "" {
+					t.Fatalf("engine %d: unexpected bdev list (-want, +got):\n%s\n", idx, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_scanTimeout(t *testing.T) {
+	for name, tc := range map[string]struct {
+		timeout   time.Duration
+		blockScan bool
+		expErr    error
+	}{
+		"scan completes": {
+			timeout: time.Second,
+		},
+		"scan blocks past deadline": {
+			timeout:   10 * time.Millisecond,
+			blockScan: true,
+			expErr:    errors.New("nvme scan timed out after 10ms"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			bmbc := &bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{storage.MockNvmeController()},
+				},
+			}
+			if tc.blockScan {
+				bmbc.ScanWait = make(chan struct{})
+				defer close(bmbc.ScanWait)
+			}
+
+			cs := mockControlService(t, log, config.DefaultServer(), bmbc, nil, nil)
+			cs.WithScanTimeout(tc.timeout)
+
+			resp, gotErr := cs.NvmeScan(bdev.ScanRequest{})
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				if errors.Cause(gotErr) != context.DeadlineExceeded {
+					t.Fatalf("expected deadline exceeded cause, got %v", gotErr)
+				}
+				return
+			}
+
+			if len(resp.Controllers) != 1 {
+				t.Fatalf("expected 1 controller, got %d", len(resp.Controllers))
+			}
+
+			// the scm scan isn't blocked by the nvme provider
+			if _, err := cs.ScmScan(scm.ScanRequest{}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+type mockScanObservation struct {
+	scanType string
+	failed   bool
+}
+
+type mockScanMetrics struct {
+	observed []mockScanObservation
+}
+
+func (msm *mockScanMetrics) ObserveScan(scanType string, elapsed time.Duration, err error) {
+	msm.observed = append(msm.observed, mockScanObservation{
+		scanType: scanType,
+		failed:   err != nil,
+	})
+}
+
+func TestServer_CtlSvc_scanMetrics(t *testing.T) {
+	for name, tc := range map[string]struct {
+		bmbc        *bdev.MockBackendConfig
+		smbc        *scm.MockBackendConfig
+		expObserved []mockScanObservation
+	}{
+		"successful scans": {
+			expObserved: []mockScanObservation{
+				{scanType: scanTypeNvme},
+				{scanType: scanTypeScm},
+				{scanType: scanTypeNvme},
+			},
+		},
+		"failed scans": {
+			bmbc: &bdev.MockBackendConfig{
+				ScanErr: errors.New("nvme failed"),
+			},
+			smbc: &scm.MockBackendConfig{
+				DiscoverErr: errors.New("scm failed"),
+			},
+			expObserved: []mockScanObservation{
+				{scanType: scanTypeNvme, failed: true},
+				{scanType: scanTypeScm, failed: true},
+				{scanType: scanTypeNvme, failed: true},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			metrics := new(mockScanMetrics)
+			cs := mockControlService(t, log, nil, tc.bmbc, tc.smbc, nil)
+			cs.WithScanMetrics(metrics)
+
+			cs.NvmeScan(bdev.ScanRequest{})
+			cs.ScmScan(scm.ScanRequest{})
+			cs.NvmeScan(bdev.ScanRequest{NoCache: true})
+
+			if diff := cmp.Diff(tc.expObserved, metrics.observed,
+				cmp.AllowUnexported(mockScanObservation{})); diff != "" {
+				t.Fatalf("unexpected observations (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_CtlSvc_healthThrottle(t *testing.T) {
+	type scanStep struct {
+		advance time.Duration
+		force   bool
+	}
+
+	for name, tc := range map[string]struct {
+		interval time.Duration
+		steps    []scanStep
+		expCalls int
+	}{
+		"throttle disabled": {
+			steps:    []scanStep{{}, {}, {}},
+			expCalls: 3,
+		},
+		"repeat scans within interval": {
+			interval: 5 * time.Second,
+			steps: []scanStep{
+				{}, {advance: time.Second}, {advance: 3 * time.Second},
+			},
+			expCalls: 1,
+		},
+		"scan after interval": {
+			interval: 5 * time.Second,
+			steps: []scanStep{
+				{}, {advance: time.Second}, {advance: 5 * time.Second}, {},
+			},
+			expCalls: 2,
+		},
+		"forced scan within interval": {
+			interval: 5 * time.Second,
+			steps: []scanStep{
+				{}, {force: true}, {},
+			},
+			expCalls: 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			ctrlr := storage.MockNvmeController()
+			bmb := bdev.NewMockBackend(&bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{
+					Controllers: storage.NvmeControllers{ctrlr},
+				},
+			})
+			cfg := config.DefaultServer().WithEngines(
+				engine.NewConfig().
+					WithTargetCount(1).
+					WithBdevClass("nvme").
+					WithBdevDeviceList(ctrlr.PciAddr),
+			)
+			cs := mockControlService(t, log, cfg, nil, nil, nil)
+			cs.bdev = bdev.NewProvider(log, bmb).WithForwardingDisabled()
+			cs.WithHealthInterval(tc.interval)
+
+			now := time.Now()
+			if cs.healthThrottle != nil {
+				cs.healthThrottle.getTime = func() time.Time { return now }
+			}
+
+			for _, step := range tc.steps {
+				now = now.Add(step.advance)
+
+				resp, err := cs.scanBdevs(context.TODO(), &ctlpb.ScanNvmeReq{
+					Health: true,
+					Force:  step.force,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				common.AssertEqual(t, 1, len(resp.Ctrlrs), "unexpected controller count")
+			}
+
+			common.AssertEqual(t, tc.expCalls, bmb.ScanCalls, "unexpected nvme scan calls")
+		})
+	}
+}
+
+func TestServer_CtlSvc_ReconcileConfig(t *testing.T) {
+	ctrlrs := storage.MockNvmeControllers(4) // sockets 0, 1, 0, 1
+	addrs := make([]string, 0, len(ctrlrs))
+	for _, ctrlr := range ctrlrs {
+		addrs = append(addrs, ctrlr.PciAddr)
+	}
+	namespaces := storage.ScmNamespaces{
+		storage.MockScmNamespace(0), storage.MockScmNamespace(1),
+	}
+	engineCfg := func(pmem string, bdevs ...string) *engine.Config {
+		return engine.NewConfig().
+			WithScmClass("dcpm").
+			WithScmMountPoint("/mnt/daos").
+			WithScmDeviceList(pmem).
+			WithBdevClass("nvme").
+			WithBdevDeviceList(bdevs...)
+	}
+
+	for name, tc := range map[string]struct {
+		engineCfgs []*engine.Config
+		namespaces storage.ScmNamespaces
+		expDiff    *StorageConfigDiff
+		expEmpty   bool
+	}{
+		"config matches scan": {
+			engineCfgs: []*engine.Config{
+				engineCfg("/dev/pmem0", addrs[0], addrs[2]),
+				engineCfg("/dev/pmem1", addrs[1], addrs[3]),
+			},
+			expDiff: &StorageConfigDiff{
+				Engines: []*EngineStorageDiff{{Index: 0}, {Index: 1}},
+			},
+			expEmpty: true,
+		},
+		"extra devices": {
+			engineCfgs: []*engine.Config{
+				engineCfg("/dev/pmem0", addrs[0]),
+				engineCfg("/dev/pmem1", addrs[1]),
+			},
+			namespaces: append(namespaces, storage.MockScmNamespace(2)),
+			expDiff: &StorageConfigDiff{
+				Engines:    []*EngineStorageDiff{{Index: 0}, {Index: 1}},
+				ExtraBdevs: []string{addrs[2], addrs[3]},
+				ExtraScm:   []string{"pmem2"},
+			},
+		},
+		"missing devices": {
+			engineCfgs: []*engine.Config{
+				engineCfg("/dev/pmem0", addrs[0], addrs[2], "0000:90:00.0"),
+				engineCfg("/dev/pmem3", addrs[1], addrs[3]),
+			},
+			expDiff: &StorageConfigDiff{
+				Engines: []*EngineStorageDiff{
+					{Index: 0, MissingBdevs: []string{"0000:90:00.0"}},
+					{Index: 1, MissingScm: []string{"/dev/pmem3"}},
+				},
+				ExtraScm: []string{"pmem1"},
+			},
+		},
+		"socket mismatch": {
+			engineCfgs: []*engine.Config{
+				engineCfg("/dev/pmem0", addrs[0], addrs[1]),
+				engineCfg("/dev/pmem1", addrs[2], addrs[3]),
+			},
+			expDiff: &StorageConfigDiff{
+				Engines: []*EngineStorageDiff{
+					{
+						Index: 0,
+						SocketMismatch: []*DeviceSocketMismatch{
+							{Device: addrs[1], SocketID: 1, ExpSocketID: 0},
+						},
+					},
+					{
+						Index: 1,
+						SocketMismatch: []*DeviceSocketMismatch{
+							{Device: addrs[2], SocketID: 0, ExpSocketID: 1},
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			if tc.namespaces == nil {
+				tc.namespaces = namespaces
+			}
+
+			bmb := bdev.NewMockBackend(&bdev.MockBackendConfig{
+				ScanRes: &bdev.ScanResponse{Controllers: ctrlrs},
+			})
+			smb := scm.NewMockBackend(&scm.MockBackendConfig{
+				DiscoverRes:         storage.ScmModules{storage.MockScmModule()},
+				GetPmemNamespaceRes: tc.namespaces,
+			})
+			cs := NewStorageControlService(log,
+				bdev.NewProvider(log, bmb).WithForwardingDisabled(),
+				scm.NewProvider(log, smb, scm.DefaultMockSysProvider()).WithForwardingDisabled(),
+				tc.engineCfgs)
+
+			gotDiff, err := cs.ReconcileConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expDiff, gotDiff); diff != "" {
+				t.Fatalf("unexpected diff (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expEmpty, gotDiff.IsEmpty(), "unexpected empty result")
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/daos-stack/daos/src/control/server/config"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 // ControlService implements the control plane control service, satisfying
@@ -20,23 +21,26 @@ import (
 type ControlService struct {
 	ctlpb.UnimplementedCtlSvcServer
 	StorageControlService
-	harness *EngineHarness
-	srvCfg  *config.Server
-	events  *events.PubSub
+	harness    *EngineHarness
+	srvCfg     *config.Server
+	events     *events.PubSub
+	membership *system.Membership
 }
 
 // NewControlService returns ControlService to be used as gRPC control service
 // datastore. Initialized with sensible defaults and provided components.
 func NewControlService(log logging.Logger, h *EngineHarness,
 	bp *bdev.Provider, sp *scm.Provider,
-	cfg *config.Server, e *events.PubSub) *ControlService {
+	cfg *config.Server, e *events.PubSub, m *system.Membership) *ControlService {
 
-	scs := NewStorageControlService(log, bp, sp, cfg.Engines)
+	scs := NewStorageControlService(log, bp, sp, cfg.Engines).
+		WithConfiguredBdevScan(cfg.ConfiguredBdevScan)
 
 	return &ControlService{
 		StorageControlService: *scs,
 		harness:               h,
 		srvCfg:                cfg,
 		events:                e,
+		membership:            m,
 	}
 }
@@ -42,6 +42,10 @@ func mockControlService(t *testing.T, log logging.Logger, cfg *config.Server, bm
 		events: events.NewPubSub(context.TODO(), log),
 		srvCfg: cfg,
 	}
+	// avoid depending on the hugepage configuration of the test host
+	cs.getHugePageInfo = func() (*hugePageInfo, error) {
+		return &hugePageInfo{Total: 1 << 20, Free: 1 << 20}, nil
+	}
 
 	for _, engineCfg := range cfg.Engines {
 		bp, err := bdev.NewClassProvider(log, "", &engineCfg.Storage.Bdev)
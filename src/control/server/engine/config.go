@@ -312,6 +312,12 @@ func (c *Config) WithBdevFileSize(size int) *Config {
 	return c
 }
 
+// WithBdevMinDevSize sets the minimum capacity in GB expected of each NVMe SSD.
+func (c *Config) WithBdevMinDevSize(size int) *Config {
+	c.Storage.Bdev.MinDevSize = size
+	return c
+}
+
 // WithBdevConfigPath sets the path to the generated NVMe config file used by SPDK.
 func (c *Config) WithBdevConfigPath(cfgPath string) *Config {
 	c.Storage.Bdev.ConfigPath = cfgPath
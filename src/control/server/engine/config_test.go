@@ -292,7 +292,14 @@ func TestEngine_BdevConfigValidation(t *testing.T) {
 			cfg: baseValidConfig().
 				WithBdevClass("nvme").
 				WithBdevDeviceList(common.MockPCIAddr(1), "0000:00:00"),
-			expErr: errors.New("unexpected pci address"),
+			expErr: errors.New("unexpected bdf format"),
+		},
+		"negative minimum device size": {
+			cfg: baseValidConfig().
+				WithBdevClass("nvme").
+				WithBdevDeviceList(common.MockPCIAddr(1)).
+				WithBdevMinDevSize(-1),
+			expErr: errors.New("bdev_min_size"),
 		},
 		"kdev class but no devices": {
 			cfg: baseValidConfig().
@@ -9,6 +9,7 @@ package engine
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/daos-stack/daos/src/control/lib/atm"
 )
@@ -23,6 +24,12 @@ type (
 		LastPid    uint64
 		ErrChanCb  func() error
 		ErrChanErr error
+		// ExitOnSignal, if set, is called for each signal received and
+		// the runner stops running if it returns true.
+		ExitOnSignal func(uint32, os.Signal) bool
+		// ExitDelay, if set, delays the runner stopping after a signal
+		// that it exits on, to simulate a process taking time to exit.
+		ExitDelay time.Duration
 	}
 
 	TestRunner struct {
@@ -72,6 +79,18 @@ func (tr *TestRunner) Signal(sig os.Signal) error {
 	if tr.runnerCfg.SignalCb != nil {
 		tr.runnerCfg.SignalCb(tr.serverCfg.Index, sig)
 	}
+	if tr.runnerCfg.ExitOnSignal != nil && tr.runnerCfg.SignalErr == nil &&
+		tr.runnerCfg.ExitOnSignal(tr.serverCfg.Index, sig) {
+
+		if tr.runnerCfg.ExitDelay > 0 {
+			go func() {
+				time.Sleep(tr.runnerCfg.ExitDelay)
+				tr.runnerCfg.Running.SetFalse()
+			}()
+		} else {
+			tr.runnerCfg.Running.SetFalse()
+		}
+	}
 	return tr.runnerCfg.SignalErr
 }
 
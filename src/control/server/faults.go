@@ -46,6 +46,21 @@ var (
 		fmt.Sprintf("%s instance not started or not responding on dRPC", build.DataPlaneName),
 		"retry the operation or check server logs for more details",
 	)
+	FaultNilRequest = serverFault(
+		code.ServerNilRequest,
+		"nil request",
+		"retry the operation with a valid request",
+	)
+	FaultNoRanksSpecified = serverFault(
+		code.ServerNoRanksSpecified,
+		"no ranks specified in request",
+		"retry the operation specifying one or more ranks",
+	)
+	FaultRankDrpcNoResult = serverFault(
+		code.ServerRankDrpcNoResult,
+		"sending request over dRPC to local ranks: nil result",
+		"retry the operation or check server logs for more details",
+	)
 )
 
 func FaultPoolInvalidServiceReps(maxSvcReps uint32) *fault.Fault {
@@ -56,11 +71,27 @@ func FaultPoolInvalidServiceReps(maxSvcReps uint32) *fault.Fault {
 	)
 }
 
-func FaultInstancesNotStopped(action string, rank system.Rank) *fault.Fault {
+// FaultInstancesNotStopped creates a Fault naming the running ranks which
+// prevent the given action.
+func FaultInstancesNotStopped(action string, ranks ...system.Rank) *fault.Fault {
+	rs := system.RankSetFromRanks(ranks)
+	noun, verb := "rank", "is"
+	if rs.Count() > 1 {
+		noun, verb = "ranks", "are"
+	}
+
 	return serverFault(
 		code.ServerInstancesNotStopped,
-		fmt.Sprintf("%s not supported when rank %d is running", action, rank),
-		fmt.Sprintf("retry %s operation after stopping rank %d", action, rank),
+		fmt.Sprintf("%s not supported when %s %s %s running", action, noun, rs, verb),
+		fmt.Sprintf("retry %s operation after stopping %s %s", action, noun, rs),
+	)
+}
+
+func FaultInstancesFormatted(action string, rank system.Rank) *fault.Fault {
+	return serverFault(
+		code.ServerInstancesFormatted,
+		fmt.Sprintf("%s refused as rank %d has formatted storage that may contain data", action, rank),
+		fmt.Sprintf("retry %s operation with force to discard the existing data of rank %d", action, rank),
 	)
 }
 
@@ -116,6 +147,14 @@ func FaultInsufficientFreeHugePages(free, requested int) *fault.Fault {
 	)
 }
 
+func FaultInsufficientHugePageMemory(allocatable, requested int) *fault.Fault {
+	return serverFault(
+		code.ServerInsufficientHugePageMemory,
+		fmt.Sprintf("requested %d hugepages; only %d can be allocated", requested, allocatable),
+		"request fewer hugepages or free up system memory before retrying",
+	)
+}
+
 func FaultScmUnmanaged(mntPoint string) *fault.Fault {
 	return serverFault(
 		code.ServerScmUnmanaged,
@@ -132,6 +171,30 @@ func FaultBdevNotFound(bdevs []string) *fault.Fault {
 	)
 }
 
+// FaultEngineBdevNotFound creates a Fault for the case where NVMe SSDs listed
+// in the config of an engine can't be found.
+func FaultEngineBdevNotFound(engineIdx int, bdevs []string) *fault.Fault {
+	return serverFault(
+		code.ServerBdevNotFound,
+		fmt.Sprintf("engine %d: NVMe SSD%s %v not found", engineIdx,
+			common.Pluralise("", len(bdevs)), bdevs),
+		fmt.Sprintf("check SSD%s %v that are specified in the bdev_list of engine %d in server config exist",
+			common.Pluralise("", len(bdevs)), bdevs, engineIdx),
+	)
+}
+
+// FaultEngineBdevTooSmall creates a Fault for the case where NVMe SSDs listed
+// in the config of an engine are smaller than the configured minimum size.
+func FaultEngineBdevTooSmall(engineIdx int, bdevs []string, minSize uint64) *fault.Fault {
+	return serverFault(
+		code.ServerBdevTooSmall,
+		fmt.Sprintf("engine %d: NVMe SSD%s %v smaller than bdev_min_size (%s)", engineIdx,
+			common.Pluralise("", len(bdevs)), bdevs, humanize.Bytes(minSize)),
+		fmt.Sprintf("replace SSD%s %v or reduce the bdev_min_size of engine %d in server config",
+			common.Pluralise("", len(bdevs)), bdevs, engineIdx),
+	)
+}
+
 func FaultWrongSystem(reqName, sysName string) *fault.Fault {
 	return serverFault(
 		code.ServerWrongSystem,
@@ -8,6 +8,9 @@ package server
 
 import (
 	"context"
+	"net"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,8 +28,37 @@ import (
 const (
 	rankReqTimeout   = 10 * time.Second
 	rankStartTimeout = 3 * rankReqTimeout
+	maxPingsInflight = 8
 )
 
+// RankStartPolicy partitions the instances to be started into groups, each
+// group is started only after all instances in the preceding group are ready.
+type RankStartPolicy func([]*EngineInstance) [][]*EngineInstance
+
+// LeaderRanksFirst returns a RankStartPolicy that starts instances with the
+// given ranks (e.g. those hosting MS replicas) before all other instances.
+func LeaderRanksFirst(leaders ...system.Rank) RankStartPolicy {
+	return func(instances []*EngineInstance) [][]*EngineInstance {
+		var first, rest []*EngineInstance
+		for _, ei := range instances {
+			r, err := ei.GetRank()
+			if err == nil && r.InList(leaders) {
+				first = append(first, ei)
+				continue
+			}
+			rest = append(rest, ei)
+		}
+
+		groups := make([][]*EngineInstance, 0, 2)
+		for _, group := range [][]*EngineInstance{first, rest} {
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+}
+
 // EngineHarness is responsible for managing Engine instances.
 type EngineHarness struct {
 	sync.RWMutex
@@ -36,6 +68,8 @@ type EngineHarness struct {
 	rankReqTimeout   time.Duration
 	rankStartTimeout time.Duration
 	faultDomain      *system.FaultDomain
+	rankStartPolicy  RankStartPolicy
+	maxPingsInflight int
 }
 
 // NewEngineHarness returns an initialized *EngineHarness.
@@ -45,6 +79,7 @@ func NewEngineHarness(log logging.Logger) *EngineHarness {
 		instances:        make([]*EngineInstance, 0),
 		rankReqTimeout:   rankReqTimeout,
 		rankStartTimeout: rankStartTimeout,
+		maxPingsInflight: maxPingsInflight,
 	}
 }
 
@@ -54,6 +89,29 @@ func (h *EngineHarness) WithFaultDomain(fd *system.FaultDomain) *EngineHarness {
 	return h
 }
 
+// WithRankStartPolicy sets the order in which instances are started on
+// request, by default all instances are started at the same time.
+func (h *EngineHarness) WithRankStartPolicy(policy RankStartPolicy) *EngineHarness {
+	h.rankStartPolicy = policy
+	return h
+}
+
+// WithMaxPingsInflight sets the maximum number of dRPC pings that may be in
+// flight to local ranks at once, zero removes the limit.
+func (h *EngineHarness) WithMaxPingsInflight(max int) *EngineHarness {
+	h.maxPingsInflight = max
+	return h
+}
+
+// rankStartGroups returns the given instances in groups to be started in
+// sequence according to the harness' RankStartPolicy.
+func (h *EngineHarness) rankStartGroups(instances []*EngineInstance) [][]*EngineInstance {
+	if h.rankStartPolicy == nil {
+		return [][]*EngineInstance{instances}
+	}
+	return h.rankStartPolicy(instances)
+}
+
 // isStarted indicates whether the EngineHarness is in a running state.
 func (h *EngineHarness) isStarted() bool {
 	return h.started.Load()
@@ -91,6 +149,69 @@ func (h *EngineHarness) FilterInstancesByRankSet(ranks string) ([]*EngineInstanc
 	return out, nil
 }
 
+// LocalRanks returns the ranks of the harness' EngineInstances in ascending
+// order, instances without a superblock or an assigned rank are skipped.
+func (h *EngineHarness) LocalRanks() ([]system.Rank, error) {
+	h.RLock()
+	defer h.RUnlock()
+
+	ranks := make([]system.Rank, 0, len(h.instances))
+	for _, ei := range h.instances {
+		r, err := ei.GetRank()
+		if err != nil {
+			continue // no rank to report
+		}
+		ranks = append(ranks, r)
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+
+	return ranks, nil
+}
+
+// fabricHost returns the host portion of a fabric URI or address, e.g.
+// "10.0.0.1" for "ofi+sockets://10.0.0.1:31416".
+func fabricHost(uri string) string {
+	if i := strings.Index(uri, "://"); i >= 0 {
+		uri = uri[i+3:]
+	}
+	if host, _, err := net.SplitHostPort(uri); err == nil {
+		return host
+	}
+
+	return uri
+}
+
+// HostRanks resolves a host identifier to the set of ranks managed by the
+// harness' EngineInstances that are bound to that host. The identifier may be
+// the local hostname, which matches all local ranks, or a fabric URI or address
+// (with or without port), which matches the ranks whose superblock URI refers
+// to the same host. Instances without a superblock or an assigned rank are
+// skipped.
+func (h *EngineHarness) HostRanks(host string) (*system.RankSet, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return nil, errors.New("empty host identifier")
+	}
+	target := fabricHost(host)
+	matchAll := target == hostname()
+
+	h.RLock()
+	defer h.RUnlock()
+
+	ranks := make(system.RankList, 0, len(h.instances))
+	for _, ei := range h.instances {
+		r, err := ei.GetRank()
+		if err != nil {
+			continue // no rank to report
+		}
+		if matchAll || fabricHost(ei.getSuperblock().URI) == target {
+			ranks = append(ranks, r)
+		}
+	}
+
+	return system.RankSetFromRanks(ranks), nil
+}
+
 // AddInstance adds a new Engine instance to be managed.
 func (h *EngineHarness) AddInstance(ei *EngineInstance) error {
 	if h.isStarted() {
@@ -432,3 +432,118 @@ func TestServer_Harness_WithFaultDomain(t *testing.T) {
 	// updatedHarness is the same as harness
 	AssertEqual(t, updatedHarness, harness, "not the same structure")
 }
+
+func TestServer_Harness_LocalRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		superblocks []*Superblock
+		expRanks    []system.Rank
+	}{
+		"no instances": {
+			expRanks: []system.Rank{},
+		},
+		"all present": {
+			superblocks: []*Superblock{
+				{Rank: system.NewRankPtr(3)},
+				{Rank: system.NewRankPtr(1)},
+			},
+			expRanks: []system.Rank{1, 3},
+		},
+		"some missing": {
+			superblocks: []*Superblock{
+				nil,
+				{Rank: system.NewRankPtr(5)},
+				{},
+				{Rank: system.NewRankPtr(0)},
+			},
+			expRanks: []system.Rank{0, 5},
+		},
+		"all missing": {
+			superblocks: []*Superblock{nil, nil},
+			expRanks:    []system.Rank{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer ShowBufferOnFailure(t, buf)
+
+			harness := NewEngineHarness(log)
+			for _, sb := range tc.superblocks {
+				ei := newTestEngine(log, false)
+				ei.setSuperblock(sb)
+				if err := harness.AddInstance(ei); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gotRanks, gotErr := harness.LocalRanks()
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+			if diff := cmp.Diff(tc.expRanks, gotRanks); diff != "" {
+				t.Fatalf("unexpected ranks (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_Harness_HostRanks(t *testing.T) {
+	superblocks := []*Superblock{
+		{Rank: system.NewRankPtr(3), URI: "ofi+sockets://10.0.0.1:31416"},
+		{Rank: system.NewRankPtr(1), URI: "ofi+sockets://10.0.0.1:31417"},
+		{Rank: system.NewRankPtr(2), URI: "ofi+verbs;ofi_rxm://10.0.1.1:31416"},
+		{URI: "ofi+sockets://10.0.0.1:31418"},
+		nil,
+	}
+
+	for name, tc := range map[string]struct {
+		host     string
+		expRanks string
+		expErr   error
+	}{
+		"empty host": {
+			host:   " ",
+			expErr: errors.New("empty host identifier"),
+		},
+		"local hostname": {
+			host:     hostname(),
+			expRanks: "1-3",
+		},
+		"fabric address": {
+			host:     "10.0.0.1",
+			expRanks: "1,3",
+		},
+		"fabric address with port": {
+			host:     "10.0.1.1:31416",
+			expRanks: "2",
+		},
+		"fabric uri": {
+			host:     "ofi+sockets://10.0.0.1:31416",
+			expRanks: "1,3",
+		},
+		"unknown host": {
+			host: "10.0.2.1",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer ShowBufferOnFailure(t, buf)
+
+			harness := NewEngineHarness(log)
+			for _, sb := range superblocks {
+				ei := newTestEngine(log, false)
+				ei.setSuperblock(sb)
+				if err := harness.AddInstance(ei); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gotRanks, gotErr := harness.HostRanks(tc.host)
+			CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			AssertEqual(t, tc.expRanks, gotRanks.String(), "host ranks")
+		})
+	}
+}
@@ -18,11 +18,12 @@ import (
 type getHugePageInfoFn func() (*hugePageInfo, error)
 
 type hugePageInfo struct {
-	Total      int
-	Free       int
-	Reserved   int
-	Surplus    int
-	PageSizeKb int
+	Total          int
+	Free           int
+	Reserved       int
+	Surplus        int
+	PageSizeKb     int
+	MemAvailableKb int
 }
 
 func (hpi *hugePageInfo) TotalMB() int {
@@ -33,6 +34,20 @@ func (hpi *hugePageInfo) FreeMB() int {
 	return (hpi.Free * hpi.PageSizeKb) / 1024
 }
 
+// InUse returns the number of hugepages that are allocated and not free.
+func (hpi *hugePageInfo) InUse() int {
+	return hpi.Total - hpi.Free
+}
+
+// Allocatable returns the number of hugepages that could be configured, being
+// those already allocated plus as many as would fit in available memory.
+func (hpi *hugePageInfo) Allocatable() int {
+	if hpi.PageSizeKb == 0 {
+		return hpi.Total
+	}
+	return hpi.Total + hpi.MemAvailableKb/hpi.PageSizeKb
+}
+
 func parseInt(a string, i *int) {
 	v, err := strconv.Atoi(strings.TrimSpace(a))
 	if err != nil {
@@ -41,6 +56,23 @@ func parseInt(a string, i *int) {
 	*i = v
 }
 
+// parseKb parses a meminfo value with a kB unit suffix, desc describes the
+// value in error messages.
+func parseKb(desc, a string, i *int) error {
+	sf := strings.Fields(a)
+	if len(sf) != 2 {
+		return errors.Errorf("unable to parse %q", a)
+	}
+	// units are hard-coded to kB in the kernel, but doesn't hurt
+	// to double-check...
+	if sf[1] != "kB" {
+		return errors.Errorf("unhandled %s unit %q", desc, sf[1])
+	}
+	parseInt(sf[0], i)
+
+	return nil
+}
+
 func parseHugePageInfo(input io.Reader) (*hugePageInfo, error) {
 	hpi := new(hugePageInfo)
 
@@ -61,16 +93,13 @@ func parseHugePageInfo(input io.Reader) (*hugePageInfo, error) {
 		case "HugePages_Surp":
 			parseInt(keyVal[1], &hpi.Surplus)
 		case "Hugepagesize":
-			sf := strings.Fields(keyVal[1])
-			if len(sf) != 2 {
-				return nil, errors.Errorf("unable to parse %q", keyVal[1])
+			if err := parseKb("page size", keyVal[1], &hpi.PageSizeKb); err != nil {
+				return nil, err
 			}
-			// units are hard-coded to kB in the kernel, but doesn't hurt
-			// to double-check...
-			if sf[1] != "kB" {
-				return nil, errors.Errorf("unhandled page size unit %q", sf[1])
+		case "MemAvailable":
+			if err := parseKb("available memory", keyVal[1], &hpi.MemAvailableKb); err != nil {
+				return nil, err
 			}
-			parseInt(sf[0], &hpi.PageSizeKb)
 		default:
 			continue
 		}
@@ -42,11 +42,13 @@ HugePages_Free:     1023
 HugePages_Rsvd:        0
 HugePages_Surp:        0
 Hugepagesize:       2048 kB
+MemAvailable:    4194304 kB
 			`,
 			expOut: &hugePageInfo{
-				Total:      1024,
-				Free:       1023,
-				PageSizeKb: 2048,
+				Total:          1024,
+				Free:           1023,
+				PageSizeKb:     2048,
+				MemAvailableKb: 4194304,
 			},
 			expFreeMB: 2046,
 		},
@@ -77,6 +79,12 @@ Hugepagesize:       1 GB
 			`,
 			expErr: errors.New("unhandled page size"),
 		},
+		"weird available memory unit": {
+			input: `
+MemAvailable:       4 GB
+			`,
+			expErr: errors.New("unhandled available memory unit"),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			rdr := strings.NewReader(tc.input)
@@ -98,3 +106,29 @@ Hugepagesize:       1 GB
 		})
 	}
 }
+
+func TestServer_hugePageInfo_Allocatable(t *testing.T) {
+	for name, tc := range map[string]struct {
+		hpi    *hugePageInfo
+		expOut int
+	}{
+		"no pagesize": {
+			hpi:    &hugePageInfo{Total: 16, MemAvailableKb: 4096},
+			expOut: 16,
+		},
+		"none available": {
+			hpi:    &hugePageInfo{Total: 16, PageSizeKb: 2048},
+			expOut: 16,
+		},
+		"some available": {
+			hpi: &hugePageInfo{
+				Total: 1024, PageSizeKb: 2048, MemAvailableKb: 4194304,
+			},
+			expOut: 3072,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.expOut, tc.hpi.Allocatable(), name)
+		})
+	}
+}
@@ -73,7 +73,9 @@ fi
 echo "calling into script: $scriptpath"
 
 if [[ $1 == reset ]]; then
-	PATH=/sbin:$PATH "$scriptpath" reset
+	# an allowlist restricts the reset to the given devices
+	PCI_WHITELIST="$_PCI_WHITELIST" \
+	 PATH=/sbin:$PATH "$scriptpath" reset
 else
 	# avoid shadowing by prefixing input envars
 	PCI_WHITELIST="$_PCI_WHITELIST" \
@@ -9,6 +9,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
@@ -74,15 +75,40 @@ func (ei *EngineInstance) CallDrpc(ctx context.Context, method drpc.Method, body
 	if sb := ei.getSuperblock(); sb != nil {
 		rankMsg = fmt.Sprintf(" (rank %s)", sb.Rank)
 	}
-	ei.log.Debugf("%dB dRPC to index %d%s: %s", proto.Size(body), ei.Index(), rankMsg, method)
+	msg := body
+	if rdr, ok := isRetryable(body); ok {
+		msg = rdr.GetMessage()
+	}
+	ei.log.Debugf("%dB dRPC to index %d%s: %s", proto.Size(msg), ei.Index(), rankMsg, method)
 
 	return makeDrpcCall(ctx, ei.log, dc, method, body)
 }
 
+// transientDrpcStatuses are the statuses with which an engine may fail a
+// request that would succeed if retried, e.g. because it is busy.
+var transientDrpcStatuses = []drpc.DaosStatus{
+	drpc.DaosBusy,
+	drpc.DaosTryAgain,
+	drpc.DaosTimedOut,
+}
+
+// isTransientStatus returns true if the status reflects a temporary failure,
+// any other failure status is permanent.
+func isTransientStatus(status drpc.DaosStatus) bool {
+	for _, ts := range transientDrpcStatuses {
+		if status == ts {
+			return true
+		}
+	}
+	return false
+}
+
 // drespToMemberResult converts drpc.Response to system.MemberResult.
 //
 // MemberResult is populated with rank, state and error dependent on processing
 // dRPC response. Target state param is populated on success, Errored otherwise.
+// If the engine provides a message in the response, it is used verbatim as the
+// result message on success and appended to the DER status on failure.
 func drespToMemberResult(rank system.Rank, dresp *drpc.Response, err error, tState system.MemberState) *system.MemberResult {
 	if err != nil {
 		return system.NewMemberResult(rank,
@@ -97,17 +123,30 @@ func drespToMemberResult(rank system.Rank, dresp *drpc.Response, err error, tSta
 			system.MemberStateErrored)
 	}
 	if resp.GetStatus() != 0 {
-		return system.NewMemberResult(rank,
-			errors.Errorf("rank %s: %s", &rank, drpc.DaosStatus(resp.GetStatus()).Error()),
-			system.MemberStateErrored)
+		status := drpc.DaosStatus(resp.GetStatus())
+		msgErr := errors.Errorf("rank %s: %s", &rank, status.Error())
+		if resp.GetMsg() != "" {
+			msgErr = errors.Errorf("%s: %s", msgErr, resp.GetMsg())
+		}
+		result := system.NewMemberResult(rank, msgErr, system.MemberStateErrored)
+		result.Transient = isTransientStatus(status)
+
+		return result
 	}
 
-	return system.NewMemberResult(rank, nil, tState)
+	result := system.NewMemberResult(rank, nil, tState)
+	result.Msg = resp.GetMsg()
+
+	return result
 }
 
 // TryDrpc attempts dRPC request to given rank managed by instance and return
 // success or error from call result or timeout encapsulated in result.
-func (ei *EngineInstance) TryDrpc(ctx context.Context, method drpc.Method) *system.MemberResult {
+//
+// If retryTransient is set, calls failing with a transient status are retried
+// until they succeed, fail permanently or the context expires. Otherwise the
+// failure is returned immediately and flagged as transient in the result.
+func (ei *EngineInstance) TryDrpc(ctx context.Context, method drpc.Method, retryTransient bool) *system.MemberResult {
 	rank, err := ei.GetRank()
 	if err != nil {
 		return nil // no rank to return result for
@@ -118,8 +157,10 @@ func (ei *EngineInstance) TryDrpc(ctx context.Context, method drpc.Method) *syst
 		// member not ready for dRPC comms, annotate result with last
 		// error as Msg field if found to be stopped
 		result := &system.MemberResult{Rank: rank, State: localState}
-		if localState == system.MemberStateStopped && ei._lastErr != nil {
-			result.Msg = ei._lastErr.Error()
+		if localState == system.MemberStateStopped {
+			if exitErr := ei.lastExitErr(); exitErr != nil {
+				result.Msg = exitErr.Error()
+			}
 		}
 		return result
 	}
@@ -139,7 +180,13 @@ func (ei *EngineInstance) TryDrpc(ctx context.Context, method drpc.Method) *syst
 
 	resChan := make(chan *system.MemberResult)
 	go func() {
-		dresp, err := ei.CallDrpc(ctx, method, nil)
+		var body proto.Message
+		if retryTransient {
+			body = &retryableDrpcReq{
+				RetryableStatuses: transientDrpcStatuses,
+			}
+		}
+		dresp, err := ei.CallDrpc(ctx, method, body)
 		resChan <- drespToMemberResult(rank, dresp, err, targetState)
 	}()
 
@@ -157,6 +204,29 @@ func (ei *EngineInstance) TryDrpc(ctx context.Context, method drpc.Method) *syst
 	}
 }
 
+// poolSvcsUp returns true if the engine reports that the pool services it
+// hosts have been started, false if they are still being brought up.
+func (ei *EngineInstance) poolSvcsUp(ctx context.Context) (bool, error) {
+	dresp, err := ei.CallDrpc(ctx, drpc.MethodPoolSvcsUp, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp := new(mgmtpb.DaosResp)
+	if err = proto.Unmarshal(dresp.Body, resp); err != nil {
+		return false, errors.Wrap(err, "unmarshal PoolSvcsUp response")
+	}
+
+	switch status := drpc.DaosStatus(resp.Status); status {
+	case drpc.DaosSuccess:
+		return true, nil
+	case drpc.DaosBusy:
+		return false, nil
+	default:
+		return false, errors.Wrap(status, "poolSvcsUp failed")
+	}
+}
+
 func (ei *EngineInstance) getBioHealth(ctx context.Context, req *ctlpb.BioHealthReq) (*ctlpb.BioHealthResp, error) {
 	dresp, err := ei.CallDrpc(ctx, drpc.MethodBioHealth, req)
 	if err != nil {
@@ -193,6 +263,29 @@ func (ei *EngineInstance) listSmdDevices(ctx context.Context, req *ctlpb.SmdDevR
 	return resp, nil
 }
 
+// checkSmdTgtIDs returns an error if the given SMD device references target
+// IDs outside of the range of targets configured for the engine, indicating
+// either a misconfiguration or corrupted metadata. The check is skipped if the
+// target count is unknown.
+func checkSmdTgtIDs(tgtCount int, dev *ctlpb.SmdDevResp_Device) error {
+	if tgtCount <= 0 {
+		return nil
+	}
+
+	var bad []string
+	for _, id := range dev.GetTgtIds() {
+		if id < 0 || int(id) >= tgtCount {
+			bad = append(bad, fmt.Sprintf("%d", id))
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("target IDs %s outside of configured range 0-%d",
+		strings.Join(bad, ","), tgtCount-1)
+}
+
 // updateInUseBdevs updates-in-place the input list of controllers with
 // new NVMe health stats and SMD metadata info.
 //
@@ -215,12 +308,30 @@ func (ei *EngineInstance) updateInUseBdevs(ctx context.Context, ctrlrMap map[str
 		if !exists {
 			return errors.Errorf("%s: didn't match any known controllers", msg)
 		}
+		if err := checkSmdTgtIDs(ei.GetTargetCount(), dev); err != nil {
+			ei.log.Infof("Warning, %s: %s", msg, err)
+		}
+
+		smdDev := new(storage.SmdDevice)
+		if err := convert.Types(dev, smdDev); err != nil {
+			return errors.Wrapf(err, "convert smd for ctrlr %s", ctrlr.PciAddr)
+		}
+		engineRank, err := ei.GetRank()
+		if err != nil {
+			return errors.Wrapf(err, "get rank")
+		}
+		smdDev.Rank = engineRank
+		smdDev.TrAddr = dev.GetTrAddr()
 
+		// blobstore usage may be unavailable for a device (e.g. if
+		// it is faulty), leave usage zeroed in that case
 		pbStats, err := ei.getBioHealth(ctx, &ctlpb.BioHealthReq{
 			DevUuid: dev.GetUuid(),
 		})
 		if err != nil {
-			return errors.Wrapf(err, "instance %d getBioHealth()", ei.Index())
+			ctrlr.UpdateSmd(smdDev)
+			ei.log.Debugf("%s: smd usage unknown: %s", msg, err)
+			continue
 		}
 
 		health := new(storage.NvmeHealth)
@@ -237,16 +348,6 @@ func (ei *EngineInstance) updateInUseBdevs(ctx context.Context, ctrlrMap map[str
 			hasUpdatedHealth[ctrlr.PciAddr] = true
 		}
 
-		smdDev := new(storage.SmdDevice)
-		if err := convert.Types(dev, smdDev); err != nil {
-			return errors.Wrapf(err, "convert smd for ctrlr %s", ctrlr.PciAddr)
-		}
-		engineRank, err := ei.GetRank()
-		if err != nil {
-			return errors.Wrapf(err, "get rank")
-		}
-		smdDev.Rank = engineRank
-		smdDev.TrAddr = dev.GetTrAddr()
 		// space utilization stats for each smd device
 		smdDev.TotalBytes = pbStats.TotalBytes
 		smdDev.AvailBytes = pbStats.AvailBytes
@@ -9,6 +9,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,10 +18,13 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
 	srvpb "github.com/daos-stack/daos/src/control/common/proto/srv"
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/engine"
+	"github.com/daos-stack/daos/src/control/server/storage"
 	. "github.com/daos-stack/daos/src/control/system"
 )
 
@@ -110,6 +114,31 @@ func TestEngineInstance_DrespToRankResult(t *testing.T) {
 				Msg: fmt.Sprintf("rank %d: %s", dRank, drpc.DaosNoSpace),
 			},
 		},
+		"rank transient failure": {
+			daosResp: &mgmtpb.DaosResp{Status: int32(drpc.DaosBusy)},
+			expResult: &MemberResult{
+				Rank: dRank, State: MemberStateErrored, Errored: true,
+				Msg:       fmt.Sprintf("rank %d: %s", dRank, drpc.DaosBusy),
+				Transient: true,
+			},
+		},
+		"rank success with engine message": {
+			daosResp: &mgmtpb.DaosResp{Status: 0, Msg: "already stopping"},
+			expResult: &MemberResult{
+				Rank: dRank, State: MemberStateJoined, Msg: "already stopping",
+			},
+		},
+		"rank failure with engine message": {
+			daosResp: &mgmtpb.DaosResp{
+				Status: int32(drpc.DaosNoSpace),
+				Msg:    "pool service out of space on target 3",
+			},
+			expResult: &MemberResult{
+				Rank: dRank, State: MemberStateErrored, Errored: true,
+				Msg: fmt.Sprintf("rank %d: %s: pool service out of space on target 3",
+					dRank, drpc.DaosNoSpace),
+			},
+		},
 		"drpc failure": {
 			inErr: errors.New("returned from CallDrpc"),
 			expResult: &MemberResult{
@@ -153,3 +182,60 @@ func TestEngineInstance_DrespToRankResult(t *testing.T) {
 		})
 	}
 }
+
+func TestEngineInstance_updateInUseBdevs_TgtIDs(t *testing.T) {
+	ctrlr := storage.MockNvmeController()
+
+	for name, tc := range map[string]struct {
+		tgtIDs     []int32
+		expWarning string
+	}{
+		"target IDs in range": {
+			tgtIDs: []int32{0, 1, 2, 3},
+		},
+		"target ID out of range": {
+			tgtIDs:     []int32{0, 1, 4},
+			expWarning: "target IDs 4 outside of configured range 0-3",
+		},
+		"negative target ID": {
+			tgtIDs:     []int32{-1, 2},
+			expWarning: "target IDs -1 outside of configured range 0-3",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			srv := newTestEngine(log, false, engine.NewConfig().WithTargetCount(4))
+
+			cfg := new(mockDrpcClientConfig)
+			cfg.setSendMsgResponseList(t,
+				&mockDrpcResponse{
+					Message: &ctlpb.SmdDevResp{
+						Devices: []*ctlpb.SmdDevResp_Device{
+							{
+								Uuid:   common.MockUUID(),
+								TrAddr: ctrlr.PciAddr,
+								TgtIds: tc.tgtIDs,
+							},
+						},
+					},
+				},
+				&mockDrpcResponse{Message: &ctlpb.BioHealthResp{}},
+			)
+			srv.setDrpcClient(newMockDrpcClient(cfg))
+
+			ctrlrMap := map[string]*storage.NvmeController{ctrlr.PciAddr: ctrlr}
+			if err := srv.updateInUseBdevs(context.TODO(), ctrlrMap); err != nil {
+				t.Fatal(err)
+			}
+
+			gotWarning := strings.Contains(buf.String(), "Warning")
+			common.AssertEqual(t, tc.expWarning != "", gotWarning, "warning logged")
+			if tc.expWarning != "" {
+				common.AssertTrue(t, strings.Contains(buf.String(), tc.expWarning),
+					fmt.Sprintf("expected %q in log", tc.expWarning))
+			}
+		})
+	}
+}
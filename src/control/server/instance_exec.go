@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -132,6 +133,31 @@ func publishInstanceExitFn(publishFn func(*events.RASEvent), hostname string) on
 	}
 }
 
+// lastExitErr returns the error recorded when the instance last exited, or nil
+// if it hasn't exited.
+func (ei *EngineInstance) lastExitErr() error {
+	ei.RLock()
+	defer ei.RUnlock()
+
+	return ei._lastErr
+}
+
+// exitCode returns the exit code of an engine process given the error returned
+// when it exited. Zero is returned for a normal exit and -1 if the process was
+// terminated by a signal or the code is otherwise unavailable.
+func exitCode(exitErr error) int32 {
+	if exitErr == nil || errors.Is(exitErr, common.NormalExit) {
+		return 0
+	}
+
+	var ee *exec.ExitError
+	if errors.As(exitErr, &ee) {
+		return int32(ee.ExitCode())
+	}
+
+	return -1
+}
+
 func (ei *EngineInstance) exit(ctx context.Context, exitErr error) {
 	engineIdx := ei.Index()
 
@@ -140,7 +166,9 @@ func (ei *EngineInstance) exit(ctx context.Context, exitErr error) {
 		ei.log.Debugf("instance %d: no rank (%s)", engineIdx, err)
 	}
 
+	ei.Lock()
 	ei._lastErr = exitErr
+	ei.Unlock()
 	exPid := ei.runner.GetLastPid()
 
 	details := []string{fmt.Sprintf("instance %d", engineIdx)}
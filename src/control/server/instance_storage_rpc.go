@@ -9,14 +9,18 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sys/unix"
 
 	"github.com/daos-stack/daos/src/control/build"
+	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/common/proto"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	"github.com/daos-stack/daos/src/control/fault"
+	"github.com/daos-stack/daos/src/control/fault/code"
+	"github.com/daos-stack/daos/src/control/server/storage"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
 )
@@ -37,6 +41,11 @@ func (ei *EngineInstance) newMntRet(inErr error) *ctlpb.ScmMountResult {
 
 // newCret creates and populates NVMe controller result and logs error
 func (ei *EngineInstance) newCret(pciAddr string, inErr error) *ctlpb.NvmeControllerResult {
+	return newNvmeCtrlrResult(pciAddr, inErr)
+}
+
+// newNvmeCtrlrResult creates and populates NVMe controller result.
+func newNvmeCtrlrResult(pciAddr string, inErr error) *ctlpb.NvmeControllerResult {
 	var info string
 	if pciAddr == "" {
 		pciAddr = "<nil>"
@@ -50,6 +59,87 @@ func (ei *EngineInstance) newCret(pciAddr string, inErr error) *ctlpb.NvmeContro
 	}
 }
 
+// nvmeFormatOutcome classifies the result of formatting an NVMe controller.
+type nvmeFormatOutcome int
+
+const (
+	nvmeFormatSuccess nvmeFormatOutcome = iota
+	nvmeFormatAlreadyFormatted
+	nvmeFormatDeviceBusy
+	nvmeFormatDeviceMissing
+	nvmeFormatFailed
+)
+
+// nvmeFormatOutcomeFor returns the outcome of a controller format given the
+// error returned from the provider, if any.
+func nvmeFormatOutcomeFor(err error) nvmeFormatOutcome {
+	if err == nil {
+		return nvmeFormatSuccess
+	}
+
+	if f, ok := errors.Cause(err).(*fault.Fault); ok {
+		switch f.Code {
+		case code.ServerBdevNotFound, code.BdevPCIAddressNotFound:
+			return nvmeFormatDeviceMissing
+		}
+	}
+
+	// errors from the backend are often flattened to strings so check the
+	// message as well as the cause
+	if errors.Cause(err) == unix.EBUSY ||
+		strings.Contains(strings.ToLower(err.Error()), unix.EBUSY.Error()) {
+		return nvmeFormatDeviceBusy
+	}
+
+	return nvmeFormatFailed
+}
+
+// nvmeFormatState maps a controller format outcome to the ResponseState
+// reported to the client. Clients can rely on the status code to tell the
+// outcomes apart:
+//
+//	success           -> CTL_SUCCESS
+//	already formatted -> CTL_SUCCESS with skip message in Info
+//	device busy       -> CTL_ERR_BUSY
+//	device missing    -> CTL_ERR_NOT_FOUND
+//	other failure     -> CTL_ERR_NVME
+func nvmeFormatState(outcome nvmeFormatOutcome, pciAddr string, err error) *ctlpb.ResponseState {
+	var info string
+	if err != nil && fault.HasResolution(err) {
+		info = fault.ShowResolutionFor(err)
+	}
+
+	switch outcome {
+	case nvmeFormatSuccess:
+		return newSuccessState()
+	case nvmeFormatAlreadyFormatted:
+		return newResponseState(nil, ctlpb.ResponseStatus_CTL_SUCCESS,
+			fmt.Sprintf(msgNvmeFormatted, pciAddr))
+	case nvmeFormatDeviceBusy:
+		if info == "" {
+			info = fmt.Sprintf(msgNvmeFormatBusy, pciAddr)
+		}
+		return newResponseState(err, ctlpb.ResponseStatus_CTL_ERR_BUSY, info)
+	case nvmeFormatDeviceMissing:
+		if err == nil {
+			err = FaultBdevNotFound([]string{pciAddr})
+			info = fault.ShowResolutionFor(err)
+		}
+		return newResponseState(err, ctlpb.ResponseStatus_CTL_ERR_NOT_FOUND, info)
+	default:
+		return newResponseState(err, ctlpb.ResponseStatus_CTL_ERR_NVME, info)
+	}
+}
+
+// newNvmeFormatResult creates a controller result for a format operation with
+// the state mapped from the given error.
+func newNvmeFormatResult(pciAddr string, inErr error) *ctlpb.NvmeControllerResult {
+	result := newNvmeCtrlrResult(pciAddr, nil)
+	result.State = nvmeFormatState(nvmeFormatOutcomeFor(inErr), result.PciAddr, inErr)
+
+	return result
+}
+
 // scmFormat will return either successful result or error.
 func (ei *EngineInstance) scmFormat(reformat bool) (*ctlpb.ScmMountResult, error) {
 	engineIdx := ei.Index()
@@ -76,40 +166,99 @@ func (ei *EngineInstance) scmFormat(reformat bool) (*ctlpb.ScmMountResult, error
 	return ei.newMntRet(nil), nil
 }
 
-func (ei *EngineInstance) bdevFormat(p *bdev.Provider) (results proto.NvmeControllerResults) {
+// NvmeFormatProgressFn is called with the result for a controller as soon as
+// its format has completed or been skipped.
+type NvmeFormatProgressFn func(*ctlpb.NvmeControllerResult)
+
+// bdevFormat formats the block devices in the instance config, if pciAddrs
+// is not empty then only the listed devices that are assigned to the
+// instance will be formatted.
+//
+// If progress is not nil, each result is passed to it as it becomes available
+// and NVMe devices are formatted one at a time so that results arrive as each
+// controller completes rather than all at once.
+//
+// If skipFormatted is set, NVMe devices that already hold a blobstore on every
+// namespace are left untouched and reported as already formatted.
+func (ei *EngineInstance) bdevFormat(p *bdev.Provider, progress NvmeFormatProgressFn, skipFormatted bool, pciAddrs ...string) (results proto.NvmeControllerResults) {
 	engineIdx := ei.Index()
 	cfg := ei.bdevConfig()
 	results = make(proto.NvmeControllerResults, 0, len(cfg.DeviceList))
 
+	addResults := func(newResults ...*ctlpb.NvmeControllerResult) {
+		results = append(results, newResults...)
+		if progress == nil {
+			return
+		}
+		for _, result := range newResults {
+			progress(result)
+		}
+	}
+
+	devList := cfg.DeviceList
+	if len(pciAddrs) != 0 {
+		devList = make([]string, 0, len(pciAddrs))
+		for _, dev := range cfg.DeviceList {
+			if common.Includes(pciAddrs, dev) {
+				devList = append(devList, dev)
+			}
+		}
+	}
+
 	// A config with SCM and no block devices is valid.
-	if len(cfg.DeviceList) == 0 {
+	if len(devList) == 0 {
 		return
 	}
 
 	ei.log.Infof("Instance %d: starting format of %s block devices %v",
-		engineIdx, cfg.Class, cfg.DeviceList)
+		engineIdx, cfg.Class, devList)
 
-	res, err := p.Format(bdev.FormatRequest{
-		Class:      cfg.Class,
-		DeviceList: cfg.DeviceList,
-		MemSize:    cfg.MemSize,
-	})
-	if err != nil {
-		results = append(results, ei.newCret("", err))
-		return
+	batches := [][]string{devList}
+	perDevice := progress != nil && cfg.Class == storage.BdevClassNvme
+	if perDevice {
+		batches = make([][]string, 0, len(devList))
+		for _, dev := range devList {
+			batches = append(batches, []string{dev})
+		}
 	}
 
-	for dev, status := range res.DeviceResponses {
-		// TODO DAOS-5828: passing status.Error directly triggers segfault
-		var err error
-		if status.Error != nil {
-			err = status.Error
+	for _, batch := range batches {
+		res, err := p.Format(bdev.FormatRequest{
+			Class:         cfg.Class,
+			DeviceList:    batch,
+			MemSize:       cfg.MemSize,
+			SkipFormatted: skipFormatted,
+		})
+		if err != nil {
+			// attribute the error to the device if formatted alone
+			var dev string
+			if perDevice {
+				dev = batch[0]
+			}
+			addResults(newNvmeFormatResult(dev, err))
+			continue
+		}
+
+		for dev, status := range res.DeviceResponses {
+			if status.AlreadyFormatted {
+				ei.log.Infof("Instance %d: "+msgNvmeFormatted, engineIdx, dev)
+				result := newNvmeCtrlrResult(dev, nil)
+				result.State = nvmeFormatState(nvmeFormatAlreadyFormatted, dev, nil)
+				addResults(result)
+				continue
+			}
+
+			// TODO DAOS-5828: passing status.Error directly triggers segfault
+			var err error
+			if status.Error != nil {
+				err = status.Error
+			}
+			addResults(newNvmeFormatResult(dev, err))
 		}
-		results = append(results, ei.newCret(dev, err))
 	}
 
 	ei.log.Infof("Instance %d: finished format of %s block devices %v",
-		engineIdx, cfg.Class, cfg.DeviceList)
+		engineIdx, cfg.Class, devList)
 
 	return
 }
@@ -165,19 +314,31 @@ func (ei *EngineInstance) StorageFormatSCM(ctx context.Context, reformat bool) (
 }
 
 // StorageFormatNVMe performs format on NVMe if superblock needs writing.
-func (ei *EngineInstance) StorageFormatNVMe(bdevProvider *bdev.Provider) (cResults proto.NvmeControllerResults) {
+func (ei *EngineInstance) StorageFormatNVMe(bdevProvider *bdev.Provider) proto.NvmeControllerResults {
+	return ei.StorageFormatNVMeProgress(bdevProvider, nil)
+}
+
+// StorageFormatNVMeProgress performs format on NVMe as StorageFormatNVMe does
+// and, if progress is not nil, passes each controller result to it as the
+// controller completes. The aggregate results are returned once all
+// controllers have been processed.
+func (ei *EngineInstance) StorageFormatNVMeProgress(bdevProvider *bdev.Provider, progress NvmeFormatProgressFn) (cResults proto.NvmeControllerResults) {
 	ei.log.Infof("Formatting nvme storage for %s instance %d", build.DataPlaneName, ei.Index())
 
 	// If no superblock exists, format NVMe and populate response with results.
 	needsSuperblock, err := ei.NeedsSuperblock()
 	if err != nil {
-		return proto.NvmeControllerResults{
+		cResults = proto.NvmeControllerResults{
 			ei.newCret("", err),
 		}
+		if progress != nil {
+			progress(cResults[0])
+		}
+		return
 	}
 
 	if needsSuperblock {
-		cResults = ei.bdevFormat(bdevProvider)
+		cResults = ei.bdevFormat(bdevProvider, progress, false)
 	}
 
 	return
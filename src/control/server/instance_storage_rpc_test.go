@@ -0,0 +1,243 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/fault"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/engine"
+	"github.com/daos-stack/daos/src/control/server/storage"
+	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+)
+
+// progressBackend formats the requested devices and records, for each format
+// call, how many progress results had been received when it was made.
+type progressBackend struct {
+	*bdev.MockBackend
+	failDev     string
+	received    *[]*ctlpb.NvmeControllerResult
+	formatReqs  [][]string
+	receivedAts []int
+}
+
+func (pb *progressBackend) Format(req bdev.FormatRequest) (*bdev.FormatResponse, error) {
+	pb.formatReqs = append(pb.formatReqs, req.DeviceList)
+	pb.receivedAts = append(pb.receivedAts, len(*pb.received))
+
+	resp := &bdev.FormatResponse{
+		DeviceResponses: make(bdev.DeviceFormatResponses),
+	}
+	for _, dev := range req.DeviceList {
+		if dev == pb.failDev {
+			return nil, errors.New("format failed")
+		}
+		resp.DeviceResponses[dev] = &bdev.DeviceFormatResponse{Formatted: true}
+	}
+
+	return resp, nil
+}
+
+func TestServer_EngineInstance_bdevFormat_Progress(t *testing.T) {
+	formatted := storage.MockNvmeController(1)
+	devs := []string{formatted.PciAddr, "0000:80:00.2", "0000:80:00.3"}
+
+	for name, tc := range map[string]struct {
+		noProgress     bool
+		failDev        string
+		expFormatReqs  [][]string
+		expReceivedAts []int
+		expResults     []string
+		expErrored     []string
+	}{
+		"without progress devices formatted together": {
+			noProgress:     true,
+			expFormatReqs:  [][]string{devs},
+			expReceivedAts: []int{0},
+			expResults:     devs,
+		},
+		"results arrive as each device completes": {
+			expFormatReqs:  [][]string{{devs[0]}, {devs[1]}, {devs[2]}},
+			expReceivedAts: []int{0, 1, 2},
+			expResults:     devs,
+		},
+		"failed device doesn't stop progress": {
+			failDev:        devs[1],
+			expFormatReqs:  [][]string{{devs[0]}, {devs[1]}, {devs[2]}},
+			expReceivedAts: []int{0, 1, 2},
+			expResults:     devs,
+			expErrored:     []string{devs[1]},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			var received []*ctlpb.NvmeControllerResult
+			progress := func(result *ctlpb.NvmeControllerResult) {
+				received = append(received, result)
+			}
+			if tc.noProgress {
+				progress = nil
+			}
+
+			pb := &progressBackend{
+				MockBackend: bdev.NewMockBackend(&bdev.MockBackendConfig{
+					ScanRes: &bdev.ScanResponse{
+						Controllers: storage.NvmeControllers{formatted},
+					},
+				}),
+				failDev:  tc.failDev,
+				received: &received,
+			}
+			provider := bdev.NewProvider(log, pb).WithForwardingDisabled()
+
+			engineCfg := engine.NewConfig().
+				WithBdevClass("nvme").
+				WithBdevDeviceList(devs...)
+			runner := engine.NewRunner(log, engineCfg)
+			instance := NewEngineInstance(log, nil, nil, nil, runner)
+
+			results := instance.bdevFormat(provider, progress, false)
+
+			if diff := cmp.Diff(tc.expFormatReqs, pb.formatReqs); diff != "" {
+				t.Fatalf("unexpected format requests (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expReceivedAts, pb.receivedAts); diff != "" {
+				t.Fatalf("unexpected progress at format (-want, +got):\n%s\n", diff)
+			}
+
+			var gotResults, gotErrored []string
+			for _, result := range results {
+				gotResults = append(gotResults, result.GetPciAddr())
+				if result.GetState().GetStatus() != ctlpb.ResponseStatus_CTL_SUCCESS {
+					gotErrored = append(gotErrored, result.GetPciAddr())
+				}
+			}
+			sort.Strings(gotResults)
+			if diff := cmp.Diff(tc.expResults, gotResults); diff != "" {
+				t.Fatalf("unexpected results (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expErrored, gotErrored); diff != "" {
+				t.Fatalf("unexpected errored results (-want, +got):\n%s\n", diff)
+			}
+
+			// the aggregate results match those passed to the callback
+			if !tc.noProgress {
+				if diff := cmp.Diff([]*ctlpb.NvmeControllerResult(results), received,
+					common.DefaultCmpOpts()...); diff != "" {
+					t.Fatalf("unexpected progress results (-want, +got):\n%s\n", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_nvmeFormatState(t *testing.T) {
+	const pciAddr = "0000:80:00.0"
+	notFound := bdev.FaultPCIAddrNotFound(pciAddr)
+	busyErr := errors.Wrap(unix.EBUSY, "claim controller")
+
+	for name, tc := range map[string]struct {
+		outcome  nvmeFormatOutcome
+		err      error
+		expState *ctlpb.ResponseState
+	}{
+		"success": {
+			outcome:  nvmeFormatSuccess,
+			expState: newSuccessState(),
+		},
+		"already formatted": {
+			outcome: nvmeFormatAlreadyFormatted,
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_SUCCESS,
+				Info:   fmt.Sprintf(msgNvmeFormatted, pciAddr),
+			},
+		},
+		"device busy": {
+			outcome: nvmeFormatDeviceBusy,
+			err:     busyErr,
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_BUSY,
+				Error:  busyErr.Error(),
+				Info:   fmt.Sprintf(msgNvmeFormatBusy, pciAddr),
+			},
+		},
+		"device busy message": {
+			outcome: nvmeFormatDeviceBusy,
+			err:     errors.New("spdk: Device or resource busy"),
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_BUSY,
+				Error:  "spdk: Device or resource busy",
+				Info:   fmt.Sprintf(msgNvmeFormatBusy, pciAddr),
+			},
+		},
+		"device missing": {
+			outcome: nvmeFormatDeviceMissing,
+			err:     notFound,
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_NOT_FOUND,
+				Error:  notFound.Error(),
+				Info:   fault.ShowResolutionFor(notFound),
+			},
+		},
+		"device missing from config": {
+			outcome: nvmeFormatDeviceMissing,
+			err:     FaultBdevNotFound([]string{pciAddr}),
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_NOT_FOUND,
+				Error:  FaultBdevNotFound([]string{pciAddr}).Error(),
+				Info:   fault.ShowResolutionFor(FaultBdevNotFound([]string{pciAddr})),
+			},
+		},
+		"device missing without error": {
+			outcome: nvmeFormatDeviceMissing,
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_NOT_FOUND,
+				Error:  FaultBdevNotFound([]string{pciAddr}).Error(),
+				Info:   fault.ShowResolutionFor(FaultBdevNotFound([]string{pciAddr})),
+			},
+		},
+		"other failure": {
+			outcome: nvmeFormatFailed,
+			err:     errors.New("format failed"),
+			expState: &ctlpb.ResponseState{
+				Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+				Error:  "format failed",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotState := nvmeFormatState(tc.outcome, pciAddr, tc.err)
+			if diff := cmp.Diff(tc.expState, gotState, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected state (-want, +got):\n%s\n", diff)
+			}
+
+			// outcomes determined by the provider error are mapped the
+			// same way when building the controller result
+			if tc.err != nil || tc.outcome == nvmeFormatSuccess {
+				common.AssertEqual(t, tc.outcome, nvmeFormatOutcomeFor(tc.err),
+					"unexpected outcome")
+
+				gotResult := newNvmeFormatResult(pciAddr, tc.err)
+				common.AssertEqual(t, pciAddr, gotResult.PciAddr, "unexpected address")
+				if diff := cmp.Diff(tc.expState, gotResult.State, common.DefaultCmpOpts()...); diff != "" {
+					t.Fatalf("unexpected result state (-want, +got):\n%s\n", diff)
+				}
+			}
+		})
+	}
+}
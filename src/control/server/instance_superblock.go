@@ -103,6 +103,20 @@ func (ei *EngineInstance) NeedsSuperblock() (bool, error) {
 	return false, nil
 }
 
+// hasFormattedStorage indicates whether a superblock exists on the instance's
+// SCM storage, which implies the storage has been formatted and may hold data.
+func (ei *EngineInstance) hasFormattedStorage() (bool, error) {
+	_, err := os.Stat(ei.superblockPath())
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, errors.Wrap(err, "checking for superblock")
+	}
+}
+
 // createSuperblock creates instance superblock if needed.
 func (ei *EngineInstance) createSuperblock(recreate bool) error {
 	if ei.isStarted() {
@@ -419,7 +419,13 @@ func (svc *mgmtSvc) Join(ctx context.Context, req *mgmtpb.JoinReq) (*mgmtpb.Join
 	return resp, nil
 }
 
-const systemReqTimeout = 30 * time.Second
+const (
+	systemReqTimeout = 30 * time.Second
+	// systemStopReserve is the fraction of the time remaining before the
+	// SystemStop deadline that the prep shutdown phase leaves for the stop
+	// phase.
+	systemStopReserve = 0.4
+)
 
 type (
 	// systemRanksFunc is an alias for control client API *Ranks() fanout
@@ -430,6 +436,10 @@ type (
 		Method       systemRanksFunc
 		Hosts, Ranks string
 		Force        bool
+		StrictRanks  bool
+		// StateFilter restricts the fanout to members currently in any
+		// of the states in the mask, zero value implies no filtering
+		StateFilter system.MemberState
 	}
 
 	fanoutResponse struct {
@@ -440,8 +450,10 @@ type (
 )
 
 // resolveRanks derives ranks to be used for fanout by comparing host and rank
-// sets with the contents of the membership.
-func (svc *mgmtSvc) resolveRanks(hosts, ranks string) (hitRS, missRS *system.RankSet, missHS *hostlist.HostSet, err error) {
+// sets with the contents of the membership. If strict is set then an error is
+// returned when the rank set references ranks that are not members, rather
+// than reporting them as absent.
+func (svc *mgmtSvc) resolveRanks(hosts, ranks string, strict bool) (hitRS, missRS *system.RankSet, missHS *hostlist.HostSet, err error) {
 	hasHosts := hosts != ""
 	hasRanks := ranks != ""
 
@@ -457,6 +469,10 @@ func (svc *mgmtSvc) resolveRanks(hosts, ranks string) (hitRS, missRS *system.Ran
 		if hitRS, missHS, err = svc.membership.CheckHosts(hosts, build.DefaultControlPort); err != nil {
 			return
 		}
+	case hasRanks && strict:
+		if hitRS, err = svc.membership.CheckRanksStrict(ranks); err != nil {
+			return
+		}
 	case hasRanks:
 		if hitRS, missRS, err = svc.membership.CheckRanks(ranks); err != nil {
 			return
@@ -479,6 +495,32 @@ func (svc *mgmtSvc) resolveRanks(hosts, ranks string) (hitRS, missRS *system.Ran
 	return
 }
 
+// filterRanksByState returns the subset of the given ranks whose members are
+// currently in any of the states in the mask.
+func (svc *mgmtSvc) filterRanksByState(ranks *system.RankSet, mask system.MemberState) *system.RankSet {
+	var filtered system.RankList
+	for _, m := range svc.membership.Members(ranks) {
+		if m.State()&mask != 0 {
+			filtered = append(filtered, m.Rank)
+		}
+	}
+
+	return system.RankSetFromRanks(filtered)
+}
+
+// prepShutdownContext returns a context for the prep shutdown phase of a
+// SystemStop that expires early enough to leave a share of the time remaining
+// before the parent deadline for the stop phase.
+func prepShutdownContext(parent context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	reserve := time.Duration(float64(time.Until(deadline)) * systemStopReserve)
+
+	return context.WithDeadline(parent, deadline.Add(-reserve))
+}
+
 // rpcFanout sends requests to ranks in list on their respective host
 // addresses through functions implementing UnaryInvoker.
 //
@@ -496,11 +538,17 @@ func (svc *mgmtSvc) rpcFanout(parent context.Context, fanReq fanoutRequest, upda
 	}
 
 	// populate missing hosts/ranks in outer response and resolve active ranks
-	hitRanks, missRanks, missHosts, err := svc.resolveRanks(fanReq.Hosts, fanReq.Ranks)
+	hitRanks, missRanks, missHosts, err := svc.resolveRanks(fanReq.Hosts, fanReq.Ranks, fanReq.StrictRanks)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if fanReq.StateFilter != 0 && hitRanks.Count() != 0 {
+		resolved := hitRanks.String()
+		hitRanks = svc.filterRanksByState(hitRanks, fanReq.StateFilter)
+		svc.log.Debugf("ranks %s filtered by state to %s", resolved, hitRanks)
+	}
+
 	resp := &fanoutResponse{AbsentHosts: missHosts, AbsentRanks: missRanks}
 	if hitRanks.Count() == 0 {
 		return resp, hitRanks, nil
@@ -563,7 +611,7 @@ func (svc *mgmtSvc) SystemQuery(ctx context.Context, req *mgmtpb.SystemQueryReq)
 	}
 	svc.log.Debugf("Received SystemQuery RPC: %+v", req)
 
-	hitRanks, missRanks, missHosts, err := svc.resolveRanks(req.Hosts, req.Ranks)
+	hitRanks, missRanks, missHosts, err := svc.resolveRanks(req.Hosts, req.Ranks, req.StrictRanks)
 	if err != nil {
 		return nil, err
 	}
@@ -607,6 +655,10 @@ func populateStopResp(fanResp *fanoutResponse, pbResp *mgmtpb.SystemStopResp, ac
 // issued to each rank and the second phase stops the running executable
 // processes associated with each rank.
 //
+// Both phases share a single deadline. When both are requested, prep shutdown
+// is limited to a portion of the time available so that the stop phase can
+// still run with whatever time remains.
+//
 // This control service method is triggered from the control API method of the
 // same name in lib/control/system.go and returns results from all selected ranks.
 func (svc *mgmtSvc) SystemStop(ctx context.Context, pbReq *mgmtpb.SystemStopReq) (*mgmtpb.SystemStopResp, error) {
@@ -636,14 +688,26 @@ func (svc *mgmtSvc) SystemStop(ctx context.Context, pbReq *mgmtpb.SystemStopReq)
 	pbResp := new(mgmtpb.SystemStopResp)
 
 	fanReq := fanoutRequest{
-		Hosts: pbReq.GetHosts(),
-		Ranks: pbReq.GetRanks(),
-		Force: pbReq.GetForce(),
+		Hosts:       pbReq.GetHosts(),
+		Ranks:       pbReq.GetRanks(),
+		Force:       pbReq.GetForce(),
+		StrictRanks: pbReq.GetStrictRanks(),
 	}
 
+	// prep shutdown and stop phases share a single overall deadline, a
+	// slow prep shutdown must not consume the time needed to stop ranks
+	ctx, cancel := context.WithTimeout(ctx, systemReqTimeout)
+	defer cancel()
+
 	if pbReq.GetPrep() {
+		prepCtx := ctx
+		if pbReq.GetKill() {
+			var prepCancel context.CancelFunc
+			prepCtx, prepCancel = prepShutdownContext(ctx)
+			defer prepCancel()
+		}
 		fanReq.Method = control.PrepShutdownRanks
-		fanResp, _, err := svc.rpcFanout(ctx, fanReq, false)
+		fanResp, _, err := svc.rpcFanout(prepCtx, fanReq, false)
 		if err != nil {
 			return nil, err
 		}
@@ -699,9 +763,10 @@ func (svc *mgmtSvc) SystemStart(ctx context.Context, pbReq *mgmtpb.SystemStartRe
 	// }
 
 	fanResp, _, err := svc.rpcFanout(ctx, fanoutRequest{
-		Method: control.StartRanks,
-		Hosts:  pbReq.GetHosts(),
-		Ranks:  pbReq.GetRanks(),
+		Method:      control.StartRanks,
+		Hosts:       pbReq.GetHosts(),
+		Ranks:       pbReq.GetRanks(),
+		StrictRanks: pbReq.GetStrictRanks(),
 	}, true)
 	if err != nil {
 		return nil, err
@@ -799,6 +864,7 @@ func (svc *mgmtSvc) SystemErase(ctx context.Context, pbReq *mgmtpb.SystemEraseRe
 	// reformat by wiping out their engine superblocks, etc.
 	fanResp, _, err := svc.rpcFanout(ctx, fanoutRequest{
 		Method: control.ResetFormatRanks,
+		Force:  true, // erase is an explicit request to discard data
 	}, false)
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"sort"
 	"strings"
@@ -751,6 +752,73 @@ func TestServer_MgmtSvc_rpcFanout(t *testing.T) {
 			expRanks:       "0-5",
 			expAbsentHosts: "10.0.0.5",
 		},
+		"ranks filtered by state": {
+			fanReq: fanoutRequest{
+				Method: control.PingRanks, Ranks: "0-5",
+				StateFilter: system.MemberStateReady,
+			},
+			members: system.Members{
+				mockMember(t, 0, 1, msReady),
+				mockMember(t, 1, 1, "joined"),
+				mockMember(t, 2, 2, msStopped),
+				mockMember(t, 3, 2, msReady),
+				mockMember(t, 4, 3, msErrored),
+				mockMember(t, 5, 3, "joined"),
+			},
+			mResps: []*control.HostResponse{
+				{
+					Addr: common.MockHostAddr(1).String(),
+					Message: &mgmtpb.SystemStartResp{
+						Results: []*sharedpb.RankResult{
+							{Rank: 0, State: msReady},
+						},
+					},
+				},
+				{
+					Addr: common.MockHostAddr(2).String(),
+					Message: &mgmtpb.SystemStartResp{
+						Results: []*sharedpb.RankResult{
+							{Rank: 3, State: msReady},
+						},
+					},
+				},
+			},
+			// only ranks in ready state are acted on, other ranks
+			// are neither absent nor updated
+			expResults: system.MemberResults{
+				{
+					Rank: 0, Addr: common.MockHostAddr(1).String(),
+					State: system.MemberStateReady,
+				},
+				{
+					Rank: 3, Addr: common.MockHostAddr(2).String(),
+					State: system.MemberStateReady,
+				},
+			},
+			expMembers: system.Members{
+				mockMember(t, 0, 1, msReady),
+				mockMember(t, 1, 1, "joined"),
+				mockMember(t, 2, 2, msStopped),
+				mockMember(t, 3, 2, msReady),
+				mockMember(t, 4, 3, msErrored),
+				mockMember(t, 5, 3, "joined"),
+			},
+			expRanks: "0,3",
+		},
+		"no ranks in filtered state": {
+			fanReq: fanoutRequest{
+				Method:      control.PingRanks,
+				StateFilter: system.MemberStateReady,
+			},
+			members: system.Members{
+				mockMember(t, 0, 1, "joined"),
+				mockMember(t, 1, 1, msStopped),
+			},
+			expMembers: system.Members{
+				mockMember(t, 0, 1, "joined"),
+				mockMember(t, 1, 1, msStopped),
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(t.Name())
@@ -797,6 +865,7 @@ func TestServer_MgmtSvc_SystemQuery(t *testing.T) {
 		nilReq         bool
 		ranks          string
 		hosts          string
+		strictRanks    bool
 		expMembers     []*mgmtpb.SystemMember
 		expRanks       string
 		expAbsentHosts string
@@ -874,6 +943,29 @@ func TestServer_MgmtSvc_SystemQuery(t *testing.T) {
 			expRanks:       "0-5",
 			expAbsentRanks: "6-9",
 		},
+		"strict with non-existent ranks": {
+			ranks:       "0,2-3,6-9",
+			strictRanks: true,
+			expErrMsg:   "non-existent ranks 6-9",
+		},
+		"strict with existing ranks": {
+			ranks:       "4-5",
+			strictRanks: true,
+			expMembers: []*mgmtpb.SystemMember{
+				{
+					Rank: 4, Addr: common.MockHostAddr(3).String(),
+					Uuid:        common.MockUUID(4),
+					State:       stateString(system.MemberStateStarting),
+					FaultDomain: "/",
+				},
+				{
+					Rank: 5, Addr: common.MockHostAddr(3).String(),
+					Uuid:        common.MockUUID(5),
+					State:       stateString(system.MemberStateStopped),
+					FaultDomain: "/",
+				},
+			},
+		},
 		"filtered and oversubscribed hosts": {
 			hosts: "10.0.0.[2-5]",
 			expMembers: []*mgmtpb.SystemMember{
@@ -945,8 +1037,10 @@ func TestServer_MgmtSvc_SystemQuery(t *testing.T) {
 			}
 
 			req := &mgmtpb.SystemQueryReq{
-				Sys:   build.DefaultSystemName,
-				Ranks: tc.ranks, Hosts: tc.hosts,
+				Sys:         build.DefaultSystemName,
+				Ranks:       tc.ranks,
+				Hosts:       tc.hosts,
+				StrictRanks: tc.strictRanks,
 			}
 			if tc.nilReq {
 				req = nil
@@ -1620,6 +1714,88 @@ func TestServer_MgmtSvc_SystemStop(t *testing.T) {
 	}
 }
 
+// slowPrepInvoker records the time remaining before the deadline of each
+// request and holds the first request until just before its deadline.
+type slowPrepInvoker struct {
+	*control.MockInvoker
+	deadlines []time.Time
+	remaining []time.Duration
+}
+
+func (spi *slowPrepInvoker) InvokeUnaryRPC(ctx context.Context, req control.UnaryRequest) (*control.UnaryResponse, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, errors.New("request has no deadline")
+	}
+	spi.deadlines = append(spi.deadlines, deadline)
+	spi.remaining = append(spi.remaining, time.Until(deadline))
+
+	if len(spi.deadlines) == 1 {
+		time.Sleep(time.Until(deadline) - 50*time.Millisecond)
+	}
+
+	return spi.MockInvoker.InvokeUnaryRPC(ctx, req)
+}
+
+func TestServer_MgmtSvc_SystemStop_SharedDeadline(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	members := system.Members{
+		mockMember(t, 0, 1, "joined"),
+		mockMember(t, 1, 1, "joined"),
+	}
+	mResps := []*control.HostResponse{
+		{
+			Addr: common.MockHostAddr(1).String(),
+			Message: &mgmtpb.SystemStopResp{
+				Results: []*sharedpb.RankResult{
+					{Rank: 0, State: stateString(system.MemberStateStopped)},
+					{Rank: 1, State: stateString(system.MemberStateStopped)},
+				},
+			},
+		},
+	}
+	svc := mgmtSystemTestSetup(t, log, members, mResps)
+	spi := &slowPrepInvoker{MockInvoker: svc.rpcClient.(*control.MockInvoker)}
+	svc.rpcClient = spi
+
+	budget := time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+	overall, _ := ctx.Deadline()
+
+	req := &mgmtpb.SystemStopReq{Prep: true, Kill: true, Sys: build.DefaultSystemName}
+	gotResp, gotErr := svc.SystemStop(ctx, req)
+	if gotErr != nil {
+		t.Fatal(gotErr)
+	}
+
+	// prep shutdown consumed its share of the budget and stop still ran
+	// with the time remaining before the overall deadline
+	common.AssertEqual(t, 2, len(spi.deadlines), "number of fanout requests")
+	common.AssertTrue(t, spi.deadlines[0].Before(overall),
+		"prep shutdown deadline should leave time for stop")
+	common.AssertTrue(t, spi.deadlines[1].Equal(overall),
+		"stop deadline should be the overall deadline")
+	common.AssertTrue(t, spi.remaining[1] > 0 && spi.remaining[1] < budget/2,
+		fmt.Sprintf("unexpected stop phase budget %s", spi.remaining[1]))
+
+	expResults := []*sharedpb.RankResult{
+		{
+			Rank: 0, Action: "stop", Addr: common.MockHostAddr(1).String(),
+			State: stateString(system.MemberStateStopped),
+		},
+		{
+			Rank: 1, Action: "stop", Addr: common.MockHostAddr(1).String(),
+			State: stateString(system.MemberStateStopped),
+		},
+	}
+	if diff := cmp.Diff(expResults, gotResp.Results, common.DefaultCmpOpts()...); diff != "" {
+		t.Fatalf("unexpected results (-want, +got)\n%s\n", diff)
+	}
+}
+
 func TestServer_MgmtSvc_SystemErase(t *testing.T) {
 	for name, tc := range map[string]struct {
 		nilReq         bool
@@ -82,6 +82,9 @@ type server struct {
 
 func newServer(ctx context.Context, log *logging.LeveledLogger, cfg *config.Server, faultDomain *system.FaultDomain) (*server, error) {
 	harness := NewEngineHarness(log).WithFaultDomain(faultDomain)
+	if len(cfg.LeaderRanks) > 0 {
+		harness.WithRankStartPolicy(LeaderRanksFirst(cfg.LeaderRanks...))
+	}
 
 	// Create storage subsystem providers.
 	scmProvider := scm.DefaultProvider(log)
@@ -139,7 +142,7 @@ func (srv *server) createServices(ctx context.Context) error {
 	srv.evtLogger = control.NewEventLogger(srv.log)
 
 	srv.ctlSvc = NewControlService(srv.log, srv.harness, srv.bdevProvider, srv.scmProvider,
-		srv.cfg, srv.pubSub)
+		srv.cfg, srv.pubSub, srv.membership)
 
 	srv.mgmtSvc = newMgmtSvc(srv.harness, srv.membership, sysdb, rpcClient, srv.pubSub)
 
@@ -185,7 +188,7 @@ func (srv *server) initNetwork(ctx context.Context) error {
 	return nil
 }
 
-func (srv *server) initStorage() error {
+func (srv *server) initStorage(ctx context.Context) error {
 	defer srv.logDuration(track("time to init storage"))
 
 	runningUser, err := user.Current()
@@ -197,7 +200,7 @@ func (srv *server) initStorage() error {
 		return err
 	}
 
-	return srv.ctlSvc.Setup()
+	return srv.ctlSvc.SetupContext(ctx)
 }
 
 func (srv *server) createEngine(ctx context.Context, idx int, cfg *engine.Config) (*EngineInstance, error) {
@@ -356,7 +359,7 @@ func Start(log *logging.LeveledLogger, cfg *config.Server) error {
 		return err
 	}
 
-	if err := srv.initStorage(); err != nil {
+	if err := srv.initStorage(ctx); err != nil {
 		return err
 	}
 
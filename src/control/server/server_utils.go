@@ -297,11 +297,18 @@ func configureFirstEngine(ctx context.Context, engine *EngineInstance, sysdb *sy
 // registerTelemetryCallbacks sets telemetry related callbacks to
 // be triggered when all engines have been started.
 func registerTelemetryCallbacks(ctx context.Context, srv *server) {
+	srv.OnEnginesStarted(func(ctxIn context.Context) error {
+		startTelemetryEventCollector(ctxIn, srv)
+		return nil
+	})
+
 	telemPort := srv.cfg.TelemetryPort
 	if telemPort == 0 {
 		return
 	}
 
+	regPromScanMetrics(srv.log, &srv.ctlSvc.StorageControlService)
+
 	srv.OnEnginesStarted(func(ctxIn context.Context) error {
 		srv.log.Debug("starting Prometheus exporter")
 		cleanup, err := startPrometheusExporter(ctxIn, srv.log, telemPort, srv.harness.Instances())
@@ -143,6 +143,7 @@ func (b *spdkBackend) formatRespFromResults(results []*spdk.FormatResult) (*Form
 		DeviceResponses: make(DeviceFormatResponses),
 	}
 	resultMap := make(map[string]map[int]error)
+	skippedMap := make(map[string]int)
 
 	// build pci address to namespace errors map
 	for _, result := range results {
@@ -156,6 +157,9 @@ func (b *spdkBackend) formatRespFromResults(results []*spdk.FormatResult) (*Form
 		}
 
 		resultMap[result.CtrlrPCIAddr][int(result.NsID)] = result.Err
+		if result.Skipped && result.Err == nil {
+			skippedMap[result.CtrlrPCIAddr]++
+		}
 	}
 
 	// populate device responses for failed/formatted namespacess
@@ -190,6 +194,14 @@ func (b *spdkBackend) formatRespFromResults(results []*spdk.FormatResult) (*Form
 			continue
 		}
 
+		// devices holding a blobstore on every namespace are left as is
+		if skippedMap[addr] == len(all) {
+			b.log.Debugf("nvme device at %s already formatted", addr)
+			devResp.AlreadyFormatted = true
+			resp.DeviceResponses[addr] = devResp
+			continue
+		}
+
 		devResp.Formatted = true
 		resp.DeviceResponses[addr] = devResp
 	}
@@ -216,7 +228,7 @@ func (b *spdkBackend) formatNvme(req FormatRequest) (*FormatResponse, error) {
 		}
 	}()
 
-	results, err := b.binding.Format(b.log)
+	results, err := b.binding.Format(b.log, req.SkipFormatted)
 	if err != nil {
 		return nil, errors.Wrapf(err, "spdk format %v", req.DeviceList)
 	}
@@ -420,35 +432,48 @@ func (b *spdkBackend) PrepareReset() error {
 	return b.script.Reset()
 }
 
-func (b *spdkBackend) UpdateFirmware(pciAddr string, path string, slot int32) error {
+func (b *spdkBackend) ResetDevice(pciAddr string) error {
+	b.log.Debugf("provider backend reset device %s", pciAddr)
+	return b.script.ResetDevice(pciAddr)
+}
+
+// initController initializes the SPDK environment and verifies that the
+// controller with the given PCI address can be accessed. The returned function
+// restores output redirected during initialization.
+func (b *spdkBackend) initController(pciAddr string) (func(), error) {
 	if pciAddr == "" {
-		return FaultBadPCIAddr("")
+		return nil, FaultBadPCIAddr("")
 	}
 
 	restoreOutput, err := b.binding.init(b.log, &spdk.EnvOptions{
 		DisableVMD: b.IsVMDDisabled(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer restoreOutput()
 
 	cs, err := b.binding.Discover(b.log)
 	if err != nil {
-		return errors.Wrap(err, "failed to discover nvme")
+		restoreOutput()
+		return nil, errors.Wrap(err, "failed to discover nvme")
 	}
 
-	var found bool
 	for _, c := range cs {
 		if c.PciAddr == pciAddr {
-			found = true
-			break
+			return restoreOutput, nil
 		}
 	}
+	restoreOutput()
 
-	if !found {
-		return FaultPCIAddrNotFound(pciAddr)
+	return nil, FaultPCIAddrNotFound(pciAddr)
+}
+
+func (b *spdkBackend) UpdateFirmware(pciAddr string, path string, slot int32) error {
+	restoreOutput, err := b.initController(pciAddr)
+	if err != nil {
+		return err
 	}
+	defer restoreOutput()
 
 	if err := b.binding.Update(b.log, pciAddr, path, slot); err != nil {
 		return err
@@ -456,3 +481,23 @@ func (b *spdkBackend) UpdateFirmware(pciAddr string, path string, slot int32) er
 
 	return nil
 }
+
+func (b *spdkBackend) StartSelfTest(pciAddr string, code storage.NvmeSelfTestCode) error {
+	restoreOutput, err := b.initController(pciAddr)
+	if err != nil {
+		return err
+	}
+	defer restoreOutput()
+
+	return b.binding.SelfTest(b.log, pciAddr, code)
+}
+
+func (b *spdkBackend) QuerySelfTest(pciAddr string) (*storage.NvmeSelfTestStatus, error) {
+	restoreOutput, err := b.initController(pciAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer restoreOutput()
+
+	return b.binding.SelfTestLog(b.log, pciAddr)
+}
@@ -14,7 +14,6 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
@@ -27,7 +26,7 @@ import (
 func defCmpOpts() []cmp.Option {
 	return []cmp.Option{
 		// ignore these fields on most tests, as they are intentionally not stable
-		cmpopts.IgnoreFields(storage.NvmeController{}, "HealthStats", "Serial"),
+		storage.NvmeControllerCmpOpt("HealthStats", "Serial"),
 	}
 }
 
@@ -305,6 +304,50 @@ func TestBdev_Backend_Format(t *testing.T) {
 				},
 			},
 		},
+		"skip formatted namespaces": {
+			mnc: spdk.MockNvmeCfg{
+				FormatRes: []*spdk.FormatResult{
+					{CtrlrPCIAddr: pci1, NsID: 1, Skipped: true},
+					{CtrlrPCIAddr: pci1, NsID: 2, Skipped: true},
+					{CtrlrPCIAddr: pci2, NsID: 1, Skipped: true},
+					{CtrlrPCIAddr: pci2, NsID: 2},
+				},
+			},
+			req: FormatRequest{
+				Class:         storage.BdevClassNvme,
+				DeviceList:    []string{pci1, pci2},
+				SkipFormatted: true,
+			},
+			expResp: &FormatResponse{
+				DeviceResponses: DeviceFormatResponses{
+					pci1: &DeviceFormatResponse{
+						AlreadyFormatted: true,
+					},
+					pci2: &DeviceFormatResponse{
+						Formatted: true,
+					},
+				},
+			},
+		},
+		"formatted namespaces not skipped unless requested": {
+			mnc: spdk.MockNvmeCfg{
+				FormatRes: []*spdk.FormatResult{
+					{CtrlrPCIAddr: pci1, NsID: 1, Skipped: true},
+					{CtrlrPCIAddr: pci1, NsID: 2, Skipped: true},
+				},
+			},
+			req: FormatRequest{
+				Class:      storage.BdevClassNvme,
+				DeviceList: []string{pci1},
+			},
+			expResp: &FormatResponse{
+				DeviceResponses: DeviceFormatResponses{
+					pci1: &DeviceFormatResponse{
+						Formatted: true,
+					},
+				},
+			},
+		},
 		"multiple namespaces on single controller failure": {
 			mnc: spdk.MockNvmeCfg{
 				FormatRes: []*spdk.FormatResult{
@@ -421,6 +464,100 @@ func TestBdev_Backend_Update(t *testing.T) {
 	}
 }
 
+func TestBdev_Backend_SelfTest(t *testing.T) {
+	numCtrlrs := 2
+	controllers := make(storage.NvmeControllers, 0, numCtrlrs)
+	for i := 0; i < numCtrlrs; i++ {
+		c := mockSpdkController(int32(i))
+		controllers = append(controllers, &c)
+	}
+	inProgress := &storage.NvmeSelfTestStatus{
+		PciAddr:    controllers[1].PciAddr,
+		Current:    storage.NvmeSelfTestShort,
+		Progress:   30,
+		LastResult: storage.NvmeSelfTestNoResult,
+	}
+
+	for name, tc := range map[string]struct {
+		pciAddr   string
+		mec       spdk.MockEnvCfg
+		mnc       spdk.MockNvmeCfg
+		expStatus *storage.NvmeSelfTestStatus
+		expErr    error
+	}{
+		"missing address": {
+			expErr: FaultBadPCIAddr(""),
+		},
+		"init failed": {
+			pciAddr: controllers[0].PciAddr,
+			mec: spdk.MockEnvCfg{
+				InitErr: errors.New("spdk init says no"),
+			},
+			mnc: spdk.MockNvmeCfg{
+				DiscoverCtrlrs: controllers,
+			},
+			expErr: errors.New("spdk init says no"),
+		},
+		"not found": {
+			pciAddr: "NotReal",
+			mnc: spdk.MockNvmeCfg{
+				DiscoverCtrlrs: controllers,
+			},
+			expErr: FaultPCIAddrNotFound("NotReal"),
+		},
+		"binding self-test fail": {
+			pciAddr: controllers[0].PciAddr,
+			mnc: spdk.MockNvmeCfg{
+				DiscoverCtrlrs: controllers,
+				SelfTestErr:    errors.New("spdk says no"),
+			},
+			expErr: errors.New("spdk says no"),
+		},
+		"binding self-test log fail": {
+			pciAddr: controllers[0].PciAddr,
+			mnc: spdk.MockNvmeCfg{
+				DiscoverCtrlrs: controllers,
+				SelfTestLogErr: errors.New("spdk says no"),
+			},
+			expErr: errors.New("spdk says no"),
+		},
+		"binding self-test success": {
+			pciAddr: controllers[1].PciAddr,
+			mnc: spdk.MockNvmeCfg{
+				DiscoverCtrlrs: controllers,
+				SelfTestStatus: inProgress,
+			},
+			expStatus: inProgress,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			b := backendWithMockBinding(log, tc.mec, tc.mnc)
+
+			// the log read failure only affects the query
+			gotErr := b.StartSelfTest(tc.pciAddr, storage.NvmeSelfTestShort)
+			if tc.mnc.SelfTestLogErr == nil {
+				common.CmpErr(t, tc.expErr, gotErr)
+				if gotErr != nil {
+					return
+				}
+			}
+
+			gotStatus, gotErr := b.QuerySelfTest(tc.pciAddr)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expStatus, gotStatus); diff != "" {
+				t.Fatalf("\nunexpected status (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
 type mockFileInfo struct {
 	name    string
 	size    int64
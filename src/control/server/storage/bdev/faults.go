@@ -70,6 +70,16 @@ func FaultFormatError(pciAddress string, err error) *fault.Fault {
 	)
 }
 
+// FaultResetError creates a Fault for the case where an attempted targeted reset
+// of a device failed.
+func FaultResetError(pciAddress string, err error) *fault.Fault {
+	return bdevFault(
+		code.BdevResetFailure,
+		fmt.Sprintf("NVMe reset failed on %q: %s", pciAddress, err),
+		"",
+	)
+}
+
 func bdevFault(code code.Code, desc, res string) *fault.Fault {
 	return &fault.Fault{
 		Domain:      "bdev",
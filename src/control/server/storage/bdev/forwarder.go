@@ -54,3 +54,25 @@ func (f *Forwarder) Format(req FormatRequest) (*FormatResponse, error) {
 
 	return res, nil
 }
+
+func (f *Forwarder) StartSelfTest(req SelfTestRequest) (*SelfTestResponse, error) {
+	req.Forwarded = true
+
+	res := new(SelfTestResponse)
+	if err := f.SendReq("BdevSelfTest", req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (f *Forwarder) QuerySelfTest(req SelfTestQueryRequest) (*SelfTestQueryResponse, error) {
+	req.Forwarded = true
+
+	res := new(SelfTestQueryResponse)
+	if err := f.SendReq("BdevSelfTestQuery", req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -8,23 +8,37 @@ package bdev
 
 import (
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/storage"
 )
 
 type (
 	MockBackendConfig struct {
 		PrepareResetErr error
+		ResetDeviceErrs map[string]error // keyed by PCI address
 		PrepareResp     *PrepareResponse
 		PrepareErr      error
+		PrepareWait     chan struct{} // if set, prepare blocks until closed
 		FormatRes       *FormatResponse
 		FormatErr       error
 		ScanRes         *ScanResponse
 		ScanErr         error
-		VmdEnabled      bool // set disabled by default
+		ScanWait        chan struct{} // if set, scan blocks until closed
+		VmdEnabled      bool          // set disabled by default
 		UpdateErr       error
+		SelfTestErr     error
+		// per-address self-test states returned by successive queries,
+		// the last state is repeated once all have been returned
+		SelfTestStates map[string][]*storage.NvmeSelfTestStatus
+		SelfTestErrs   map[string]error // query errors keyed by PCI address
 	}
 
 	MockBackend struct {
-		cfg MockBackendConfig
+		cfg              MockBackendConfig
+		ScanCalls        int
+		ScanReqs         []ScanRequest
+		ResetDeviceCalls []string
+		SelfTestCalls    []string
+		SelfTestCodes    []storage.NvmeSelfTestCode
 	}
 )
 
@@ -43,38 +57,71 @@ func DefaultMockBackend() *MockBackend {
 }
 
 func (mb *MockBackend) Scan(req ScanRequest) (*ScanResponse, error) {
+	mb.ScanCalls++
+	mb.ScanReqs = append(mb.ScanReqs, req)
+	if mb.cfg.ScanWait != nil {
+		<-mb.cfg.ScanWait
+	}
 	if mb.cfg.ScanRes == nil {
 		mb.cfg.ScanRes = new(ScanResponse)
 	}
 	// hack: filter based on request here because mock
 	// provider has forwarding disabled and filter is
 	// therefore skipped in test
-	_, resp := mb.cfg.ScanRes.filter(req.DeviceList...)
+	_, resp := mb.cfg.ScanRes.filterDevices(req)
 
 	return resp, mb.cfg.ScanErr
 }
 
+// Format returns the configured response. Devices configured as already
+// formatted are only reported as such if the request skips formatted devices,
+// otherwise they are reported as formatted.
 func (mb *MockBackend) Format(req FormatRequest) (*FormatResponse, error) {
 	if mb.cfg.FormatRes == nil {
 		mb.cfg.FormatRes = new(FormatResponse)
 	}
+	if req.SkipFormatted || mb.cfg.FormatRes.DeviceResponses == nil {
+		return mb.cfg.FormatRes, mb.cfg.FormatErr
+	}
+
+	resp := &FormatResponse{
+		DeviceResponses: make(DeviceFormatResponses),
+	}
+	for dev, devResp := range mb.cfg.FormatRes.DeviceResponses {
+		dr := *devResp
+		if dr.AlreadyFormatted {
+			dr.AlreadyFormatted = false
+			dr.Formatted = true
+		}
+		resp.DeviceResponses[dev] = &dr
+	}
 
-	return mb.cfg.FormatRes, mb.cfg.FormatErr
+	return resp, mb.cfg.FormatErr
 }
 
 func (mb *MockBackend) PrepareReset() error {
 	return mb.cfg.PrepareResetErr
 }
 
+func (mb *MockBackend) ResetDevice(pciAddr string) error {
+	mb.ResetDeviceCalls = append(mb.ResetDeviceCalls, pciAddr)
+	return mb.cfg.ResetDeviceErrs[pciAddr]
+}
+
 func (mb *MockBackend) Prepare(_ PrepareRequest) (*PrepareResponse, error) {
+	if mb.cfg.PrepareWait != nil {
+		<-mb.cfg.PrepareWait
+	}
 	if mb.cfg.PrepareErr != nil {
 		return nil, mb.cfg.PrepareErr
 	}
 	if mb.cfg.PrepareResp == nil {
 		return new(PrepareResponse), nil
 	}
+	// copy so that the provider doesn't modify the configured response
+	resp := *mb.cfg.PrepareResp
 
-	return mb.cfg.PrepareResp, nil
+	return &resp, nil
 }
 
 func (mb *MockBackend) DisableVMD() {
@@ -89,6 +136,31 @@ func (mb *MockBackend) UpdateFirmware(_ string, _ string, _ int32) error {
 	return mb.cfg.UpdateErr
 }
 
+func (mb *MockBackend) StartSelfTest(pciAddr string, code storage.NvmeSelfTestCode) error {
+	mb.SelfTestCalls = append(mb.SelfTestCalls, pciAddr)
+	mb.SelfTestCodes = append(mb.SelfTestCodes, code)
+	return mb.cfg.SelfTestErr
+}
+
+func (mb *MockBackend) QuerySelfTest(pciAddr string) (*storage.NvmeSelfTestStatus, error) {
+	if err := mb.cfg.SelfTestErrs[pciAddr]; err != nil {
+		return nil, err
+	}
+
+	states := mb.cfg.SelfTestStates[pciAddr]
+	if len(states) == 0 {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    pciAddr,
+			LastResult: storage.NvmeSelfTestNoResult,
+		}, nil
+	}
+	if len(states) > 1 {
+		mb.cfg.SelfTestStates[pciAddr] = states[1:]
+	}
+
+	return states[0], nil
+}
+
 func NewMockProvider(log logging.Logger, mbc *MockBackendConfig) *Provider {
 	return NewProvider(log, NewMockBackend(mbc)).WithForwardingDisabled()
 }
@@ -8,6 +8,7 @@ package bdev
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,18 @@ type (
 		DeviceList []string
 		DisableVMD bool
 		NoCache    bool
+		// PciFilter restricts results to controllers with a PCI
+		// address starting with the given prefix.
+		PciFilter string
+		// SocketID restricts results to controllers attached to the
+		// given NUMA socket, nil implies all sockets.
+		SocketID *int32
+		// ExpandVMD extends the DeviceList filter to also match the
+		// controllers behind any VMD addresses in the list.
+		ExpandVMD bool
+		// Backend selects how controllers are enumerated, the
+		// provider's configured backend is used by default.
+		Backend ScanBackend
 	}
 
 	// ScanResponse contains information gleaned during a successful Scan operation.
@@ -44,13 +57,39 @@ type (
 		ResetOnly             bool
 		DisableVFIO           bool
 		DisableVMD            bool
+		DryRun                bool
 	}
 
 	// PrepareResponse contains the results of a successful Prepare operation.
 	PrepareResponse struct {
 		VmdDetected bool
+		// PCIAddrs, Driver and HugePageCount are populated unless the
+		// request is a reset and hold the NVMe devices bound to the
+		// user-space driver, the name of that driver and the number of
+		// hugepages allocated. On dry-run they describe what would be
+		// done and empty PCIAddrs implies all devices that are not
+		// blocklisted.
+		PCIAddrs      []string
+		Driver        string
+		HugePageCount int
+		// DeviceResets is only populated on a targeted reset and holds
+		// the result for each PCI address in the request allowlist.
+		DeviceResets DeviceResetResponses
+		// HugePagesInUse is only populated by the caller following a
+		// full reset and holds the number of hugepages still in use,
+		// indicating pages allocated by a previous prepare have leaked.
+		HugePagesInUse int
 	}
 
+	// DeviceResetResponse contains device-specific targeted reset results.
+	DeviceResetResponse struct {
+		Reset bool
+		Error *fault.Fault
+	}
+
+	// DeviceResetResponses is a map of PCI addresses to targeted reset results.
+	DeviceResetResponses map[string]*DeviceResetResponse
+
 	// FormatRequest defines the parameters for a Format operation.
 	FormatRequest struct {
 		pbin.ForwardableRequest
@@ -58,6 +97,9 @@ type (
 		DeviceList []string
 		MemSize    int // size MiB memory to be used by SPDK proc
 		DisableVMD bool
+		// SkipFormatted leaves namespaces that already hold a blobstore
+		// untouched
+		SkipFormatted bool
 	}
 
 	// DeviceFormatRequest designs the parameters for a device-specific format.
@@ -69,8 +111,9 @@ type (
 
 	// DeviceFormatResponse contains device-specific Format operation results.
 	DeviceFormatResponse struct {
-		Formatted bool
-		Error     *fault.Fault
+		Formatted        bool
+		AlreadyFormatted bool // all namespaces skipped as already formatted
+		Error            *fault.Fault
 	}
 
 	// DeviceFormatResponses is a map of device identifiers to device Format results.
@@ -84,12 +127,15 @@ type (
 	// Backend defines a set of methods to be implemented by a Block Device backend.
 	Backend interface {
 		PrepareReset() error
+		ResetDevice(pciAddr string) error
 		Prepare(PrepareRequest) (*PrepareResponse, error)
 		Scan(ScanRequest) (*ScanResponse, error)
 		Format(FormatRequest) (*FormatResponse, error)
 		DisableVMD()
 		IsVMDDisabled() bool
 		UpdateFirmware(pciAddr string, path string, slot int32) error
+		StartSelfTest(pciAddr string, code storage.NvmeSelfTestCode) error
+		QuerySelfTest(pciAddr string) (*storage.NvmeSelfTestStatus, error)
 	}
 
 	// Provider encapsulates configuration and logic for interacting with a Block
@@ -101,6 +147,7 @@ type (
 		backend   Backend
 		fwd       *Forwarder
 		scanCache *ScanResponse
+		sysfsRoot string
 	}
 )
 
@@ -112,9 +159,10 @@ func DefaultProvider(log logging.Logger) *Provider {
 // NewProvider returns an initialized *Provider.
 func NewProvider(log logging.Logger, backend Backend) *Provider {
 	p := &Provider{
-		log:     log,
-		backend: backend,
-		fwd:     NewForwarder(log),
+		log:       log,
+		backend:   backend,
+		fwd:       NewForwarder(log),
+		sysfsRoot: defaultSysfsRoot,
 	}
 	p.setupFirmwareProvider(log)
 	return p
@@ -158,6 +206,60 @@ func (resp *ScanResponse) filter(pciFilter ...string) (int, *ScanResponse) {
 	return skipped, &ScanResponse{Controllers: out}
 }
 
+// filterDevices returns a response containing only controllers in the
+// request device list, along with the number of controllers skipped. If the
+// request sets ExpandVMD, controllers whose PCI domain matches the compressed
+// BDF of a listed VMD address are also kept.
+func (resp *ScanResponse) filterDevices(req ScanRequest) (int, *ScanResponse) {
+	if !req.ExpandVMD || len(req.DeviceList) == 0 {
+		return resp.filter(req.DeviceList...)
+	}
+
+	pciFilter := append([]string{}, req.DeviceList...)
+	vmdDomains := make(map[string]bool)
+	for _, addr := range req.DeviceList {
+		_, b, d, f, err := common.ParsePCIAddress(addr)
+		if err != nil {
+			continue
+		}
+		vmdDomains[fmt.Sprintf("%02x%02x%02x", b, d, f)] = true
+	}
+	for _, c := range resp.Controllers {
+		domain, _, _, _, err := common.ParsePCIAddress(c.PciAddr)
+		if err != nil {
+			continue
+		}
+		if vmdDomains[fmt.Sprintf("%x", domain)] {
+			pciFilter = append(pciFilter, c.PciAddr)
+		}
+	}
+
+	return resp.filter(pciFilter...)
+}
+
+// filterLocality returns a response containing only controllers matching
+// the PCI address prefix and socket ID specified in the request, along with
+// the number of controllers skipped.
+func (resp *ScanResponse) filterLocality(req ScanRequest) (int, *ScanResponse) {
+	var skipped int
+	out := make(storage.NvmeControllers, 0)
+
+	if req.PciFilter == "" && req.SocketID == nil {
+		return skipped, &ScanResponse{Controllers: resp.Controllers}
+	}
+
+	for _, c := range resp.Controllers {
+		if !strings.HasPrefix(c.PciAddr, req.PciFilter) ||
+			(req.SocketID != nil && c.SocketID != *req.SocketID) {
+			skipped++
+			continue
+		}
+		out = append(out, c)
+	}
+
+	return skipped, &ScanResponse{Controllers: out}
+}
+
 type scanFwdFn func(ScanRequest) (*ScanResponse, error)
 
 func forwardScan(req ScanRequest, cache *ScanResponse, scan scanFwdFn) (msg string, resp *ScanResponse, update bool, err error) {
@@ -191,7 +293,7 @@ func forwardScan(req ScanRequest, cache *ScanResponse, scan scanFwdFn) (msg stri
 	msg += fmt.Sprintf(" (%d", len(resp.Controllers))
 	if len(req.DeviceList) != 0 && len(resp.Controllers) != 0 {
 		var num int
-		num, resp = resp.filter(req.DeviceList...)
+		num, resp = resp.filterDevices(req)
 		if num != 0 {
 			msg += fmt.Sprintf("-%d filtered", num)
 		}
@@ -202,24 +304,63 @@ func forwardScan(req ScanRequest, cache *ScanResponse, scan scanFwdFn) (msg stri
 	return
 }
 
+// scanSysfs enumerates controllers from sysfs. No privileges are required so
+// the request is never forwarded and results are not cached.
+func (p *Provider) scanSysfs(req ScanRequest) (*ScanResponse, error) {
+	resp, err := sysfsScan(p.sysfsRoot)
+	if err != nil {
+		return nil, err
+	}
+	p.log.Debugf("bdev scan: sysfs (%d devices)", len(resp.Controllers))
+
+	if len(req.DeviceList) != 0 {
+		_, resp = resp.filterDevices(req)
+	}
+	_, resp = resp.filterLocality(req)
+
+	return resp, nil
+}
+
 // Scan attempts to perform a scan to discover NVMe components in the
 // system. Results will be cached at the provider and returned if
 // "NoCache" is set to "false" in the request. Returned results will be
-// filtered by request "DeviceList" and empty filter implies allowing all.
+// filtered by request "DeviceList", "PciFilter" and "SocketID" and empty
+// filters imply allowing all.
+//
+// If the sysfs backend is selected in the request, only controllers bound to
+// the kernel NVMe driver are returned with a subset of details populated.
 func (p *Provider) Scan(req ScanRequest) (resp *ScanResponse, err error) {
+	switch req.Backend {
+	case ScanBackendDefault:
+	case ScanBackendSysfs:
+		return p.scanSysfs(req)
+	default:
+		return nil, errors.Errorf("unsupported bdev scan backend %d", req.Backend)
+	}
+
 	if p.shouldForward(req) {
 		req.DisableVMD = p.IsVMDDisabled()
 
 		p.Lock()
 		defer p.Unlock()
 
-		msg, resp, update, err := forwardScan(req, p.scanCache, p.fwd.Scan)
+		// locality filters are applied locally so that cached results
+		// remain complete
+		fwdReq := req
+		fwdReq.PciFilter = ""
+		fwdReq.SocketID = nil
+
+		msg, resp, update, err := forwardScan(fwdReq, p.scanCache, p.fwd.Scan)
 		p.log.Debug(msg)
 		if update {
 			p.scanCache = resp
 		}
+		if err != nil {
+			return nil, err
+		}
 
-		return resp, err
+		_, resp = resp.filterLocality(req)
+		return resp, nil
 	}
 
 	// set vmd state on remote provider in forwarded request
@@ -227,12 +368,92 @@ func (p *Provider) Scan(req ScanRequest) (resp *ScanResponse, err error) {
 		p.disableVMD()
 	}
 
-	return p.backend.Scan(req)
+	resp, err = p.backend.Scan(req)
+	if err != nil || resp == nil {
+		return
+	}
+
+	_, resp = resp.filterLocality(req)
+	return
+}
+
+// prepareDriver returns the name of the driver that a Prepare operation binds
+// NVMe devices to.
+func prepareDriver(req PrepareRequest) string {
+	if req.DisableVFIO {
+		return vfioDisabledDriver
+	}
+	return vfioDriver
+}
+
+// prepareHugePageCount returns the number of hugepages that a Prepare
+// operation allocates.
+func prepareHugePageCount(req PrepareRequest) int {
+	if req.HugePageCount <= 0 {
+		return defaultNrHugepages
+	}
+	return req.HugePageCount
+}
+
+// prepareDryRun returns a response describing the actions that would be
+// performed by a Prepare operation without making any changes.
+func prepareDryRun(req PrepareRequest) *PrepareResponse {
+	resp := new(PrepareResponse)
+	if req.ResetOnly {
+		return resp
+	}
+
+	resp.Driver = prepareDriver(req)
+	resp.HugePageCount = prepareHugePageCount(req)
+
+	blocked := strings.Fields(req.PCIBlocklist)
+	for _, addr := range strings.Fields(req.PCIAllowlist) {
+		if common.Includes(blocked, addr) {
+			continue
+		}
+		resp.PCIAddrs = append(resp.PCIAddrs, addr)
+	}
+
+	return resp
+}
+
+// resetDevices returns each of the given controllers to the kernel driver in
+// turn, recording a result per address so that an invalid address or failure
+// on one device does not prevent the others being reset.
+func (p *Provider) resetDevices(addrs []string) *PrepareResponse {
+	resp := &PrepareResponse{
+		DeviceResets: make(DeviceResetResponses),
+	}
+
+	for _, addr := range addrs {
+		result := new(DeviceResetResponse)
+		resp.DeviceResets[addr] = result
+
+		if _, _, _, _, err := common.ParsePCIAddress(addr); err != nil {
+			result.Error = FaultBadPCIAddr(addr)
+			continue
+		}
+		if err := p.backend.ResetDevice(addr); err != nil {
+			result.Error = FaultResetError(addr, err)
+			continue
+		}
+		result.Reset = true
+	}
+
+	return resp
 }
 
 // Prepare attempts to perform all actions necessary to make NVMe
-// components available for use by DAOS.
+// components available for use by DAOS. If DryRun is set in the request,
+// the planned actions are returned and no changes are made.
+//
+// If ResetOnly is set along with a PCI allowlist, only the listed devices
+// are returned to the kernel driver and results are reported per device.
 func (p *Provider) Prepare(req PrepareRequest) (*PrepareResponse, error) {
+	if req.DryRun {
+		return prepareDryRun(req), nil
+	}
+
 	if p.shouldForward(req) {
 		resp, err := p.fwd.Prepare(req)
 		// set vmd state on local provider after forwarding request
@@ -243,6 +464,10 @@ func (p *Provider) Prepare(req PrepareRequest) (*PrepareResponse, error) {
 		return resp, err
 	}
 
+	if req.ResetOnly && req.PCIAllowlist != "" {
+		return p.resetDevices(strings.Fields(req.PCIAllowlist)), nil
+	}
+
 	// run reset first to ensure reallocation of hugepages
 	if err := p.backend.PrepareReset(); err != nil {
 		return nil, errors.Wrap(err, "bdev prepare reset")
@@ -254,7 +479,20 @@ func (p *Provider) Prepare(req PrepareRequest) (*PrepareResponse, error) {
 		return resp, nil
 	}
 
-	return p.backend.Prepare(req)
+	resp, err := p.backend.Prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Driver = prepareDriver(req)
+	resp.HugePageCount = prepareHugePageCount(req)
+	resp.PCIAddrs, err = sysfsBoundDevices(p.sysfsRoot, resp.Driver)
+	if err != nil {
+		// devices have been bound so don't fail the prepare
+		p.log.Debugf("listing devices bound to %s: %s", resp.Driver, err)
+	}
+
+	return resp, nil
 }
 
 // Format attempts to initialize NVMe devices for use by DAOS.
@@ -78,6 +78,118 @@ func TestBdev_ScanResponse_filter(t *testing.T) {
 	}
 }
 
+func TestBdev_ScanResponse_filterDevices(t *testing.T) {
+	vmdAddr := "0000:5d:05.5"
+	mkCtrlrs := func(addrs ...string) storage.NvmeControllers {
+		ctrlrs := make(storage.NvmeControllers, len(addrs))
+		for i, addr := range addrs {
+			ctrlrs[i] = &storage.NvmeController{PciAddr: addr}
+		}
+		return ctrlrs
+	}
+	scanResp := &ScanResponse{
+		Controllers: mkCtrlrs("0000:8a:00.0", "5d0505:01:00.0", "0000:8b:00.0", "5d0505:03:00.0"),
+	}
+
+	for name, tc := range map[string]struct {
+		req     ScanRequest
+		expResp *ScanResponse
+		expNum  int
+	}{
+		"no filter": {
+			req:     ScanRequest{ExpandVMD: true},
+			expResp: scanResp,
+		},
+		"vmd not expanded": {
+			req:     ScanRequest{DeviceList: []string{"0000:8a:00.0", vmdAddr}},
+			expResp: &ScanResponse{Controllers: mkCtrlrs("0000:8a:00.0")},
+			expNum:  3,
+		},
+		"vmd expanded": {
+			req: ScanRequest{
+				DeviceList: []string{"0000:8a:00.0", vmdAddr},
+				ExpandVMD:  true,
+			},
+			expResp: &ScanResponse{
+				Controllers: mkCtrlrs("0000:8a:00.0", "5d0505:01:00.0", "5d0505:03:00.0"),
+			},
+			expNum: 1,
+		},
+		"vmd expanded no backing devices": {
+			req: ScanRequest{
+				DeviceList: []string{"0000:d7:05.5"},
+				ExpandVMD:  true,
+			},
+			expResp: &ScanResponse{Controllers: storage.NvmeControllers{}},
+			expNum:  4,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotNum, gotResp := scanResp.filterDevices(tc.req)
+
+			common.AssertEqual(t, tc.expNum, gotNum, name+" expected number filtered")
+			if diff := cmp.Diff(tc.expResp, gotResp, defCmpOpts()...); diff != "" {
+				t.Fatalf("\nunexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestBdev_ScanResponse_filterLocality(t *testing.T) {
+	// mock controllers alternate between sockets 0 and 1
+	ctrlrs := storage.MockNvmeControllers(4)
+	ctrlrs[3].PciAddr = "0000:81:00.3"
+	socket0 := int32(0)
+	socket1 := int32(1)
+	socket2 := int32(2)
+
+	for name, tc := range map[string]struct {
+		req     ScanRequest
+		expResp *ScanResponse
+		expNum  int
+	}{
+		"no filter": {
+			expResp: &ScanResponse{Controllers: ctrlrs},
+		},
+		"socket filter": {
+			req: ScanRequest{SocketID: &socket1},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{ctrlrs[1], ctrlrs[3]},
+			},
+			expNum: 2,
+		},
+		"socket filter no match": {
+			req:     ScanRequest{SocketID: &socket2},
+			expResp: &ScanResponse{Controllers: storage.NvmeControllers{}},
+			expNum:  4,
+		},
+		"pci filter": {
+			req: ScanRequest{PciFilter: "0000:80"},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{ctrlrs[0], ctrlrs[1], ctrlrs[2]},
+			},
+			expNum: 1,
+		},
+		"pci and socket filter": {
+			req: ScanRequest{PciFilter: "0000:80", SocketID: &socket0},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{ctrlrs[0], ctrlrs[2]},
+			},
+			expNum: 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			scanResp := &ScanResponse{Controllers: ctrlrs}
+			gotNum, gotResp := scanResp.filterLocality(tc.req)
+
+			common.AssertEqual(t, tc.expNum, gotNum, name+" expected number filtered")
+			if diff := cmp.Diff(tc.expResp, gotResp, defCmpOpts()...); diff != "" {
+				t.Fatalf("\nunexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
 func TestBdev_forwardScan(t *testing.T) {
 	for name, tc := range map[string]struct {
 		scanReq      ScanRequest
@@ -271,6 +383,20 @@ func TestBdevScan(t *testing.T) {
 			},
 			expVMDDisabled: true,
 		},
+		"multiple devices filtered by pci prefix": {
+			req: ScanRequest{PciFilter: ctrlr2.PciAddr},
+			mbc: &MockBackendConfig{
+				ScanRes: &ScanResponse{
+					Controllers: storage.NvmeControllers{
+						ctrlr1, ctrlr2, ctrlr3,
+					},
+				},
+			},
+			expRes: &ScanResponse{
+				Controllers: storage.NvmeControllers{ctrlr2},
+			},
+			expVMDDisabled: true,
+		},
 		"failure": {
 			req: ScanRequest{},
 			mbc: &MockBackendConfig{
@@ -308,6 +434,7 @@ func TestBdevPrepare(t *testing.T) {
 		mbc           *MockBackendConfig
 		vmdDetectErr  error
 		expRes        *PrepareResponse
+		expResetCalls []string
 		expErr        error
 	}{
 		"reset fails": {
@@ -334,15 +461,126 @@ func TestBdevPrepare(t *testing.T) {
 			expErr: errors.New("prepare failed"),
 		},
 		"prepare succeeds": {
-			req:    PrepareRequest{},
+			req: PrepareRequest{},
+			mbc: &MockBackendConfig{
+				PrepareResp: &PrepareResponse{VmdDetected: true},
+			},
+			expRes: &PrepareResponse{
+				VmdDetected:   true,
+				PCIAddrs:      []string{"0000:80:00.0", "0000:81:00.0"},
+				Driver:        vfioDriver,
+				HugePageCount: defaultNrHugepages,
+			},
+		},
+		"prepare succeeds; vfio disabled": {
+			req: PrepareRequest{DisableVFIO: true, HugePageCount: 1024},
+			expRes: &PrepareResponse{
+				PCIAddrs:      []string{"0000:82:00.0"},
+				Driver:        vfioDisabledDriver,
+				HugePageCount: 1024,
+			},
+		},
+		"dry-run": {
+			req: PrepareRequest{
+				DryRun:        true,
+				HugePageCount: 1024,
+				PCIAllowlist:  "0000:80:00.0 0000:81:00.0",
+			},
+			mbc: &MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				PrepareErr:      errors.New("should not get this far"),
+			},
+			expRes: &PrepareResponse{
+				PCIAddrs:      []string{"0000:80:00.0", "0000:81:00.0"},
+				Driver:        vfioDriver,
+				HugePageCount: 1024,
+			},
+		},
+		"dry-run; default hugepages with blocklist": {
+			req: PrepareRequest{
+				DryRun:       true,
+				PCIAllowlist: "0000:80:00.0 0000:81:00.0",
+				PCIBlocklist: "0000:81:00.0",
+				DisableVFIO:  true,
+			},
+			mbc: &MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				PrepareErr:      errors.New("should not get this far"),
+			},
+			expRes: &PrepareResponse{
+				PCIAddrs:      []string{"0000:80:00.0"},
+				Driver:        vfioDisabledDriver,
+				HugePageCount: defaultNrHugepages,
+			},
+		},
+		"dry-run; reset-only": {
+			req: PrepareRequest{
+				DryRun:        true,
+				ResetOnly:     true,
+				HugePageCount: 1024,
+				PCIAllowlist:  "0000:80:00.0",
+			},
+			mbc: &MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				PrepareErr:      errors.New("should not get this far"),
+			},
 			expRes: &PrepareResponse{},
 		},
+		"targeted reset": {
+			req: PrepareRequest{
+				ResetOnly:    true,
+				PCIAllowlist: "0000:80:00.0 0000:81:00.0",
+			},
+			mbc: &MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+				ResetDeviceErrs: map[string]error{
+					"0000:81:00.0": errors.New("unbind failed"),
+				},
+			},
+			expRes: &PrepareResponse{
+				DeviceResets: DeviceResetResponses{
+					"0000:80:00.0": {Reset: true},
+					"0000:81:00.0": {
+						Error: FaultResetError("0000:81:00.0",
+							errors.New("unbind failed")),
+					},
+				},
+			},
+		},
+		"targeted reset; invalid address": {
+			req: PrepareRequest{
+				ResetOnly:    true,
+				PCIAllowlist: "0000:80:00.0 0000:8z:00.0 0000:82:00",
+			},
+			mbc: &MockBackendConfig{
+				PrepareResetErr: errors.New("should not get this far"),
+			},
+			expRes: &PrepareResponse{
+				DeviceResets: DeviceResetResponses{
+					"0000:80:00.0": {Reset: true},
+					"0000:8z:00.0": {Error: FaultBadPCIAddr("0000:8z:00.0")},
+					"0000:82:00":   {Error: FaultBadPCIAddr("0000:82:00")},
+				},
+			},
+			expResetCalls: []string{"0000:80:00.0"},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(name)
 			defer common.ShowBufferOnFailure(t, buf)
 
-			p := NewMockProvider(log, tc.mbc)
+			root, cleanup := common.CreateTestDir(t)
+			defer cleanup()
+			createMockSysfsPCI(t, root,
+				mockSysfsPCIDev{"0000:81:00.0", nvmePCIClass, vfioDriver},
+				mockSysfsPCIDev{"0000:80:00.0", nvmePCIClass, vfioDriver},
+				mockSysfsPCIDev{"0000:3d:00.0", "0x030000", vfioDriver},
+				mockSysfsPCIDev{"0000:82:00.0", nvmePCIClass, vfioDisabledDriver},
+			)
+
+			mb := NewMockBackend(tc.mbc)
+			p := NewProvider(log, mb).WithForwardingDisabled()
+			p.sysfsRoot = root
 
 			gotRes, gotErr := p.Prepare(tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
@@ -353,6 +591,12 @@ func TestBdevPrepare(t *testing.T) {
 			if diff := cmp.Diff(tc.expRes, gotRes); diff != "" {
 				t.Fatalf("\nunexpected response (-want, +got):\n%s\n", diff)
 			}
+
+			if tc.expResetCalls != nil {
+				if diff := cmp.Diff(tc.expResetCalls, mb.ResetDeviceCalls); diff != "" {
+					t.Fatalf("\nunexpected reset calls (-want, +got):\n%s\n", diff)
+				}
+			}
 		})
 	}
 }
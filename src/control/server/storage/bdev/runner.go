@@ -24,6 +24,7 @@ const (
 	pciAllowListEnv    = "_PCI_WHITELIST"
 	pciBlockListEnv    = "_PCI_BLACKLIST"
 	driverOverrideEnv  = "_DRIVER_OVERRIDE"
+	vfioDriver         = "vfio-pci"
 	vfioDisabledDriver = "uio_pci_generic"
 )
 
@@ -97,6 +98,21 @@ func (s *spdkSetupScript) Reset() error {
 	return errors.Wrapf(err, "spdk reset failed (%s)", out)
 }
 
+// ResetDevice executes setup script to return a single PCI device to its
+// previous driver binding, leaving other devices and hugepages untouched.
+//
+// NOTE: will make the controller reappear in /dev.
+func (s *spdkSetupScript) ResetDevice(pciAddr string) error {
+	env := []string{
+		fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
+		fmt.Sprintf("%s=%s", pciAllowListEnv, pciAddr),
+	}
+
+	s.log.Debugf("spdk reset env: %v", env)
+	out, err := s.runCmd(s.log, env, s.scriptPath, "reset")
+	return errors.Wrapf(err, "spdk reset of %s failed (%s)", pciAddr, out)
+}
+
 // Prepare executes setup script to allocate hugepages and unbind PCI devices
 // (that don't have active mountpoints) from generic kernel driver to be
 // used with SPDK. Either all PCI devices will be unbound by default if wlist
@@ -105,10 +121,7 @@ func (s *spdkSetupScript) Reset() error {
 //
 // NOTE: will make the controller disappear from /dev until reset() called.
 func (s *spdkSetupScript) Prepare(req PrepareRequest) error {
-	nrHugepages := req.HugePageCount
-	if nrHugepages <= 0 {
-		nrHugepages = defaultNrHugepages
-	}
+	nrHugepages := prepareHugePageCount(req)
 
 	env := []string{
 		fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
@@ -152,3 +152,52 @@ func TestBdev_Runner_Prepare(t *testing.T) {
 		})
 	}
 }
+
+func TestBdev_Runner_ResetDevice(t *testing.T) {
+	const testPciAddr = "0000:81:00.0"
+
+	for name, tc := range map[string]struct {
+		runErr  error
+		expArgs []string
+		expEnv  []string
+		expErr  error
+	}{
+		"reset fails": {
+			runErr: errors.New("unbind failed"),
+			expErr: errors.New("spdk reset of 0000:81:00.0 failed"),
+		},
+		"reset succeeds": {
+			expArgs: []string{"reset"},
+			expEnv: []string{
+				fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
+				fmt.Sprintf("%s=%s", pciAllowListEnv, testPciAddr),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			s := &spdkSetupScript{
+				log: log,
+				runCmd: func(log logging.Logger, env []string, cmdStr string, args ...string) (string, error) {
+					if tc.runErr != nil {
+						return "", tc.runErr
+					}
+
+					if diff := cmp.Diff(tc.expArgs, args); diff != "" {
+						t.Fatalf("\nunexpected cmd args (-want, +got):\n%s\n", diff)
+					}
+					if diff := cmp.Diff(tc.expEnv, env); diff != "" {
+						t.Fatalf("\nunexpected cmd env (-want, +got):\n%s\n", diff)
+					}
+
+					return "", nil
+				},
+			}
+
+			gotErr := s.ResetDevice(testPciAddr)
+			common.CmpErr(t, tc.expErr, gotErr)
+		})
+	}
+}
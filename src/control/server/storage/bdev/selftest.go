@@ -0,0 +1,85 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package bdev
+
+import (
+	"github.com/daos-stack/daos/src/control/pbin"
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+type (
+	// SelfTestRequest defines the parameters for starting a device
+	// self-test on an NVMe controller.
+	SelfTestRequest struct {
+		pbin.ForwardableRequest
+		PciAddr  string
+		Extended bool // run extended rather than short self-test
+	}
+
+	// SelfTestResponse contains the results of starting a device self-test.
+	SelfTestResponse struct {
+		Code storage.NvmeSelfTestCode
+	}
+
+	// SelfTestQueryRequest defines the parameters for querying the device
+	// self-test log of an NVMe controller.
+	SelfTestQueryRequest struct {
+		pbin.ForwardableRequest
+		PciAddr string
+	}
+
+	// SelfTestQueryResponse contains the results of a device self-test
+	// query.
+	SelfTestQueryResponse struct {
+		Status storage.NvmeSelfTestStatus
+	}
+)
+
+// StartSelfTest starts a short or extended device self-test on the NVMe
+// controller with the requested PCI address. The test runs in the background
+// on the device and its progress and result are retrieved with QuerySelfTest.
+func (p *Provider) StartSelfTest(req SelfTestRequest) (*SelfTestResponse, error) {
+	if p.shouldForward(req) {
+		return p.fwd.StartSelfTest(req)
+	}
+
+	if req.PciAddr == "" {
+		return nil, FaultBadPCIAddr("")
+	}
+
+	code := storage.NvmeSelfTestShort
+	if req.Extended {
+		code = storage.NvmeSelfTestExtended
+	}
+
+	p.log.Debugf("starting %s self-test on nvme controller %s", code, req.PciAddr)
+	if err := p.backend.StartSelfTest(req.PciAddr, code); err != nil {
+		return nil, err
+	}
+
+	return &SelfTestResponse{Code: code}, nil
+}
+
+// QuerySelfTest reads the progress of any device self-test running on the
+// NVMe controller with the requested PCI address along with the result of the
+// most recent completed test.
+func (p *Provider) QuerySelfTest(req SelfTestQueryRequest) (*SelfTestQueryResponse, error) {
+	if p.shouldForward(req) {
+		return p.fwd.QuerySelfTest(req)
+	}
+
+	if req.PciAddr == "" {
+		return nil, FaultBadPCIAddr("")
+	}
+
+	status, err := p.backend.QuerySelfTest(req.PciAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SelfTestQueryResponse{Status: *status}, nil
+}
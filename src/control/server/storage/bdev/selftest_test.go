@@ -0,0 +1,162 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package bdev
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+func TestBdevProvider_StartSelfTest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req      SelfTestRequest
+		bmbc     *MockBackendConfig
+		expCode  storage.NvmeSelfTestCode
+		expCalls []string
+		expErr   error
+	}{
+		"missing address": {
+			expErr: FaultBadPCIAddr(""),
+		},
+		"backend fails": {
+			req: SelfTestRequest{PciAddr: "0000:80:00.0"},
+			bmbc: &MockBackendConfig{
+				SelfTestErr: errors.New("device says no"),
+			},
+			expCalls: []string{"0000:80:00.0"},
+			expErr:   errors.New("device says no"),
+		},
+		"short": {
+			req:      SelfTestRequest{PciAddr: "0000:80:00.0"},
+			expCode:  storage.NvmeSelfTestShort,
+			expCalls: []string{"0000:80:00.0"},
+		},
+		"extended": {
+			req:      SelfTestRequest{PciAddr: "0000:80:00.0", Extended: true},
+			expCode:  storage.NvmeSelfTestExtended,
+			expCalls: []string{"0000:80:00.0"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mb := NewMockBackend(tc.bmbc)
+			p := NewProvider(log, mb).WithForwardingDisabled()
+
+			gotResp, gotErr := p.StartSelfTest(tc.req)
+			if diff := cmp.Diff(tc.expCalls, mb.SelfTestCalls); diff != "" {
+				t.Fatalf("unexpected backend calls (-want, +got):\n%s\n", diff)
+			}
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expCode, gotResp.Code, "self-test code")
+			common.AssertEqual(t, []storage.NvmeSelfTestCode{tc.expCode}, mb.SelfTestCodes,
+				"backend self-test codes")
+		})
+	}
+}
+
+func TestBdevProvider_QuerySelfTest(t *testing.T) {
+	pciAddr := "0000:80:00.0"
+	progress := func(pct uint32) *storage.NvmeSelfTestStatus {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    pciAddr,
+			Current:    storage.NvmeSelfTestShort,
+			Progress:   pct,
+			LastResult: storage.NvmeSelfTestNoResult,
+		}
+	}
+	completed := func(result storage.NvmeSelfTestResult) *storage.NvmeSelfTestStatus {
+		return &storage.NvmeSelfTestStatus{
+			PciAddr:    pciAddr,
+			LastCode:   storage.NvmeSelfTestShort,
+			LastResult: result,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		req       SelfTestQueryRequest
+		states    []*storage.NvmeSelfTestStatus
+		queryErr  error
+		expStates []*storage.NvmeSelfTestStatus
+		expErr    error
+	}{
+		"missing address": {
+			expErr: FaultBadPCIAddr(""),
+		},
+		"backend fails": {
+			req:      SelfTestQueryRequest{PciAddr: pciAddr},
+			queryErr: errors.New("device says no"),
+			expErr:   errors.New("device says no"),
+		},
+		"never run": {
+			req: SelfTestQueryRequest{PciAddr: pciAddr},
+			expStates: []*storage.NvmeSelfTestStatus{
+				{PciAddr: pciAddr, LastResult: storage.NvmeSelfTestNoResult},
+			},
+		},
+		"in progress then passed": {
+			req:    SelfTestQueryRequest{PciAddr: pciAddr},
+			states: []*storage.NvmeSelfTestStatus{progress(10), progress(60), completed(storage.NvmeSelfTestPassed)},
+			expStates: []*storage.NvmeSelfTestStatus{
+				progress(10), progress(60),
+				completed(storage.NvmeSelfTestPassed),
+				completed(storage.NvmeSelfTestPassed),
+			},
+		},
+		"in progress then failed": {
+			req:    SelfTestQueryRequest{PciAddr: pciAddr},
+			states: []*storage.NvmeSelfTestStatus{progress(90), completed(storage.NvmeSelfTestFailedSegment)},
+			expStates: []*storage.NvmeSelfTestStatus{
+				progress(90),
+				completed(storage.NvmeSelfTestFailedSegment),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			p := NewMockProvider(log, &MockBackendConfig{
+				SelfTestStates: map[string][]*storage.NvmeSelfTestStatus{
+					pciAddr: tc.states,
+				},
+				SelfTestErrs: map[string]error{
+					pciAddr: tc.queryErr,
+				},
+			})
+
+			if tc.expErr != nil {
+				_, gotErr := p.QuerySelfTest(tc.req)
+				common.CmpErr(t, tc.expErr, gotErr)
+				return
+			}
+
+			// poll the device until each expected state has been seen
+			for i, expState := range tc.expStates {
+				gotResp, gotErr := p.QuerySelfTest(tc.req)
+				if gotErr != nil {
+					t.Fatal(gotErr)
+				}
+
+				if diff := cmp.Diff(expState, &gotResp.Status); diff != "" {
+					t.Fatalf("unexpected status for query %d (-want, +got):\n%s\n", i, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package bdev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+const (
+	defaultSysfsRoot = "/sys"
+	nvmeClassDir     = "class/nvme"
+	pciDevicesDir    = "bus/pci/devices"
+	pciDriversDir    = "bus/pci/drivers"
+	nvmePCIClass     = "0x010802" // mass storage, non-volatile memory, NVMe
+)
+
+// ScanBackend selects the method used to enumerate NVMe controllers in a Scan
+// operation.
+type ScanBackend uint
+
+const (
+	// ScanBackendDefault scans with the provider's configured backend.
+	ScanBackendDefault ScanBackend = iota
+	// ScanBackendSysfs enumerates controllers bound to the kernel NVMe
+	// driver from sysfs without binding them to SPDK. Only the PCI
+	// address, model, serial, firmware revision and socket ID of each
+	// controller are populated.
+	ScanBackendSysfs
+)
+
+func (sb ScanBackend) String() string {
+	switch sb {
+	case ScanBackendDefault:
+		return "default"
+	case ScanBackendSysfs:
+		return "sysfs"
+	default:
+		return "unknown"
+	}
+}
+
+// readSysfsAttr returns the trimmed contents of the attribute file at path.
+func readSysfsAttr(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// sysfsController reads the details of the controller with the given class
+// directory. Devices without a NUMA affinity report a node of -1 and are
+// assigned to socket 0.
+func sysfsController(ctrlrDir string) (*storage.NvmeController, error) {
+	ctrlr := new(storage.NvmeController)
+	for _, attr := range []struct {
+		name string
+		dest *string
+	}{
+		{"address", &ctrlr.PciAddr},
+		{"model", &ctrlr.Model},
+		{"serial", &ctrlr.Serial},
+		{"firmware_rev", &ctrlr.FwRev},
+	} {
+		val, err := readSysfsAttr(filepath.Join(ctrlrDir, attr.name))
+		if err != nil {
+			return nil, err
+		}
+		*attr.dest = val
+	}
+
+	numaNode, err := readSysfsAttr(filepath.Join(ctrlrDir, "device", "numa_node"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if numaNode != "" {
+		socketID, err := strconv.ParseInt(numaNode, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing numa node %q", numaNode)
+		}
+		if socketID > 0 {
+			ctrlr.SocketID = int32(socketID)
+		}
+	}
+
+	return ctrlr, nil
+}
+
+// sysfsScan enumerates the NVMe controllers bound to the kernel driver under
+// the given sysfs root, sorted by PCI address. Controllers whose details can't
+// be read are skipped.
+func sysfsScan(root string) (*ScanResponse, error) {
+	classDir := filepath.Join(root, nvmeClassDir)
+
+	entries, err := ioutil.ReadDir(classDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ScanResponse{Controllers: storage.NvmeControllers{}}, nil
+		}
+		return nil, errors.Wrap(err, "reading nvme class directory")
+	}
+
+	ctrlrs := make(storage.NvmeControllers, 0, len(entries))
+	for _, entry := range entries {
+		// class entries are normally symlinks to the device directory
+		ctrlr, err := sysfsController(filepath.Join(classDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		ctrlrs = append(ctrlrs, ctrlr)
+	}
+	sort.Slice(ctrlrs, func(i, j int) bool { return ctrlrs[i].PciAddr < ctrlrs[j].PciAddr })
+
+	return &ScanResponse{Controllers: ctrlrs}, nil
+}
+
+// sysfsBoundDevices returns the PCI addresses of the NVMe devices bound to the
+// given driver under the given sysfs root, sorted by PCI address. Devices of
+// other classes bound to the driver are skipped.
+func sysfsBoundDevices(root, driver string) ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(root, pciDriversDir, driver))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, errors.Wrapf(err, "reading %s driver directory", driver)
+	}
+
+	addrs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// driver directories also hold control files such as bind
+		class, err := readSysfsAttr(filepath.Join(root, pciDevicesDir, entry.Name(), "class"))
+		if err != nil || class != nvmePCIClass {
+			continue
+		}
+		addrs = append(addrs, entry.Name())
+	}
+	sort.Strings(addrs)
+
+	return addrs, nil
+}
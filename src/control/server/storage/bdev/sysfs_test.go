@@ -0,0 +1,278 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package bdev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/storage"
+)
+
+type mockSysfsCtrlr struct {
+	name     string
+	attrs    map[string]string
+	numaNode string
+}
+
+func newMockSysfsCtrlr(name, pciAddr, numaNode string) mockSysfsCtrlr {
+	return mockSysfsCtrlr{
+		name: name,
+		attrs: map[string]string{
+			// sysfs pads some attributes with trailing spaces
+			"address":      pciAddr + "\n",
+			"model":        "INTEL SSDPE2KE016T8    \n",
+			"serial":       "PHLN" + name + "   \n",
+			"firmware_rev": "VDV10170\n",
+		},
+		numaNode: numaNode,
+	}
+}
+
+// createMockSysfs creates a tree under root mirroring the layout of sysfs,
+// where each NVMe class entry is a symlink to the controller device directory.
+func createMockSysfs(t *testing.T, root string, ctrlrs ...mockSysfsCtrlr) {
+	t.Helper()
+
+	classDir := filepath.Join(root, nvmeClassDir)
+	if err := os.MkdirAll(classDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range ctrlrs {
+		devDir := filepath.Join(root, "devices", c.name)
+		if err := os.MkdirAll(filepath.Join(devDir, "device"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, val := range c.attrs {
+			if err := ioutil.WriteFile(filepath.Join(devDir, name), []byte(val), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if c.numaNode != "" {
+			if err := ioutil.WriteFile(filepath.Join(devDir, "device", "numa_node"),
+				[]byte(c.numaNode+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Symlink(devDir, filepath.Join(classDir, c.name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type mockSysfsPCIDev struct {
+	pciAddr string
+	class   string
+	driver  string
+}
+
+// createMockSysfsPCI creates PCI device and driver directories under root
+// mirroring the layout of sysfs, where each driver entry is a symlink to the
+// device directory.
+func createMockSysfsPCI(t *testing.T, root string, devs ...mockSysfsPCIDev) {
+	t.Helper()
+
+	for _, d := range devs {
+		devDir := filepath.Join(root, pciDevicesDir, d.pciAddr)
+		drvDir := filepath.Join(root, pciDriversDir, d.driver)
+		for _, dir := range []string{devDir, drvDir} {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ioutil.WriteFile(filepath.Join(devDir, "class"), []byte(d.class+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(drvDir, "bind"), nil, 0200); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(devDir, filepath.Join(drvDir, d.pciAddr)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func sysfsCtrlr(name, pciAddr string, socketID int32) *storage.NvmeController {
+	return &storage.NvmeController{
+		PciAddr:  pciAddr,
+		Model:    "INTEL SSDPE2KE016T8",
+		Serial:   "PHLN" + name,
+		FwRev:    "VDV10170",
+		SocketID: socketID,
+	}
+}
+
+func TestBdev_sysfsScan(t *testing.T) {
+	noModel := newMockSysfsCtrlr("nvme3", "0000:d8:00.0", "1")
+	delete(noModel.attrs, "model")
+	badNuma := newMockSysfsCtrlr("nvme4", "0000:d9:00.0", "one")
+
+	for name, tc := range map[string]struct {
+		noClassDir bool
+		ctrlrs     []mockSysfsCtrlr
+		expResp    *ScanResponse
+	}{
+		"no nvme class": {
+			noClassDir: true,
+			expResp:    &ScanResponse{Controllers: storage.NvmeControllers{}},
+		},
+		"no controllers": {
+			expResp: &ScanResponse{Controllers: storage.NvmeControllers{}},
+		},
+		"controllers sorted by pci address": {
+			ctrlrs: []mockSysfsCtrlr{
+				newMockSysfsCtrlr("nvme0", "0000:81:00.0", "1"),
+				newMockSysfsCtrlr("nvme1", "0000:5e:00.0", "0"),
+			},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme1", "0000:5e:00.0", 0),
+					sysfsCtrlr("nvme0", "0000:81:00.0", 1),
+				},
+			},
+		},
+		"no numa affinity": {
+			ctrlrs: []mockSysfsCtrlr{
+				newMockSysfsCtrlr("nvme0", "0000:81:00.0", "-1"),
+				newMockSysfsCtrlr("nvme1", "0000:82:00.0", ""),
+			},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme0", "0000:81:00.0", 0),
+					sysfsCtrlr("nvme1", "0000:82:00.0", 0),
+				},
+			},
+		},
+		"unreadable controllers skipped": {
+			ctrlrs: []mockSysfsCtrlr{
+				newMockSysfsCtrlr("nvme0", "0000:81:00.0", "1"),
+				noModel,
+				badNuma,
+			},
+			expResp: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme0", "0000:81:00.0", 1),
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			root, cleanup := common.CreateTestDir(t)
+			defer cleanup()
+
+			if !tc.noClassDir {
+				createMockSysfs(t, root, tc.ctrlrs...)
+			}
+
+			gotResp, gotErr := sysfsScan(root)
+			if gotErr != nil {
+				t.Fatal(gotErr)
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp, storage.NvmeControllerCmpOpt()); diff != "" {
+				t.Fatalf("\nunexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestBdev_sysfsScan_NotDirectory(t *testing.T) {
+	root, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	if err := os.MkdirAll(filepath.Join(root, "class"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, nvmeClassDir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, gotErr := sysfsScan(root)
+	common.CmpErr(t, errors.New("reading nvme class directory"), gotErr)
+}
+
+func TestBdevScan_Sysfs(t *testing.T) {
+	socketID := int32(1)
+
+	for name, tc := range map[string]struct {
+		req    ScanRequest
+		expRes *ScanResponse
+		expErr error
+	}{
+		"all controllers": {
+			req: ScanRequest{Backend: ScanBackendSysfs},
+			expRes: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme1", "0000:5e:00.0", 0),
+					sysfsCtrlr("nvme0", "0000:81:00.0", 1),
+				},
+			},
+		},
+		"filtered by device list": {
+			req: ScanRequest{
+				Backend:    ScanBackendSysfs,
+				DeviceList: []string{"0000:5e:00.0"},
+			},
+			expRes: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme1", "0000:5e:00.0", 0),
+				},
+			},
+		},
+		"filtered by socket": {
+			req: ScanRequest{
+				Backend:  ScanBackendSysfs,
+				SocketID: &socketID,
+			},
+			expRes: &ScanResponse{
+				Controllers: storage.NvmeControllers{
+					sysfsCtrlr("nvme0", "0000:81:00.0", 1),
+				},
+			},
+		},
+		"unknown backend": {
+			req:    ScanRequest{Backend: ScanBackendSysfs + 1},
+			expErr: errors.New("unsupported bdev scan backend"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			root, cleanup := common.CreateTestDir(t)
+			defer cleanup()
+			createMockSysfs(t, root,
+				newMockSysfsCtrlr("nvme0", "0000:81:00.0", "1"),
+				newMockSysfsCtrlr("nvme1", "0000:5e:00.0", "0"),
+			)
+
+			// the backend scan isn't used when sysfs is selected
+			p := NewMockProvider(log, &MockBackendConfig{
+				ScanErr: errors.New("backend scan called"),
+			})
+			p.sysfsRoot = root
+
+			gotRes, gotErr := p.Scan(tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expRes, gotRes, storage.NvmeControllerCmpOpt()); diff != "" {
+				t.Fatalf("\nunexpected response (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
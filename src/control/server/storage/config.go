@@ -144,6 +144,7 @@ type BdevConfig struct {
 	VmdDisabled bool      `yaml:"-"` // set during start-up
 	DeviceCount int       `yaml:"bdev_number,omitempty"`
 	FileSize    int       `yaml:"bdev_size,omitempty"`
+	MinDevSize  int       `yaml:"bdev_min_size,omitempty"` // GB per NVMe SSD
 	MemSize     int       `yaml:"-" cmdLongFlag:"--mem_size,nonzero" cmdShortFlag:"-r,nonzero"`
 	VosEnv      string    `yaml:"-" cmdEnv:"VOS_BDEV_CLASS"`
 	Hostname    string    `yaml:"-"` // used when generating templates
@@ -163,6 +164,9 @@ func (bc *BdevConfig) Validate() error {
 	if common.StringSliceHasDuplicates(bc.DeviceList) {
 		return errors.New("bdev_list contains duplicate pci addresses")
 	}
+	if bc.MinDevSize < 0 {
+		return errors.New("bdev_min_size must not be negative")
+	}
 
 	switch bc.Class {
 	case BdevClassFile:
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/daos-stack/daos/src/control/common"
 )
@@ -100,6 +102,24 @@ func MockNvmeController(varIdx ...int32) *NvmeController {
 	}
 }
 
+// NvmeControllerCmpOpt returns a go-cmp option comparing every field of
+// NvmeController pointers other than the named ones. Tests should use it in
+// place of the identity-only NvmeController.Equal method which go-cmp would
+// otherwise call.
+func NvmeControllerCmpOpt(ignoreFields ...string) cmp.Option {
+	var opts []cmp.Option
+	if len(ignoreFields) > 0 {
+		opts = append(opts, cmpopts.IgnoreFields(NvmeController{}, ignoreFields...))
+	}
+
+	return cmp.Comparer(func(x, y *NvmeController) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return cmp.Equal(*x, *y, opts...)
+	})
+}
+
 // MockNvmeControllers returns slice of example NvmeController structs with
 // examples values.
 func MockNvmeControllers(length int) NvmeControllers {
@@ -148,6 +168,8 @@ func MockScmMountPoint(varIdx ...int32) *ScmMountPoint {
 		Path:       fmt.Sprintf("/mnt/daos%d", idx),
 		TotalBytes: uint64(humanize.TByte) * uint64(idx+1),
 		AvailBytes: uint64(humanize.TByte/4) * uint64(idx+1), // 75% used
+		UsedBytes:  uint64(humanize.TByte/4*3) * uint64(idx+1),
+		Mounted:    true,
 	}
 }
 
@@ -89,6 +89,7 @@ const (
 const (
 	cmdCreateNamespace  = "ndctl create-namespace"  // returns json ns info
 	cmdListNamespaces   = "ndctl list -N -v"        // returns json ns info
+	cmdListDimmHealth   = "ndctl list -D -H -M"     // returns json dimm health info
 	cmdDisableNamespace = "ndctl disable-namespace" // expect device name param
 	cmdDestroyNamespace = "ndctl destroy-namespace" // expect device name param
 )
@@ -152,6 +153,10 @@ func (cr *cmdRunner) listNamespaces() (string, error) {
 	return cr.runCmd(cmdListNamespaces)
 }
 
+func (cr *cmdRunner) listDimmHealth() (string, error) {
+	return cr.runCmd(cmdListDimmHealth)
+}
+
 func (cr *cmdRunner) disableNamespace(name string) (string, error) {
 	return cr.runCmd(fmt.Sprintf("%s %s", cmdDisableNamespace, name))
 }
@@ -516,6 +521,74 @@ func parseNamespaces(jsonData string) (nss storage.ScmNamespaces, err error) {
 	return
 }
 
+// GetModuleHealth calls ndctl to list the health of each SCM module and
+// returns it keyed by module UID. Modules that ndctl doesn't report health
+// for are omitted.
+func (cr *cmdRunner) GetModuleHealth() (map[string]*storage.ScmHealth, error) {
+	if err := cr.checkNdctl(); err != nil {
+		return nil, err
+	}
+
+	out, err := cr.listDimmHealth()
+	if err != nil {
+		return nil, errors.WithMessage(err, "list dimm health cmd")
+	}
+
+	return parseDimmHealth(out)
+}
+
+// ndctlDimm is the subset of `ndctl list -D -H -M` output for a DIMM that is
+// used to populate SCM module health. Each badblocks entry is a media error
+// range and badblock_count is the number of poisoned (uncorrectable) blocks.
+type ndctlDimm struct {
+	ID            string            `json:"id"`
+	Badblocks     []json.RawMessage `json:"badblocks"`
+	BadblockCount uint64            `json:"badblock_count"`
+	Health        *struct {
+		HealthState   string  `json:"health_state"`
+		LifeUsed      *uint32 `json:"life_used_percentage"`
+		ShutdownCount uint64  `json:"shutdown_count"`
+	} `json:"health"`
+}
+
+func parseDimmHealth(jsonData string) (map[string]*storage.ScmHealth, error) {
+	jsonData = strings.TrimSpace(jsonData)
+	if jsonData == "" {
+		return map[string]*storage.ScmHealth{}, nil
+	}
+	// turn single entries into arrays
+	if !strings.HasPrefix(jsonData, "[") {
+		jsonData = "[" + jsonData + "]"
+	}
+
+	var dimms []ndctlDimm
+	if err := json.Unmarshal([]byte(jsonData), &dimms); err != nil {
+		return nil, err
+	}
+
+	healths := make(map[string]*storage.ScmHealth)
+	for _, d := range dimms {
+		if d.ID == "" || d.Health == nil {
+			continue
+		}
+		sh := &storage.ScmHealth{
+			HealthState:         d.Health.HealthState,
+			MediaErrors:         uint64(len(d.Badblocks)),
+			UncorrectableErrors: d.BadblockCount,
+			UnsafeShutdowns:     d.Health.ShutdownCount,
+		}
+		switch {
+		case d.Health.LifeUsed == nil:
+			sh.LifeUnavailable = true
+		case *d.Health.LifeUsed <= 100:
+			sh.LifeRemaining = 100 - *d.Health.LifeUsed
+		}
+		healths[d.ID] = sh
+	}
+
+	return healths, nil
+}
+
 func defaultCmdRunner(log logging.Logger) *cmdRunner {
 	return newCmdRunner(log, &ipmctl.NvmMgmt{}, run, exec.LookPath)
 }
@@ -484,6 +484,164 @@ func TestIpmctl_GetPmemNamespaces(t *testing.T) {
 	}
 }
 
+func TestIpmctl_parseDimmHealth(t *testing.T) {
+	// template for `ndctl list -D -H -M` output
+	dimmTmpl := `{
+   "dev":"nmem%d",
+   "id":"8089-a2-1837-0000%04d",
+   "handle":%d,
+   "phys_id":%d,
+   "health":{
+     "health_state":"%s",
+     "temperature_celsius":25.0,
+     "controller_temperature_celsius":30.0,
+     "spares_percentage":100,
+     "alarm_temperature":false,
+     "alarm_controller_temperature":false,
+     "alarm_spares":false,
+     "life_used_percentage":%d,
+     "shutdown_state":"clean",
+     "shutdown_count":%d
+   },
+   "badblock_count":%d
+}`
+
+	for name, tc := range map[string]struct {
+		in        string
+		expHealth map[string]*storage.ScmHealth
+		expErr    error
+	}{
+		"empty": {
+			expHealth: map[string]*storage.ScmHealth{},
+		},
+		"single": {
+			in: fmt.Sprintf(dimmTmpl, 0, 0, 1, 4370, "ok", 3, 7, 0),
+			expHealth: map[string]*storage.ScmHealth{
+				"8089-a2-1837-00000000": {
+					HealthState:     "ok",
+					LifeRemaining:   97,
+					UnsafeShutdowns: 7,
+				},
+			},
+		},
+		"double": {
+			in: strings.Join([]string{
+				"[", fmt.Sprintf(dimmTmpl, 0, 0, 1, 4370, "ok", 0, 1, 0), ",",
+				fmt.Sprintf(dimmTmpl, 1, 1, 17, 4371, "critical", 95, 2, 12), "]"}, ""),
+			expHealth: map[string]*storage.ScmHealth{
+				"8089-a2-1837-00000000": {
+					HealthState:     "ok",
+					LifeRemaining:   100,
+					UnsafeShutdowns: 1,
+				},
+				"8089-a2-1837-00000001": {
+					HealthState:         "critical",
+					UncorrectableErrors: 12,
+					LifeRemaining:       5,
+					UnsafeShutdowns:     2,
+				},
+			},
+		},
+		"health unavailable": {
+			in:        `[{"dev":"nmem0","id":"8089-a2-1837-00000000","handle":1}]`,
+			expHealth: map[string]*storage.ScmHealth{},
+		},
+		"life used not reported": {
+			in: `{"dev":"nmem0","id":"8089-a2-1837-00000000","health":{"health_state":"non-critical"}}`,
+			expHealth: map[string]*storage.ScmHealth{
+				"8089-a2-1837-00000000": {
+					HealthState:     "non-critical",
+					LifeUnavailable: true,
+				},
+			},
+		},
+		"media errors": {
+			in: `{"dev":"nmem0","id":"8089-a2-1837-00000000",` +
+				`"health":{"health_state":"fatal","life_used_percentage":20},` +
+				`"badblock_count":9,"badblocks":[` +
+				`{"offset":8,"length":1,"dimms":["nmem0"]},` +
+				`{"offset":64,"length":8,"dimms":["nmem0"]}]}`,
+			expHealth: map[string]*storage.ScmHealth{
+				"8089-a2-1837-00000000": {
+					HealthState:         "fatal",
+					MediaErrors:         2,
+					UncorrectableErrors: 9,
+					LifeRemaining:       80,
+				},
+			},
+		},
+		"malformed": {
+			in:     `{"dev":"nmem0`,
+			expErr: errors.New("JSON input"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotHealth, gotErr := parseDimmHealth(tc.in)
+
+			CmpErr(t, tc.expErr, gotErr)
+			if diff := cmp.Diff(tc.expHealth, gotHealth); diff != "" {
+				t.Fatalf("unexpected health result (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestIpmctl_GetModuleHealth(t *testing.T) {
+	for name, tc := range map[string]struct {
+		lookPathErr error
+		runErr      error
+		cmdOut      string
+		expHealth   map[string]*storage.ScmHealth
+		expCommands []string
+		expErr      error
+	}{
+		"ndctl not installed": {
+			lookPathErr: errors.New("not found"),
+			expErr:      FaultMissingNdctl,
+		},
+		"list fails": {
+			runErr:      errors.New("failed"),
+			expCommands: []string{cmdListDimmHealth},
+			expErr:      errors.New("list dimm health cmd: failed"),
+		},
+		"success": {
+			cmdOut:      `[{"id":"Device0","health":{"health_state":"ok","life_used_percentage":10}}]`,
+			expCommands: []string{cmdListDimmHealth},
+			expHealth: map[string]*storage.ScmHealth{
+				"Device0": {
+					HealthState:   "ok",
+					LifeRemaining: 90,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer ShowBufferOnFailure(t, buf)
+
+			var commands []string
+			mockLookPath := func(string) (string, error) {
+				return "", tc.lookPathErr
+			}
+			mockRun := func(in string) (string, error) {
+				commands = append(commands, in)
+				return tc.cmdOut, tc.runErr
+			}
+
+			cr := newCmdRunner(log, newMockIpmctl(&mockIpmctlCfg{}), mockRun, mockLookPath)
+
+			gotHealth, gotErr := cr.GetModuleHealth()
+			CmpErr(t, tc.expErr, gotErr)
+			if diff := cmp.Diff(tc.expCommands, commands); diff != "" {
+				t.Fatalf("unexpected commands (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expHealth, gotHealth); diff != "" {
+				t.Fatalf("unexpected health result (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
 func TestIpmctl_Discover(t *testing.T) {
 	testDevices := []ipmctl.DeviceDiscovery{
 		MockDiscovery(),
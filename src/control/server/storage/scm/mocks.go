@@ -114,11 +114,14 @@ type MockBackendConfig struct {
 	GetPmemNamespaceRes  storage.ScmNamespaces
 	GetPmemNamespaceErr  error
 	GetPmemStateErr      error
+	GetModuleHealthRes   map[string]*storage.ScmHealth
+	GetModuleHealthErr   error
 	StartingState        storage.ScmState
 	NextState            storage.ScmState
 	PrepNeedsReboot      bool
 	PrepNamespaceRes     storage.ScmNamespaces
 	PrepErr              error
+	PrepWait             chan struct{} // if set, prep blocks until closed
 	GetFirmwareStatusErr error
 	GetFirmwareStatusRes *storage.ScmFirmwareInfo
 	UpdateFirmwareErr    error
@@ -126,11 +129,15 @@ type MockBackendConfig struct {
 
 type MockBackend struct {
 	sync.RWMutex
-	curState storage.ScmState
-	cfg      MockBackendConfig
+	curState      storage.ScmState
+	cfg           MockBackendConfig
+	DiscoverCalls int
 }
 
 func (mb *MockBackend) Discover() (storage.ScmModules, error) {
+	mb.Lock()
+	mb.DiscoverCalls++
+	mb.Unlock()
 	return mb.cfg.DiscoverRes, mb.cfg.DiscoverErr
 }
 
@@ -138,6 +145,10 @@ func (mb *MockBackend) GetPmemNamespaces() (storage.ScmNamespaces, error) {
 	return mb.cfg.GetPmemNamespaceRes, mb.cfg.GetPmemNamespaceErr
 }
 
+func (mb *MockBackend) GetModuleHealth() (map[string]*storage.ScmHealth, error) {
+	return mb.cfg.GetModuleHealthRes, mb.cfg.GetModuleHealthErr
+}
+
 func (mb *MockBackend) GetPmemState() (storage.ScmState, error) {
 	if mb.cfg.GetPmemStateErr != nil {
 		return storage.ScmStateUnknown, mb.cfg.GetPmemStateErr
@@ -148,6 +159,9 @@ func (mb *MockBackend) GetPmemState() (storage.ScmState, error) {
 }
 
 func (mb *MockBackend) Prep(_ storage.ScmState) (bool, storage.ScmNamespaces, error) {
+	if mb.cfg.PrepWait != nil {
+		<-mb.cfg.PrepWait
+	}
 	if mb.cfg.PrepErr == nil {
 		mb.Lock()
 		mb.curState = mb.cfg.NextState
@@ -157,6 +171,9 @@ func (mb *MockBackend) Prep(_ storage.ScmState) (bool, storage.ScmNamespaces, er
 }
 
 func (mb *MockBackend) PrepReset(_ storage.ScmState) (bool, error) {
+	if mb.cfg.PrepWait != nil {
+		<-mb.cfg.PrepWait
+	}
 	if mb.cfg.PrepErr == nil {
 		mb.Lock()
 		mb.curState = mb.cfg.NextState
@@ -129,6 +129,7 @@ type (
 		PrepReset(storage.ScmState) (bool, error)
 		GetPmemState() (storage.ScmState, error)
 		GetPmemNamespaces() (storage.ScmNamespaces, error)
+		GetModuleHealth() (map[string]*storage.ScmHealth, error)
 		GetFirmwareStatus(deviceUID string) (*storage.ScmFirmwareInfo, error)
 		UpdateFirmware(deviceUID string, firmwarePath string) error
 	}
@@ -193,6 +194,67 @@ func CreateFormatRequest(scmCfg storage.ScmConfig, reformat bool) (*FormatReques
 	return &req, nil
 }
 
+// PrepareAction describes the operation that a Prepare request would perform
+// given the current state of SCM.
+type PrepareAction int
+
+const (
+	// PrepareActionNone indicates that Prepare would not make any changes.
+	PrepareActionNone PrepareAction = iota
+	// PrepareActionCreateRegions indicates that Prepare would create
+	// AppDirect regions, which requires a reboot.
+	PrepareActionCreateRegions
+	// PrepareActionCreateNamespaces indicates that Prepare would create
+	// pmem namespaces in the free capacity of existing regions.
+	PrepareActionCreateNamespaces
+	// PrepareActionRemoveRegions indicates that Prepare would remove
+	// existing namespaces and regions, which requires a reboot.
+	PrepareActionRemoveRegions
+)
+
+func (pa PrepareAction) String() string {
+	switch pa {
+	case PrepareActionCreateRegions:
+		return "create regions (needs reboot)"
+	case PrepareActionCreateNamespaces:
+		return "create namespaces"
+	case PrepareActionRemoveRegions:
+		return "remove namespaces and regions (needs reboot)"
+	}
+	return "no-op"
+}
+
+// RebootRequired indicates whether the action needs a reboot to take effect.
+func (pa PrepareAction) RebootRequired() bool {
+	return pa == PrepareActionCreateRegions || pa == PrepareActionRemoveRegions
+}
+
+// GetPrepareAction returns the action that a Prepare request would perform
+// when SCM is in the given state, without performing it.
+func GetPrepareAction(state storage.ScmState, reset bool) (PrepareAction, error) {
+	switch state {
+	case storage.ScmStateNoRegions:
+		if reset {
+			return PrepareActionNone, nil
+		}
+		return PrepareActionCreateRegions, nil
+	case storage.ScmStateFreeCapacity:
+		if reset {
+			return PrepareActionRemoveRegions, nil
+		}
+		return PrepareActionCreateNamespaces, nil
+	case storage.ScmStateNoCapacity:
+		if reset {
+			return PrepareActionRemoveRegions, nil
+		}
+		return PrepareActionNone, nil
+	case storage.ScmStateUnknown:
+		return PrepareActionNone, errors.New("unknown scm state")
+	default:
+		return PrepareActionNone, errors.Errorf("unhandled scm state %q", state)
+	}
+}
+
 // Validate checks the request for validity.
 func (r FormatRequest) Validate() error {
 	if r.Mountpoint == "" {
@@ -426,6 +488,7 @@ func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.addModuleHealth(modules)
 
 	p.Lock()
 	p.scanCompleted = true
@@ -455,6 +518,26 @@ func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
 	return p.createScanResponse(), nil
 }
 
+// addModuleHealth populates module health where the platform provides it.
+// Health is left unset, and reported as unavailable, otherwise.
+func (p *Provider) addModuleHealth(modules storage.ScmModules) {
+	if len(modules) == 0 {
+		return
+	}
+
+	healths, err := p.backend.GetModuleHealth()
+	if err != nil {
+		p.log.Debugf("SCM module health unavailable: %s", err)
+		return
+	}
+
+	for _, m := range modules {
+		if h, found := healths[m.UID]; found {
+			m.Health = h
+		}
+	}
+}
+
 // Prepare attempts to fulfill a SCM Prepare request.
 func (p *Provider) Prepare(req PrepareRequest) (res *PrepareResponse, err error) {
 	if !p.isInitialized() {
@@ -128,6 +128,105 @@ func TestProviderScan(t *testing.T) {
 	}
 }
 
+func TestProviderScan_ModuleHealth(t *testing.T) {
+	for name, tc := range map[string]struct {
+		healthRes  map[string]*storage.ScmHealth
+		healthErr  error
+		expHealths []*storage.ScmHealth
+		expStrings []string
+	}{
+		"health unavailable": {
+			healthErr:  FaultMissingNdctl,
+			expHealths: []*storage.ScmHealth{nil, nil},
+			expStrings: []string{"unavailable", "unavailable"},
+		},
+		"health for some modules": {
+			healthRes: map[string]*storage.ScmHealth{
+				"Device1": {
+					HealthState:         "critical",
+					MediaErrors:         3,
+					UncorrectableErrors: 5,
+					LifeRemaining:       8,
+					UnsafeShutdowns:     1,
+				},
+			},
+			expHealths: []*storage.ScmHealth{
+				nil,
+				{
+					HealthState:         "critical",
+					MediaErrors:         3,
+					UncorrectableErrors: 5,
+					LifeRemaining:       8,
+					UnsafeShutdowns:     1,
+				},
+			},
+			expStrings: []string{
+				"unavailable",
+				"critical (media errors:3 uncorrectable errors:5 life remaining:8% unsafe shutdowns:1)",
+			},
+		},
+		"health for all modules": {
+			healthRes: map[string]*storage.ScmHealth{
+				"Device0": {HealthState: "ok", LifeRemaining: 100},
+				"Device1": {HealthState: "non-critical", LifeRemaining: 40},
+			},
+			expHealths: []*storage.ScmHealth{
+				{HealthState: "ok", LifeRemaining: 100},
+				{HealthState: "non-critical", LifeRemaining: 40},
+			},
+			expStrings: []string{
+				"healthy (media errors:0 uncorrectable errors:0 life remaining:100% unsafe shutdowns:0)",
+				"warning (media errors:0 uncorrectable errors:0 life remaining:40% unsafe shutdowns:0)",
+			},
+		},
+		"lifetime unavailable": {
+			healthRes: map[string]*storage.ScmHealth{
+				"Device0": {HealthState: "ok", LifeUnavailable: true},
+				"Device1": {HealthState: "ok", LifeRemaining: 0},
+			},
+			expHealths: []*storage.ScmHealth{
+				{HealthState: "ok", LifeUnavailable: true},
+				{HealthState: "ok", LifeRemaining: 0},
+			},
+			expStrings: []string{
+				"healthy (media errors:0 uncorrectable errors:0 life remaining:unavailable unsafe shutdowns:0)",
+				"healthy (media errors:0 uncorrectable errors:0 life remaining:0% unsafe shutdowns:0)",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mbc := &MockBackendConfig{
+				DiscoverRes:         storage.MockScmModules(2),
+				GetPmemNamespaceRes: storage.ScmNamespaces{defaultNamespace},
+				GetModuleHealthRes:  tc.healthRes,
+				GetModuleHealthErr:  tc.healthErr,
+			}
+			p := NewMockProvider(log, mbc, nil)
+
+			res, err := p.Scan(ScanRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotHealths []*storage.ScmHealth
+			var gotStrings []string
+			for _, m := range res.Modules {
+				gotHealths = append(gotHealths, m.Health)
+				gotStrings = append(gotStrings, m.Health.String())
+			}
+			if diff := cmp.Diff(tc.expHealths, gotHealths); diff != "" {
+				t.Fatalf("unexpected module health (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expStrings, gotStrings); diff != "" {
+				t.Fatalf("unexpected module health strings (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
 func TestProviderPrepare(t *testing.T) {
 	for name, tc := range map[string]struct {
 		startInitialized bool
@@ -303,6 +402,72 @@ func TestProviderGetPmemState(t *testing.T) {
 	}
 }
 
+func TestGetPrepareAction(t *testing.T) {
+	for name, tc := range map[string]struct {
+		state     storage.ScmState
+		reset     bool
+		expAction PrepareAction
+		expReboot bool
+		expErr    error
+	}{
+		"unknown state": {
+			state:  storage.ScmStateUnknown,
+			expErr: errors.New("unknown scm state"),
+		},
+		"unknown state; reset": {
+			state:  storage.ScmStateUnknown,
+			reset:  true,
+			expErr: errors.New("unknown scm state"),
+		},
+		"unhandled state": {
+			state:  storage.ScmState(42),
+			expErr: errors.New("unhandled scm state"),
+		},
+		"no regions": {
+			state:     storage.ScmStateNoRegions,
+			expAction: PrepareActionCreateRegions,
+			expReboot: true,
+		},
+		"no regions; reset": {
+			state:     storage.ScmStateNoRegions,
+			reset:     true,
+			expAction: PrepareActionNone,
+		},
+		"free capacity": {
+			state:     storage.ScmStateFreeCapacity,
+			expAction: PrepareActionCreateNamespaces,
+		},
+		"free capacity; reset": {
+			state:     storage.ScmStateFreeCapacity,
+			reset:     true,
+			expAction: PrepareActionRemoveRegions,
+			expReboot: true,
+		},
+		"no capacity": {
+			state:     storage.ScmStateNoCapacity,
+			expAction: PrepareActionNone,
+		},
+		"no capacity; reset": {
+			state:     storage.ScmStateNoCapacity,
+			reset:     true,
+			expAction: PrepareActionRemoveRegions,
+			expReboot: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			action, err := GetPrepareAction(tc.state, tc.reset)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			common.AssertEqual(t, tc.expAction, action, "unexpected action")
+			common.AssertEqual(t, tc.expReboot, action.RebootRequired(),
+				"unexpected reboot required")
+		})
+	}
+}
+
 func TestProviderCheckFormat(t *testing.T) {
 	const (
 		goodMountPoint = "/mnt/daos"
@@ -0,0 +1,161 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package storage
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// NvmeSelfTestLogSize is the size in bytes of the NVMe device
+	// self-test log page.
+	NvmeSelfTestLogSize = 564
+
+	selfTestLogHeaderSize = 4
+	selfTestLogEntrySize  = 28
+)
+
+// NvmeSelfTestCode identifies the type of an NVMe device self-test operation
+// and matches the self-test code values defined in the NVMe specification.
+type NvmeSelfTestCode uint8
+
+const (
+	// NvmeSelfTestNone indicates that no self-test operation is in progress.
+	NvmeSelfTestNone NvmeSelfTestCode = 0x0
+	// NvmeSelfTestShort is a short device self-test operation.
+	NvmeSelfTestShort NvmeSelfTestCode = 0x1
+	// NvmeSelfTestExtended is an extended device self-test operation.
+	NvmeSelfTestExtended NvmeSelfTestCode = 0x2
+	// NvmeSelfTestVendor is a vendor specific self-test operation.
+	NvmeSelfTestVendor NvmeSelfTestCode = 0xE
+)
+
+func (c NvmeSelfTestCode) String() string {
+	switch c {
+	case NvmeSelfTestNone:
+		return "none"
+	case NvmeSelfTestShort:
+		return "short"
+	case NvmeSelfTestExtended:
+		return "extended"
+	case NvmeSelfTestVendor:
+		return "vendor"
+	}
+	return "unknown"
+}
+
+// NvmeSelfTestResult is the result of a completed NVMe device self-test and
+// matches the result values defined in the NVMe specification.
+type NvmeSelfTestResult uint8
+
+const (
+	// NvmeSelfTestPassed indicates that the self-test completed without
+	// error.
+	NvmeSelfTestPassed NvmeSelfTestResult = 0x0
+	// NvmeSelfTestAbortedCmd indicates that the self-test was aborted by
+	// a self-test command.
+	NvmeSelfTestAbortedCmd NvmeSelfTestResult = 0x1
+	// NvmeSelfTestAbortedReset indicates that the self-test was aborted by
+	// a controller reset.
+	NvmeSelfTestAbortedReset NvmeSelfTestResult = 0x2
+	// NvmeSelfTestAbortedNsRemoval indicates that the self-test was aborted
+	// by the removal of a namespace.
+	NvmeSelfTestAbortedNsRemoval NvmeSelfTestResult = 0x3
+	// NvmeSelfTestAbortedFormat indicates that the self-test was aborted by
+	// a format command.
+	NvmeSelfTestAbortedFormat NvmeSelfTestResult = 0x4
+	// NvmeSelfTestFatal indicates that a fatal error or unknown test error
+	// occurred.
+	NvmeSelfTestFatal NvmeSelfTestResult = 0x5
+	// NvmeSelfTestFailedUnknownSegment indicates that the self-test failed
+	// in an unknown segment.
+	NvmeSelfTestFailedUnknownSegment NvmeSelfTestResult = 0x6
+	// NvmeSelfTestFailedSegment indicates that one or more segments of the
+	// self-test failed.
+	NvmeSelfTestFailedSegment NvmeSelfTestResult = 0x7
+	// NvmeSelfTestAbortedUnknown indicates that the self-test was aborted
+	// for an unknown reason.
+	NvmeSelfTestAbortedUnknown NvmeSelfTestResult = 0x8
+	// NvmeSelfTestNoResult indicates that the log entry is unused.
+	NvmeSelfTestNoResult NvmeSelfTestResult = 0xF
+)
+
+func (r NvmeSelfTestResult) String() string {
+	switch r {
+	case NvmeSelfTestPassed:
+		return "passed"
+	case NvmeSelfTestAbortedCmd:
+		return "aborted by self-test command"
+	case NvmeSelfTestAbortedReset:
+		return "aborted by controller reset"
+	case NvmeSelfTestAbortedNsRemoval:
+		return "aborted by namespace removal"
+	case NvmeSelfTestAbortedFormat:
+		return "aborted by format"
+	case NvmeSelfTestFatal:
+		return "fatal error"
+	case NvmeSelfTestFailedUnknownSegment:
+		return "failed in unknown segment"
+	case NvmeSelfTestFailedSegment:
+		return "failed segments"
+	case NvmeSelfTestAbortedUnknown:
+		return "aborted for unknown reason"
+	case NvmeSelfTestNoResult:
+		return "no result"
+	}
+	return "unknown"
+}
+
+// NvmeSelfTestStatus reports the progress of any self-test in progress on an
+// NVMe controller along with the outcome of the most recent completed test,
+// as reported in the device self-test log page.
+type NvmeSelfTestStatus struct {
+	PciAddr    string             `json:"pci_addr"`
+	Current    NvmeSelfTestCode   `json:"current"`
+	Progress   uint32             `json:"progress"`
+	LastCode   NvmeSelfTestCode   `json:"last_code"`
+	LastResult NvmeSelfTestResult `json:"last_result"`
+}
+
+// InProgress returns true if a self-test is running on the controller.
+func (sts *NvmeSelfTestStatus) InProgress() bool {
+	return sts.Current != NvmeSelfTestNone
+}
+
+// HasResult returns true if the controller has completed a self-test.
+func (sts *NvmeSelfTestStatus) HasResult() bool {
+	return sts.LastResult != NvmeSelfTestNoResult
+}
+
+// Passed returns true if the most recent completed self-test passed.
+func (sts *NvmeSelfTestStatus) Passed() bool {
+	return sts.LastResult == NvmeSelfTestPassed
+}
+
+// ParseNvmeSelfTestLog decodes a raw device self-test log page into a status.
+// Only the most recent result entry is decoded.
+func ParseNvmeSelfTestLog(page []byte) (*NvmeSelfTestStatus, error) {
+	if len(page) < selfTestLogHeaderSize+selfTestLogEntrySize {
+		return nil, errors.Errorf("self-test log page too short (%d bytes)", len(page))
+	}
+
+	// the newest result is the first entry after the header
+	entry := page[selfTestLogHeaderSize]
+	status := &NvmeSelfTestStatus{
+		Current:    NvmeSelfTestCode(page[0] & 0xF),
+		LastCode:   NvmeSelfTestCode(entry >> 4),
+		LastResult: NvmeSelfTestResult(entry & 0xF),
+	}
+	if status.InProgress() {
+		status.Progress = uint32(page[1] & 0x7F)
+	}
+	if !status.HasResult() {
+		status.LastCode = NvmeSelfTestNone
+	}
+
+	return status, nil
+}
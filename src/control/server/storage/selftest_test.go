@@ -0,0 +1,105 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// mockSelfTestLog returns a raw self-test log page with the given current
+// operation and completion percentage, and newest result entry.
+func mockSelfTestLog(current NvmeSelfTestCode, progress uint8, lastCode NvmeSelfTestCode, lastResult NvmeSelfTestResult) []byte {
+	page := make([]byte, NvmeSelfTestLogSize)
+	page[0] = byte(current)
+	page[1] = progress
+	for i := selfTestLogHeaderSize; i < len(page); i += selfTestLogEntrySize {
+		page[i] = byte(NvmeSelfTestNoResult)
+	}
+	page[selfTestLogHeaderSize] = byte(lastCode)<<4 | byte(lastResult)
+
+	return page
+}
+
+func TestStorage_ParseNvmeSelfTestLog(t *testing.T) {
+	for name, tc := range map[string]struct {
+		page          []byte
+		expStatus     *NvmeSelfTestStatus
+		expInProgress bool
+		expHasResult  bool
+		expPassed     bool
+		expErr        error
+	}{
+		"short page": {
+			page:   make([]byte, 16),
+			expErr: errors.New("too short"),
+		},
+		"never run": {
+			page: mockSelfTestLog(NvmeSelfTestNone, 0, NvmeSelfTestNone, NvmeSelfTestNoResult),
+			expStatus: &NvmeSelfTestStatus{
+				LastResult: NvmeSelfTestNoResult,
+			},
+		},
+		"short test in progress": {
+			page: mockSelfTestLog(NvmeSelfTestShort, 42, NvmeSelfTestNone, NvmeSelfTestNoResult),
+			expStatus: &NvmeSelfTestStatus{
+				Current:    NvmeSelfTestShort,
+				Progress:   42,
+				LastResult: NvmeSelfTestNoResult,
+			},
+			expInProgress: true,
+		},
+		"extended test in progress after previous pass": {
+			page: mockSelfTestLog(NvmeSelfTestExtended, 0x80|7, NvmeSelfTestShort, NvmeSelfTestPassed),
+			expStatus: &NvmeSelfTestStatus{
+				Current:    NvmeSelfTestExtended,
+				Progress:   7,
+				LastCode:   NvmeSelfTestShort,
+				LastResult: NvmeSelfTestPassed,
+			},
+			expInProgress: true,
+			expHasResult:  true,
+			expPassed:     true,
+		},
+		"completed and passed": {
+			page: mockSelfTestLog(NvmeSelfTestNone, 0, NvmeSelfTestExtended, NvmeSelfTestPassed),
+			expStatus: &NvmeSelfTestStatus{
+				LastCode:   NvmeSelfTestExtended,
+				LastResult: NvmeSelfTestPassed,
+			},
+			expHasResult: true,
+			expPassed:    true,
+		},
+		"completed and failed": {
+			page: mockSelfTestLog(NvmeSelfTestNone, 0, NvmeSelfTestShort, NvmeSelfTestFailedSegment),
+			expStatus: &NvmeSelfTestStatus{
+				LastCode:   NvmeSelfTestShort,
+				LastResult: NvmeSelfTestFailedSegment,
+			},
+			expHasResult: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotStatus, gotErr := ParseNvmeSelfTestLog(tc.page)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expStatus, gotStatus); diff != "" {
+				t.Fatalf("unexpected status (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, tc.expInProgress, gotStatus.InProgress(), "InProgress()")
+			common.AssertEqual(t, tc.expHasResult, gotStatus.HasResult(), "HasResult()")
+			common.AssertEqual(t, tc.expPassed, gotStatus.Passed(), "Passed()")
+		})
+	}
+}
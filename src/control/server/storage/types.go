@@ -9,9 +9,12 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 
 	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/system"
@@ -35,6 +38,87 @@ const (
 	ScmStateNoCapacity
 )
 
+// ScmStateCause provides a machine-readable explanation of the probed
+// ScmState, its String method returns a human-readable reason.
+type ScmStateCause int
+
+const (
+	// ScmCauseUnknown indicates that the cause of the state is not known.
+	ScmCauseUnknown ScmStateCause = iota
+	// ScmCauseNoModules indicates that no SCM modules are present.
+	ScmCauseNoModules
+	// ScmCauseNoRegions indicates that SCM modules are present but
+	// AppDirect regions have not been configured.
+	ScmCauseNoRegions
+	// ScmCauseNoNamespaces indicates that regions exist with free
+	// capacity and pmem namespaces need to be created.
+	ScmCauseNoNamespaces
+	// ScmCauseReady indicates that region capacity is fully allocated
+	// to pmem namespaces.
+	ScmCauseReady
+)
+
+func (sc ScmStateCause) String() string {
+	switch sc {
+	case ScmCauseNoModules:
+		return "no SCM modules present"
+	case ScmCauseNoRegions:
+		return "SCM modules present but AppDirect regions not configured"
+	case ScmCauseNoNamespaces:
+		return "AppDirect regions have free capacity, pmem namespaces need to be created"
+	case ScmCauseReady:
+		return "AppDirect region capacity allocated to pmem namespaces"
+	}
+	return "SCM state could not be determined"
+}
+
+// GetScmStateCause returns the cause of the given state, taking into account
+// the number of SCM modules present.
+func GetScmStateCause(state ScmState, numModules int) ScmStateCause {
+	if numModules == 0 {
+		return ScmCauseNoModules
+	}
+
+	switch state {
+	case ScmStateNoRegions:
+		return ScmCauseNoRegions
+	case ScmStateFreeCapacity:
+		return ScmCauseNoNamespaces
+	case ScmStateNoCapacity:
+		return ScmCauseReady
+	}
+	return ScmCauseUnknown
+}
+
+// NvmeHealthSeverity classifies the health of an NVMe controller based on
+// the warnings reported in its health statistics.
+type NvmeHealthSeverity int
+
+const (
+	// NvmeHealthUnknown indicates that no health statistics are available.
+	NvmeHealthUnknown NvmeHealthSeverity = iota
+	// NvmeHealthHealthy indicates that no warnings are reported.
+	NvmeHealthHealthy
+	// NvmeHealthWarning indicates that temperature or spare capacity
+	// warnings are reported.
+	NvmeHealthWarning
+	// NvmeHealthCritical indicates that the device is unreliable, has
+	// been put into read-only mode or its volatile memory backup failed.
+	NvmeHealthCritical
+)
+
+func (nhs NvmeHealthSeverity) String() string {
+	switch nhs {
+	case NvmeHealthHealthy:
+		return "healthy"
+	case NvmeHealthWarning:
+		return "warning"
+	case NvmeHealthCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
 type (
 	// ScmModule represents a SCM DIMM.
 	//
@@ -49,6 +133,20 @@ type (
 		UID              string
 		PartNumber       string
 		FirmwareRevision string
+		Health           *ScmHealth `hash:"ignore"`
+	}
+
+	// ScmHealth represents the health of a SCM module as reported by the
+	// platform (via ndctl). Nil if the platform doesn't expose module health.
+	// LifeUnavailable is set when the platform doesn't report how much of
+	// the module's rated lifetime has been used.
+	ScmHealth struct {
+		HealthState         string `json:"health_state"`
+		MediaErrors         uint64 `json:"media_errs"`
+		UncorrectableErrors uint64 `json:"uncorrectable_errs"`
+		LifeRemaining       uint32 `json:"life_remaining"`
+		LifeUnavailable     bool   `json:"life_unavailable"`
+		UnsafeShutdowns     uint64 `json:"unsafe_shutdowns"`
 	}
 
 	// ScmModules is a type alias for []ScmModule that implements fmt.Stringer.
@@ -60,6 +158,8 @@ type (
 		Path       string `json:"path"`
 		TotalBytes uint64 `json:"total_bytes"`
 		AvailBytes uint64 `json:"avail_bytes"`
+		UsedBytes  uint64 `json:"used_bytes"`
+		Mounted    bool   `json:"mounted"`
 	}
 
 	// ScmMountPoints is a type alias for []ScmMountPoint that implements fmt.Stringer.
@@ -106,6 +206,7 @@ type (
 		UnmapErrors     uint32 `json:"bio_unmap_errs"`
 		ChecksumErrors  uint32 `json:"checksum_errs"`
 		Temperature     uint32 `json:"temperature"`
+		AvailSpare      uint32 `json:"avail_spare"`
 		TempWarn        bool   `json:"temp_warn"`
 		AvailSpareWarn  bool   `json:"avail_spare_warn"`
 		ReliabilityWarn bool   `json:"dev_reliability_warn"`
@@ -235,6 +336,19 @@ func (sn ScmNamespace) Free() uint64 {
 	return sn.Mount.AvailBytes
 }
 
+// Used returns the bytes in use on mounted SCM namespace as reported by OS.
+func (sn ScmNamespace) Used() uint64 {
+	if sn.Mount == nil {
+		return 0
+	}
+	return sn.Mount.UsedBytes
+}
+
+// Mounted returns true if the SCM namespace is mounted.
+func (sn ScmNamespace) Mounted() bool {
+	return sn.Mount != nil && sn.Mount.Mounted
+}
+
 // Capacity reports total storage capacity (bytes) across all namespaces.
 func (sns ScmNamespaces) Capacity() (tb uint64) {
 	for _, sn := range sns {
@@ -287,6 +401,151 @@ func (nch *NvmeHealth) TempF() float32 {
 	return (nch.TempC() * (9.0 / 5.0)) + 32.0
 }
 
+// Severity returns the severity level indicated by the controller health
+// warnings.
+func (nch *NvmeHealth) Severity() NvmeHealthSeverity {
+	switch {
+	case nch == nil:
+		return NvmeHealthUnknown
+	case nch.ReliabilityWarn, nch.ReadOnlyWarn, nch.VolatileWarn:
+		return NvmeHealthCritical
+	case nch.TempWarn, nch.AvailSpareWarn:
+		return NvmeHealthWarning
+	}
+	return NvmeHealthHealthy
+}
+
+// HealthThresholds specifies the limits beyond which NVMe controller health
+// statistics are classified as a warning or critical, in addition to the
+// warnings reported by the controller itself.
+type HealthThresholds struct {
+	// MaxTemperature is the temperature in Kelvin above which a
+	// warning is raised.
+	MaxTemperature uint32 `json:"max_temperature"`
+	// MaxMediaErrors is the number of media errors above which the
+	// controller is critical, zero disables the check.
+	MaxMediaErrors uint64 `json:"max_media_errors"`
+	// MinAvailSpare is the percentage of spare capacity below which a
+	// warning is raised, zero disables the check.
+	MinAvailSpare uint32 `json:"min_avail_spare"`
+}
+
+// DefaultHealthThresholds returns thresholds matching common vendor
+// recommendations: a 70C composite temperature limit and at least 10% spare
+// capacity remaining. Media errors aren't limited by default as vendors don't
+// recommend a count, controllers report unrecoverable errors through their
+// own critical warnings.
+func DefaultHealthThresholds() *HealthThresholds {
+	return &HealthThresholds{
+		MaxTemperature: 343,
+		MinAvailSpare:  10,
+	}
+}
+
+// SeverityWithThresholds returns the severity level indicated by the
+// controller health warnings and the given thresholds. Default thresholds are
+// used if none are supplied.
+//
+// Spare capacity is only reported when health is read directly from the
+// controller, a zero value is treated as unknown and the spare threshold isn't
+// applied. A controller that has run out of spare capacity sets AvailSpareWarn.
+func (nch *NvmeHealth) SeverityWithThresholds(cfg *HealthThresholds) NvmeHealthSeverity {
+	if cfg == nil {
+		cfg = DefaultHealthThresholds()
+	}
+
+	switch sev := nch.Severity(); {
+	case sev == NvmeHealthUnknown, sev == NvmeHealthCritical:
+		return sev
+	case cfg.MaxMediaErrors != 0 && nch.MediaErrors > cfg.MaxMediaErrors:
+		return NvmeHealthCritical
+	case sev == NvmeHealthWarning:
+		return sev
+	case nch.Temperature > cfg.MaxTemperature:
+		return NvmeHealthWarning
+	case nch.AvailSpare != 0 && nch.AvailSpare < cfg.MinAvailSpare:
+		return NvmeHealthWarning
+	}
+	return NvmeHealthHealthy
+}
+
+// ScmHealthUnavailable is reported in place of SCM module health when the
+// platform doesn't provide it.
+const ScmHealthUnavailable = "unavailable"
+
+// LifeUsed returns the percentage of rated module lifetime that has been used
+// and false if the platform doesn't report it.
+func (sh *ScmHealth) LifeUsed() (uint32, bool) {
+	if sh == nil || sh.LifeUnavailable {
+		return 0, false
+	}
+	if sh.LifeRemaining > 100 {
+		return 0, true
+	}
+	return 100 - sh.LifeRemaining, true
+}
+
+// Severity returns the severity level indicated by the module health state.
+func (sh *ScmHealth) Severity() NvmeHealthSeverity {
+	if sh == nil {
+		return NvmeHealthUnknown
+	}
+	switch strings.ToLower(sh.HealthState) {
+	case "ok":
+		return NvmeHealthHealthy
+	case "non-critical":
+		return NvmeHealthWarning
+	case "critical", "fatal":
+		return NvmeHealthCritical
+	}
+	return NvmeHealthUnknown
+}
+
+func (sh *ScmHealth) String() string {
+	if sh == nil {
+		return ScmHealthUnavailable
+	}
+	life := ScmHealthUnavailable
+	if !sh.LifeUnavailable {
+		life = fmt.Sprintf("%d%%", sh.LifeRemaining)
+	}
+	return fmt.Sprintf("%s (media errors:%d uncorrectable errors:%d life remaining:%s unsafe shutdowns:%d)",
+		sh.Severity(), sh.MediaErrors, sh.UncorrectableErrors, life, sh.UnsafeShutdowns)
+}
+
+// UsageKnown returns true if blobstore space usage has been reported for the
+// SMD device. Usage is left zeroed when it couldn't be retrieved.
+func (sd *SmdDevice) UsageKnown() bool {
+	return sd != nil && sd.TotalBytes != 0
+}
+
+// UsedBytes returns the number of bytes in use on the device blobstore.
+func (sd *SmdDevice) UsedBytes() uint64 {
+	if !sd.UsageKnown() || sd.AvailBytes > sd.TotalBytes {
+		return 0
+	}
+	return sd.TotalBytes - sd.AvailBytes
+}
+
+// FreeBytes returns the number of bytes available on the device blobstore.
+func (sd *SmdDevice) FreeBytes() uint64 {
+	if !sd.UsageKnown() {
+		return 0
+	}
+	return sd.AvailBytes
+}
+
+// UsageString returns a human readable summary of blobstore space usage or
+// "unknown" if usage hasn't been reported.
+func (sd *SmdDevice) UsageString() string {
+	if !sd.UsageKnown() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s/%s (%s used)", humanize.Bytes(sd.UsedBytes()),
+		humanize.Bytes(sd.TotalBytes),
+		common.PercentageString(sd.UsedBytes(), sd.TotalBytes))
+}
+
 // UpdateSmd adds or updates SMD device entry for an NVMe Controller.
 func (nc *NvmeController) UpdateSmd(smdDev *SmdDevice) {
 	for idx := range nc.SmdDevices {
@@ -300,6 +559,140 @@ func (nc *NvmeController) UpdateSmd(smdDev *SmdDevice) {
 	nc.SmdDevices = append(nc.SmdDevices, smdDev)
 }
 
+// NvmeControllerChange describes the change in value of an identity field of
+// an NVMe controller between scans. Field is the JSON name of the field.
+type NvmeControllerChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (ncc NvmeControllerChange) String() string {
+	return fmt.Sprintf("%s changed from %q to %q", ncc.Field, ncc.Old, ncc.New)
+}
+
+// identity returns the stable identity fields of the controller, keyed by
+// JSON field name, in a fixed order.
+func (nc *NvmeController) identity() [][2]string {
+	if nc == nil {
+		nc = &NvmeController{}
+	}
+
+	return [][2]string{
+		{"model", nc.Model},
+		{"serial", nc.Serial},
+		{"pci_addr", nc.PciAddr},
+		{"fw_rev", nc.FwRev},
+		{"socket_id", fmt.Sprint(nc.SocketID)},
+	}
+}
+
+// Equal returns true if the controllers have the same identity, i.e. model,
+// serial, PCI address, firmware revision and socket. Health statistics,
+// namespaces and SMD devices are volatile and not compared.
+func (nc *NvmeController) Equal(other *NvmeController) bool {
+	if nc == nil || other == nil {
+		return nc == other
+	}
+
+	return len(nc.Diff(other)) == 0
+}
+
+// Diff returns the identity fields that differ between this controller and
+// the other, e.g. firmware revision after an update or PCI address after the
+// device has been relocated. A nil controller is compared as zero-valued.
+func (nc *NvmeController) Diff(other *NvmeController) []NvmeControllerChange {
+	var changes []NvmeControllerChange
+
+	theirs := other.identity()
+	for i, ours := range nc.identity() {
+		if ours[1] != theirs[i][1] {
+			changes = append(changes, NvmeControllerChange{
+				Field: ours[0],
+				Old:   ours[1],
+				New:   theirs[i][1],
+			})
+		}
+	}
+
+	return changes
+}
+
+// NvmeNamespaceDevices links a namespace of an NVMe controller with the SMD
+// devices (blobstores) created on it.
+type NvmeNamespaceDevices struct {
+	CtrlrPciAddr string
+	Namespace    *NvmeNamespace
+	SmdDevices   []*SmdDevice
+}
+
+// firstTarget returns the lowest VOS target ID assigned to the SMD device.
+func (sd *SmdDevice) firstTarget() int32 {
+	first := int32(math.MaxInt32)
+	for _, id := range sd.TargetIDs {
+		if id < first {
+			first = id
+		}
+	}
+	return first
+}
+
+// LinkNamespaces returns a view of the controller's namespaces, ordered by ID,
+// each with the SMD devices created on it. SMD devices that can't be linked
+// to a namespace, e.g. because their transport address doesn't match the
+// controller's PCI address, are returned separately.
+//
+// SMD devices only identify their parent controller so when a controller has
+// multiple namespaces, devices are linked in the order their blobstores were
+// created, which follows namespace order. The device with the lowest target ID
+// is linked to the namespace with the lowest ID and so on.
+//
+// TODO: remove when SMD devices are reported as part of their namespace
+func (nc *NvmeController) LinkNamespaces() ([]*NvmeNamespaceDevices, []*SmdDevice) {
+	linked := make([]*NvmeNamespaceDevices, 0, len(nc.Namespaces))
+	for _, ns := range nc.Namespaces {
+		linked = append(linked, &NvmeNamespaceDevices{
+			CtrlrPciAddr: nc.PciAddr,
+			Namespace:    ns,
+		})
+	}
+	sort.Slice(linked, func(i, j int) bool {
+		return linked[i].Namespace.ID < linked[j].Namespace.ID
+	})
+
+	var matched, unlinked []*SmdDevice
+	seen := make(map[string]bool)
+	for _, sd := range nc.SmdDevices {
+		switch {
+		case seen[sd.UUID]:
+			continue
+		case sd.TrAddr != nc.PciAddr || len(linked) == 0:
+			unlinked = append(unlinked, sd)
+		default:
+			matched = append(matched, sd)
+		}
+		seen[sd.UUID] = true
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].firstTarget() < matched[j].firstTarget()
+	})
+
+	if len(linked) == 1 {
+		linked[0].SmdDevices = matched
+		return linked, unlinked
+	}
+
+	for i, sd := range matched {
+		if i >= len(linked) {
+			unlinked = append(unlinked, matched[i:]...)
+			break
+		}
+		linked[i].SmdDevices = []*SmdDevice{sd}
+	}
+
+	return linked, unlinked
+}
+
 // Capacity returns the cumulative total bytes of all namespace sizes.
 func (nc *NvmeController) Capacity() (tb uint64) {
 	for _, n := range nc.Namespaces {
@@ -378,3 +771,38 @@ func (ncs NvmeControllers) Update(ctrlrs ...*NvmeController) NvmeControllers {
 
 	return ncs
 }
+
+// ValidateIdentities checks that no two controllers share a serial number or
+// PCI address, as can happen in cloned or virtualized environments, and
+// returns an error listing any collisions. Empty serial numbers are ignored.
+func (ncs NvmeControllers) ValidateIdentities() error {
+	serials := make(map[string][]string)
+	addrs := make(map[string]int)
+	for _, c := range ncs {
+		if c.Serial != "" {
+			serials[c.Serial] = append(serials[c.Serial], c.PciAddr)
+		}
+		addrs[c.PciAddr]++
+	}
+
+	var msgs []string
+	for serial, pciAddrs := range serials {
+		if len(pciAddrs) > 1 {
+			msgs = append(msgs, fmt.Sprintf("serial %q shared by controllers %s",
+				serial, strings.Join(pciAddrs, ", ")))
+		}
+	}
+	for addr, count := range addrs {
+		if count > 1 {
+			msgs = append(msgs, fmt.Sprintf("pci address %q shared by %d controllers",
+				addr, count))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	sort.Strings(msgs)
+
+	return errors.Errorf("duplicate NVMe controller identities: %s",
+		strings.Join(msgs, "; "))
+}
@@ -0,0 +1,352 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestStorage_NvmeController_Diff(t *testing.T) {
+	for name, tc := range map[string]struct {
+		nc         *NvmeController
+		modify     func(*NvmeController)
+		other      *NvmeController
+		expEqual   bool
+		expChanges []NvmeControllerChange
+		expStrings []string
+	}{
+		"identical": {
+			nc:       MockNvmeController(),
+			expEqual: true,
+		},
+		"health stats changed": {
+			nc: MockNvmeController(),
+			modify: func(nc *NvmeController) {
+				nc.HealthStats = MockNvmeHealth(5)
+				nc.SmdDevices = nil
+			},
+			expEqual: true,
+		},
+		"firmware changed": {
+			nc: MockNvmeController(0),
+			modify: func(nc *NvmeController) {
+				nc.FwRev = "fwRev-1"
+			},
+			expChanges: []NvmeControllerChange{
+				{Field: "fw_rev", Old: "fwRev-0", New: "fwRev-1"},
+			},
+			expStrings: []string{`fw_rev changed from "fwRev-0" to "fwRev-1"`},
+		},
+		"relocated": {
+			nc: MockNvmeController(0),
+			modify: func(nc *NvmeController) {
+				nc.PciAddr = "0000:5e:00.0"
+				nc.SocketID = 1
+			},
+			expChanges: []NvmeControllerChange{
+				{Field: "pci_addr", Old: "0000:80:00.0", New: "0000:5e:00.0"},
+				{Field: "socket_id", Old: "0", New: "1"},
+			},
+			expStrings: []string{
+				`pci_addr changed from "0000:80:00.0" to "0000:5e:00.0"`,
+				`socket_id changed from "0" to "1"`,
+			},
+		},
+		"other nil": {
+			nc: &NvmeController{Model: "model0"},
+			expChanges: []NvmeControllerChange{
+				{Field: "model", Old: "model0", New: ""},
+			},
+			expStrings: []string{`model changed from "model0" to ""`},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			other := tc.other
+			if tc.modify != nil {
+				other = new(NvmeController)
+				*other = *tc.nc
+				tc.modify(other)
+			} else if tc.expEqual {
+				other = tc.nc
+			}
+
+			common.AssertEqual(t, tc.expEqual, tc.nc.Equal(other), "Equal()")
+
+			gotChanges := tc.nc.Diff(other)
+			if diff := cmp.Diff(tc.expChanges, gotChanges); diff != "" {
+				t.Fatalf("unexpected changes (-want, +got):\n%s\n", diff)
+			}
+
+			var gotStrings []string
+			for _, c := range gotChanges {
+				gotStrings = append(gotStrings, c.String())
+			}
+			if diff := cmp.Diff(tc.expStrings, gotStrings); diff != "" {
+				t.Fatalf("unexpected change strings (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestStorage_NvmeController_EqualNil(t *testing.T) {
+	var nilCtrlr *NvmeController
+
+	common.AssertTrue(t, nilCtrlr.Equal(nil), "nil controllers should be equal")
+	common.AssertFalse(t, nilCtrlr.Equal(MockNvmeController()),
+		"nil controller shouldn't equal non-nil")
+	common.AssertFalse(t, MockNvmeController().Equal(nil),
+		"non-nil controller shouldn't equal nil")
+}
+
+func TestStorage_NvmeController_LinkNamespaces(t *testing.T) {
+	const pciAddr = "0000:81:00.0"
+
+	ns := func(id uint32) *NvmeNamespace {
+		return &NvmeNamespace{ID: id, Size: uint64(id) * 1024}
+	}
+	smd := func(idx int32, tgts ...int32) *SmdDevice {
+		sd := MockSmdDevice(pciAddr, idx)
+		if len(tgts) > 0 {
+			sd.TargetIDs = tgts
+		}
+		return sd
+	}
+	otherSmd := MockSmdDevice("0000:82:00.0", 9)
+
+	for name, tc := range map[string]struct {
+		namespaces  []*NvmeNamespace
+		smdDevices  []*SmdDevice
+		expLinked   map[uint32][]*SmdDevice
+		expUnlinked []*SmdDevice
+	}{
+		"no namespaces": {
+			smdDevices:  []*SmdDevice{smd(1)},
+			expLinked:   map[uint32][]*SmdDevice{},
+			expUnlinked: []*SmdDevice{smd(1)},
+		},
+		"namespace without smd devices": {
+			namespaces: []*NvmeNamespace{ns(1)},
+			expLinked:  map[uint32][]*SmdDevice{1: nil},
+		},
+		"single namespace multiple smd devices": {
+			namespaces: []*NvmeNamespace{ns(1)},
+			smdDevices: []*SmdDevice{smd(2, 1, 3), smd(1, 0, 2)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 0, 2), smd(2, 1, 3)},
+			},
+		},
+		"multiple namespaces multiple smd devices": {
+			namespaces: []*NvmeNamespace{ns(2), ns(1), ns(3)},
+			smdDevices: []*SmdDevice{smd(3, 8, 4), smd(1, 0, 6), smd(2, 5, 1)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 0, 6)},
+				2: {smd(2, 5, 1)},
+				3: {smd(3, 8, 4)},
+			},
+		},
+		"more namespaces than smd devices": {
+			namespaces: []*NvmeNamespace{ns(1), ns(2), ns(3)},
+			smdDevices: []*SmdDevice{smd(2, 4), smd(1, 2)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 2)},
+				2: {smd(2, 4)},
+				3: nil,
+			},
+		},
+		"more smd devices than namespaces": {
+			namespaces: []*NvmeNamespace{ns(1), ns(2)},
+			smdDevices: []*SmdDevice{smd(1, 0), smd(2, 1), smd(3, 2)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 0)},
+				2: {smd(2, 1)},
+			},
+			expUnlinked: []*SmdDevice{smd(3, 2)},
+		},
+		"smd device on other controller": {
+			namespaces: []*NvmeNamespace{ns(1), ns(2)},
+			smdDevices: []*SmdDevice{otherSmd, smd(2, 1), smd(1, 0)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 0)},
+				2: {smd(2, 1)},
+			},
+			expUnlinked: []*SmdDevice{otherSmd},
+		},
+		"duplicate smd uuid": {
+			namespaces: []*NvmeNamespace{ns(1), ns(2)},
+			smdDevices: []*SmdDevice{smd(1, 0), smd(1, 0), smd(2, 1)},
+			expLinked: map[uint32][]*SmdDevice{
+				1: {smd(1, 0)},
+				2: {smd(2, 1)},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			nc := &NvmeController{
+				PciAddr:    pciAddr,
+				Namespaces: tc.namespaces,
+				SmdDevices: tc.smdDevices,
+			}
+
+			gotLinked, gotUnlinked := nc.LinkNamespaces()
+
+			var lastID uint32
+			gotMap := make(map[uint32][]*SmdDevice)
+			for _, nd := range gotLinked {
+				if nd.Namespace.ID < lastID {
+					t.Fatalf("namespaces out of order: %d after %d", nd.Namespace.ID, lastID)
+				}
+				lastID = nd.Namespace.ID
+				common.AssertEqual(t, pciAddr, nd.CtrlrPciAddr, "controller address")
+				gotMap[nd.Namespace.ID] = nd.SmdDevices
+			}
+
+			if diff := cmp.Diff(tc.expLinked, gotMap); diff != "" {
+				t.Fatalf("unexpected linked devices (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expUnlinked, gotUnlinked); diff != "" {
+				t.Fatalf("unexpected unlinked devices (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestStorage_NvmeHealth_SeverityWithThresholds(t *testing.T) {
+	nominal := func() *NvmeHealth {
+		return &NvmeHealth{
+			Temperature: 300,
+			AvailSpare:  100,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		health     *NvmeHealth
+		thresholds *HealthThresholds
+		expSev     NvmeHealthSeverity
+	}{
+		"nil health": {
+			expSev: NvmeHealthUnknown,
+		},
+		"nominal with defaults": {
+			health: nominal(),
+			expSev: NvmeHealthHealthy,
+		},
+		"controller critical warning": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.ReadOnlyWarn = true
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000},
+			expSev:     NvmeHealthCritical,
+		},
+		"controller warning": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.TempWarn = true
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000},
+			expSev:     NvmeHealthWarning,
+		},
+		"hot with defaults": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.Temperature = 350
+				return h
+			}(),
+			expSev: NvmeHealthWarning,
+		},
+		"hot within custom limit": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.Temperature = 350
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 360},
+			expSev:     NvmeHealthHealthy,
+		},
+		"media errors with defaults": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.MediaErrors = 3
+				return h
+			}(),
+			expSev: NvmeHealthHealthy,
+		},
+		"media errors above custom limit": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.MediaErrors = 3
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000, MaxMediaErrors: 2},
+			expSev:     NvmeHealthCritical,
+		},
+		"media errors within custom limit": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.MediaErrors = 3
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000, MaxMediaErrors: 5},
+			expSev:     NvmeHealthHealthy,
+		},
+		"media errors override warning": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.TempWarn = true
+				h.MediaErrors = 3
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000, MaxMediaErrors: 2},
+			expSev:     NvmeHealthCritical,
+		},
+		"low spare with defaults": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.AvailSpare = 5
+				return h
+			}(),
+			expSev: NvmeHealthWarning,
+		},
+		"unknown spare with defaults": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.AvailSpare = 0
+				return h
+			}(),
+			expSev: NvmeHealthHealthy,
+		},
+		"low spare within custom limit": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.AvailSpare = 5
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000, MinAvailSpare: 5},
+			expSev:     NvmeHealthHealthy,
+		},
+		"stricter custom spare limit": {
+			health: func() *NvmeHealth {
+				h := nominal()
+				h.AvailSpare = 40
+				return h
+			}(),
+			thresholds: &HealthThresholds{MaxTemperature: 1000, MinAvailSpare: 50},
+			expSev:     NvmeHealthWarning,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotSev := tc.health.SeverityWithThresholds(tc.thresholds)
+			common.AssertEqual(t, tc.expSev, gotSev, "unexpected severity")
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,6 +20,67 @@ import (
 	"github.com/daos-stack/daos/src/control/logging"
 )
 
+// promScanMetrics implements ScanMetrics by updating Prometheus metrics that
+// are exported alongside the engine telemetry.
+type promScanMetrics struct {
+	duration *prometheus.GaugeVec
+	scans    *prometheus.CounterVec
+	failures *prometheus.CounterVec
+}
+
+// newPromScanMetrics creates storage scan metrics and registers them with the
+// supplied registerer.
+func newPromScanMetrics(reg prometheus.Registerer) (*promScanMetrics, error) {
+	psm := &promScanMetrics{
+		duration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "daos_server",
+			Subsystem: "storage",
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of the most recent storage scan",
+		}, []string{"type"}),
+		scans: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "daos_server",
+			Subsystem: "storage",
+			Name:      "scans_total",
+			Help:      "Number of storage scans performed",
+		}, []string{"type"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "daos_server",
+			Subsystem: "storage",
+			Name:      "scan_failures_total",
+			Help:      "Number of storage scans that failed",
+		}, []string{"type"}),
+	}
+
+	for _, c := range []prometheus.Collector{psm.duration, psm.scans, psm.failures} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register storage scan metrics")
+		}
+	}
+
+	return psm, nil
+}
+
+// ObserveScan records the duration and outcome of a storage scan.
+func (psm *promScanMetrics) ObserveScan(scanType string, elapsed time.Duration, err error) {
+	psm.duration.WithLabelValues(scanType).Set(elapsed.Seconds())
+	psm.scans.WithLabelValues(scanType).Inc()
+	if err != nil {
+		psm.failures.WithLabelValues(scanType).Inc()
+	}
+}
+
+// regPromScanMetrics enables export of storage scan metrics from the given
+// service.
+func regPromScanMetrics(log logging.Logger, scs *StorageControlService) {
+	psm, err := newPromScanMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Errorf("storage scan metrics unavailable: %s", err)
+		return
+	}
+	scs.WithScanMetrics(psm)
+}
+
 func regPromEngineSources(ctx context.Context, log logging.Logger, engines []*EngineInstance) ([]func(), error) {
 	numEngines := len(engines)
 	if numEngines == 0 {
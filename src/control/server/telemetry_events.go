@@ -0,0 +1,177 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/daos-stack/daos/src/control/events"
+	"github.com/daos-stack/daos/src/control/lib/telemetry"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+const telemetryEventInterval = 30 * time.Second
+
+// telemetryThreshold describes a condition on engine telemetry metrics which
+// results in a RAS event being published when the condition is first met.
+type telemetryThreshold struct {
+	pattern  string // glob or regexp as accepted by FindSourceMetrics
+	limit    float64
+	delta    bool // compare the change since the previous walk
+	severity events.RASSeverityID
+	desc     string
+}
+
+// defaultTelemetryThresholds are checked against the NVMe health metrics
+// exported by each engine.
+var defaultTelemetryThresholds = []telemetryThreshold{
+	{
+		pattern:  "nvme/*/temp/warn",
+		limit:    1,
+		severity: events.RASSeverityWarning,
+		desc:     "SSD temperature above warning threshold",
+	},
+	{
+		pattern:  "nvme/*/reliability/avail_spare_warn",
+		limit:    1,
+		severity: events.RASSeverityWarning,
+		desc:     "SSD available spare below threshold",
+	},
+	{
+		pattern:  "nvme/*/commands/media_errs",
+		limit:    1,
+		delta:    true,
+		severity: events.RASSeverityError,
+		desc:     "SSD media errors increasing",
+	},
+}
+
+// telemetryEventCollector periodically walks the telemetry of each engine and
+// publishes an event when a metric crosses one of the thresholds. Once raised,
+// the event isn't published again until the metric has dropped back below the
+// threshold.
+type telemetryEventCollector struct {
+	log        logging.Logger
+	pubSub     *events.PubSub
+	hostname   string
+	interval   time.Duration
+	thresholds []telemetryThreshold
+	sources    []telemetry.Source
+	previous   map[string]float64
+	crossed    map[string]bool
+}
+
+// newTelemetryEventCollector returns a collector for the given sources, one
+// per engine.
+func newTelemetryEventCollector(log logging.Logger, ps *events.PubSub, interval time.Duration, thresholds []telemetryThreshold, sources ...telemetry.Source) *telemetryEventCollector {
+	return &telemetryEventCollector{
+		log:        log,
+		pubSub:     ps,
+		hostname:   hostname(),
+		interval:   interval,
+		thresholds: thresholds,
+		sources:    sources,
+		previous:   make(map[string]float64),
+		crossed:    make(map[string]bool),
+	}
+}
+
+// Start checks the thresholds immediately and then on every interval in a
+// background goroutine, until the context is cancelled.
+func (tec *telemetryEventCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(tec.interval)
+		defer ticker.Stop()
+
+		for {
+			tec.check(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (tec *telemetryEventCollector) check(ctx context.Context) {
+	for srcIdx, src := range tec.sources {
+		for thIdx, th := range tec.thresholds {
+			metrics, err := telemetry.FindSourceMetrics(ctx, src, th.pattern)
+			if err != nil {
+				tec.log.Debugf("engine %d: reading %q: %s", srcIdx, th.pattern, err)
+				continue
+			}
+
+			for _, m := range metrics {
+				tec.checkMetric(ctx, src, m, th,
+					fmt.Sprintf("%d:%d:%s/%s", srcIdx, thIdx, m.Path(), m.Name()))
+			}
+		}
+	}
+}
+
+func (tec *telemetryEventCollector) checkMetric(ctx context.Context, src telemetry.Source, m telemetry.Metric, th telemetryThreshold, key string) {
+	value := m.FloatValue()
+	if value == telemetry.BadFloatVal {
+		return
+	}
+
+	if th.delta {
+		prev, found := tec.previous[key]
+		tec.previous[key] = value
+		if !found {
+			return
+		}
+		// a counter that went backwards has been reset
+		if value >= prev {
+			value -= prev
+		}
+	}
+
+	over := value >= th.limit
+	wasOver := tec.crossed[key]
+	tec.crossed[key] = over
+	if !over || wasOver {
+		return
+	}
+
+	rank, err := src.GetRank(ctx)
+	if err != nil {
+		rank = math.MaxUint32
+	}
+
+	tec.pubSub.Publish(events.NewTelemetryThresholdEvent(tec.hostname, rank,
+		m.Path()+"/"+m.Name(), th.desc, value, th.limit, th.severity))
+}
+
+// startTelemetryEventCollector opens the telemetry of each engine and starts
+// a collector publishing threshold events to the server's PubSub. Engines
+// whose telemetry can't be opened are skipped.
+func startTelemetryEventCollector(ctx context.Context, srv *server) {
+	var sources []telemetry.Source
+	for _, ei := range srv.harness.Instances() {
+		src, cleanup, err := telemetry.OpenSource(ctx, ei.Index())
+		if err != nil {
+			srv.log.Errorf("engine %d: telemetry events unavailable: %s", ei.Index(), err)
+			continue
+		}
+		srv.OnShutdown(cleanup)
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return
+	}
+
+	srv.log.Debug("starting telemetry event collector")
+	newTelemetryEventCollector(srv.log, srv.pubSub, telemetryEventInterval,
+		defaultTelemetryThresholds, sources...).Start(ctx)
+}
@@ -0,0 +1,163 @@
+//
+// (C) Copyright 2021 Intel Corporation.
+//
+// SPDX-License-Identifier: BSD-2-Clause-Patent
+//
+
+package server
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/events"
+	"github.com/daos-stack/daos/src/control/lib/telemetry"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+var testTelemetryThresholds = []telemetryThreshold{
+	{
+		pattern:  "nvme/*/temp/warn",
+		limit:    1,
+		severity: events.RASSeverityWarning,
+		desc:     "temp warning",
+	},
+	{
+		pattern:  "nvme/*/commands/media_errs",
+		limit:    2,
+		delta:    true,
+		severity: events.RASSeverityError,
+		desc:     "media errors",
+	},
+}
+
+// subscribeThresholdEvents returns a channel receiving the messages of the
+// threshold events published.
+func subscribeThresholdEvents(ps *events.PubSub) chan string {
+	rx := make(chan string, 16)
+	ps.Subscribe(events.RASTypeInfoOnly,
+		events.HandlerFunc(func(_ context.Context, evt *events.RASEvent) {
+			rx <- evt.Msg
+		}))
+
+	return rx
+}
+
+func TestServer_telemetryEventCollector_check(t *testing.T) {
+	for name, tc := range map[string]struct {
+		warnVals []float64
+		errVals  []float64
+		expMsgs  []string
+	}{
+		"below thresholds": {
+			warnVals: []float64{0, 0, 0},
+			errVals:  []float64{0, 1, 2},
+		},
+		"gauge crosses threshold": {
+			warnVals: []float64{0, 1, 1},
+			errVals:  []float64{0, 0, 0},
+			expMsgs: []string{
+				"/nvme/d0/temp/warn: temp warning (value 1, threshold 1)",
+			},
+		},
+		"gauge crosses threshold again after dropping": {
+			warnVals: []float64{1, 0, 1},
+			errVals:  []float64{0, 0, 0},
+			expMsgs: []string{
+				"/nvme/d0/temp/warn: temp warning (value 1, threshold 1)",
+				"/nvme/d0/temp/warn: temp warning (value 1, threshold 1)",
+			},
+		},
+		"counter increases faster than threshold": {
+			warnVals: []float64{0, 0, 0, 0},
+			errVals:  []float64{5, 8, 11, 11},
+			expMsgs: []string{
+				"/nvme/d0/commands/media_errs: media errors (value 3, threshold 2)",
+			},
+		},
+		"unreadable metric ignored": {
+			warnVals: []float64{telemetry.BadFloatVal, 0},
+			errVals:  []float64{0, 0},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ps := events.NewPubSub(ctx, log)
+			defer ps.Close()
+			rx := subscribeThresholdEvents(ps)
+
+			warn := telemetry.NewMockMetric("/nvme/d0/temp/warn", telemetry.MetricTypeGauge, 0)
+			errs := telemetry.NewMockMetric("/nvme/d0/commands/media_errs", telemetry.MetricTypeCounter, 0)
+			src := &telemetry.MockSource{
+				Metrics: []telemetry.Metric{warn, errs},
+				Rank:    3,
+			}
+
+			tec := newTelemetryEventCollector(log, ps, time.Hour, testTelemetryThresholds, src)
+			for i := range tc.warnVals {
+				warn.Value = tc.warnVals[i]
+				errs.Value = tc.errVals[i]
+				tec.check(ctx)
+			}
+
+			// handlers are called asynchronously so wait for the
+			// expected events then check no others arrive
+			var gotMsgs []string
+			for len(gotMsgs) < len(tc.expMsgs) {
+				select {
+				case msg := <-rx:
+					gotMsgs = append(gotMsgs, msg)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for events, got %v", gotMsgs)
+				}
+			}
+			select {
+			case msg := <-rx:
+				t.Fatalf("unexpected event %q", msg)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			sort.Strings(gotMsgs)
+			if diff := cmp.Diff(tc.expMsgs, gotMsgs); diff != "" {
+				t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestServer_telemetryEventCollector_Start(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	ps := events.NewPubSub(context.Background(), log)
+	defer ps.Close()
+	rx := subscribeThresholdEvents(ps)
+
+	src := &telemetry.MockSource{
+		Metrics: []telemetry.Metric{
+			telemetry.NewMockMetric("/nvme/d0/temp/warn", telemetry.MetricTypeGauge, 1),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newTelemetryEventCollector(log, ps, 10*time.Millisecond, testTelemetryThresholds, src).Start(ctx)
+
+	select {
+	case msg := <-rx:
+		common.AssertEqual(t, "/nvme/d0/temp/warn: temp warning (value 1, threshold 1)",
+			msg, "unexpected event")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for threshold event")
+	}
+}
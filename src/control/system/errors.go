@@ -196,6 +196,23 @@ func IsMemberNotFound(err error) bool {
 	return ok
 }
 
+// ErrRanksNotFound indicates that a request referenced ranks that are not
+// members of the system.
+type ErrRanksNotFound struct {
+	Ranks *RankSet
+}
+
+func (err *ErrRanksNotFound) Error() string {
+	return fmt.Sprintf("non-existent ranks %s", err.Ranks)
+}
+
+// IsRanksNotFound returns a boolean indicating whether or not the
+// supplied error is an instance of ErrRanksNotFound.
+func IsRanksNotFound(err error) bool {
+	_, ok := errors.Cause(err).(*ErrRanksNotFound)
+	return ok
+}
+
 // ErrPoolNotFound indicates a failure to find a pool service with the
 // given search criterion.
 type ErrPoolNotFound struct {
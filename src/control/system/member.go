@@ -270,6 +270,15 @@ type MemberResult struct {
 	Errored bool
 	Msg     string
 	State   MemberState `json:"state"`
+	// ExitCode is the exit code of the engine process, set when the
+	// result reflects the process having exited.
+	ExitCode int32 `json:"exit_code,omitempty"`
+	// Transient is set when the failure reflects a temporary condition
+	// such as the engine being busy, so that a retry may succeed.
+	Transient bool `json:"transient,omitempty"`
+	// Skipped is set when the rank was requested but isn't hosted by the
+	// server reporting the result, so no action was taken on it.
+	Skipped bool `json:"skipped,omitempty"`
 }
 
 // MarshalJSON marshals system.MemberResult to JSON.
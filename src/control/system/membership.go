@@ -345,6 +345,11 @@ func (m *Membership) UpdateMemberStates(results MemberResults, updateOnFail bool
 	defer m.Unlock()
 
 	for _, result := range results {
+		// skipped results carry no state for the rank
+		if result.Skipped {
+			continue
+		}
+
 		member, err := m.db.FindMemberByRank(result.Rank)
 		if err != nil {
 			return err
@@ -413,6 +418,21 @@ func (m *Membership) CheckRanks(ranks string) (hit, miss *RankSet, err error) {
 	return
 }
 
+// CheckRanksStrict behaves like CheckRanks but returns an ErrRanksNotFound
+// error if any of the ranks in the provided rank set string are missing from
+// the membership, rather than returning them in a separate rank set.
+func (m *Membership) CheckRanksStrict(ranks string) (*RankSet, error) {
+	hit, miss, err := m.CheckRanks(ranks)
+	if err != nil {
+		return nil, err
+	}
+	if miss.Count() > 0 {
+		return nil, &ErrRanksNotFound{Ranks: miss}
+	}
+
+	return hit, nil
+}
+
 // CheckHosts returns set of all ranks on any of the hosts in provided host set
 // string and another slice of all hosts from input hostset string that are
 // missing from the membership. Host addresses are resolved before looking up
@@ -390,6 +390,53 @@ func TestSystem_Membership_CheckRanklist(t *testing.T) {
 	}
 }
 
+func TestSystem_Membership_CheckRanksStrict(t *testing.T) {
+	members := Members{
+		MockMember(t, 1, MemberStateJoined),
+		MockMember(t, 2, MemberStateStopped),
+	}
+
+	for name, tc := range map[string]struct {
+		inRanklist string
+		expRanks   string
+		expErr     error
+	}{
+		"no rank list": {
+			expRanks: "1-2",
+		},
+		"bad rank list": {
+			inRanklist: "foobar",
+			expErr:     errors.New("unexpected alphabetic character(s)"),
+		},
+		"all ranks exist": {
+			inRanklist: "1-2",
+			expRanks:   "1-2",
+		},
+		"non-existent rank": {
+			inRanklist: "0-3",
+			expErr:     &ErrRanksNotFound{Ranks: MustCreateRankSet("0,3")},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer ShowBufferOnFailure(t, buf)
+
+			ms := populateMembership(t, log, members...)
+
+			hit, err := ms.CheckRanksStrict(tc.inRanklist)
+			CmpErr(t, tc.expErr, err)
+			if err != nil {
+				if _, expNotFound := tc.expErr.(*ErrRanksNotFound); expNotFound && !IsRanksNotFound(err) {
+					t.Fatalf("expected %T, got %T", tc.expErr, err)
+				}
+				return
+			}
+
+			AssertEqual(t, tc.expRanks, hit.String(), "extant ranks")
+		})
+	}
+}
+
 func mockResolveFn(netString string, address string) (*net.TCPAddr, error) {
 	if netString != "tcp" {
 		return nil, errors.Errorf("unexpected network type in test: %s, want 'tcp'", netString)
@@ -621,6 +668,21 @@ func TestSystem_Membership_UpdateMemberStates(t *testing.T) {
 				MockMember(t, 6, MemberStateStopped),
 			},
 		},
+		"skipped results ignored": {
+			members: Members{
+				MockMember(t, 1, MemberStateJoined),
+				MockMember(t, 2, MemberStateStopped),
+			},
+			results: MemberResults{
+				NewMemberResult(1, nil, MemberStateStopped),
+				&MemberResult{Rank: 2, Msg: "not local", Skipped: true},
+				&MemberResult{Rank: 3, Msg: "not local", Skipped: true},
+			},
+			expMembers: Members{
+				MockMember(t, 1, MemberStateStopped),
+				MockMember(t, 2, MemberStateStopped),
+			},
+		},
 		"errored result with nonerrored state": {
 			members: Members{
 				MockMember(t, 1, MemberStateJoined),
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -151,6 +152,86 @@ func ParseRanks(stringRanks string) ([]Rank, error) {
 	return rs.Ranks(), nil
 }
 
+type rankRange struct {
+	lo, hi Rank
+}
+
+func parseRankRange(token string) (*rankRange, error) {
+	parseRank := func(s string) (Rank, error) {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return NilRank, errors.Errorf("invalid rank %q", s)
+		}
+		if Rank(n) > MaxRank {
+			return NilRank, errors.Errorf("rank %d out of range", n)
+		}
+		return Rank(n), nil
+	}
+
+	bounds := strings.Split(token, "-")
+	if len(bounds) > 2 {
+		return nil, errors.Errorf("invalid rank range %q", token)
+	}
+
+	lo, err := parseRank(bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	hi := lo
+	if len(bounds) == 2 {
+		if hi, err = parseRank(bounds[1]); err != nil {
+			return nil, err
+		}
+		if hi < lo {
+			return nil, errors.Errorf("rank range %q out of order", token)
+		}
+	}
+
+	return &rankRange{lo: lo, hi: hi}, nil
+}
+
+// ParseRankList takes a rangelist string representation of ranks e.g. 0-3,6
+// and returns the ranks in ascending order.
+//
+// Unlike ParseRanks, input is strictly validated: ranges with a start greater
+// than the end and ranks specified more than once, whether repeated or in
+// overlapping ranges, are rejected. Ranges may otherwise be given in any order.
+func ParseRankList(stringRanks string) ([]Rank, error) {
+	stringRanks = fixBrackets(strings.TrimSpace(stringRanks), true)
+	if stringRanks == "" {
+		return []Rank{}, nil
+	}
+
+	var ranges []*rankRange
+	for _, token := range strings.Split(stringRanks, ",") {
+		rr, err := parseRankRange(token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing rank list %q", stringRanks)
+		}
+		ranges = append(ranges, rr)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+	var ranks []Rank
+	for i, rr := range ranges {
+		if i > 0 && rr.lo <= ranges[i-1].hi {
+			return nil, errors.Errorf("parsing rank list %q: rank %d specified more than once",
+				stringRanks, rr.lo)
+		}
+		for r := rr.lo; r <= rr.hi; r++ {
+			ranks = append(ranks, r)
+		}
+	}
+
+	return ranks, nil
+}
+
+// FormatRankList returns the canonical rangelist string representation of the
+// given ranks e.g. 0-3,6, as accepted by ParseRankList.
+func FormatRankList(ranks []Rank) string {
+	return RankSetFromRanks(ranks).String()
+}
+
 // RankGroups maps a set of ranks to string value (group).
 type RankGroups map[string]*RankSet
 
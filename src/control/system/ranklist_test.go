@@ -97,6 +97,149 @@ func TestSystem_RankSet(t *testing.T) {
 	}
 }
 
+func TestSystem_ParseRankList(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ranks    string
+		expRanks []Rank
+		expErr   error
+	}{
+		"empty": {
+			expRanks: []Rank{},
+		},
+		"single rank": {
+			ranks:    "3",
+			expRanks: []Rank{3},
+		},
+		"range": {
+			ranks:    "0-3",
+			expRanks: []Rank{0, 1, 2, 3},
+		},
+		"list": {
+			ranks:    "0,3",
+			expRanks: []Rank{0, 3},
+		},
+		"brackets": {
+			ranks:    "[0-1,5]",
+			expRanks: []Rank{0, 1, 5},
+		},
+		"ranges given out of order": {
+			ranks:    "8,4-5,0-1",
+			expRanks: []Rank{0, 1, 4, 5, 8},
+		},
+		"single rank range": {
+			ranks:    "2-2",
+			expRanks: []Rank{2},
+		},
+		"reversed range": {
+			ranks:  "3-0",
+			expErr: errors.New("out of order"),
+		},
+		"overlapping ranges": {
+			ranks:  "0-3,2-5",
+			expErr: errors.New("rank 2 specified more than once"),
+		},
+		"duplicate rank": {
+			ranks:  "1,4,1",
+			expErr: errors.New("rank 1 specified more than once"),
+		},
+		"rank inside range": {
+			ranks:  "6,4-8",
+			expErr: errors.New("rank 6 specified more than once"),
+		},
+		"empty element": {
+			ranks:  "0,,3",
+			expErr: errors.New("invalid rank"),
+		},
+		"trailing separator": {
+			ranks:  "0-3,",
+			expErr: errors.New("invalid rank"),
+		},
+		"open range": {
+			ranks:  "0-",
+			expErr: errors.New("invalid rank"),
+		},
+		"too many bounds": {
+			ranks:  "0-3-5",
+			expErr: errors.New("invalid rank range"),
+		},
+		"alphabetic": {
+			ranks:  "node1",
+			expErr: errors.New("invalid rank"),
+		},
+		"negative": {
+			ranks:  "-1",
+			expErr: errors.New("invalid rank"),
+		},
+		"embedded whitespace": {
+			ranks:  "1, 5",
+			expErr: errors.New("invalid rank"),
+		},
+		"nil rank": {
+			ranks:  "4294967295",
+			expErr: errors.New("out of range"),
+		},
+		"too large": {
+			ranks:  "4294967296",
+			expErr: errors.New("invalid rank"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotRanks, gotErr := ParseRankList(tc.ranks)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expRanks, gotRanks); diff != "" {
+				t.Fatalf("unexpected ranks (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestSystem_FormatRankList(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ranks  []Rank
+		expOut string
+	}{
+		"empty": {
+			expOut: "",
+		},
+		"single rank": {
+			ranks:  []Rank{3},
+			expOut: "3",
+		},
+		"contiguous": {
+			ranks:  []Rank{0, 1, 2, 3},
+			expOut: "0-3",
+		},
+		"discontiguous": {
+			ranks:  []Rank{0, 3},
+			expOut: "0,3",
+		},
+		"unsorted with duplicates": {
+			ranks:  []Rank{9, 5, 1, 2, 3, 5, 8},
+			expOut: "1-3,5,8-9",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotOut := FormatRankList(tc.ranks)
+			if diff := cmp.Diff(tc.expOut, gotOut); diff != "" {
+				t.Fatalf("unexpected output (-want, +got):\n%s\n", diff)
+			}
+
+			// canonical output should round-trip unchanged
+			gotRanks, err := ParseRankList(gotOut)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(gotOut, FormatRankList(gotRanks)); diff != "" {
+				t.Fatalf("unexpected round-trip output (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
 func TestSystem_RankGroupsFromMembers(t *testing.T) {
 	for name, tc := range map[string]struct {
 		rankGroups RankGroups
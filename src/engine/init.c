@@ -390,6 +390,18 @@ dss_init_state_set(enum dss_init_state state)
 	ABT_mutex_unlock(server_init_state_mutex);
 }
 
+enum dss_init_state
+dss_init_state_get(void)
+{
+	enum dss_init_state	state;
+
+	ABT_mutex_lock(server_init_state_mutex);
+	state = server_init_state;
+	ABT_mutex_unlock(server_init_state_mutex);
+
+	return state;
+}
+
 static int
 abt_max_num_xstreams(void)
 {
@@ -672,6 +684,7 @@ server_init(int argc, char *argv[])
 		D_GOTO(exit_init_state, rc);
 
 	dss_xstreams_open_barrier();
+	dss_init_state_set(DSS_INIT_STATE_SVC_UP);
 	D_INFO("Service fully up\n");
 
 	/** Report timestamp when engine was open for business */
@@ -69,6 +69,7 @@ enum drpc_mgmt_method {
 	DRPC_METHOD_MGMT_DEV_IDENTIFY		= 234,
 	DRPC_METHOD_MGMT_NOTIFY_POOL_CONNECT	= 235,
 	DRPC_METHOD_MGMT_NOTIFY_POOL_DISCONNECT	= 236,
+	DRPC_METHOD_MGMT_POOL_SVCS_UP		= 237,
 
 	NUM_DRPC_MGMT_METHODS			/* Must be last */
 };
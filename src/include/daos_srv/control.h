@@ -80,6 +80,7 @@ struct nvme_stats {
 	uint32_t	 bio_unmap_errs;
 	uint32_t	 checksum_errs;
 	uint16_t	 temperature; /* in Kelvin */
+	uint8_t		 avail_spare; /* normalized percentage */
 	/* Critical warnings */
 	bool		 temp_warn;
 	bool		 avail_spare_warn;
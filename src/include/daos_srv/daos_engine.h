@@ -814,7 +814,8 @@ ds_migrate_abort(uuid_t pool_uuid, uint32_t ver);
 /** Server init state (see server_init) */
 enum dss_init_state {
 	DSS_INIT_STATE_INIT,		/**< initial state */
-	DSS_INIT_STATE_SET_UP		/**< ready to set up modules */
+	DSS_INIT_STATE_SET_UP,		/**< ready to set up modules */
+	DSS_INIT_STATE_SVC_UP		/**< modules set up, services started */
 };
 
 enum dss_media_error_type {
@@ -825,6 +826,7 @@ enum dss_media_error_type {
 };
 
 void dss_init_state_set(enum dss_init_state state);
+enum dss_init_state dss_init_state_get(void);
 
 /* Notify control-plane of a bio error. */
 int
@@ -53,7 +53,10 @@
 	X(RAS_SWIM_RANK_ALIVE,		"swim_rank_alive")		\
 	X(RAS_SWIM_RANK_DEAD,		"swim_rank_dead")		\
 	X(RAS_SYSTEM_START_FAILED,	"system_start_failed")		\
-	X(RAS_SYSTEM_STOP_FAILED,	"system_stop_failed")
+	X(RAS_SYSTEM_STOP_FAILED,	"system_stop_failed")		\
+	X(RAS_ENGINE_RESET_FORMAT,	"engine_reset_format_progress")	\
+	X(RAS_RANK_OPERATION,		"rank_operation")		\
+	X(RAS_TELEMETRY_THRESHOLD,	"telemetry_threshold_crossed")
 
 /** Define RAS event enum */
 typedef enum {
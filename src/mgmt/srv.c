@@ -84,6 +84,9 @@ process_drpc_request(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
 	case DRPC_METHOD_MGMT_SET_UP:
 		ds_mgmt_drpc_set_up(drpc_req, drpc_resp);
 		break;
+	case DRPC_METHOD_MGMT_POOL_SVCS_UP:
+		ds_mgmt_drpc_pool_svcs_up(drpc_req, drpc_resp);
+		break;
 	case DRPC_METHOD_MGMT_EXCLUDE:
 		ds_mgmt_drpc_pool_exclude(drpc_req, drpc_resp);
 		break;
@@ -2016,6 +2016,20 @@ ds_mgmt_drpc_set_up(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
 	pack_daos_response(&resp, drpc_resp);
 }
 
+void
+ds_mgmt_drpc_pool_svcs_up(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
+{
+	Mgmt__DaosResp	resp = MGMT__DAOS_RESP__INIT;
+
+	/* pool services are started during module setup */
+	if (dss_init_state_get() != DSS_INIT_STATE_SVC_UP) {
+		resp.status = -DER_BUSY;
+		resp.msg = "pool services not yet started";
+	}
+
+	pack_daos_response(&resp, drpc_resp);
+}
+
 void
 ds_mgmt_drpc_cont_set_owner(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
 {
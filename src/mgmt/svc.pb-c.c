@@ -604,7 +604,7 @@ void   mgmt__pool_monitor_req__free_unpacked
   assert(message->base.descriptor == &mgmt__pool_monitor_req__descriptor);
   protobuf_c_message_free_unpacked ((ProtobufCMessage*)message, allocator);
 }
-static const ProtobufCFieldDescriptor mgmt__daos_resp__field_descriptors[1] =
+static const ProtobufCFieldDescriptor mgmt__daos_resp__field_descriptors[2] =
 {
   {
     "status",
@@ -618,14 +618,27 @@ static const ProtobufCFieldDescriptor mgmt__daos_resp__field_descriptors[1] =
     0,             /* flags */
     0,NULL,NULL    /* reserved1,reserved2, etc */
   },
+  {
+    "msg",
+    2,
+    PROTOBUF_C_LABEL_NONE,
+    PROTOBUF_C_TYPE_STRING,
+    0,   /* quantifier_offset */
+    offsetof(Mgmt__DaosResp, msg),
+    NULL,
+    &protobuf_c_empty_string,
+    0,             /* flags */
+    0,NULL,NULL    /* reserved1,reserved2, etc */
+  },
 };
 static const unsigned mgmt__daos_resp__field_indices_by_name[] = {
+  1,   /* field[1] = msg */
   0,   /* field[0] = status */
 };
 static const ProtobufCIntRange mgmt__daos_resp__number_ranges[1 + 1] =
 {
   { 1, 0 },
-  { 0, 1 }
+  { 0, 2 }
 };
 const ProtobufCMessageDescriptor mgmt__daos_resp__descriptor =
 {
@@ -635,7 +648,7 @@ const ProtobufCMessageDescriptor mgmt__daos_resp__descriptor =
   "Mgmt__DaosResp",
   "mgmt",
   sizeof(Mgmt__DaosResp),
-  1,
+  2,
   mgmt__daos_resp__field_descriptors,
   mgmt__daos_resp__field_indices_by_name,
   1,  mgmt__daos_resp__number_ranges,
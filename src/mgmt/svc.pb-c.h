@@ -58,10 +58,14 @@ struct  _Mgmt__DaosResp
    * DAOS error code.
    */
   int32_t status;
+  /*
+   * Optional engine-provided detail.
+   */
+  char *msg;
 };
 #define MGMT__DAOS_RESP__INIT \
  { PROTOBUF_C_MESSAGE_INIT (&mgmt__daos_resp__descriptor) \
-    , 0 }
+    , 0, (char *)protobuf_c_empty_string }
 
 
 struct  _Mgmt__GroupUpdateReq__Engine
@@ -383,6 +383,12 @@ dss_init_state_set(enum dss_init_state state)
 {
 }
 
+enum dss_init_state
+dss_init_state_get(void)
+{
+	return DSS_INIT_STATE_SVC_UP;
+}
+
 size_t
 ds_rsvc_get_md_cap(void)
 {